@@ -41,6 +41,36 @@ var (
 		Status:     false,
 		Message:    "BAD REQUEST ERROR",
 	}
+	unsupportedMediaTypeError = CustomError{
+		Code:       "ERR0006",
+		StatusCode: http.StatusUnsupportedMediaType,
+		Status:     false,
+		Message:    "UNSUPPORTED MEDIA TYPE",
+	}
+	conflictError = CustomError{
+		Code:       "ERR0007",
+		StatusCode: http.StatusConflict,
+		Status:     false,
+		Message:    "CONFLICT",
+	}
+	unprocessableEntityError = CustomError{
+		Code:       "ERR0008",
+		StatusCode: http.StatusUnprocessableEntity,
+		Status:     false,
+		Message:    "UNPROCESSABLE ENTITY",
+	}
+	notAcceptableError = CustomError{
+		Code:       "ERR0009",
+		StatusCode: http.StatusNotAcceptable,
+		Status:     false,
+		Message:    "NOT ACCEPTABLE",
+	}
+	forbiddenError = CustomError{
+		Code:       "ERR0010",
+		StatusCode: http.StatusForbidden,
+		Status:     false,
+		Message:    "FORBIDDEN",
+	}
 )
 
 func GeneralError(message ...string) *CustomError {
@@ -85,7 +115,7 @@ func NotFoundError(message ...string) *CustomError {
 }
 
 func NotFoundErrorWithAdditionalInfo(info interface{}, message ...string) *CustomError {
-	err := repositoryError
+	err := notFoundError
 	err.AdditionalInfo = info
 	if len(message) != 0 {
 		err.Message = message[0]
@@ -125,3 +155,88 @@ func BadRequestErrorWithAdditionalInfo(info interface{}, message ...string) *Cus
 	}
 	return &err
 }
+
+func UnsupportedMediaTypeError(message ...string) *CustomError {
+	err := unsupportedMediaTypeError
+	if len(message) != 0 {
+		err.Message = message[0]
+	}
+	return &err
+}
+
+func UnsupportedMediaTypeErrorWithAdditionalInfo(info interface{}, message ...string) *CustomError {
+	err := unsupportedMediaTypeError
+	err.AdditionalInfo = info
+	if len(message) != 0 {
+		err.Message = message[0]
+	}
+	return &err
+}
+
+func ConflictError(message ...string) *CustomError {
+	err := conflictError
+	if len(message) != 0 {
+		err.Message = message[0]
+	}
+	return &err
+}
+
+func ConflictErrorWithAdditionalInfo(info interface{}, message ...string) *CustomError {
+	err := conflictError
+	err.AdditionalInfo = info
+	if len(message) != 0 {
+		err.Message = message[0]
+	}
+	return &err
+}
+
+func UnprocessableEntityError(message ...string) *CustomError {
+	err := unprocessableEntityError
+	if len(message) != 0 {
+		err.Message = message[0]
+	}
+	return &err
+}
+
+func UnprocessableEntityErrorWithAdditionalInfo(info interface{}, message ...string) *CustomError {
+	err := unprocessableEntityError
+	err.AdditionalInfo = info
+	if len(message) != 0 {
+		err.Message = message[0]
+	}
+	return &err
+}
+
+func NotAcceptableError(message ...string) *CustomError {
+	err := notAcceptableError
+	if len(message) != 0 {
+		err.Message = message[0]
+	}
+	return &err
+}
+
+func NotAcceptableErrorWithAdditionalInfo(info interface{}, message ...string) *CustomError {
+	err := notAcceptableError
+	err.AdditionalInfo = info
+	if len(message) != 0 {
+		err.Message = message[0]
+	}
+	return &err
+}
+
+func ForbiddenError(message ...string) *CustomError {
+	err := forbiddenError
+	if len(message) != 0 {
+		err.Message = message[0]
+	}
+	return &err
+}
+
+func ForbiddenErrorWithAdditionalInfo(info interface{}, message ...string) *CustomError {
+	err := forbiddenError
+	err.AdditionalInfo = info
+	if len(message) != 0 {
+		err.Message = message[0]
+	}
+	return &err
+}
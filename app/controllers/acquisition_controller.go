@@ -0,0 +1,208 @@
+package controllers
+
+import (
+	"golang-backend-test/app/commons/response"
+	"golang-backend-test/app/params"
+	"golang-backend-test/app/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AcquisitionController interface {
+	GetListVendors(ginCtx *gin.Context)
+	CreateVendor(ginCtx *gin.Context)
+	GetListFunds(ginCtx *gin.Context)
+	FindFundById(ginCtx *gin.Context)
+	CreateFund(ginCtx *gin.Context)
+	GetListPurchaseOrders(ginCtx *gin.Context)
+	FindPurchaseOrderById(ginCtx *gin.Context)
+	CreatePurchaseOrder(ginCtx *gin.Context)
+	SubmitPurchaseOrder(ginCtx *gin.Context)
+	CancelPurchaseOrder(ginCtx *gin.Context)
+	ReceivePurchaseOrder(ginCtx *gin.Context)
+}
+
+type AcquisitionControllerImpl struct {
+	AcquisitionService services.AcquisitionService
+}
+
+func NewAcquisitionController(acquisitionService services.AcquisitionService) AcquisitionController {
+	return &AcquisitionControllerImpl{
+		AcquisitionService: acquisitionService,
+	}
+}
+
+func (controller *AcquisitionControllerImpl) GetListVendors(ginCtx *gin.Context) {
+	result, custErr := controller.AcquisitionService.FindAllVendors(ginCtx)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+	resp := response.GeneralSuccessCustomMessageAndPayload("Success get data vendors.", result)
+	ginCtx.JSON(resp.StatusCode, resp)
+}
+
+func (controller *AcquisitionControllerImpl) CreateVendor(ginCtx *gin.Context) {
+	var request = new(params.VendorRequest)
+	err := ginCtx.ShouldBindJSON(request)
+	if err != nil {
+		errParam := response.GeneralError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+
+	result, custErr := controller.AcquisitionService.CreateVendor(ginCtx, request)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+	resp := response.CreatedSuccessCustomMessageAndPayload("Success create data vendors", result)
+	ginCtx.JSON(resp.StatusCode, resp)
+}
+
+func (controller *AcquisitionControllerImpl) GetListFunds(ginCtx *gin.Context) {
+	result, custErr := controller.AcquisitionService.FindAllFunds(ginCtx)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+	resp := response.GeneralSuccessCustomMessageAndPayload("Success get data funds.", result)
+	ginCtx.JSON(resp.StatusCode, resp)
+}
+
+func (controller *AcquisitionControllerImpl) FindFundById(ginCtx *gin.Context) {
+	id, err := strconv.Atoi(ginCtx.Param("id"))
+	if err != nil {
+		errParam := response.NotFoundError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+	result, custErr := controller.AcquisitionService.FindDetailFund(ginCtx, id)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+	resp := response.GeneralSuccessCustomMessageAndPayload("Success get data detail funds.", result)
+	ginCtx.JSON(resp.StatusCode, resp)
+}
+
+func (controller *AcquisitionControllerImpl) CreateFund(ginCtx *gin.Context) {
+	var request = new(params.FundRequest)
+	err := ginCtx.ShouldBindJSON(request)
+	if err != nil {
+		errParam := response.GeneralError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+
+	result, custErr := controller.AcquisitionService.CreateFund(ginCtx, request)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+	resp := response.CreatedSuccessCustomMessageAndPayload("Success create data funds", result)
+	ginCtx.JSON(resp.StatusCode, resp)
+}
+
+func (controller *AcquisitionControllerImpl) GetListPurchaseOrders(ginCtx *gin.Context) {
+	result, custErr := controller.AcquisitionService.FindAllPurchaseOrders(ginCtx, ginCtx.Query("status"))
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+	resp := response.GeneralSuccessCustomMessageAndPayload("Success get data purchase orders.", result)
+	ginCtx.JSON(resp.StatusCode, resp)
+}
+
+func (controller *AcquisitionControllerImpl) FindPurchaseOrderById(ginCtx *gin.Context) {
+	id, err := strconv.Atoi(ginCtx.Param("id"))
+	if err != nil {
+		errParam := response.NotFoundError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+	result, custErr := controller.AcquisitionService.FindDetailPurchaseOrder(ginCtx, id)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+	resp := response.GeneralSuccessCustomMessageAndPayload("Success get data detail purchase orders.", result)
+	ginCtx.JSON(resp.StatusCode, resp)
+}
+
+func (controller *AcquisitionControllerImpl) CreatePurchaseOrder(ginCtx *gin.Context) {
+	var request = new(params.PurchaseOrderRequest)
+	err := ginCtx.ShouldBindJSON(request)
+	if err != nil {
+		errParam := response.GeneralError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+
+	result, custErr := controller.AcquisitionService.CreatePurchaseOrder(ginCtx, ginCtx.GetInt("authId"), request)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+	resp := response.CreatedSuccessCustomMessageAndPayload("Success create data purchase orders", result)
+	ginCtx.JSON(resp.StatusCode, resp)
+}
+
+func (controller *AcquisitionControllerImpl) SubmitPurchaseOrder(ginCtx *gin.Context) {
+	id, err := strconv.Atoi(ginCtx.Param("id"))
+	if err != nil {
+		errParam := response.NotFoundError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+
+	result, custErr := controller.AcquisitionService.SubmitPurchaseOrder(ginCtx, ginCtx.GetInt("authId"), id)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+	resp := response.GeneralSuccessCustomMessageAndPayload("Success submit data purchase orders", result)
+	ginCtx.JSON(resp.StatusCode, resp)
+}
+
+func (controller *AcquisitionControllerImpl) CancelPurchaseOrder(ginCtx *gin.Context) {
+	id, err := strconv.Atoi(ginCtx.Param("id"))
+	if err != nil {
+		errParam := response.NotFoundError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+
+	result, custErr := controller.AcquisitionService.CancelPurchaseOrder(ginCtx, id)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+	resp := response.GeneralSuccessCustomMessageAndPayload("Success cancel data purchase orders", result)
+	ginCtx.JSON(resp.StatusCode, resp)
+}
+
+func (controller *AcquisitionControllerImpl) ReceivePurchaseOrder(ginCtx *gin.Context) {
+	var request = new(params.PurchaseOrderReceiveRequest)
+	err := ginCtx.ShouldBindJSON(request)
+	if err != nil {
+		errParam := response.GeneralError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+	id, err := strconv.Atoi(ginCtx.Param("id"))
+	if err != nil {
+		errParam := response.NotFoundError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+
+	result, custErr := controller.AcquisitionService.ReceivePurchaseOrder(ginCtx, id, request)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+	resp := response.GeneralSuccessCustomMessageAndPayload("Success receive data purchase orders", result)
+	ginCtx.JSON(resp.StatusCode, resp)
+}
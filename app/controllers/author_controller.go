@@ -4,6 +4,8 @@ import (
 	"golang-backend-test/app/commons/response"
 	"golang-backend-test/app/params"
 	"golang-backend-test/app/services"
+	"net/http"
+	"path"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
@@ -14,7 +16,12 @@ type AuthorController interface {
 	GetListAuthors(ginCtx *gin.Context)
 	CreateAuthor(ginCtx *gin.Context)
 	UpdateAuthor(ginCtx *gin.Context)
+	PatchAuthor(ginCtx *gin.Context)
 	DeleteAuthor(ginCtx *gin.Context)
+	FindDuplicateAuthors(ginCtx *gin.Context)
+	MergeAuthor(ginCtx *gin.Context)
+	GetListAuthorBooks(ginCtx *gin.Context)
+	GetAuthorStats(ginCtx *gin.Context)
 }
 
 type AuthorControllerImpl struct {
@@ -39,13 +46,42 @@ func (controller *AuthorControllerImpl) FindAuthorById(ginCtx *gin.Context) {
 		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
 		return
 	}
+	if result.ID != uint(id) {
+		// the requested author was merged into another one
+		location := path.Join(path.Dir(ginCtx.Request.URL.Path), strconv.Itoa(int(result.ID)))
+		ginCtx.Redirect(http.StatusMovedPermanently, location)
+		return
+	}
 
 	resp := response.GeneralSuccessCustomMessageAndPayload("Success get data detail authors.", result)
 	ginCtx.JSON(resp.StatusCode, resp)
 }
 
 func (controller *AuthorControllerImpl) GetListAuthors(ginCtx *gin.Context) {
-	result, custErr := controller.AuthorService.FindAllAuthors(ginCtx)
+	if ginCtx.Query("q") == "" && wantsPage(ginCtx) {
+		var request = new(params.PaginationRequest)
+		if err := ginCtx.ShouldBindQuery(request); err != nil {
+			errParam := response.BadRequestErrorWithAdditionalInfo(err.Error())
+			ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+			return
+		}
+		result, custErr := controller.AuthorService.FindAuthorsPage(ginCtx, request)
+		if custErr != nil {
+			ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+			return
+		}
+		resp := response.GeneralSuccessCustomMessageAndPayload("Success get data authors.", result)
+		ginCtx.JSON(resp.StatusCode, resp)
+		return
+	}
+
+	var result []*params.AuthorResponse
+	var custErr *response.CustomError
+	if query := ginCtx.Query("q"); query != "" {
+		result, custErr = controller.AuthorService.SearchAuthors(ginCtx, query)
+	} else {
+		result, custErr = controller.AuthorService.FindAllAuthors(ginCtx)
+	}
 	if custErr != nil {
 		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
 		return
@@ -64,7 +100,7 @@ func (controller *AuthorControllerImpl) CreateAuthor(ginCtx *gin.Context) {
 		return
 	}
 
-	custErr := controller.AuthorService.CrateAuthor(ginCtx, request)
+	_, custErr := controller.AuthorService.CrateAuthor(ginCtx, request)
 	if custErr != nil {
 		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
 		return
@@ -97,6 +133,29 @@ func (controller *AuthorControllerImpl) UpdateAuthor(ginCtx *gin.Context) {
 	ginCtx.JSON(resp.StatusCode, resp)
 }
 
+func (controller *AuthorControllerImpl) PatchAuthor(ginCtx *gin.Context) {
+	id, err := strconv.Atoi(ginCtx.Param("id"))
+	if err != nil {
+		errParam := response.NotFoundError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+	patchDoc, err := ginCtx.GetRawData()
+	if err != nil {
+		errParam := response.GeneralError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+
+	result, custErr := controller.AuthorService.PatchAuthor(ginCtx, id, ginCtx.ContentType(), patchDoc)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+	resp := response.GeneralSuccessCustomMessageAndPayload("Success patch data authors", result)
+	ginCtx.JSON(resp.StatusCode, resp)
+}
+
 func (controller *AuthorControllerImpl) DeleteAuthor(ginCtx *gin.Context) {
 	id, err := strconv.Atoi(ginCtx.Param("id"))
 	if err != nil {
@@ -113,3 +172,88 @@ func (controller *AuthorControllerImpl) DeleteAuthor(ginCtx *gin.Context) {
 	resp := response.GeneralSuccess()
 	ginCtx.JSON(resp.StatusCode, resp)
 }
+
+func (controller *AuthorControllerImpl) FindDuplicateAuthors(ginCtx *gin.Context) {
+	minScore, err := strconv.ParseFloat(ginCtx.DefaultQuery("min_score", "0.75"), 64)
+	if err != nil {
+		errParam := response.BadRequestErrorWithAdditionalInfo("min_score must be a number")
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+
+	result, custErr := controller.AuthorService.FindDuplicateAuthors(ginCtx, minScore)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+
+	resp := response.GeneralSuccessCustomMessageAndPayload("Success get data duplicate authors.", result)
+	ginCtx.JSON(resp.StatusCode, resp)
+}
+
+func (controller *AuthorControllerImpl) MergeAuthor(ginCtx *gin.Context) {
+	var request = new(params.AuthorMergeRequest)
+	err := ginCtx.ShouldBindJSON(request)
+	if err != nil {
+		errParam := response.GeneralError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+	id, err := strconv.Atoi(ginCtx.Param("id"))
+	if err != nil {
+		errParam := response.NotFoundError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+
+	result, custErr := controller.AuthorService.MergeAuthor(ginCtx, id, request)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+	resp := response.GeneralSuccessCustomMessageAndPayload("Success merge data authors", result)
+	ginCtx.JSON(resp.StatusCode, resp)
+}
+
+func (controller *AuthorControllerImpl) GetListAuthorBooks(ginCtx *gin.Context) {
+	id, err := strconv.Atoi(ginCtx.Param("id"))
+	if err != nil {
+		errParam := response.NotFoundError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+	var request = new(params.PaginationRequest)
+	err = ginCtx.ShouldBindQuery(request)
+	if err != nil {
+		errParam := response.BadRequestErrorWithAdditionalInfo(err.Error())
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+
+	result, custErr := controller.AuthorService.FindAuthorBooks(ginCtx, id, request)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+
+	resp := response.GeneralSuccessCustomMessageAndPayload("Success get data author books.", result)
+	ginCtx.JSON(resp.StatusCode, resp)
+}
+
+func (controller *AuthorControllerImpl) GetAuthorStats(ginCtx *gin.Context) {
+	id, err := strconv.Atoi(ginCtx.Param("id"))
+	if err != nil {
+		errParam := response.NotFoundError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+
+	result, custErr := controller.AuthorService.FindAuthorStats(ginCtx, id)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+
+	resp := response.GeneralSuccessCustomMessageAndPayload("Success get data author stats.", result)
+	ginCtx.JSON(resp.StatusCode, resp)
+}
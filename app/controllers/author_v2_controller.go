@@ -0,0 +1,176 @@
+package controllers
+
+import (
+	"golang-backend-test/app/commons/response"
+	"golang-backend-test/app/params"
+	"golang-backend-test/app/services"
+	"net/http"
+	"path"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuthorV2Controller serves authors under /v2. It goes through the same
+// AuthorService as AuthorController, mapping the results to the v2 shapes.
+type AuthorV2Controller interface {
+	FindAuthorById(ginCtx *gin.Context)
+	GetListAuthors(ginCtx *gin.Context)
+	CreateAuthor(ginCtx *gin.Context)
+	UpdateAuthor(ginCtx *gin.Context)
+	PatchAuthor(ginCtx *gin.Context)
+	DeleteAuthor(ginCtx *gin.Context)
+	GetListAuthorBooks(ginCtx *gin.Context)
+}
+
+type AuthorV2ControllerImpl struct {
+	AuthorService services.AuthorService
+}
+
+func NewAuthorV2Controller(authorService services.AuthorService) AuthorV2Controller {
+	return &AuthorV2ControllerImpl{
+		AuthorService: authorService,
+	}
+}
+
+func (controller *AuthorV2ControllerImpl) FindAuthorById(ginCtx *gin.Context) {
+	id, err := strconv.Atoi(ginCtx.Param("id"))
+	if err != nil {
+		abortV2(ginCtx, response.NotFoundError())
+		return
+	}
+	result, custErr := controller.AuthorService.FindDetailAuthor(ginCtx, id)
+	if custErr != nil {
+		abortV2(ginCtx, custErr)
+		return
+	}
+	if result.ID != uint(id) {
+		// the requested author was merged into another one
+		location := path.Join(path.Dir(ginCtx.Request.URL.Path), strconv.Itoa(int(result.ID)))
+		ginCtx.Redirect(http.StatusMovedPermanently, location)
+		return
+	}
+	ginCtx.JSON(http.StatusOK, authorV2Response(result))
+}
+
+// GetListAuthors answers with a page of authors, or with every author
+// matching q in a single page.
+func (controller *AuthorV2ControllerImpl) GetListAuthors(ginCtx *gin.Context) {
+	if query := ginCtx.Query("q"); query != "" {
+		result, custErr := controller.AuthorService.SearchAuthors(ginCtx, query)
+		if custErr != nil {
+			abortV2(ginCtx, custErr)
+			return
+		}
+		ginCtx.JSON(http.StatusOK, &params.ListV2Response{Items: authorsV2Response(result), Total: int64(len(result))})
+		return
+	}
+
+	var request = new(params.PaginationRequest)
+	if err := ginCtx.ShouldBindQuery(request); err != nil {
+		abortV2(ginCtx, response.BadRequestErrorWithAdditionalInfo(err.Error()))
+		return
+	}
+	result, custErr := controller.AuthorService.FindAuthorsPage(ginCtx, request)
+	if custErr != nil {
+		abortV2(ginCtx, custErr)
+		return
+	}
+	ginCtx.JSON(http.StatusOK, listV2Response(result))
+}
+
+func (controller *AuthorV2ControllerImpl) CreateAuthor(ginCtx *gin.Context) {
+	var request = new(params.AuthorRequest)
+	if err := ginCtx.ShouldBindJSON(request); err != nil {
+		abortV2(ginCtx, response.BadRequestErrorWithAdditionalInfo(err.Error()))
+		return
+	}
+	result, custErr := controller.AuthorService.CrateAuthor(ginCtx, request)
+	if custErr != nil {
+		abortV2(ginCtx, custErr)
+		return
+	}
+	ginCtx.Header("Location", ginCtx.Request.URL.Path+strconv.Itoa(int(result.ID)))
+	ginCtx.JSON(http.StatusCreated, authorV2Response(result))
+}
+
+func (controller *AuthorV2ControllerImpl) UpdateAuthor(ginCtx *gin.Context) {
+	id, err := strconv.Atoi(ginCtx.Param("id"))
+	if err != nil {
+		abortV2(ginCtx, response.NotFoundError())
+		return
+	}
+	var request = new(params.AuthorRequest)
+	if err := ginCtx.ShouldBindJSON(request); err != nil {
+		abortV2(ginCtx, response.BadRequestErrorWithAdditionalInfo(err.Error()))
+		return
+	}
+	result, custErr := controller.AuthorService.UpdateAuthor(ginCtx, id, request)
+	if custErr != nil {
+		abortV2(ginCtx, custErr)
+		return
+	}
+	controller.writeAuthor(ginCtx, result.ID)
+}
+
+func (controller *AuthorV2ControllerImpl) PatchAuthor(ginCtx *gin.Context) {
+	id, err := strconv.Atoi(ginCtx.Param("id"))
+	if err != nil {
+		abortV2(ginCtx, response.NotFoundError())
+		return
+	}
+	patchDoc, err := ginCtx.GetRawData()
+	if err != nil {
+		abortV2(ginCtx, response.BadRequestErrorWithAdditionalInfo(err.Error()))
+		return
+	}
+	result, custErr := controller.AuthorService.PatchAuthor(ginCtx, id, ginCtx.ContentType(), patchDoc)
+	if custErr != nil {
+		abortV2(ginCtx, custErr)
+		return
+	}
+	controller.writeAuthor(ginCtx, result.ID)
+}
+
+// writeAuthor answers with the author a change was applied to, which is the
+// author a merged one was merged into.
+func (controller *AuthorV2ControllerImpl) writeAuthor(ginCtx *gin.Context, id uint) {
+	result, custErr := controller.AuthorService.FindDetailAuthor(ginCtx, int(id))
+	if custErr != nil {
+		abortV2(ginCtx, custErr)
+		return
+	}
+	ginCtx.JSON(http.StatusOK, authorV2Response(result))
+}
+
+func (controller *AuthorV2ControllerImpl) DeleteAuthor(ginCtx *gin.Context) {
+	id, err := strconv.Atoi(ginCtx.Param("id"))
+	if err != nil {
+		abortV2(ginCtx, response.NotFoundError())
+		return
+	}
+	if custErr := controller.AuthorService.DeleteAuthor(ginCtx, id); custErr != nil {
+		abortV2(ginCtx, custErr)
+		return
+	}
+	ginCtx.Status(http.StatusNoContent)
+}
+
+func (controller *AuthorV2ControllerImpl) GetListAuthorBooks(ginCtx *gin.Context) {
+	id, err := strconv.Atoi(ginCtx.Param("id"))
+	if err != nil {
+		abortV2(ginCtx, response.NotFoundError())
+		return
+	}
+	var request = new(params.PaginationRequest)
+	if err := ginCtx.ShouldBindQuery(request); err != nil {
+		abortV2(ginCtx, response.BadRequestErrorWithAdditionalInfo(err.Error()))
+		return
+	}
+	result, custErr := controller.AuthorService.FindAuthorBooks(ginCtx, id, request)
+	if custErr != nil {
+		abortV2(ginCtx, custErr)
+		return
+	}
+	ginCtx.JSON(http.StatusOK, listV2Response(result))
+}
@@ -0,0 +1,41 @@
+package controllers
+
+import (
+	"golang-backend-test/app/commons/response"
+	"golang-backend-test/app/params"
+	"golang-backend-test/app/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BatchController interface {
+	RunBatch(ginCtx *gin.Context)
+}
+
+type BatchControllerImpl struct {
+	BatchService services.BatchService
+}
+
+func NewBatchController(batchService services.BatchService) BatchController {
+	return &BatchControllerImpl{
+		BatchService: batchService,
+	}
+}
+
+func (controller *BatchControllerImpl) RunBatch(ginCtx *gin.Context) {
+	var request = new(params.BatchRequest)
+	err := ginCtx.ShouldBindJSON(request)
+	if err != nil {
+		errParam := response.GeneralError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+
+	result, custErr := controller.BatchService.RunBatch(ginCtx, request)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+	resp := response.GeneralSuccessCustomMessageAndPayload("Success run batch.", result)
+	ginCtx.JSON(resp.StatusCode, resp)
+}
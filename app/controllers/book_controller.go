@@ -14,16 +14,21 @@ type BookController interface {
 	GetListBooks(ginCtx *gin.Context)
 	CreateBook(ginCtx *gin.Context)
 	UpdateBook(ginCtx *gin.Context)
+	PatchBook(ginCtx *gin.Context)
 	DeleteBook(ginCtx *gin.Context)
+	ImportBooks(ginCtx *gin.Context)
+	RateBook(ginCtx *gin.Context)
 }
 
 type BookControllerImpl struct {
 	BookService services.BookService
+	JobService  services.JobService
 }
 
-func NewBookController(bookService services.BookService) BookController {
+func NewBookController(bookService services.BookService, jobService services.JobService) BookController {
 	return &BookControllerImpl{
 		BookService: bookService,
+		JobService:  jobService,
 	}
 }
 
@@ -34,7 +39,13 @@ func (controller *BookControllerImpl) FindBookById(ginCtx *gin.Context) {
 		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
 		return
 	}
-	result, custErr := controller.BookService.FindDetailBook(ginCtx, id)
+	var fieldset = new(params.FieldsetRequest)
+	if err := ginCtx.ShouldBindQuery(fieldset); err != nil {
+		errParam := response.BadRequestErrorWithAdditionalInfo(err.Error())
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+	result, custErr := controller.BookService.FindDetailBook(ginCtx, id, fieldset)
 	if custErr != nil {
 		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
 		return
@@ -45,7 +56,43 @@ func (controller *BookControllerImpl) FindBookById(ginCtx *gin.Context) {
 }
 
 func (controller *BookControllerImpl) GetListBooks(ginCtx *gin.Context) {
-	result, custErr := controller.BookService.FindAllBooks(ginCtx)
+	var fieldset = new(params.FieldsetRequest)
+	if err := ginCtx.ShouldBindQuery(fieldset); err != nil {
+		errParam := response.BadRequestErrorWithAdditionalInfo(err.Error())
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+
+	if ginCtx.Query("branch_id") == "" && wantsPage(ginCtx) {
+		var request = new(params.PaginationRequest)
+		if err := ginCtx.ShouldBindQuery(request); err != nil {
+			errParam := response.BadRequestErrorWithAdditionalInfo(err.Error())
+			ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+			return
+		}
+		result, custErr := controller.BookService.FindBooksPage(ginCtx, request, fieldset)
+		if custErr != nil {
+			ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+			return
+		}
+		resp := response.GeneralSuccessCustomMessageAndPayload("Success get data books.", result)
+		ginCtx.JSON(resp.StatusCode, resp)
+		return
+	}
+
+	var result []*params.BookResponse
+	var custErr *response.CustomError
+	if branch := ginCtx.Query("branch_id"); branch != "" {
+		branchId, err := strconv.Atoi(branch)
+		if err != nil {
+			errParam := response.BadRequestErrorWithAdditionalInfo("branch_id must be a number")
+			ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+			return
+		}
+		result, custErr = controller.BookService.FindBranchBooks(ginCtx, branchId, fieldset)
+	} else {
+		result, custErr = controller.BookService.FindAllBooks(ginCtx, fieldset)
+	}
 	if custErr != nil {
 		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
 		return
@@ -64,7 +111,7 @@ func (controller *BookControllerImpl) CreateBook(ginCtx *gin.Context) {
 		return
 	}
 
-	custErr := controller.BookService.CrateBook(ginCtx, request)
+	_, custErr := controller.BookService.CrateBook(ginCtx, request)
 	if custErr != nil {
 		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
 		return
@@ -97,6 +144,29 @@ func (controller *BookControllerImpl) UpdateBook(ginCtx *gin.Context) {
 	ginCtx.JSON(resp.StatusCode, resp)
 }
 
+func (controller *BookControllerImpl) PatchBook(ginCtx *gin.Context) {
+	id, err := strconv.Atoi(ginCtx.Param("id"))
+	if err != nil {
+		errParam := response.NotFoundError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+	patchDoc, err := ginCtx.GetRawData()
+	if err != nil {
+		errParam := response.GeneralError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+
+	result, custErr := controller.BookService.PatchBook(ginCtx, id, ginCtx.ContentType(), patchDoc)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+	resp := response.GeneralSuccessCustomMessageAndPayload("Success patch data books", result)
+	ginCtx.JSON(resp.StatusCode, resp)
+}
+
 func (controller *BookControllerImpl) DeleteBook(ginCtx *gin.Context) {
 	id, err := strconv.Atoi(ginCtx.Param("id"))
 	if err != nil {
@@ -113,3 +183,62 @@ func (controller *BookControllerImpl) DeleteBook(ginCtx *gin.Context) {
 	resp := response.GeneralSuccess()
 	ginCtx.JSON(resp.StatusCode, resp)
 }
+
+// wantsPage tells whether a list request asks for one page of the list
+// rather than all of it.
+func wantsPage(ginCtx *gin.Context) bool {
+	for _, key := range []string{"page", "limit", "sort", "cursor"} {
+		if _, ok := ginCtx.GetQuery(key); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (controller *BookControllerImpl) RateBook(ginCtx *gin.Context) {
+	var request = new(params.BookRatingRequest)
+	err := ginCtx.ShouldBindJSON(request)
+	if err != nil {
+		errParam := response.GeneralError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+	id, err := strconv.Atoi(ginCtx.Param("id"))
+	if err != nil {
+		errParam := response.NotFoundError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+
+	result, custErr := controller.BookService.RateBook(ginCtx, ginCtx.GetInt("authId"), id, request)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+	resp := response.GeneralSuccessCustomMessageAndPayload("Success rate data books", result)
+	ginCtx.JSON(resp.StatusCode, resp)
+}
+
+// ImportBooks queues an import of the books with the ISBNs; the job it
+// answers with tells how the import went.
+func (controller *BookControllerImpl) ImportBooks(ginCtx *gin.Context) {
+	var request = new(params.BookImportRequest)
+	err := ginCtx.ShouldBindJSON(request)
+	if err != nil {
+		errParam := response.GeneralError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+	if custErr := services.ValidateBookImport(request); custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+
+	result, custErr := controller.JobService.Enqueue(ginCtx, &params.JobRequest{Type: services.JobImportBooks, Payload: request})
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+	resp := response.CreatedSuccessCustomMessageAndPayload("Success queue import books.", result)
+	ginCtx.JSON(resp.StatusCode, resp)
+}
@@ -0,0 +1,98 @@
+package controllers
+
+import (
+	"golang-backend-test/app/commons/response"
+	"golang-backend-test/app/params"
+	"golang-backend-test/app/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BookCopyController interface {
+	GetListBookCopies(ginCtx *gin.Context)
+	CreateBookCopy(ginCtx *gin.Context)
+	UpdateBookCopy(ginCtx *gin.Context)
+}
+
+type BookCopyControllerImpl struct {
+	BookCopyService services.BookCopyService
+}
+
+func NewBookCopyController(bookCopyService services.BookCopyService) BookCopyController {
+	return &BookCopyControllerImpl{
+		BookCopyService: bookCopyService,
+	}
+}
+
+func (controller *BookCopyControllerImpl) GetListBookCopies(ginCtx *gin.Context) {
+	id, err := strconv.Atoi(ginCtx.Param("id"))
+	if err != nil {
+		errParam := response.NotFoundError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+
+	result, custErr := controller.BookCopyService.FindBookCopies(ginCtx, id)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+
+	resp := response.GeneralSuccessCustomMessageAndPayload("Success get data book copies.", result)
+	ginCtx.JSON(resp.StatusCode, resp)
+}
+
+func (controller *BookCopyControllerImpl) CreateBookCopy(ginCtx *gin.Context) {
+	var request = new(params.BookCopyRequest)
+	err := ginCtx.ShouldBindJSON(request)
+	if err != nil {
+		errParam := response.GeneralError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+	id, err := strconv.Atoi(ginCtx.Param("id"))
+	if err != nil {
+		errParam := response.NotFoundError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+
+	result, custErr := controller.BookCopyService.CreateBookCopy(ginCtx, id, request)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+	resp := response.CreatedSuccessCustomMessageAndPayload("Success create data book copies", result)
+	ginCtx.JSON(resp.StatusCode, resp)
+}
+
+func (controller *BookCopyControllerImpl) UpdateBookCopy(ginCtx *gin.Context) {
+	var request = new(params.BookCopyRequest)
+	err := ginCtx.ShouldBindJSON(request)
+	if err != nil {
+		errParam := response.GeneralError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+	id, err := strconv.Atoi(ginCtx.Param("id"))
+	if err != nil {
+		errParam := response.NotFoundError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+	copyId, err := strconv.Atoi(ginCtx.Param("copy_id"))
+	if err != nil {
+		errParam := response.NotFoundError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+
+	result, custErr := controller.BookCopyService.UpdateBookCopy(ginCtx, id, copyId, request)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+	resp := response.GeneralSuccessCustomMessageAndPayload("Success update data book copies", result)
+	ginCtx.JSON(resp.StatusCode, resp)
+}
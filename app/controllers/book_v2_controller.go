@@ -0,0 +1,143 @@
+package controllers
+
+import (
+	"golang-backend-test/app/commons/response"
+	"golang-backend-test/app/params"
+	"golang-backend-test/app/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BookV2Controller serves books under /v2. It goes through the same
+// BookService as BookController, mapping the results to the v2 shapes.
+type BookV2Controller interface {
+	FindBookById(ginCtx *gin.Context)
+	GetListBooks(ginCtx *gin.Context)
+	CreateBook(ginCtx *gin.Context)
+	UpdateBook(ginCtx *gin.Context)
+	PatchBook(ginCtx *gin.Context)
+	DeleteBook(ginCtx *gin.Context)
+}
+
+type BookV2ControllerImpl struct {
+	BookService services.BookService
+}
+
+func NewBookV2Controller(bookService services.BookService) BookV2Controller {
+	return &BookV2ControllerImpl{
+		BookService: bookService,
+	}
+}
+
+// bookV2Fieldset loads the author of books, which v2 always shows.
+func bookV2Fieldset() *params.FieldsetRequest {
+	expand := "author"
+	return &params.FieldsetRequest{Expand: &expand}
+}
+
+func (controller *BookV2ControllerImpl) FindBookById(ginCtx *gin.Context) {
+	id, err := strconv.Atoi(ginCtx.Param("id"))
+	if err != nil {
+		abortV2(ginCtx, response.NotFoundError())
+		return
+	}
+	result, custErr := controller.BookService.FindDetailBook(ginCtx, id, bookV2Fieldset())
+	if custErr != nil {
+		abortV2(ginCtx, custErr)
+		return
+	}
+	ginCtx.JSON(http.StatusOK, bookV2Response(result))
+}
+
+// GetListBooks always answers with a page, of 10 books unless limit says
+// otherwise.
+func (controller *BookV2ControllerImpl) GetListBooks(ginCtx *gin.Context) {
+	var request = new(params.PaginationRequest)
+	if err := ginCtx.ShouldBindQuery(request); err != nil {
+		abortV2(ginCtx, response.BadRequestErrorWithAdditionalInfo(err.Error()))
+		return
+	}
+	result, custErr := controller.BookService.FindBooksPage(ginCtx, request, bookV2Fieldset())
+	if custErr != nil {
+		abortV2(ginCtx, custErr)
+		return
+	}
+	ginCtx.JSON(http.StatusOK, listV2Response(result))
+}
+
+func (controller *BookV2ControllerImpl) CreateBook(ginCtx *gin.Context) {
+	var request = new(params.BookRequest)
+	if err := ginCtx.ShouldBindJSON(request); err != nil {
+		abortV2(ginCtx, response.BadRequestErrorWithAdditionalInfo(err.Error()))
+		return
+	}
+	created, custErr := controller.BookService.CrateBook(ginCtx, request)
+	if custErr != nil {
+		abortV2(ginCtx, custErr)
+		return
+	}
+	// read back with its author, as the v2 shape has it
+	result, custErr := controller.BookService.FindDetailBook(ginCtx, int(created.ID), bookV2Fieldset())
+	if custErr != nil {
+		abortV2(ginCtx, custErr)
+		return
+	}
+	ginCtx.Header("Location", ginCtx.Request.URL.Path+strconv.Itoa(int(result.ID)))
+	ginCtx.JSON(http.StatusCreated, bookV2Response(result))
+}
+
+func (controller *BookV2ControllerImpl) UpdateBook(ginCtx *gin.Context) {
+	id, err := strconv.Atoi(ginCtx.Param("id"))
+	if err != nil {
+		abortV2(ginCtx, response.NotFoundError())
+		return
+	}
+	var request = new(params.BookRequest)
+	if err := ginCtx.ShouldBindJSON(request); err != nil {
+		abortV2(ginCtx, response.BadRequestErrorWithAdditionalInfo(err.Error()))
+		return
+	}
+	// UpdateBook cannot tell a missing book from a bad request
+	if _, custErr := controller.BookService.FindDetailBook(ginCtx, id, bookV2Fieldset()); custErr != nil {
+		abortV2(ginCtx, custErr)
+		return
+	}
+	if _, custErr := controller.BookService.UpdateBook(ginCtx, id, request); custErr != nil {
+		abortV2(ginCtx, custErr)
+		return
+	}
+	controller.FindBookById(ginCtx)
+}
+
+func (controller *BookV2ControllerImpl) PatchBook(ginCtx *gin.Context) {
+	id, err := strconv.Atoi(ginCtx.Param("id"))
+	if err != nil {
+		abortV2(ginCtx, response.NotFoundError())
+		return
+	}
+	patchDoc, err := ginCtx.GetRawData()
+	if err != nil {
+		abortV2(ginCtx, response.BadRequestErrorWithAdditionalInfo(err.Error()))
+		return
+	}
+	if _, custErr := controller.BookService.PatchBook(ginCtx, id, ginCtx.ContentType(), patchDoc); custErr != nil {
+		abortV2(ginCtx, custErr)
+		return
+	}
+	controller.FindBookById(ginCtx)
+}
+
+func (controller *BookV2ControllerImpl) DeleteBook(ginCtx *gin.Context) {
+	id, err := strconv.Atoi(ginCtx.Param("id"))
+	if err != nil {
+		abortV2(ginCtx, response.NotFoundError())
+		return
+	}
+	if custErr := controller.BookService.DeleteBook(ginCtx, id); custErr != nil {
+		abortV2(ginCtx, custErr)
+		return
+	}
+	ginCtx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,140 @@
+package controllers
+
+import (
+	"golang-backend-test/app/commons/response"
+	"golang-backend-test/app/params"
+	"golang-backend-test/app/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BranchController interface {
+	FindBranchById(ginCtx *gin.Context)
+	GetListBranches(ginCtx *gin.Context)
+	CreateBranch(ginCtx *gin.Context)
+	UpdateBranch(ginCtx *gin.Context)
+	DeleteBranch(ginCtx *gin.Context)
+	CreateShelfLocation(ginCtx *gin.Context)
+}
+
+type BranchControllerImpl struct {
+	BranchService services.BranchService
+}
+
+func NewBranchController(branchService services.BranchService) BranchController {
+	return &BranchControllerImpl{
+		BranchService: branchService,
+	}
+}
+
+func (controller *BranchControllerImpl) FindBranchById(ginCtx *gin.Context) {
+	id, err := strconv.Atoi(ginCtx.Param("id"))
+	if err != nil {
+		errParam := response.NotFoundError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+	result, custErr := controller.BranchService.FindDetailBranch(ginCtx, id)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+
+	resp := response.GeneralSuccessCustomMessageAndPayload("Success get data detail branches.", result)
+	ginCtx.JSON(resp.StatusCode, resp)
+}
+
+func (controller *BranchControllerImpl) GetListBranches(ginCtx *gin.Context) {
+	result, custErr := controller.BranchService.FindAllBranches(ginCtx)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+
+	resp := response.GeneralSuccessCustomMessageAndPayload("Success get data branches.", result)
+	ginCtx.JSON(resp.StatusCode, resp)
+}
+
+func (controller *BranchControllerImpl) CreateBranch(ginCtx *gin.Context) {
+	var request = new(params.BranchRequest)
+	err := ginCtx.ShouldBindJSON(request)
+	if err != nil {
+		errParam := response.GeneralError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+
+	custErr := controller.BranchService.CreateBranch(ginCtx, request)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+	resp := response.CreatedSuccess()
+	ginCtx.JSON(resp.StatusCode, resp)
+}
+
+func (controller *BranchControllerImpl) UpdateBranch(ginCtx *gin.Context) {
+	var request = new(params.BranchRequest)
+	err := ginCtx.ShouldBindJSON(request)
+	if err != nil {
+		errParam := response.GeneralError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+	id, err := strconv.Atoi(ginCtx.Param("id"))
+	if err != nil {
+		errParam := response.NotFoundError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+
+	result, custErr := controller.BranchService.UpdateBranch(ginCtx, id, request)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+	resp := response.GeneralSuccessCustomMessageAndPayload("Success update data branches", result)
+	ginCtx.JSON(resp.StatusCode, resp)
+}
+
+func (controller *BranchControllerImpl) DeleteBranch(ginCtx *gin.Context) {
+	id, err := strconv.Atoi(ginCtx.Param("id"))
+	if err != nil {
+		errParam := response.NotFoundError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+
+	custErr := controller.BranchService.DeleteBranch(ginCtx, id)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+	resp := response.GeneralSuccess()
+	ginCtx.JSON(resp.StatusCode, resp)
+}
+
+func (controller *BranchControllerImpl) CreateShelfLocation(ginCtx *gin.Context) {
+	var request = new(params.ShelfLocationRequest)
+	err := ginCtx.ShouldBindJSON(request)
+	if err != nil {
+		errParam := response.GeneralError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+	id, err := strconv.Atoi(ginCtx.Param("id"))
+	if err != nil {
+		errParam := response.NotFoundError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+
+	result, custErr := controller.BranchService.CreateShelfLocation(ginCtx, id, request)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+	resp := response.CreatedSuccessCustomMessageAndPayload("Success create data shelf locations", result)
+	ginCtx.JSON(resp.StatusCode, resp)
+}
@@ -0,0 +1,91 @@
+package controllers
+
+import (
+	"fmt"
+	"golang-backend-test/app/commons/response"
+	"golang-backend-test/app/params"
+	"golang-backend-test/app/services"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type EventController interface {
+	Stream(ginCtx *gin.Context)
+}
+
+type EventControllerImpl struct {
+	StreamService services.StreamService
+}
+
+func NewEventController(streamService services.StreamService) EventController {
+	return &EventControllerImpl{
+		StreamService: streamService,
+	}
+}
+
+const (
+	// streamKeepAlive is how often an idle stream sends a comment, so that
+	// proxies do not close it
+	streamKeepAlive = 15 * time.Second
+	// streamRetry is how long browsers wait before reconnecting
+	streamRetry = 3 * time.Second
+)
+
+// Stream sends the changes to books and authors as Server-Sent Events: the
+// id of each is its position in the stream, the event its name, such as
+// book.created, and the data the JSON of the resource. A reset event tells
+// a client that reconnected that it missed changes it can no longer get.
+func (controller *EventControllerImpl) Stream(ginCtx *gin.Context) {
+	var request = new(params.EventStreamRequest)
+	if err := ginCtx.ShouldBindQuery(request); err != nil {
+		errParam := response.BadRequestErrorWithAdditionalInfo(err.Error())
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+	if header := ginCtx.GetHeader("Last-Event-ID"); header != "" {
+		id, err := strconv.ParseUint(header, 10, 64)
+		if err != nil {
+			errParam := response.BadRequestErrorWithAdditionalInfo("Last-Event-ID must be an event id")
+			ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+			return
+		}
+		request.LastEventID = uint(id)
+	}
+
+	stream, custErr := controller.StreamService.Subscribe(ginCtx, request)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+	defer stream.Close()
+
+	ginCtx.Header("Content-Type", "text/event-stream")
+	ginCtx.Header("Cache-Control", "no-cache")
+	ginCtx.Header("X-Accel-Buffering", "no")
+	ginCtx.Status(http.StatusOK)
+	fmt.Fprintf(ginCtx.Writer, "retry: %d\n\n", streamRetry.Milliseconds())
+	if stream.Reset {
+		fmt.Fprint(ginCtx.Writer, "event: reset\ndata: {}\n\n")
+	}
+	ginCtx.Writer.Flush()
+
+	keepAlive := time.NewTicker(streamKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-ginCtx.Request.Context().Done():
+			return
+		case <-keepAlive.C:
+			fmt.Fprint(ginCtx.Writer, ": keep-alive\n\n")
+		case event, ok := <-stream.Events:
+			if !ok {
+				return
+			}
+			fmt.Fprintf(ginCtx.Writer, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Event, event.Data)
+		}
+		ginCtx.Writer.Flush()
+	}
+}
@@ -0,0 +1,51 @@
+package controllers
+
+import (
+	"golang-backend-test/app/commons/response"
+	"golang-backend-test/app/services"
+	"golang-backend-test/pkg/graphql"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// graphqlLimits keep a single query from loading the whole catalog: lists
+// count as many times as their limit asks for.
+var graphqlLimits = graphql.Limits{
+	MaxDepth:      8,
+	MaxComplexity: 1000,
+}
+
+type GraphQLController interface {
+	Query(ginCtx *gin.Context)
+}
+
+type GraphQLControllerImpl struct {
+	Schema *graphql.Schema
+}
+
+func NewGraphQLController(bookService services.BookService, authorService services.AuthorService, userService services.UserService) GraphQLController {
+	return &GraphQLControllerImpl{
+		Schema: newGraphQLSchema(bookService, authorService, userService),
+	}
+}
+
+// Query runs a GraphQL request. Requests that fail before they run, such as
+// ones that do not parse, get a 400; errors of single fields come back next
+// to the data with a 200.
+func (controller *GraphQLControllerImpl) Query(ginCtx *gin.Context) {
+	var request = new(graphql.Request)
+	err := ginCtx.ShouldBindJSON(request)
+	if err != nil {
+		errParam := response.BadRequestErrorWithAdditionalInfo(err.Error())
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+
+	result := graphql.Execute(ginCtx, controller.Schema, graphqlLimits, request, &graphqlRoot{authID: ginCtx.GetInt("authId")})
+	if result.Data == nil {
+		ginCtx.JSON(http.StatusBadRequest, result)
+		return
+	}
+	ginCtx.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,488 @@
+package controllers
+
+import (
+	"context"
+	"golang-backend-test/app/commons/response"
+	"golang-backend-test/app/params"
+	"golang-backend-test/app/services"
+	"golang-backend-test/pkg/graphql"
+	"strings"
+	"unicode"
+)
+
+// graphqlRoot is the source of the query and mutation fields.
+type graphqlRoot struct {
+	authID int
+}
+
+// graphqlError carries a service error into a GraphQL response, keeping its
+// code and details as extensions.
+type graphqlError struct {
+	*response.CustomError
+}
+
+func (err graphqlError) Error() string {
+	return err.Message
+}
+
+func (err graphqlError) Extensions() map[string]interface{} {
+	extensions := map[string]interface{}{
+		"code":        err.Code,
+		"status_code": err.StatusCode,
+	}
+	if err.AdditionalInfo != nil {
+		extensions["additional_info"] = err.AdditionalInfo
+	}
+	return extensions
+}
+
+func fromCustomError(custErr *response.CustomError) error {
+	if custErr == nil {
+		return nil
+	}
+	return graphqlError{custErr}
+}
+
+// newGraphQLSchema builds the schema:
+//
+//	type Query {
+//	  me: User
+//	  book(id: ID!): Book
+//	  books(limit: Int, cursor: String, sort: String, branchId: ID): BookPage
+//	  author(id: ID!): Author
+//	  authors(q: String, limit: Int, cursor: String, sort: String): AuthorPage
+//	}
+//	type Mutation {
+//	  createBook(input: BookInput!): Book
+//	  updateBook(id: ID!, input: BookInput!): Book
+//	  deleteBook(id: ID!): Boolean
+//	  createAuthor(input: AuthorInput!): Author
+//	  updateAuthor(id: ID!, input: AuthorInput!): Author
+//	  deleteAuthor(id: ID!): Boolean
+//	}
+//	type Book { id title isbn publicationDate publisher pageCount coverUrl author: Author }
+//	type Author {
+//	  id name birthdate deathDate biography nationality pseudonyms aliases
+//	  books(page: Int, limit: Int, cursor: String, sort: String): BookPage
+//	}
+//	type BookPage { items: [Book] page limit total nextCursor prevCursor }
+//	type AuthorPage { items: [Author] limit total nextCursor prevCursor }
+//	type User { id username }
+//
+// Inputs take the fields of the REST request bodies in camel case, and go
+// through the same services, so they are validated the same way.
+func newGraphQLSchema(bookService services.BookService, authorService services.AuthorService, userService services.UserService) *graphql.Schema {
+	book := graphql.NewObject("Book")
+	author := graphql.NewObject("Author")
+	bookPage := graphql.NewObject("BookPage")
+	authorPage := graphql.NewObject("AuthorPage")
+	user := graphql.NewObject("User")
+
+	book.Fields = map[string]*graphql.Field{
+		"id":              bookField(func(b *params.BookResponse) interface{} { return b.ID }),
+		"title":           bookField(func(b *params.BookResponse) interface{} { return b.Title }),
+		"isbn":            bookField(func(b *params.BookResponse) interface{} { return b.ISBN }),
+		"publicationDate": bookField(func(b *params.BookResponse) interface{} { return optionalString(b.PublicationDate) }),
+		"publisher":       bookField(func(b *params.BookResponse) interface{} { return optionalString(b.Publisher) }),
+		"pageCount":       bookField(func(b *params.BookResponse) interface{} { return b.PageCount }),
+		"coverUrl":        bookField(func(b *params.BookResponse) interface{} { return optionalString(b.CoverURL) }),
+		"author": {
+			Type: author,
+			// the authors of every book in the response are loaded
+			// together, however the books were reached
+			Resolve: func(ctx context.Context, sources []interface{}, args map[string]interface{}) ([]interface{}, error) {
+				var ids []int
+				seen := map[uint]bool{}
+				for _, source := range sources {
+					id := source.(*params.BookResponse).AuthorID
+					if !seen[id] {
+						seen[id] = true
+						ids = append(ids, int(id))
+					}
+				}
+				authors, custErr := authorService.FindAuthorsByIds(ctx, ids)
+				if custErr != nil {
+					return nil, fromCustomError(custErr)
+				}
+				byID := map[uint]*params.AuthorResponse{}
+				for _, author := range authors {
+					byID[author.ID] = author
+				}
+				values := make([]interface{}, len(sources))
+				for i, source := range sources {
+					values[i] = byID[source.(*params.BookResponse).AuthorID]
+				}
+				return values, nil
+			},
+		},
+	}
+
+	author.Fields = map[string]*graphql.Field{
+		"id":          authorField(func(a *params.AuthorResponse) interface{} { return a.ID }),
+		"name":        authorField(func(a *params.AuthorResponse) interface{} { return a.Name }),
+		"birthdate":   authorField(func(a *params.AuthorResponse) interface{} { return optionalString(a.Birthdate) }),
+		"deathDate":   authorField(func(a *params.AuthorResponse) interface{} { return optionalString(a.DeathDate) }),
+		"biography":   authorField(func(a *params.AuthorResponse) interface{} { return optionalString(a.Biography) }),
+		"nationality": authorField(func(a *params.AuthorResponse) interface{} { return optionalString(a.Nationality) }),
+		"pseudonyms":  authorField(func(a *params.AuthorResponse) interface{} { return nonNilList(a.Pseudonyms) }),
+		"aliases":     authorField(func(a *params.AuthorResponse) interface{} { return nonNilList(a.Aliases) }),
+		"books": {
+			Type:       bookPage,
+			Args:       []string{"page", "limit", "cursor", "sort"},
+			Multiplier: limitMultiplier,
+			Resolve: graphql.Each(func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+				request, err := paginationArgs(args)
+				if err != nil {
+					return nil, err
+				}
+				page, _, err := graphql.IntArg(args, "page")
+				if err != nil {
+					return nil, err
+				}
+				request.Page = page
+				result, custErr := authorService.FindAuthorBooks(ctx, int(source.(*params.AuthorResponse).ID), request)
+				if custErr != nil {
+					return nil, fromCustomError(custErr)
+				}
+				return result, nil
+			}),
+		},
+	}
+
+	bookPage.Fields = pageFields(book)
+	authorPage.Fields = pageFields(author)
+	delete(authorPage.Fields, "page")
+
+	user.Fields = map[string]*graphql.Field{
+		"id": {Resolve: graphql.Each(func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+			return source.(*params.UserDetailResponse).ID, nil
+		})},
+		"username": {Resolve: graphql.Each(func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+			return source.(*params.UserDetailResponse).Username, nil
+		})},
+	}
+
+	query := graphql.NewObject("Query")
+	query.Fields = map[string]*graphql.Field{
+		"me": {
+			Type: user,
+			Resolve: graphql.Each(func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+				result, custErr := userService.FindDetailUser(ctx, source.(*graphqlRoot).authID)
+				return result, fromCustomError(custErr)
+			}),
+		},
+		"book": {
+			Type: book,
+			Args: []string{"id"},
+			Resolve: graphql.Each(func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+				id, err := graphql.IDArg(args, "id")
+				if err != nil {
+					return nil, err
+				}
+				result, custErr := bookService.FindDetailBook(ctx, id, withoutRelations())
+				if custErr != nil {
+					return nil, fromCustomError(custErr)
+				}
+				return result, nil
+			}),
+		},
+		"books": {
+			Type:       bookPage,
+			Args:       []string{"limit", "cursor", "sort", "branchId"},
+			Multiplier: limitMultiplier,
+			Resolve: graphql.Each(func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+				if _, ok := args["branchId"]; ok {
+					if len(args) > 1 {
+						return nil, &graphql.Error{Message: "branchId cannot be combined with limit, cursor or sort"}
+					}
+					branchId, err := graphql.IDArg(args, "branchId")
+					if err != nil {
+						return nil, err
+					}
+					books, custErr := bookService.FindBranchBooks(ctx, branchId, nil)
+					if custErr != nil {
+						return nil, fromCustomError(custErr)
+					}
+					if books == nil {
+						books = []*params.BookResponse{}
+					}
+					return &params.PaginationResponse{Items: books, Limit: len(books), Total: int64(len(books))}, nil
+				}
+
+				request, err := paginationArgs(args)
+				if err != nil {
+					return nil, err
+				}
+				result, custErr := bookService.FindBooksPage(ctx, request, withoutRelations())
+				if custErr != nil {
+					return nil, fromCustomError(custErr)
+				}
+				return result, nil
+			}),
+		},
+		"author": {
+			Type: author,
+			Args: []string{"id"},
+			Resolve: graphql.Each(func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+				id, err := graphql.IDArg(args, "id")
+				if err != nil {
+					return nil, err
+				}
+				result, custErr := authorService.FindDetailAuthor(ctx, id)
+				if custErr != nil {
+					return nil, fromCustomError(custErr)
+				}
+				return result, nil
+			}),
+		},
+		"authors": {
+			Type:       authorPage,
+			Args:       []string{"q", "limit", "cursor", "sort"},
+			Multiplier: limitMultiplier,
+			Resolve: graphql.Each(func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+				q, _, err := graphql.StringArg(args, "q")
+				if err != nil {
+					return nil, err
+				}
+				if q != "" {
+					if len(args) > 1 {
+						return nil, &graphql.Error{Message: "q cannot be combined with limit, cursor or sort"}
+					}
+					authors, custErr := authorService.SearchAuthors(ctx, q)
+					if custErr != nil {
+						return nil, fromCustomError(custErr)
+					}
+					if authors == nil {
+						authors = []*params.AuthorResponse{}
+					}
+					return &params.PaginationResponse{Items: authors, Limit: len(authors), Total: int64(len(authors))}, nil
+				}
+
+				request, err := paginationArgs(args)
+				if err != nil {
+					return nil, err
+				}
+				result, custErr := authorService.FindAuthorsPage(ctx, request)
+				if custErr != nil {
+					return nil, fromCustomError(custErr)
+				}
+				return result, nil
+			}),
+		},
+	}
+
+	mutation := graphql.NewObject("Mutation")
+	mutation.Fields = map[string]*graphql.Field{
+		"createBook": {
+			Type: book,
+			Args: []string{"input"},
+			Resolve: graphql.Each(func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+				var request = new(params.BookRequest)
+				if err := decodeInput(args, request); err != nil {
+					return nil, err
+				}
+				result, custErr := bookService.CrateBook(ctx, request)
+				if custErr != nil {
+					return nil, fromCustomError(custErr)
+				}
+				return result, nil
+			}),
+		},
+		"updateBook": {
+			Type: book,
+			Args: []string{"id", "input"},
+			Resolve: graphql.Each(func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+				id, err := graphql.IDArg(args, "id")
+				if err != nil {
+					return nil, err
+				}
+				var request = new(params.BookRequest)
+				if err := decodeInput(args, request); err != nil {
+					return nil, err
+				}
+				result, custErr := bookService.UpdateBook(ctx, id, request)
+				if custErr != nil {
+					return nil, fromCustomError(custErr)
+				}
+				return result, nil
+			}),
+		},
+		"deleteBook": {
+			Args: []string{"id"},
+			Resolve: graphql.Each(func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+				id, err := graphql.IDArg(args, "id")
+				if err != nil {
+					return nil, err
+				}
+				if custErr := bookService.DeleteBook(ctx, id); custErr != nil {
+					return nil, fromCustomError(custErr)
+				}
+				return true, nil
+			}),
+		},
+		"createAuthor": {
+			Type: author,
+			Args: []string{"input"},
+			Resolve: graphql.Each(func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+				var request = new(params.AuthorRequest)
+				if err := decodeInput(args, request); err != nil {
+					return nil, err
+				}
+				result, custErr := authorService.CrateAuthor(ctx, request)
+				if custErr != nil {
+					return nil, fromCustomError(custErr)
+				}
+				return result, nil
+			}),
+		},
+		"updateAuthor": {
+			Type: author,
+			Args: []string{"id", "input"},
+			Resolve: graphql.Each(func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+				id, err := graphql.IDArg(args, "id")
+				if err != nil {
+					return nil, err
+				}
+				var request = new(params.AuthorRequest)
+				if err := decodeInput(args, request); err != nil {
+					return nil, err
+				}
+				result, custErr := authorService.UpdateAuthor(ctx, id, request)
+				if custErr != nil {
+					return nil, fromCustomError(custErr)
+				}
+				return result, nil
+			}),
+		},
+		"deleteAuthor": {
+			Args: []string{"id"},
+			Resolve: graphql.Each(func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+				id, err := graphql.IDArg(args, "id")
+				if err != nil {
+					return nil, err
+				}
+				if custErr := authorService.DeleteAuthor(ctx, id); custErr != nil {
+					return nil, fromCustomError(custErr)
+				}
+				return true, nil
+			}),
+		},
+	}
+
+	return &graphql.Schema{Query: query, Mutation: mutation}
+}
+
+func bookField(value func(*params.BookResponse) interface{}) *graphql.Field {
+	return &graphql.Field{Resolve: graphql.Each(func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+		return value(source.(*params.BookResponse)), nil
+	})}
+}
+
+func authorField(value func(*params.AuthorResponse) interface{}) *graphql.Field {
+	return &graphql.Field{Resolve: graphql.Each(func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+		return value(source.(*params.AuthorResponse)), nil
+	})}
+}
+
+// pageFields are the fields of a page of items of object.
+func pageFields(object *graphql.Object) map[string]*graphql.Field {
+	page := func(value func(*params.PaginationResponse) interface{}) *graphql.Field {
+		return &graphql.Field{Resolve: graphql.Each(func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+			return value(source.(*params.PaginationResponse)), nil
+		})}
+	}
+	return map[string]*graphql.Field{
+		"items": {
+			Type: object,
+			List: true,
+			// the field holding the page already counts its limit
+			Multiplier: func(args map[string]interface{}) int { return 1 },
+			Resolve: graphql.Each(func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+				return source.(*params.PaginationResponse).Items, nil
+			}),
+		},
+		"page":       page(func(p *params.PaginationResponse) interface{} { return p.Page }),
+		"limit":      page(func(p *params.PaginationResponse) interface{} { return p.Limit }),
+		"total":      page(func(p *params.PaginationResponse) interface{} { return p.Total }),
+		"nextCursor": page(func(p *params.PaginationResponse) interface{} { return optionalString(p.NextCursor) }),
+		"prevCursor": page(func(p *params.PaginationResponse) interface{} { return optionalString(p.PrevCursor) }),
+	}
+}
+
+// limitMultiplier counts a page as many items as it asks for, or the
+// default page size of the services.
+func limitMultiplier(args map[string]interface{}) int {
+	if limit, ok, err := graphql.IntArg(args, "limit"); err == nil && ok && limit > 0 {
+		return limit
+	}
+	return 10
+}
+
+func paginationArgs(args map[string]interface{}) (*params.PaginationRequest, error) {
+	var request = new(params.PaginationRequest)
+	var err error
+	if request.Limit, _, err = graphql.IntArg(args, "limit"); err != nil {
+		return nil, err
+	}
+	if request.Cursor, _, err = graphql.StringArg(args, "cursor"); err != nil {
+		return nil, err
+	}
+	if request.Sort, _, err = graphql.StringArg(args, "sort"); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// withoutRelations loads books alone; their authors are loaded by the
+// author field of Book when they are asked for.
+func withoutRelations() *params.FieldsetRequest {
+	expand := ""
+	return &params.FieldsetRequest{Expand: &expand}
+}
+
+// decodeInput binds the input argument like the JSON body of the matching
+// REST request.
+func decodeInput(args map[string]interface{}, target interface{}) error {
+	input, ok := args["input"].(map[string]interface{})
+	if !ok {
+		return &graphql.Error{Message: "argument input must be an input object"}
+	}
+	args = map[string]interface{}{"input": snakeKeys(input)}
+	return graphql.DecodeArg(args, "input", target)
+}
+
+func snakeKeys(value interface{}) interface{} {
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return value
+	}
+	result := make(map[string]interface{}, len(object))
+	for key, item := range object {
+		var name strings.Builder
+		for i, r := range key {
+			if unicode.IsUpper(r) {
+				if i > 0 {
+					name.WriteByte('_')
+				}
+				r = unicode.ToLower(r)
+			}
+			name.WriteRune(r)
+		}
+		result[name.String()] = snakeKeys(item)
+	}
+	return result
+}
+
+func optionalString(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+	return value
+}
+
+func nonNilList(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"golang-backend-test/app/commons/response"
+	"golang-backend-test/app/params"
+	"golang-backend-test/app/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type JobController interface {
+	GetListJobs(ginCtx *gin.Context)
+	FindJobById(ginCtx *gin.Context)
+	RetryJob(ginCtx *gin.Context)
+	CancelJob(ginCtx *gin.Context)
+}
+
+type JobControllerImpl struct {
+	JobService services.JobService
+}
+
+func NewJobController(jobService services.JobService) JobController {
+	return &JobControllerImpl{
+		JobService: jobService,
+	}
+}
+
+func (controller *JobControllerImpl) GetListJobs(ginCtx *gin.Context) {
+	var request = new(params.JobListRequest)
+	if err := ginCtx.ShouldBindQuery(request); err != nil {
+		errParam := response.BadRequestErrorWithAdditionalInfo(err.Error())
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+
+	result, custErr := controller.JobService.FindAllJobs(ginCtx, request)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+	resp := response.GeneralSuccessCustomMessageAndPayload("Success get data jobs.", result)
+	ginCtx.JSON(resp.StatusCode, resp)
+}
+
+func (controller *JobControllerImpl) FindJobById(ginCtx *gin.Context) {
+	id, err := strconv.Atoi(ginCtx.Param("id"))
+	if err != nil {
+		errParam := response.NotFoundError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+
+	result, custErr := controller.JobService.FindDetailJob(ginCtx, id)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+	resp := response.GeneralSuccessCustomMessageAndPayload("Success get data detail jobs.", result)
+	ginCtx.JSON(resp.StatusCode, resp)
+}
+
+func (controller *JobControllerImpl) RetryJob(ginCtx *gin.Context) {
+	id, err := strconv.Atoi(ginCtx.Param("id"))
+	if err != nil {
+		errParam := response.NotFoundError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+
+	result, custErr := controller.JobService.RetryJob(ginCtx, id)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+	resp := response.GeneralSuccessCustomMessageAndPayload("Success retry job", result)
+	ginCtx.JSON(resp.StatusCode, resp)
+}
+
+func (controller *JobControllerImpl) CancelJob(ginCtx *gin.Context) {
+	id, err := strconv.Atoi(ginCtx.Param("id"))
+	if err != nil {
+		errParam := response.NotFoundError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+
+	result, custErr := controller.JobService.CancelJob(ginCtx, id)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+	resp := response.GeneralSuccessCustomMessageAndPayload("Success cancel job", result)
+	ginCtx.JSON(resp.StatusCode, resp)
+}
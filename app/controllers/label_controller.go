@@ -0,0 +1,105 @@
+package controllers
+
+import (
+	"golang-backend-test/app/commons/response"
+	"golang-backend-test/app/params"
+	"golang-backend-test/app/services"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LabelController interface {
+	BookBarcode(ginCtx *gin.Context)
+	BookQRCode(ginCtx *gin.Context)
+	SpineLabels(ginCtx *gin.Context)
+}
+
+type LabelControllerImpl struct {
+	LabelService  services.LabelService
+	PublicBaseURL string
+}
+
+// NewLabelController builds the label controller. QR codes link to book
+// pages under publicBaseURL, such as "https://library.example.com".
+func NewLabelController(labelService services.LabelService, publicBaseURL string) LabelController {
+	return &LabelControllerImpl{
+		LabelService:  labelService,
+		PublicBaseURL: strings.TrimSuffix(publicBaseURL, "/"),
+	}
+}
+
+func (controller *LabelControllerImpl) BookBarcode(ginCtx *gin.Context) {
+	id, err := strconv.Atoi(ginCtx.Param("id"))
+	if err != nil {
+		errParam := response.NotFoundError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+	var request = new(params.BarcodeRequest)
+	err = ginCtx.ShouldBindQuery(request)
+	if err != nil {
+		errParam := response.BadRequestErrorWithAdditionalInfo(err.Error())
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+
+	result, custErr := controller.LabelService.BookBarcode(ginCtx, id, request)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+	writeFile(ginCtx, "inline", result)
+}
+
+func (controller *LabelControllerImpl) BookQRCode(ginCtx *gin.Context) {
+	id, err := strconv.Atoi(ginCtx.Param("id"))
+	if err != nil {
+		errParam := response.NotFoundError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+	var request = new(params.BarcodeRequest)
+	err = ginCtx.ShouldBindQuery(request)
+	if err != nil {
+		errParam := response.BadRequestErrorWithAdditionalInfo(err.Error())
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+
+	// the host comes from the configuration, never from the request, so a
+	// forged Host header cannot end up printed on a label
+	detailURL := controller.PublicBaseURL + path.Dir(ginCtx.Request.URL.Path)
+
+	result, custErr := controller.LabelService.BookQRCode(ginCtx, id, detailURL, request)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+	writeFile(ginCtx, "inline", result)
+}
+
+func (controller *LabelControllerImpl) SpineLabels(ginCtx *gin.Context) {
+	var request = new(params.SpineLabelRequest)
+	err := ginCtx.ShouldBindJSON(request)
+	if err != nil {
+		errParam := response.GeneralError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+
+	result, custErr := controller.LabelService.SpineLabels(ginCtx, request)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+	writeFile(ginCtx, "attachment", result)
+}
+
+func writeFile(ginCtx *gin.Context, disposition string, file *params.FileResponse) {
+	ginCtx.Header("Content-Disposition", disposition+`; filename="`+file.Filename+`"`)
+	ginCtx.Data(http.StatusOK, file.ContentType, file.Body)
+}
@@ -0,0 +1,78 @@
+package controllers
+
+import (
+	"golang-backend-test/app/commons/response"
+	"golang-backend-test/app/params"
+	"golang-backend-test/app/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LoanController interface {
+	FindLoanById(ginCtx *gin.Context)
+	CreateLoan(ginCtx *gin.Context)
+	ReturnLoan(ginCtx *gin.Context)
+}
+
+type LoanControllerImpl struct {
+	LoanService services.LoanService
+}
+
+func NewLoanController(loanService services.LoanService) LoanController {
+	return &LoanControllerImpl{
+		LoanService: loanService,
+	}
+}
+
+func (controller *LoanControllerImpl) FindLoanById(ginCtx *gin.Context) {
+	id, err := strconv.Atoi(ginCtx.Param("id"))
+	if err != nil {
+		errParam := response.NotFoundError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+	result, custErr := controller.LoanService.FindDetailLoan(ginCtx, id)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+
+	resp := response.GeneralSuccessCustomMessageAndPayload("Success get data detail loans.", result)
+	ginCtx.JSON(resp.StatusCode, resp)
+}
+
+func (controller *LoanControllerImpl) CreateLoan(ginCtx *gin.Context) {
+	var request = new(params.LoanRequest)
+	err := ginCtx.ShouldBindJSON(request)
+	if err != nil {
+		errParam := response.GeneralError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+
+	result, custErr := controller.LoanService.CreateLoan(ginCtx, ginCtx.GetInt("authId"), request)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+	resp := response.CreatedSuccessCustomMessageAndPayload("Success create data loans", result)
+	ginCtx.JSON(resp.StatusCode, resp)
+}
+
+func (controller *LoanControllerImpl) ReturnLoan(ginCtx *gin.Context) {
+	id, err := strconv.Atoi(ginCtx.Param("id"))
+	if err != nil {
+		errParam := response.NotFoundError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+
+	result, custErr := controller.LoanService.ReturnLoan(ginCtx, id)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+	resp := response.GeneralSuccessCustomMessageAndPayload("Success return data loans", result)
+	ginCtx.JSON(resp.StatusCode, resp)
+}
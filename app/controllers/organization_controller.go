@@ -0,0 +1,64 @@
+package controllers
+
+import (
+	"golang-backend-test/app/commons/response"
+	"golang-backend-test/app/params"
+	"golang-backend-test/app/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OrganizationController interface {
+	CreateOrganization(ginCtx *gin.Context)
+	GetCurrentOrganization(ginCtx *gin.Context)
+	CreateInvite(ginCtx *gin.Context)
+}
+
+type OrganizationControllerImpl struct {
+	OrganizationService services.OrganizationService
+}
+
+func NewOrganizationController(organizationService services.OrganizationService) OrganizationController {
+	return &OrganizationControllerImpl{
+		OrganizationService: organizationService,
+	}
+}
+
+func (controller *OrganizationControllerImpl) CreateOrganization(ginCtx *gin.Context) {
+	var request = new(params.OrganizationRequest)
+	err := ginCtx.ShouldBindJSON(request)
+	if err != nil {
+		errParam := response.GeneralError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+
+	result, custErr := controller.OrganizationService.CreateOrganization(ginCtx, request)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+	resp := response.CreatedSuccessCustomMessageAndPayload("Success create data organizations", result)
+	ginCtx.JSON(resp.StatusCode, resp)
+}
+
+func (controller *OrganizationControllerImpl) GetCurrentOrganization(ginCtx *gin.Context) {
+	result, custErr := controller.OrganizationService.FindCurrentOrganization(ginCtx)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+
+	resp := response.GeneralSuccessCustomMessageAndPayload("Success get data organizations.", result)
+	ginCtx.JSON(resp.StatusCode, resp)
+}
+
+func (controller *OrganizationControllerImpl) CreateInvite(ginCtx *gin.Context) {
+	result, custErr := controller.OrganizationService.CreateInvite(ginCtx)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+	resp := response.CreatedSuccessCustomMessageAndPayload("Success create data invites", result)
+	ginCtx.JSON(resp.StatusCode, resp)
+}
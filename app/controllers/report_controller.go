@@ -0,0 +1,142 @@
+package controllers
+
+import (
+	"golang-backend-test/app/commons/response"
+	"golang-backend-test/app/params"
+	"golang-backend-test/app/services"
+	"golang-backend-test/pkg/csvutil"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReportController interface {
+	BooksPerAuthor(ginCtx *gin.Context)
+	BooksWithoutISBN(ginCtx *gin.Context)
+	AuthorsWithoutBooks(ginCtx *gin.Context)
+	MostBorrowedBooks(ginCtx *gin.Context)
+	LeastBorrowedBooks(ginCtx *gin.Context)
+	AcquisitionsPerMonth(ginCtx *gin.Context)
+}
+
+type ReportControllerImpl struct {
+	ReportService services.ReportService
+}
+
+func NewReportController(reportService services.ReportService) ReportController {
+	return &ReportControllerImpl{
+		ReportService: reportService,
+	}
+}
+
+func (controller *ReportControllerImpl) BooksPerAuthor(ginCtx *gin.Context) {
+	request, ok := bindReportRequest(ginCtx)
+	if !ok {
+		return
+	}
+	result, custErr := controller.ReportService.BooksPerAuthor(ginCtx, request)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+	renderReport(ginCtx, request, "books-per-author", "Success get report books per author.", result)
+}
+
+func (controller *ReportControllerImpl) BooksWithoutISBN(ginCtx *gin.Context) {
+	request, ok := bindReportRequest(ginCtx)
+	if !ok {
+		return
+	}
+	result, custErr := controller.ReportService.BooksWithoutISBN(ginCtx, request)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+	renderReport(ginCtx, request, "books-without-isbn", "Success get report books without isbn.", result)
+}
+
+func (controller *ReportControllerImpl) AuthorsWithoutBooks(ginCtx *gin.Context) {
+	request, ok := bindReportRequest(ginCtx)
+	if !ok {
+		return
+	}
+	result, custErr := controller.ReportService.AuthorsWithoutBooks(ginCtx, request)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+	renderReport(ginCtx, request, "authors-without-books", "Success get report authors without books.", result)
+}
+
+func (controller *ReportControllerImpl) MostBorrowedBooks(ginCtx *gin.Context) {
+	request, ok := bindReportRequest(ginCtx)
+	if !ok {
+		return
+	}
+	result, custErr := controller.ReportService.MostBorrowedBooks(ginCtx, request)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+	renderReport(ginCtx, request, "most-borrowed", "Success get report most borrowed books.", result)
+}
+
+func (controller *ReportControllerImpl) LeastBorrowedBooks(ginCtx *gin.Context) {
+	request, ok := bindReportRequest(ginCtx)
+	if !ok {
+		return
+	}
+	result, custErr := controller.ReportService.LeastBorrowedBooks(ginCtx, request)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+	renderReport(ginCtx, request, "least-borrowed", "Success get report least borrowed books.", result)
+}
+
+func (controller *ReportControllerImpl) AcquisitionsPerMonth(ginCtx *gin.Context) {
+	request, ok := bindReportRequest(ginCtx)
+	if !ok {
+		return
+	}
+	result, custErr := controller.ReportService.AcquisitionsPerMonth(ginCtx, request)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+	renderReport(ginCtx, request, "acquisitions", "Success get report acquisitions per month.", result)
+}
+
+func bindReportRequest(ginCtx *gin.Context) (*params.ReportRequest, bool) {
+	var request = new(params.ReportRequest)
+	err := ginCtx.ShouldBindQuery(request)
+	if err != nil {
+		errParam := response.BadRequestErrorWithAdditionalInfo(err.Error())
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return nil, false
+	}
+	if request.Format == "" && strings.Contains(ginCtx.GetHeader("Accept"), "text/csv") {
+		request.Format = "csv"
+	}
+	return request, true
+}
+
+// renderReport writes result as a CSV attachment or wrapped in the usual
+// JSON envelope, depending on the requested format.
+func renderReport(ginCtx *gin.Context, request *params.ReportRequest, name string, message string, result interface{}) {
+	if request.Format == "csv" {
+		body, err := csvutil.Marshal(result)
+		if err != nil {
+			errParam := response.GeneralErrorWithAdditionalInfo(err.Error())
+			ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+			return
+		}
+		ginCtx.Header("Content-Disposition", `attachment; filename="`+name+`.csv"`)
+		ginCtx.Data(http.StatusOK, "text/csv; charset=utf-8", body)
+		return
+	}
+
+	resp := response.GeneralSuccessCustomMessageAndPayload(message, result)
+	ginCtx.JSON(resp.StatusCode, resp)
+}
@@ -0,0 +1,148 @@
+package controllers
+
+import (
+	"golang-backend-test/app/commons/response"
+	"golang-backend-test/app/params"
+	"golang-backend-test/app/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StocktakeController interface {
+	FindStocktakeById(ginCtx *gin.Context)
+	OpenStocktake(ginCtx *gin.Context)
+	ScanStocktake(ginCtx *gin.Context)
+	CloseStocktake(ginCtx *gin.Context)
+	GetStocktakeReport(ginCtx *gin.Context)
+	MarkMissingLost(ginCtx *gin.Context)
+}
+
+type StocktakeControllerImpl struct {
+	StocktakeService services.StocktakeService
+}
+
+func NewStocktakeController(stocktakeService services.StocktakeService) StocktakeController {
+	return &StocktakeControllerImpl{
+		StocktakeService: stocktakeService,
+	}
+}
+
+func (controller *StocktakeControllerImpl) FindStocktakeById(ginCtx *gin.Context) {
+	id, err := strconv.Atoi(ginCtx.Param("id"))
+	if err != nil {
+		errParam := response.NotFoundError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+	result, custErr := controller.StocktakeService.FindDetailStocktake(ginCtx, id)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+
+	resp := response.GeneralSuccessCustomMessageAndPayload("Success get data detail stocktakes.", result)
+	ginCtx.JSON(resp.StatusCode, resp)
+}
+
+func (controller *StocktakeControllerImpl) OpenStocktake(ginCtx *gin.Context) {
+	var request = new(params.StocktakeRequest)
+	err := ginCtx.ShouldBindJSON(request)
+	if err != nil {
+		errParam := response.GeneralError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+
+	result, custErr := controller.StocktakeService.OpenStocktake(ginCtx, ginCtx.GetInt("authId"), request)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+	resp := response.CreatedSuccessCustomMessageAndPayload("Success create data stocktakes", result)
+	ginCtx.JSON(resp.StatusCode, resp)
+}
+
+func (controller *StocktakeControllerImpl) ScanStocktake(ginCtx *gin.Context) {
+	var request = new(params.StocktakeScanRequest)
+	err := ginCtx.ShouldBindJSON(request)
+	if err != nil {
+		errParam := response.GeneralError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+	id, err := strconv.Atoi(ginCtx.Param("id"))
+	if err != nil {
+		errParam := response.NotFoundError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+
+	result, custErr := controller.StocktakeService.ScanStocktake(ginCtx, ginCtx.GetInt("authId"), id, request)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+	resp := response.CreatedSuccessCustomMessageAndPayload("Success create data stocktake scans", result)
+	ginCtx.JSON(resp.StatusCode, resp)
+}
+
+func (controller *StocktakeControllerImpl) CloseStocktake(ginCtx *gin.Context) {
+	id, err := strconv.Atoi(ginCtx.Param("id"))
+	if err != nil {
+		errParam := response.NotFoundError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+
+	result, custErr := controller.StocktakeService.CloseStocktake(ginCtx, ginCtx.GetInt("authId"), id)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+	resp := response.GeneralSuccessCustomMessageAndPayload("Success close data stocktakes", result)
+	ginCtx.JSON(resp.StatusCode, resp)
+}
+
+func (controller *StocktakeControllerImpl) GetStocktakeReport(ginCtx *gin.Context) {
+	id, err := strconv.Atoi(ginCtx.Param("id"))
+	if err != nil {
+		errParam := response.NotFoundError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+	result, custErr := controller.StocktakeService.FindStocktakeReport(ginCtx, id)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+
+	resp := response.GeneralSuccessCustomMessageAndPayload("Success get data stocktake reports.", result)
+	ginCtx.JSON(resp.StatusCode, resp)
+}
+
+func (controller *StocktakeControllerImpl) MarkMissingLost(ginCtx *gin.Context) {
+	var request = new(params.StocktakeMarkLostRequest)
+	// the body is optional: without one every missing copy is marked
+	if ginCtx.Request.ContentLength != 0 {
+		if err := ginCtx.ShouldBindJSON(request); err != nil {
+			errParam := response.GeneralError()
+			ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+			return
+		}
+	}
+	id, err := strconv.Atoi(ginCtx.Param("id"))
+	if err != nil {
+		errParam := response.NotFoundError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+
+	result, custErr := controller.StocktakeService.MarkMissingLost(ginCtx, ginCtx.GetInt("authId"), id, request)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+	resp := response.GeneralSuccessCustomMessageAndPayload("Success mark data stocktake missing copies lost", result)
+	ginCtx.JSON(resp.StatusCode, resp)
+}
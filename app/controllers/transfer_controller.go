@@ -0,0 +1,139 @@
+package controllers
+
+import (
+	"golang-backend-test/app/commons/response"
+	"golang-backend-test/app/params"
+	"golang-backend-test/app/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TransferController interface {
+	FindTransferById(ginCtx *gin.Context)
+	GetBranchTransfers(ginCtx *gin.Context)
+	RequestTransfer(ginCtx *gin.Context)
+	ShipTransfer(ginCtx *gin.Context)
+	ReceiveTransfer(ginCtx *gin.Context)
+	CancelTransfer(ginCtx *gin.Context)
+}
+
+type TransferControllerImpl struct {
+	TransferService services.TransferService
+}
+
+func NewTransferController(transferService services.TransferService) TransferController {
+	return &TransferControllerImpl{
+		TransferService: transferService,
+	}
+}
+
+func (controller *TransferControllerImpl) FindTransferById(ginCtx *gin.Context) {
+	id, err := strconv.Atoi(ginCtx.Param("id"))
+	if err != nil {
+		errParam := response.NotFoundError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+	result, custErr := controller.TransferService.FindDetailTransfer(ginCtx, id)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+
+	resp := response.GeneralSuccessCustomMessageAndPayload("Success get data detail transfers.", result)
+	ginCtx.JSON(resp.StatusCode, resp)
+}
+
+func (controller *TransferControllerImpl) GetBranchTransfers(ginCtx *gin.Context) {
+	id, err := strconv.Atoi(ginCtx.Param("id"))
+	if err != nil {
+		errParam := response.NotFoundError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+	result, custErr := controller.TransferService.FindBranchTransfers(ginCtx, id)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+
+	resp := response.GeneralSuccessCustomMessageAndPayload("Success get data branch transfers.", result)
+	ginCtx.JSON(resp.StatusCode, resp)
+}
+
+func (controller *TransferControllerImpl) RequestTransfer(ginCtx *gin.Context) {
+	var request = new(params.TransferRequest)
+	err := ginCtx.ShouldBindJSON(request)
+	if err != nil {
+		errParam := response.GeneralError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+
+	result, custErr := controller.TransferService.RequestTransfer(ginCtx, ginCtx.GetInt("authId"), request)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+	resp := response.CreatedSuccessCustomMessageAndPayload("Success create data transfers", result)
+	ginCtx.JSON(resp.StatusCode, resp)
+}
+
+func (controller *TransferControllerImpl) ShipTransfer(ginCtx *gin.Context) {
+	id, err := strconv.Atoi(ginCtx.Param("id"))
+	if err != nil {
+		errParam := response.NotFoundError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+
+	result, custErr := controller.TransferService.ShipTransfer(ginCtx, ginCtx.GetInt("authId"), id)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+	resp := response.GeneralSuccessCustomMessageAndPayload("Success ship data transfers", result)
+	ginCtx.JSON(resp.StatusCode, resp)
+}
+
+func (controller *TransferControllerImpl) ReceiveTransfer(ginCtx *gin.Context) {
+	var request = new(params.TransferReceiveRequest)
+	err := ginCtx.ShouldBindJSON(request)
+	if err != nil {
+		errParam := response.GeneralError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+	id, err := strconv.Atoi(ginCtx.Param("id"))
+	if err != nil {
+		errParam := response.NotFoundError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+
+	result, custErr := controller.TransferService.ReceiveTransfer(ginCtx, ginCtx.GetInt("authId"), id, request)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+	resp := response.GeneralSuccessCustomMessageAndPayload("Success receive data transfers", result)
+	ginCtx.JSON(resp.StatusCode, resp)
+}
+
+func (controller *TransferControllerImpl) CancelTransfer(ginCtx *gin.Context) {
+	id, err := strconv.Atoi(ginCtx.Param("id"))
+	if err != nil {
+		errParam := response.NotFoundError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+
+	result, custErr := controller.TransferService.CancelTransfer(ginCtx, ginCtx.GetInt("authId"), id)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+	resp := response.GeneralSuccessCustomMessageAndPayload("Success cancel data transfers", result)
+	ginCtx.JSON(resp.StatusCode, resp)
+}
@@ -0,0 +1,92 @@
+package controllers
+
+import (
+	"golang-backend-test/app/commons/response"
+	"golang-backend-test/app/params"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// abortV2 writes a service error the /v2 way: with its real status, which
+// makes a missing resource a 404, and without the v1 envelope.
+func abortV2(ginCtx *gin.Context, custErr *response.CustomError) {
+	status := custErr.StatusCode
+	if custErr.Code == response.NotFoundError().Code {
+		status = http.StatusNotFound
+	}
+	ginCtx.AbortWithStatusJSON(status, &params.ErrorV2Response{
+		Error: params.ErrorV2Detail{
+			Code:    custErr.Code,
+			Message: custErr.Message,
+			Details: custErr.AdditionalInfo,
+		},
+	})
+}
+
+func bookV2Response(book *params.BookResponse) *params.BookV2Response {
+	result := &params.BookV2Response{
+		ID:              book.ID,
+		Title:           book.Title,
+		ISBN:            book.ISBN,
+		PublicationDate: book.PublicationDate,
+		Publisher:       book.Publisher,
+		PageCount:       book.PageCount,
+		CoverURL:        book.CoverURL,
+	}
+	if book.AuthorResponse != nil {
+		result.Author = &params.AuthorSummaryResponse{ID: book.AuthorResponse.ID, Name: book.AuthorResponse.Name}
+	}
+	return result
+}
+
+func authorV2Response(author *params.AuthorResponse) *params.AuthorV2Response {
+	result := &params.AuthorV2Response{
+		ID:          author.ID,
+		Name:        author.Name,
+		Birthdate:   author.Birthdate,
+		DeathDate:   author.DeathDate,
+		Biography:   author.Biography,
+		Nationality: author.Nationality,
+		Identifiers: author.Identifiers,
+		Pseudonyms:  author.Pseudonyms,
+		Aliases:     author.Aliases,
+	}
+	// lists are never null in v2
+	if result.Pseudonyms == nil {
+		result.Pseudonyms = []string{}
+	}
+	if result.Aliases == nil {
+		result.Aliases = []string{}
+	}
+	return result
+}
+
+// listV2Response maps a page of the services, whose items are a slice of
+// *params.BookResponse or *params.AuthorResponse.
+func listV2Response(page *params.PaginationResponse) *params.ListV2Response {
+	result := &params.ListV2Response{
+		Total:      page.Total,
+		NextCursor: page.NextCursor,
+		PrevCursor: page.PrevCursor,
+	}
+	switch items := page.Items.(type) {
+	case []*params.BookResponse:
+		books := []*params.BookV2Response{}
+		for _, book := range items {
+			books = append(books, bookV2Response(book))
+		}
+		result.Items = books
+	case []*params.AuthorResponse:
+		result.Items = authorsV2Response(items)
+	}
+	return result
+}
+
+func authorsV2Response(authors []*params.AuthorResponse) []*params.AuthorV2Response {
+	result := []*params.AuthorV2Response{}
+	for _, author := range authors {
+		result = append(result, authorV2Response(author))
+	}
+	return result
+}
@@ -0,0 +1,162 @@
+package controllers
+
+import (
+	"golang-backend-test/app/commons/response"
+	"golang-backend-test/app/params"
+	"golang-backend-test/app/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookController interface {
+	GetListSubscriptions(ginCtx *gin.Context)
+	FindSubscriptionById(ginCtx *gin.Context)
+	CreateSubscription(ginCtx *gin.Context)
+	UpdateSubscription(ginCtx *gin.Context)
+	DeleteSubscription(ginCtx *gin.Context)
+	GetListDeliveries(ginCtx *gin.Context)
+	Redeliver(ginCtx *gin.Context)
+}
+
+type WebhookControllerImpl struct {
+	WebhookService services.WebhookService
+}
+
+func NewWebhookController(webhookService services.WebhookService) WebhookController {
+	return &WebhookControllerImpl{
+		WebhookService: webhookService,
+	}
+}
+
+func (controller *WebhookControllerImpl) GetListSubscriptions(ginCtx *gin.Context) {
+	result, custErr := controller.WebhookService.FindAllSubscriptions(ginCtx)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+	resp := response.GeneralSuccessCustomMessageAndPayload("Success get data webhooks.", result)
+	ginCtx.JSON(resp.StatusCode, resp)
+}
+
+func (controller *WebhookControllerImpl) FindSubscriptionById(ginCtx *gin.Context) {
+	id, err := strconv.Atoi(ginCtx.Param("id"))
+	if err != nil {
+		errParam := response.NotFoundError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+
+	result, custErr := controller.WebhookService.FindDetailSubscription(ginCtx, id)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+	resp := response.GeneralSuccessCustomMessageAndPayload("Success get data detail webhooks.", result)
+	ginCtx.JSON(resp.StatusCode, resp)
+}
+
+func (controller *WebhookControllerImpl) CreateSubscription(ginCtx *gin.Context) {
+	var request = new(params.WebhookSubscriptionRequest)
+	err := ginCtx.ShouldBindJSON(request)
+	if err != nil {
+		errParam := response.GeneralError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+
+	result, custErr := controller.WebhookService.CreateSubscription(ginCtx, request)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+	resp := response.CreatedSuccessCustomMessageAndPayload("Success create data webhooks", result)
+	ginCtx.JSON(resp.StatusCode, resp)
+}
+
+func (controller *WebhookControllerImpl) UpdateSubscription(ginCtx *gin.Context) {
+	var request = new(params.WebhookSubscriptionRequest)
+	err := ginCtx.ShouldBindJSON(request)
+	if err != nil {
+		errParam := response.GeneralError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+	id, err := strconv.Atoi(ginCtx.Param("id"))
+	if err != nil {
+		errParam := response.NotFoundError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+
+	result, custErr := controller.WebhookService.UpdateSubscription(ginCtx, id, request)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+	resp := response.GeneralSuccessCustomMessageAndPayload("Success update data webhooks", result)
+	ginCtx.JSON(resp.StatusCode, resp)
+}
+
+func (controller *WebhookControllerImpl) DeleteSubscription(ginCtx *gin.Context) {
+	id, err := strconv.Atoi(ginCtx.Param("id"))
+	if err != nil {
+		errParam := response.NotFoundError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+
+	custErr := controller.WebhookService.DeleteSubscription(ginCtx, id)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+	resp := response.GeneralSuccess()
+	ginCtx.JSON(resp.StatusCode, resp)
+}
+
+func (controller *WebhookControllerImpl) GetListDeliveries(ginCtx *gin.Context) {
+	id, err := strconv.Atoi(ginCtx.Param("id"))
+	if err != nil {
+		errParam := response.NotFoundError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+	var request = new(params.WebhookDeliveryRequest)
+	if err := ginCtx.ShouldBindQuery(request); err != nil {
+		errParam := response.BadRequestErrorWithAdditionalInfo(err.Error())
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+
+	result, custErr := controller.WebhookService.FindDeliveries(ginCtx, id, request)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+	resp := response.GeneralSuccessCustomMessageAndPayload("Success get data webhook deliveries.", result)
+	ginCtx.JSON(resp.StatusCode, resp)
+}
+
+func (controller *WebhookControllerImpl) Redeliver(ginCtx *gin.Context) {
+	id, err := strconv.Atoi(ginCtx.Param("id"))
+	if err != nil {
+		errParam := response.NotFoundError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+	deliveryId, err := strconv.Atoi(ginCtx.Param("delivery_id"))
+	if err != nil {
+		errParam := response.NotFoundError()
+		ginCtx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return
+	}
+
+	result, custErr := controller.WebhookService.Redeliver(ginCtx, id, deliveryId)
+	if custErr != nil {
+		ginCtx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+		return
+	}
+	resp := response.CreatedSuccessCustomMessageAndPayload("Success redeliver webhook", result)
+	ginCtx.JSON(resp.StatusCode, resp)
+}
@@ -3,7 +3,20 @@ package models
 import "time"
 
 type Author struct {
-	ID        uint      `gorm:"primaryKey"`
-	Name      string    `gorm:"size:255"`
-	Birthdate time.Time `gorm:"type:date"`
+	ID          uint              `gorm:"primaryKey"`
+	TenantID    uint              `gorm:"index"`
+	Name        string            `gorm:"size:255"`
+	Birthdate   time.Time         `gorm:"type:date"`
+	DeathDate   *time.Time        `gorm:"type:date"`
+	Biography   string            `gorm:"type:text"`
+	Nationality string            `gorm:"size:100"`
+	Identifiers AuthorIdentifiers `gorm:"embedded;embeddedPrefix:identifier_"`
+	Aliases     []AuthorAlias     `gorm:"constraint:OnDelete:CASCADE;"`
+	Pseudonyms  []AuthorPseudonym `gorm:"constraint:OnDelete:CASCADE;"`
+}
+
+type AuthorIdentifiers struct {
+	VIAF     string `gorm:"size:32"`
+	ORCID    string `gorm:"size:19"`
+	Wikidata string `gorm:"size:32"`
 }
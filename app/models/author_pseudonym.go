@@ -0,0 +1,8 @@
+package models
+
+type AuthorPseudonym struct {
+	ID       uint   `gorm:"primaryKey"`
+	TenantID uint   `gorm:"index"`
+	AuthorID uint   `gorm:"index"`
+	Name     string `gorm:"size:255;index"`
+}
@@ -0,0 +1,7 @@
+package models
+
+type AuthorRedirect struct {
+	ID       uint `gorm:"primaryKey;autoIncrement:false"`
+	TenantID uint `gorm:"index"`
+	AuthorID uint `gorm:"index"`
+}
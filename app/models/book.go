@@ -1,9 +1,18 @@
 package models
 
+import "time"
+
 type Book struct {
-	ID       uint   `gorm:"primaryKey"`
-	Title    string `gorm:"size:255"`
-	ISBN     string `gorm:"unique"`
-	AuthorID uint
-	Author   Author `gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+	ID              uint       `gorm:"primaryKey"`
+	TenantID        uint       `gorm:"uniqueIndex:idx_books_tenant_isbn"`
+	Title           string     `gorm:"size:255"`
+	ISBN            string     `gorm:"uniqueIndex:idx_books_tenant_isbn"`
+	PublicationDate *time.Time `gorm:"type:date;index"`
+	Publisher       string     `gorm:"size:255"`
+	PageCount       int
+	CoverURL        string `gorm:"size:512"`
+	AuthorID        uint
+	Author          Author `gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+	Copies          []BookCopy
+	CreatedAt       time.Time
 }
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+const (
+	CopyAvailable = "available"
+	CopyInTransit = "in_transit"
+	CopyLost      = "lost"
+)
+
+type BookCopy struct {
+	ID                uint           `gorm:"primaryKey"`
+	TenantID          uint           `gorm:"uniqueIndex:idx_book_copies_tenant_barcode"`
+	BookID            uint           `gorm:"index"`
+	Barcode           string         `gorm:"size:64;uniqueIndex:idx_book_copies_tenant_barcode"`
+	ShelfLocationID   *uint          `gorm:"index"`
+	ShelfLocation     *ShelfLocation `gorm:"constraint:OnDelete:SET NULL;"`
+	CallNumber        string         `gorm:"size:64"`
+	CallNumberScheme  string         `gorm:"size:10"`
+	CallNumberSortKey string         `gorm:"size:255;index"`
+	Status            string         `gorm:"size:20;default:available"`
+	CreatedAt         time.Time
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// BookRating is the score from 1 to 5 a user gives a book; each user rates a
+// book once.
+type BookRating struct {
+	ID        uint `gorm:"primaryKey"`
+	TenantID  uint `gorm:"index"`
+	BookID    uint `gorm:"uniqueIndex:idx_book_ratings_book_user"`
+	UserID    uint `gorm:"uniqueIndex:idx_book_ratings_book_user"`
+	Score     int
+	CreatedAt time.Time
+}
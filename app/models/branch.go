@@ -0,0 +1,10 @@
+package models
+
+type Branch struct {
+	ID        uint            `gorm:"primaryKey"`
+	TenantID  uint            `gorm:"uniqueIndex:idx_branches_tenant_code"`
+	Code      string          `gorm:"size:20;uniqueIndex:idx_branches_tenant_code"`
+	Name      string          `gorm:"size:255"`
+	Address   string          `gorm:"size:255"`
+	Locations []ShelfLocation `gorm:"constraint:OnDelete:CASCADE;"`
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// CatalogEvent is a change to a book or an author, kept so that clients of
+// the event stream can catch up on the ones they missed. Only the newest
+// ones are kept. Data is the JSON of the resource the event is about.
+type CatalogEvent struct {
+	ID        uint   `gorm:"primaryKey"`
+	TenantID  uint   `gorm:"index"`
+	Event     string `gorm:"size:64"`
+	Entity    string `gorm:"size:20"`
+	Data      []byte
+	CreatedAt time.Time
+}
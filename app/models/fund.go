@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// Fund is a budget line that purchase orders are charged to. Amounts are in
+// minor currency units.
+type Fund struct {
+	ID         uint   `gorm:"primaryKey"`
+	TenantID   uint   `gorm:"uniqueIndex:idx_funds_tenant_code"`
+	Code       string `gorm:"size:20;uniqueIndex:idx_funds_tenant_code"`
+	Name       string `gorm:"size:255"`
+	Allocation int64
+	CreatedAt  time.Time
+}
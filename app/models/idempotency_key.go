@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// IdempotencyKey remembers a request sent with an Idempotency-Key header and
+// the response it got, so a retry of the request can be answered with the
+// same response. StatusCode stays zero while the first request is running.
+type IdempotencyKey struct {
+	ID          uint   `gorm:"primaryKey"`
+	TenantID    uint   `gorm:"uniqueIndex:idx_idempotency_keys_tenant_user_key"`
+	UserID      uint   `gorm:"uniqueIndex:idx_idempotency_keys_tenant_user_key"`
+	Key         string `gorm:"size:255;uniqueIndex:idx_idempotency_keys_tenant_user_key"`
+	Method      string `gorm:"size:10"`
+	Path        string `gorm:"size:255"`
+	Fingerprint string `gorm:"size:64"`
+	StatusCode  int
+	ContentType string `gorm:"size:255"`
+	Body        []byte
+	CreatedAt   time.Time
+	ExpiresAt   time.Time `gorm:"index"`
+}
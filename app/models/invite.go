@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// Invite lets one user register into an organization. Admins hand them out,
+// and creating an organization makes the invite of its first admin. UsedAt
+// is set once the invite is used.
+type Invite struct {
+	ID        uint      `gorm:"primaryKey"`
+	TenantID  uint      `gorm:"index"`
+	Token     string    `gorm:"size:64;uniqueIndex"`
+	ExpiresAt time.Time `gorm:"index"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+const (
+	JobPending   = "pending"
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	JobFailed    = "failed"
+	JobCanceled  = "canceled"
+)
+
+// Job is a piece of background work of type Type, run by the workers of
+// Queue once RunAt has come. A job that fails is pending again with a later
+// RunAt until it has had MaxAttempts, and then failed. Payload is the JSON
+// the handler of the type decodes.
+type Job struct {
+	ID          uint   `gorm:"primaryKey"`
+	TenantID    uint   `gorm:"index"`
+	Queue       string `gorm:"size:64;index"`
+	Type        string `gorm:"size:64"`
+	Payload     []byte
+	Status      string `gorm:"size:20;index"`
+	Attempts    int
+	MaxAttempts int
+	RunAt       time.Time `gorm:"index"`
+	LastError   string    `gorm:"size:1024"`
+	CreatedAt   time.Time
+	StartedAt   *time.Time
+	FinishedAt  *time.Time
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+type Loan struct {
+	ID         uint `gorm:"primaryKey"`
+	TenantID   uint `gorm:"index"`
+	BookCopyID uint `gorm:"index"`
+	UserID     uint `gorm:"index"`
+	BorrowedAt time.Time
+	DueAt      time.Time
+	ReturnedAt *time.Time
+	// OverdueNotifiedAt is when loan.overdue was published for the loan
+	OverdueNotifiedAt *time.Time
+}
@@ -0,0 +1,10 @@
+package models
+
+import "time"
+
+type Organization struct {
+	ID        uint   `gorm:"primaryKey"`
+	Name      string `gorm:"size:255"`
+	Slug      string `gorm:"size:64;uniqueIndex"`
+	CreatedAt time.Time
+}
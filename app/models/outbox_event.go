@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// OutboxEvent is an event written in the transaction of the change it is
+// about, waiting for the relay to hand it to the sinks. Aggregate and
+// AggregateID name the record that changed, such as book 4; the events of
+// one aggregate are relayed in order. Payload is the JSON of the data.
+type OutboxEvent struct {
+	ID            uint   `gorm:"primaryKey"`
+	TenantID      uint   `gorm:"index"`
+	Aggregate     string `gorm:"size:20"`
+	AggregateID   uint
+	Event         string `gorm:"size:64"`
+	Payload       []byte
+	CreatedAt     time.Time
+	PublishedAt   *time.Time `gorm:"index"`
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     string `gorm:"size:1024"`
+}
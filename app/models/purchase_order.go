@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+const (
+	PurchaseOrderDraft             = "draft"
+	PurchaseOrderOrdered           = "ordered"
+	PurchaseOrderPartiallyReceived = "partially_received"
+	PurchaseOrderReceived          = "received"
+	PurchaseOrderCancelled         = "cancelled"
+)
+
+type PurchaseOrder struct {
+	ID        uint `gorm:"primaryKey"`
+	TenantID  uint `gorm:"index"`
+	VendorID  uint `gorm:"index"`
+	Vendor    Vendor
+	FundID    uint `gorm:"index"`
+	Fund      Fund
+	Status    string              `gorm:"size:20;index"`
+	Lines     []PurchaseOrderLine `gorm:"constraint:OnDelete:CASCADE;"`
+	CreatedAt time.Time
+	CreatedBy uint
+	OrderedAt *time.Time
+	OrderedBy *uint
+}
+
+// PurchaseOrderLine orders Quantity copies of a title at UnitPrice, in minor
+// currency units. BookID is set once the first copies are received and the
+// title is in the catalog.
+type PurchaseOrderLine struct {
+	ID               uint   `gorm:"primaryKey"`
+	TenantID         uint   `gorm:"index"`
+	PurchaseOrderID  uint   `gorm:"index"`
+	ISBN             string `gorm:"size:20"`
+	Title            string `gorm:"size:255"`
+	AuthorName       string `gorm:"size:255"`
+	Quantity         int
+	UnitPrice        int64
+	ReceivedQuantity int
+	BookID           *uint
+}
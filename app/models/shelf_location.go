@@ -0,0 +1,9 @@
+package models
+
+type ShelfLocation struct {
+	ID       uint   `gorm:"primaryKey"`
+	TenantID uint   `gorm:"index"`
+	BranchID uint   `gorm:"index"`
+	Name     string `gorm:"size:255"`
+	Branch   Branch
+}
@@ -0,0 +1,61 @@
+package models
+
+import "time"
+
+const (
+	StocktakeOpen   = "open"
+	StocktakeClosed = "closed"
+)
+
+const (
+	DiscrepancyMissing    = "missing"
+	DiscrepancyUnexpected = "unexpected"
+	DiscrepancyWrongShelf = "wrong_shelf"
+)
+
+// Stocktake is an inventory audit of a branch, or of a single shelf when
+// ShelfLocationID is set.
+type Stocktake struct {
+	ID              uint `gorm:"primaryKey"`
+	TenantID        uint `gorm:"index"`
+	BranchID        uint `gorm:"index"`
+	Branch          Branch
+	ShelfLocationID *uint          `gorm:"index"`
+	ShelfLocation   *ShelfLocation `gorm:"constraint:OnDelete:SET NULL;"`
+	Status          string         `gorm:"size:20;index"`
+	ExpectedCount   int
+	FoundCount      int
+	OpenedAt        time.Time
+	OpenedBy        uint
+	ClosedAt        *time.Time
+	ClosedBy        *uint
+}
+
+type StocktakeScan struct {
+	ID              uint   `gorm:"primaryKey"`
+	TenantID        uint   `gorm:"index"`
+	StocktakeID     uint   `gorm:"index"`
+	Barcode         string `gorm:"size:64"`
+	ShelfLocationID *uint
+	ScannedAt       time.Time
+	ScannedBy       uint
+}
+
+// StocktakeDiscrepancy is a finding of a closed stocktake. Findings are kept
+// so that the report does not change when copies move afterwards.
+type StocktakeDiscrepancy struct {
+	ID                 uint   `gorm:"primaryKey"`
+	TenantID           uint   `gorm:"index"`
+	StocktakeID        uint   `gorm:"index"`
+	Kind               string `gorm:"size:20"`
+	Barcode            string `gorm:"size:64"`
+	Reason             string `gorm:"size:255"`
+	BookCopyID         *uint
+	BookCopy           *BookCopy `gorm:"constraint:OnDelete:SET NULL;"`
+	ExpectedLocationID *uint
+	ExpectedLocation   *ShelfLocation `gorm:"constraint:OnDelete:SET NULL;"`
+	FoundLocationID    *uint
+	FoundLocation      *ShelfLocation `gorm:"constraint:OnDelete:SET NULL;"`
+	MarkedLostAt       *time.Time
+	MarkedLostBy       *uint
+}
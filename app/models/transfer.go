@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+const (
+	TransferRequested = "requested"
+	TransferInTransit = "in_transit"
+	TransferReceived  = "received"
+	TransferCancelled = "cancelled"
+)
+
+type Transfer struct {
+	ID           uint `gorm:"primaryKey"`
+	TenantID     uint `gorm:"index"`
+	BookCopyID   uint `gorm:"index"`
+	BookCopy     BookCopy
+	FromBranchID uint `gorm:"index"`
+	FromBranch   Branch
+	ToBranchID   uint `gorm:"index"`
+	ToBranch     Branch
+	Status       string `gorm:"size:20;index"`
+	RequestedAt  time.Time
+	RequestedBy  uint
+	ShippedAt    *time.Time
+	ShippedBy    *uint
+	ReceivedAt   *time.Time
+	ReceivedBy   *uint
+	CancelledAt  *time.Time
+	CancelledBy  *uint
+}
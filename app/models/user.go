@@ -1,7 +1,11 @@
 package models
 
+// User is a member of an organization. Admins manage the organization's
+// settings, such as its webhooks; the first user of an organization is one.
 type User struct {
 	ID       uint   `gorm:"primaryKey"`
+	TenantID uint   `gorm:"index"`
 	Username string `gorm:"size:255"`
 	Password string `gorm:"size:255"`
+	Admin    bool   `gorm:"not null;default:false"`
 }
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+type Vendor struct {
+	ID        uint   `gorm:"primaryKey"`
+	TenantID  uint   `gorm:"index"`
+	Name      string `gorm:"size:255"`
+	Email     string `gorm:"size:255"`
+	Phone     string `gorm:"size:50"`
+	CreatedAt time.Time
+}
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryDead      = "dead"
+)
+
+// WebhookSubscription sends the events it lists to URL, signed with Secret.
+// Events holds the event names separated by commas.
+type WebhookSubscription struct {
+	ID        uint   `gorm:"primaryKey"`
+	TenantID  uint   `gorm:"index"`
+	URL       string `gorm:"size:2048"`
+	Secret    string `gorm:"size:255"`
+	Events    string `gorm:"size:1024"`
+	Active    bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// WebhookDelivery is one event sent to one subscription. It stays pending
+// while attempts fail, each one scheduling the next at NextAttemptAt, and is
+// dead once it runs out of attempts. Payload is the body that is sent.
+type WebhookDelivery struct {
+	ID             uint `gorm:"primaryKey"`
+	TenantID       uint `gorm:"index"`
+	SubscriptionID uint `gorm:"index"`
+	Subscription   WebhookSubscription
+	EventID        string `gorm:"size:64;index"`
+	Event          string `gorm:"size:64"`
+	Payload        []byte
+	Status         string `gorm:"size:20;index"`
+	Attempts       int
+	NextAttemptAt  time.Time `gorm:"index"`
+	LastStatusCode int
+	LastError      string `gorm:"size:1024"`
+	CreatedAt      time.Time
+	DeliveredAt    *time.Time
+}
@@ -0,0 +1,42 @@
+package params
+
+type VendorRequest struct {
+	Name  string `json:"name" validate:"required,max=255"`
+	Email string `json:"email" validate:"omitempty,email,max=255"`
+	Phone string `json:"phone" validate:"max=50"`
+}
+
+// FundRequest takes the allocation in minor currency units.
+type FundRequest struct {
+	Code       string `json:"code" validate:"required,max=20"`
+	Name       string `json:"name" validate:"required,max=255"`
+	Allocation int64  `json:"allocation" validate:"min=0"`
+}
+
+type PurchaseOrderRequest struct {
+	VendorID uint                       `json:"vendor_id" validate:"required"`
+	FundID   uint                       `json:"fund_id" validate:"required"`
+	Lines    []PurchaseOrderLineRequest `json:"lines" validate:"required,min=1,dive"`
+}
+
+// PurchaseOrderLineRequest takes the unit price in minor currency units.
+type PurchaseOrderLineRequest struct {
+	ISBN       string `json:"isbn" validate:"required,max=20"`
+	Title      string `json:"title" validate:"required,max=255"`
+	AuthorName string `json:"author_name" validate:"required,max=255"`
+	Quantity   int    `json:"quantity" validate:"required,min=1"`
+	UnitPrice  int64  `json:"unit_price" validate:"min=0"`
+}
+
+type PurchaseOrderReceiveRequest struct {
+	ShelfLocationID *uint                             `json:"shelf_location_id"`
+	Lines           []PurchaseOrderReceiveLineRequest `json:"lines" validate:"required,min=1,dive"`
+}
+
+// PurchaseOrderReceiveLineRequest receives Quantity copies of a line. When
+// Barcodes is empty the copies get generated barcodes.
+type PurchaseOrderReceiveLineRequest struct {
+	LineID   uint     `json:"line_id" validate:"required"`
+	Quantity int      `json:"quantity" validate:"required,min=1"`
+	Barcodes []string `json:"barcodes" validate:"dive,required,max=64"`
+}
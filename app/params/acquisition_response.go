@@ -0,0 +1,54 @@
+package params
+
+type VendorResponse struct {
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email,omitempty"`
+	Phone string `json:"phone,omitempty"`
+}
+
+type FundResponse struct {
+	ID     uint                `json:"id"`
+	Code   string              `json:"code"`
+	Name   string              `json:"name"`
+	Budget *FundBudgetResponse `json:"budget,omitempty"`
+}
+
+// FundBudgetResponse shows the spend of a fund against its allocation:
+// Committed is the value of the orders placed, Spent what of it has been
+// received and Available what is left to order.
+type FundBudgetResponse struct {
+	Allocation int64 `json:"allocation"`
+	Committed  int64 `json:"committed"`
+	Spent      int64 `json:"spent"`
+	Available  int64 `json:"available"`
+}
+
+type PurchaseOrderResponse struct {
+	ID      uint                         `json:"id"`
+	Status  string                       `json:"status"`
+	Vendor  *VendorResponse              `json:"vendor"`
+	Fund    *FundResponse                `json:"fund"`
+	Lines   []*PurchaseOrderLineResponse `json:"lines"`
+	Total   int64                        `json:"total"`
+	Created *StepResponse                `json:"created"`
+	Ordered *StepResponse                `json:"ordered,omitempty"`
+}
+
+type PurchaseOrderLineResponse struct {
+	ID               uint   `json:"id"`
+	ISBN             string `json:"isbn"`
+	Title            string `json:"title"`
+	AuthorName       string `json:"author_name"`
+	Quantity         int    `json:"quantity"`
+	ReceivedQuantity int    `json:"received_quantity"`
+	Outstanding      int    `json:"outstanding"`
+	UnitPrice        int64  `json:"unit_price"`
+	Total            int64  `json:"total"`
+	BookID           *uint  `json:"book_id,omitempty"`
+}
+
+type PurchaseOrderReceiveResponse struct {
+	PurchaseOrder *PurchaseOrderResponse `json:"purchase_order"`
+	Copies        []*BookCopyResponse    `json:"copies"`
+}
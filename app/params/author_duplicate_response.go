@@ -0,0 +1,7 @@
+package params
+
+type AuthorDuplicateResponse struct {
+	Author    *AuthorResponse `json:"author"`
+	Duplicate *AuthorResponse `json:"duplicate"`
+	Score     float64         `json:"score"`
+}
@@ -0,0 +1,5 @@
+package params
+
+type AuthorMergeRequest struct {
+	DuplicateID uint `json:"duplicate_id" validate:"required"`
+}
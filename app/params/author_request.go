@@ -1,6 +1,17 @@
 package params
 
 type AuthorRequest struct {
-	Name      string `json:"name" validate:"required"`
-	Birthdate string `json:"birthdate" validate:"required"`
+	Name        string            `json:"name" validate:"required"`
+	Birthdate   string            `json:"birthdate" validate:"required"`
+	DeathDate   string            `json:"death_date"`
+	Biography   string            `json:"biography"`
+	Nationality string            `json:"nationality" validate:"max=100"`
+	Identifiers AuthorIdentifiers `json:"identifiers"`
+	Pseudonyms  []string          `json:"pseudonyms" validate:"dive,required,max=255"`
+}
+
+type AuthorIdentifiers struct {
+	VIAF     string `json:"viaf,omitempty" validate:"omitempty,viaf"`
+	ORCID    string `json:"orcid,omitempty" validate:"omitempty,orcid"`
+	Wikidata string `json:"wikidata,omitempty" validate:"omitempty,wikidata"`
 }
@@ -1,7 +1,13 @@
 package params
 
 type AuthorResponse struct {
-	ID        uint   `json:"id"`
-	Name      string `json:"name"`
-	Birthdate string `json:"birthdate,omitempty"`
+	ID          uint               `json:"id"`
+	Name        string             `json:"name"`
+	Birthdate   string             `json:"birthdate,omitempty"`
+	DeathDate   string             `json:"death_date,omitempty"`
+	Biography   string             `json:"biography,omitempty"`
+	Nationality string             `json:"nationality,omitempty"`
+	Identifiers *AuthorIdentifiers `json:"identifiers,omitempty"`
+	Pseudonyms  []string           `json:"pseudonyms,omitempty"`
+	Aliases     []string           `json:"aliases,omitempty"`
 }
@@ -0,0 +1,13 @@
+package params
+
+type AuthorStatsResponse struct {
+	AuthorID              uint    `json:"author_id"`
+	TotalBooks            int64   `json:"total_books"`
+	TotalCopies           int64   `json:"total_copies"`
+	TotalLoans            int64   `json:"total_loans"`
+	AverageRating         float64 `json:"average_rating"`
+	RatingCount           int64   `json:"rating_count"`
+	FirstPublicationDate  string  `json:"first_publication_date,omitempty"`
+	LatestPublicationDate string  `json:"latest_publication_date,omitempty"`
+	ActiveYears           int     `json:"active_years"`
+}
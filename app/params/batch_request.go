@@ -0,0 +1,22 @@
+package params
+
+import "encoding/json"
+
+// BatchRequest runs Operations in order in one transaction. With Partial set
+// a failed operation is rolled back on its own and the others are kept.
+type BatchRequest struct {
+	Partial    bool                    `json:"partial"`
+	Operations []BatchOperationRequest `json:"operations" validate:"required,min=1,max=100,dive"`
+}
+
+// BatchOperationRequest is one operation of a batch. Body is the request the
+// matching single endpoint takes. ID and Body may use {"$ref": "name"} in
+// place of an ID, which resolves to the ID of the earlier operation with that
+// Ref.
+type BatchOperationRequest struct {
+	Ref      string          `json:"ref" validate:"max=64"`
+	Action   string          `json:"action" validate:"required,oneof=create update delete"`
+	Resource string          `json:"resource" validate:"required,oneof=book author"`
+	ID       json.RawMessage `json:"id"`
+	Body     json.RawMessage `json:"body"`
+}
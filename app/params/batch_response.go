@@ -0,0 +1,18 @@
+package params
+
+type BatchResponse struct {
+	Committed bool                      `json:"committed"`
+	Results   []*BatchOperationResponse `json:"results"`
+}
+
+// BatchOperationResponse is the outcome of one operation: "ok", "failed",
+// "rolled_back" when it succeeded but the batch was rolled back, or
+// "skipped" when an earlier operation failed first.
+type BatchOperationResponse struct {
+	Index  int         `json:"index"`
+	Ref    string      `json:"ref,omitempty"`
+	Status string      `json:"status"`
+	ID     uint        `json:"id,omitempty"`
+	Data   interface{} `json:"data,omitempty"`
+	Error  interface{} `json:"error,omitempty"`
+}
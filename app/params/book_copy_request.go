@@ -0,0 +1,8 @@
+package params
+
+type BookCopyRequest struct {
+	Barcode          string `json:"barcode" validate:"required,max=64"`
+	ShelfLocationID  *uint  `json:"shelf_location_id"`
+	CallNumber       string `json:"call_number" validate:"required_with=CallNumberScheme,max=64"`
+	CallNumberScheme string `json:"call_number_scheme" validate:"required_with=CallNumber,omitempty,oneof=dewey lcc"`
+}
@@ -0,0 +1,11 @@
+package params
+
+type BookCopyResponse struct {
+	ID               uint                   `json:"id"`
+	Barcode          string                 `json:"barcode"`
+	CallNumber       string                 `json:"call_number,omitempty"`
+	CallNumberScheme string                 `json:"call_number_scheme,omitempty"`
+	Location         *ShelfLocationResponse `json:"location,omitempty"`
+	Status           string                 `json:"status,omitempty"`
+	Available        bool                   `json:"available"`
+}
@@ -1,7 +1,23 @@
 package params
 
 type BookRequest struct {
-	Title    string `json:"title" validate:"required"`
-	ISBN     string `json:"isbn"`
-	AuthorID uint   `json:"author_id" validate:"required"`
+	Title           string `json:"title" validate:"required"`
+	ISBN            string `json:"isbn"`
+	PublicationDate string `json:"publication_date"`
+	AuthorID        uint   `json:"author_id" validate:"required"`
+	Publisher       string `json:"publisher" validate:"max=255"`
+	PageCount       int    `json:"page_count" validate:"min=0"`
+	CoverURL        string `json:"cover_url" validate:"omitempty,url,max=512"`
+}
+
+// BookImportRequest adds the books with ISBNs from the metadata provider,
+// leaving out those already in the catalog.
+type BookImportRequest struct {
+	ISBNs []string `json:"isbns" validate:"required,min=1,max=100,dive,required,max=20"`
+}
+
+// BookRatingRequest rates a book for the user making the request, replacing
+// the score they gave it before.
+type BookRatingRequest struct {
+	Score int `json:"score" validate:"required,min=1,max=5"`
 }
@@ -1,8 +1,26 @@
 package params
 
 type BookResponse struct {
-	ID             uint            `json:"id"`
-	Title          string          `json:"title"`
-	ISBN           string          `json:"isbn"`
-	AuthorResponse *AuthorResponse `json:"author,omitempty"`
+	ID              uint                `json:"id"`
+	Title           string              `json:"title"`
+	ISBN            string              `json:"isbn"`
+	PublicationDate string              `json:"publication_date,omitempty"`
+	Publisher       string              `json:"publisher,omitempty"`
+	PageCount       int                 `json:"page_count,omitempty"`
+	CoverURL        string              `json:"cover_url,omitempty"`
+	AuthorResponse  *AuthorResponse     `json:"author,omitempty"`
+	Copies          []*BookCopyResponse `json:"copies,omitempty"`
+	AuthorID        uint                `json:"-"`
+	Fields          Fieldset            `json:"-"`
+}
+
+func (book BookResponse) MarshalJSON() ([]byte, error) {
+	type plain BookResponse
+	return book.Fields.marshal(plain(book))
+}
+
+type BookRatingResponse struct {
+	BookID uint `json:"book_id"`
+	UserID uint `json:"user_id"`
+	Score  int  `json:"score"`
 }
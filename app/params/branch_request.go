@@ -0,0 +1,11 @@
+package params
+
+type BranchRequest struct {
+	Code    string `json:"code" validate:"required,max=20"`
+	Name    string `json:"name" validate:"required"`
+	Address string `json:"address"`
+}
+
+type ShelfLocationRequest struct {
+	Name string `json:"name" validate:"required"`
+}
@@ -0,0 +1,15 @@
+package params
+
+type BranchResponse struct {
+	ID        uint                     `json:"id"`
+	Code      string                   `json:"code"`
+	Name      string                   `json:"name"`
+	Address   string                   `json:"address,omitempty"`
+	Locations []*ShelfLocationResponse `json:"locations,omitempty"`
+}
+
+type ShelfLocationResponse struct {
+	ID     uint            `json:"id"`
+	Name   string          `json:"name"`
+	Branch *BranchResponse `json:"branch,omitempty"`
+}
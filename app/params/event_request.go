@@ -0,0 +1,9 @@
+package params
+
+// EventStreamRequest picks the events of the stream: those of one entity,
+// or all of them, after LastEventID. The Last-Event-ID header takes the
+// place of LastEventID when it is sent, as browsers do when they reconnect.
+type EventStreamRequest struct {
+	Entity      string `form:"entity" validate:"omitempty,oneof=book author"`
+	LastEventID uint   `form:"last_event_id"`
+}
@@ -0,0 +1,27 @@
+package params
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// StreamEvent is a change to the catalog as the event stream sends it. Data
+// is the JSON of the resource, or of a DeletedResponse.
+type StreamEvent struct {
+	ID     uint
+	Event  string
+	Entity string
+	Data   json.RawMessage
+}
+
+// EventMessage is an event as the outbox relays it. ID is the same every
+// time the event is relayed. Data is the JSON of the resource, or of a
+// DeletedResponse.
+type EventMessage struct {
+	ID          uint
+	Event       string
+	Aggregate   string
+	AggregateID uint
+	CreatedAt   time.Time
+	Data        json.RawMessage
+}
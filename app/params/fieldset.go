@@ -0,0 +1,32 @@
+package params
+
+import "encoding/json"
+
+// FieldsetRequest reads ?fields= and ?expand=, both comma separated. Expand
+// is nil when the query leaves it out, so that the default relations apply.
+type FieldsetRequest struct {
+	Fields string  `form:"fields"`
+	Expand *string `form:"expand"`
+}
+
+// Fieldset names the fields a response is written with. A nil fieldset
+// writes every field.
+type Fieldset map[string]bool
+
+func (fields Fieldset) marshal(value interface{}) ([]byte, error) {
+	encoded, err := json.Marshal(value)
+	if err != nil || fields == nil {
+		return encoded, err
+	}
+
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(encoded, &all); err != nil {
+		return nil, err
+	}
+	for name := range all {
+		if !fields[name] {
+			delete(all, name)
+		}
+	}
+	return json.Marshal(all)
+}
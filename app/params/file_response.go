@@ -0,0 +1,7 @@
+package params
+
+type FileResponse struct {
+	Filename    string
+	ContentType string
+	Body        []byte
+}
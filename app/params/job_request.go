@@ -0,0 +1,20 @@
+package params
+
+import "time"
+
+// JobRequest queues a job of a type that has a handler. RunAt schedules it
+// for later, and MaxAttempts defaults to 5.
+type JobRequest struct {
+	Type        string      `json:"type" validate:"required,max=64"`
+	Payload     interface{} `json:"payload"`
+	RunAt       *time.Time  `json:"run_at,omitempty"`
+	MaxAttempts int         `json:"max_attempts,omitempty" validate:"omitempty,min=1,max=100"`
+}
+
+// JobListRequest picks the newest jobs with a status and in a queue, or any
+// of them when left out. Limit defaults to 50.
+type JobListRequest struct {
+	Status string `form:"status" validate:"omitempty,oneof=pending running succeeded failed canceled"`
+	Queue  string `form:"queue" validate:"omitempty,max=64"`
+	Limit  int    `form:"limit" validate:"omitempty,min=1,max=100"`
+}
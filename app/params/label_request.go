@@ -0,0 +1,13 @@
+package params
+
+type BarcodeRequest struct {
+	Symbology string `form:"symbology" validate:"omitempty,oneof=ean13 code128"`
+	Format    string `form:"format" validate:"omitempty,oneof=png svg"`
+	Scale     int    `form:"scale" validate:"min=0,max=20"`
+}
+
+type SpineLabelRequest struct {
+	BookIDs []uint `json:"book_ids" validate:"required,min=1,max=500"`
+	Stock   string `json:"stock" validate:"omitempty,oneof=5160 5167 L7160"`
+	Skip    int    `json:"skip" validate:"min=0"`
+}
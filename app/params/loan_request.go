@@ -0,0 +1,8 @@
+package params
+
+// LoanRequest lends a copy to the user making the request for Days days, or
+// for the default loan period when Days is left out.
+type LoanRequest struct {
+	BookCopyID uint `json:"book_copy_id" validate:"required"`
+	Days       int  `json:"days" validate:"min=0,max=90"`
+}
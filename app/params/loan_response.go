@@ -0,0 +1,10 @@
+package params
+
+type LoanResponse struct {
+	ID         uint   `json:"id"`
+	BookCopyID uint   `json:"book_copy_id"`
+	UserID     uint   `json:"user_id"`
+	BorrowedAt string `json:"borrowed_at"`
+	DueAt      string `json:"due_at"`
+	ReturnedAt string `json:"returned_at,omitempty"`
+}
@@ -0,0 +1,6 @@
+package params
+
+type OrganizationRequest struct {
+	Name string `json:"name" validate:"required"`
+	Slug string `json:"slug" validate:"required,max=64,slug"`
+}
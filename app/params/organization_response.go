@@ -0,0 +1,15 @@
+package params
+
+// OrganizationResponse only carries the invite of the first admin when the
+// organization is created.
+type OrganizationResponse struct {
+	ID     uint            `json:"id"`
+	Name   string          `json:"name"`
+	Slug   string          `json:"slug"`
+	Invite *InviteResponse `json:"invite,omitempty"`
+}
+
+type InviteResponse struct {
+	Token     string `json:"token"`
+	ExpiresAt string `json:"expires_at"`
+}
@@ -0,0 +1,20 @@
+package params
+
+// PaginationRequest reads a page either by number or, when Cursor is set, by
+// the opaque cursor of an earlier response.
+type PaginationRequest struct {
+	Page   int    `form:"page" validate:"min=0"`
+	Limit  int    `form:"limit" validate:"min=0,max=100"`
+	Sort   string `form:"sort"`
+	Cursor string `form:"cursor"`
+}
+
+// PaginationResponse leaves Page out for pages read by cursor.
+type PaginationResponse struct {
+	Items      interface{} `json:"items"`
+	Page       int         `json:"page,omitempty"`
+	Limit      int         `json:"limit"`
+	Total      int64       `json:"total"`
+	NextCursor string      `json:"next_cursor,omitempty"`
+	PrevCursor string      `json:"prev_cursor,omitempty"`
+}
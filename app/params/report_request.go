@@ -0,0 +1,8 @@
+package params
+
+type ReportRequest struct {
+	From   string `form:"from"`
+	To     string `form:"to"`
+	Format string `form:"format" validate:"omitempty,oneof=json csv"`
+	Limit  int    `form:"limit" validate:"min=0,max=1000"`
+}
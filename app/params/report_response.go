@@ -0,0 +1,33 @@
+package params
+
+type BooksPerAuthorReport struct {
+	AuthorID   uint   `json:"author_id"`
+	AuthorName string `json:"author_name"`
+	TotalBooks int64  `json:"total_books"`
+}
+
+type BookWithoutISBNReport struct {
+	BookID     uint   `json:"book_id"`
+	Title      string `json:"title"`
+	AuthorID   uint   `json:"author_id"`
+	AuthorName string `json:"author_name"`
+	CreatedAt  string `json:"created_at"`
+}
+
+type AuthorWithoutBooksReport struct {
+	AuthorID uint   `json:"author_id"`
+	Name     string `json:"name"`
+}
+
+type BorrowedBookReport struct {
+	BookID     uint   `json:"book_id"`
+	Title      string `json:"title"`
+	ISBN       string `json:"isbn"`
+	TotalLoans int64  `json:"total_loans"`
+}
+
+type AcquisitionReport struct {
+	Month       string `json:"month"`
+	TotalCopies int64  `json:"total_copies"`
+	TotalTitles int64  `json:"total_titles"`
+}
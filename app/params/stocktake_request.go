@@ -0,0 +1,17 @@
+package params
+
+type StocktakeRequest struct {
+	BranchID        uint  `json:"branch_id" validate:"required"`
+	ShelfLocationID *uint `json:"shelf_location_id"`
+}
+
+// StocktakeScanRequest takes a single scanned barcode or a batch of them.
+type StocktakeScanRequest struct {
+	Barcode         string   `json:"barcode" validate:"required_without=Barcodes,max=64"`
+	Barcodes        []string `json:"barcodes" validate:"required_without=Barcode,max=500,dive,required,max=64"`
+	ShelfLocationID *uint    `json:"shelf_location_id"`
+}
+
+type StocktakeMarkLostRequest struct {
+	BookCopyIDs []uint `json:"book_copy_ids"`
+}
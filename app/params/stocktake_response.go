@@ -0,0 +1,35 @@
+package params
+
+type StocktakeResponse struct {
+	ID            uint                   `json:"id"`
+	Status        string                 `json:"status"`
+	Branch        *BranchResponse        `json:"branch"`
+	ShelfLocation *ShelfLocationResponse `json:"shelf_location,omitempty"`
+	ScanCount     int64                  `json:"scan_count"`
+	Opened        *StepResponse          `json:"opened"`
+	Closed        *StepResponse          `json:"closed,omitempty"`
+}
+
+type StocktakeScanResponse struct {
+	Accepted  int   `json:"accepted"`
+	ScanCount int64 `json:"scan_count"`
+}
+
+type StocktakeReportResponse struct {
+	Stocktake     *StocktakeResponse              `json:"stocktake"`
+	ExpectedCount int                             `json:"expected_count"`
+	FoundCount    int                             `json:"found_count"`
+	Missing       []*StocktakeDiscrepancyResponse `json:"missing"`
+	Unexpected    []*StocktakeDiscrepancyResponse `json:"unexpected"`
+	WrongShelf    []*StocktakeDiscrepancyResponse `json:"wrong_shelf"`
+}
+
+type StocktakeDiscrepancyResponse struct {
+	ID               uint                   `json:"id"`
+	Barcode          string                 `json:"barcode"`
+	Reason           string                 `json:"reason,omitempty"`
+	BookCopy         *BookCopyResponse      `json:"book_copy,omitempty"`
+	ExpectedLocation *ShelfLocationResponse `json:"expected_location,omitempty"`
+	FoundLocation    *ShelfLocationResponse `json:"found_location,omitempty"`
+	MarkedLost       *StepResponse          `json:"marked_lost,omitempty"`
+}
@@ -0,0 +1,10 @@
+package params
+
+type TransferRequest struct {
+	BookCopyID uint `json:"book_copy_id" validate:"required"`
+	ToBranchID uint `json:"to_branch_id" validate:"required"`
+}
+
+type TransferReceiveRequest struct {
+	ShelfLocationID uint `json:"shelf_location_id" validate:"required"`
+}
@@ -0,0 +1,24 @@
+package params
+
+type TransferResponse struct {
+	ID         uint              `json:"id"`
+	Status     string            `json:"status"`
+	BookCopy   *BookCopyResponse `json:"book_copy"`
+	FromBranch *BranchResponse   `json:"from_branch"`
+	ToBranch   *BranchResponse   `json:"to_branch"`
+	Requested  *StepResponse     `json:"requested"`
+	Shipped    *StepResponse     `json:"shipped,omitempty"`
+	Received   *StepResponse     `json:"received,omitempty"`
+	Cancelled  *StepResponse     `json:"cancelled,omitempty"`
+}
+
+// StepResponse tells when and by whom a workflow step was taken.
+type StepResponse struct {
+	At string `json:"at"`
+	By uint   `json:"by"`
+}
+
+type BranchTransfersResponse struct {
+	Inbound  []*TransferResponse `json:"inbound"`
+	Outbound []*TransferResponse `json:"outbound"`
+}
@@ -1,6 +1,10 @@
 package params
 
+// UserRequest registers and logs in users. Registering takes an invite of
+// the organization.
 type UserRequest struct {
-	Username string `json:"username" validate:"required"`
-	Password string `json:"password" validate:"required,min=8"`
+	Organization string `json:"organization" validate:"required"`
+	Username     string `json:"username" validate:"required"`
+	Password     string `json:"password" validate:"required,min=8"`
+	Invite       string `json:"invite,omitempty"`
 }
@@ -3,3 +3,9 @@ package params
 type UserResponse struct {
 	Token string `json:"token"`
 }
+
+type UserDetailResponse struct {
+	ID       uint   `json:"id"`
+	Username string `json:"username"`
+	Admin    bool   `json:"admin"`
+}
@@ -0,0 +1,54 @@
+package params
+
+// The /v2 API answers with these shapes instead of the Response envelope:
+// a resource is the body itself, lists are a ListV2Response and errors an
+// ErrorV2Response.
+
+type BookV2Response struct {
+	ID              uint                   `json:"id"`
+	Title           string                 `json:"title"`
+	ISBN            string                 `json:"isbn,omitempty"`
+	PublicationDate string                 `json:"publication_date,omitempty"`
+	Publisher       string                 `json:"publisher,omitempty"`
+	PageCount       int                    `json:"page_count,omitempty"`
+	CoverURL        string                 `json:"cover_url,omitempty"`
+	Author          *AuthorSummaryResponse `json:"author,omitempty"`
+}
+
+type AuthorSummaryResponse struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+type AuthorV2Response struct {
+	ID          uint               `json:"id"`
+	Name        string             `json:"name"`
+	Birthdate   string             `json:"birthdate,omitempty"`
+	DeathDate   string             `json:"death_date,omitempty"`
+	Biography   string             `json:"biography,omitempty"`
+	Nationality string             `json:"nationality,omitempty"`
+	Identifiers *AuthorIdentifiers `json:"identifiers,omitempty"`
+	Pseudonyms  []string           `json:"pseudonyms"`
+	Aliases     []string           `json:"aliases"`
+}
+
+// ListV2Response is a page of a list. The cursors are left out on the first
+// and last pages.
+type ListV2Response struct {
+	Items      interface{} `json:"items"`
+	Total      int64       `json:"total"`
+	NextCursor string      `json:"next_cursor,omitempty"`
+	PrevCursor string      `json:"prev_cursor,omitempty"`
+}
+
+type ErrorV2Response struct {
+	Error ErrorV2Detail `json:"error"`
+}
+
+// ErrorV2Detail keeps the code of response.CustomError, so that clients of
+// both versions tell errors apart the same way.
+type ErrorV2Detail struct {
+	Code    string      `json:"code"`
+	Message string      `json:"message"`
+	Details interface{} `json:"details,omitempty"`
+}
@@ -0,0 +1,14 @@
+package params
+
+// WebhookSubscriptionRequest subscribes a URL to events. Secret signs the
+// deliveries, and is generated when left out. Active defaults to true.
+type WebhookSubscriptionRequest struct {
+	URL    string   `json:"url" validate:"required,url,max=2048"`
+	Events []string `json:"events" validate:"required,min=1,dive,oneof=book.created book.updated book.deleted author.created author.updated author.deleted loan.overdue"`
+	Secret string   `json:"secret,omitempty" validate:"omitempty,min=16,max=255"`
+	Active *bool    `json:"active,omitempty"`
+}
+
+type WebhookDeliveryRequest struct {
+	Status string `form:"status" validate:"omitempty,oneof=pending delivered dead"`
+}
@@ -0,0 +1,41 @@
+package params
+
+// WebhookSubscriptionResponse only carries the secret when the subscription
+// is created.
+type WebhookSubscriptionResponse struct {
+	ID        uint     `json:"id"`
+	URL       string   `json:"url"`
+	Events    []string `json:"events"`
+	Active    bool     `json:"active"`
+	Secret    string   `json:"secret,omitempty"`
+	CreatedAt string   `json:"created_at"`
+}
+
+type WebhookDeliveryResponse struct {
+	ID             uint   `json:"id"`
+	EventID        string `json:"event_id"`
+	Event          string `json:"event"`
+	Status         string `json:"status"`
+	Attempts       int    `json:"attempts"`
+	NextAttemptAt  string `json:"next_attempt_at,omitempty"`
+	LastStatusCode int    `json:"last_status_code,omitempty"`
+	LastError      string `json:"last_error,omitempty"`
+	CreatedAt      string `json:"created_at"`
+	DeliveredAt    string `json:"delivered_at,omitempty"`
+}
+
+// WebhookEvent is the body of a delivery. Data is the resource the event is
+// about, in the form the API answers with.
+type WebhookEvent struct {
+	ID        string      `json:"id"`
+	Event     string      `json:"event"`
+	CreatedAt string      `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+// DeletedResponse is the data of the *.deleted events. MergedInto is set
+// when an author was deleted by merging it into another one.
+type DeletedResponse struct {
+	ID         uint `json:"id"`
+	MergedInto uint `json:"merged_into,omitempty"`
+}
@@ -0,0 +1,94 @@
+package repositories
+
+import (
+	"context"
+	"golang-backend-test/app/models"
+
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockAcquisitionRepository struct {
+	mock.Mock
+}
+
+func (mock *MockAcquisitionRepository) FindVendorById(ctx context.Context, db *gorm.DB, id int) (*models.Vendor, error) {
+	args := mock.Called(ctx, db, id)
+	if vendor, ok := args.Get(0).(*models.Vendor); ok {
+		return vendor, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (mock *MockAcquisitionRepository) GetListVendors(ctx context.Context, db *gorm.DB) ([]*models.Vendor, error) {
+	args := mock.Called(ctx, db)
+	if vendors, ok := args.Get(0).([]*models.Vendor); ok {
+		return vendors, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (mock *MockAcquisitionRepository) CreateVendor(ctx context.Context, db *gorm.DB, vendor *models.Vendor) error {
+	args := mock.Called(ctx, db, vendor)
+	return args.Error(0)
+}
+
+func (mock *MockAcquisitionRepository) FindFundById(ctx context.Context, db *gorm.DB, id int) (*models.Fund, error) {
+	args := mock.Called(ctx, db, id)
+	if fund, ok := args.Get(0).(*models.Fund); ok {
+		return fund, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (mock *MockAcquisitionRepository) GetListFunds(ctx context.Context, db *gorm.DB) ([]*models.Fund, error) {
+	args := mock.Called(ctx, db)
+	if funds, ok := args.Get(0).([]*models.Fund); ok {
+		return funds, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (mock *MockAcquisitionRepository) CreateFund(ctx context.Context, db *gorm.DB, fund *models.Fund) error {
+	args := mock.Called(ctx, db, fund)
+	return args.Error(0)
+}
+
+func (mock *MockAcquisitionRepository) GetFundSpending(ctx context.Context, db *gorm.DB, fundIds []uint) (map[uint]*FundSpending, error) {
+	args := mock.Called(ctx, db, fundIds)
+	if spending, ok := args.Get(0).(map[uint]*FundSpending); ok {
+		return spending, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (mock *MockAcquisitionRepository) FindPurchaseOrderById(ctx context.Context, db *gorm.DB, id int) (*models.PurchaseOrder, error) {
+	args := mock.Called(ctx, db, id)
+	if order, ok := args.Get(0).(*models.PurchaseOrder); ok {
+		return order, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (mock *MockAcquisitionRepository) GetListPurchaseOrders(ctx context.Context, db *gorm.DB, status string) ([]*models.PurchaseOrder, error) {
+	args := mock.Called(ctx, db, status)
+	if orders, ok := args.Get(0).([]*models.PurchaseOrder); ok {
+		return orders, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (mock *MockAcquisitionRepository) CreatePurchaseOrder(ctx context.Context, db *gorm.DB, order *models.PurchaseOrder) error {
+	args := mock.Called(ctx, db, order)
+	return args.Error(0)
+}
+
+func (mock *MockAcquisitionRepository) UpdatePurchaseOrder(ctx context.Context, db *gorm.DB, order *models.PurchaseOrder) error {
+	args := mock.Called(ctx, db, order)
+	return args.Error(0)
+}
+
+func (mock *MockAcquisitionRepository) ReceivePurchaseOrderLine(ctx context.Context, db *gorm.DB, line *models.PurchaseOrderLine, quantity int) error {
+	args := mock.Called(ctx, db, line, quantity)
+	return args.Error(0)
+}
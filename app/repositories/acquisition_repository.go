@@ -0,0 +1,166 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"golang-backend-test/app/models"
+
+	"gorm.io/gorm"
+)
+
+type AcquisitionRepository interface {
+	FindVendorById(ctx context.Context, db *gorm.DB, id int) (*models.Vendor, error)
+	GetListVendors(ctx context.Context, db *gorm.DB) ([]*models.Vendor, error)
+	CreateVendor(ctx context.Context, db *gorm.DB, vendor *models.Vendor) error
+	FindFundById(ctx context.Context, db *gorm.DB, id int) (*models.Fund, error)
+	GetListFunds(ctx context.Context, db *gorm.DB) ([]*models.Fund, error)
+	CreateFund(ctx context.Context, db *gorm.DB, fund *models.Fund) error
+	GetFundSpending(ctx context.Context, db *gorm.DB, fundIds []uint) (map[uint]*FundSpending, error)
+	FindPurchaseOrderById(ctx context.Context, db *gorm.DB, id int) (*models.PurchaseOrder, error)
+	GetListPurchaseOrders(ctx context.Context, db *gorm.DB, status string) ([]*models.PurchaseOrder, error)
+	CreatePurchaseOrder(ctx context.Context, db *gorm.DB, order *models.PurchaseOrder) error
+	UpdatePurchaseOrder(ctx context.Context, db *gorm.DB, order *models.PurchaseOrder) error
+	ReceivePurchaseOrderLine(ctx context.Context, db *gorm.DB, line *models.PurchaseOrderLine, quantity int) error
+}
+
+// FundSpending sums the purchase order lines charged to a fund: Committed
+// is what was ordered, Spent what of it was received.
+type FundSpending struct {
+	FundID    uint
+	Committed int64
+	Spent     int64
+}
+
+type AcquisitionRepositoryImpl struct {
+}
+
+func NewAcquisitionRepository() AcquisitionRepository {
+	return &AcquisitionRepositoryImpl{}
+}
+
+func (repository *AcquisitionRepositoryImpl) FindVendorById(ctx context.Context, db *gorm.DB, id int) (*models.Vendor, error) {
+	var vendor models.Vendor
+	if err := db.WithContext(ctx).First(&vendor, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("vendor not found")
+		}
+		return nil, err
+	}
+	return &vendor, nil
+}
+func (repository *AcquisitionRepositoryImpl) GetListVendors(ctx context.Context, db *gorm.DB) ([]*models.Vendor, error) {
+	var vendors []*models.Vendor
+	if err := db.WithContext(ctx).Order("name, id").Find(&vendors).Error; err != nil {
+		return nil, err
+	}
+	return vendors, nil
+}
+func (repository *AcquisitionRepositoryImpl) CreateVendor(ctx context.Context, db *gorm.DB, vendor *models.Vendor) error {
+	if err := db.WithContext(ctx).Create(vendor).Error; err != nil {
+		return err
+	}
+	return nil
+}
+func (repository *AcquisitionRepositoryImpl) FindFundById(ctx context.Context, db *gorm.DB, id int) (*models.Fund, error) {
+	var fund models.Fund
+	if err := db.WithContext(ctx).First(&fund, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("fund not found")
+		}
+		return nil, err
+	}
+	return &fund, nil
+}
+func (repository *AcquisitionRepositoryImpl) GetListFunds(ctx context.Context, db *gorm.DB) ([]*models.Fund, error) {
+	var funds []*models.Fund
+	if err := db.WithContext(ctx).Order("code").Find(&funds).Error; err != nil {
+		return nil, err
+	}
+	return funds, nil
+}
+func (repository *AcquisitionRepositoryImpl) CreateFund(ctx context.Context, db *gorm.DB, fund *models.Fund) error {
+	if err := db.WithContext(ctx).Create(fund).Error; err != nil {
+		return err
+	}
+	return nil
+}
+func (repository *AcquisitionRepositoryImpl) GetFundSpending(ctx context.Context, db *gorm.DB, fundIds []uint) (map[uint]*FundSpending, error) {
+	var rows []*FundSpending
+	if err := db.WithContext(ctx).Model(&models.PurchaseOrderLine{}).
+		Select("purchase_orders.fund_id AS fund_id, "+
+			"SUM(purchase_order_lines.quantity * purchase_order_lines.unit_price) AS committed, "+
+			"SUM(purchase_order_lines.received_quantity * purchase_order_lines.unit_price) AS spent").
+		Joins("JOIN purchase_orders ON purchase_orders.id = purchase_order_lines.purchase_order_id").
+		Where("purchase_orders.fund_id IN ?", fundIds).
+		Where("purchase_orders.status IN ?", []string{models.PurchaseOrderOrdered, models.PurchaseOrderPartiallyReceived, models.PurchaseOrderReceived}).
+		Group("purchase_orders.fund_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	spending := make(map[uint]*FundSpending)
+	for _, id := range fundIds {
+		spending[id] = &FundSpending{FundID: id}
+	}
+	for _, row := range rows {
+		spending[row.FundID] = row
+	}
+	return spending, nil
+}
+func (repository *AcquisitionRepositoryImpl) FindPurchaseOrderById(ctx context.Context, db *gorm.DB, id int) (*models.PurchaseOrder, error) {
+	var order models.PurchaseOrder
+	if err := db.WithContext(ctx).Preload("Vendor").Preload("Fund").
+		Preload("Lines", func(db *gorm.DB) *gorm.DB {
+			return db.Order("id")
+		}).
+		First(&order, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("purchase order not found")
+		}
+		return nil, err
+	}
+	return &order, nil
+}
+func (repository *AcquisitionRepositoryImpl) GetListPurchaseOrders(ctx context.Context, db *gorm.DB, status string) ([]*models.PurchaseOrder, error) {
+	var orders []*models.PurchaseOrder
+	query := db.WithContext(ctx).Preload("Vendor").Preload("Fund").
+		Preload("Lines", func(db *gorm.DB) *gorm.DB {
+			return db.Order("id")
+		})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Order("id DESC").Find(&orders).Error; err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+func (repository *AcquisitionRepositoryImpl) CreatePurchaseOrder(ctx context.Context, db *gorm.DB, order *models.PurchaseOrder) error {
+	if err := db.WithContext(ctx).Omit("Vendor", "Fund").Create(order).Error; err != nil {
+		return err
+	}
+	return nil
+}
+func (repository *AcquisitionRepositoryImpl) UpdatePurchaseOrder(ctx context.Context, db *gorm.DB, order *models.PurchaseOrder) error {
+	if err := db.WithContext(ctx).Omit("Vendor", "Fund", "Lines").Save(order).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// ReceivePurchaseOrderLine adds quantity to the copies received of line and
+// links it to its book, unless the line has fewer copies outstanding by now.
+func (repository *AcquisitionRepositoryImpl) ReceivePurchaseOrderLine(ctx context.Context, db *gorm.DB, line *models.PurchaseOrderLine, quantity int) error {
+	result := db.WithContext(ctx).Model(&models.PurchaseOrderLine{}).
+		Where("id = ? AND received_quantity + ? <= quantity", line.ID, quantity).
+		Updates(map[string]interface{}{
+			"received_quantity": gorm.Expr("received_quantity + ?", quantity),
+			"book_id":           line.BookID,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("purchase order line not found")
+	}
+	return nil
+}
@@ -20,6 +20,14 @@ func (mock *MockAuthorRepository) FindAuthorById(ctx context.Context, db *gorm.D
 	return nil, args.Error(1)
 }
 
+func (mock *MockAuthorRepository) FindAuthorsByIds(ctx context.Context, db *gorm.DB, ids []int) ([]*models.Author, error) {
+	args := mock.Called(ctx, db, ids)
+	if authors, ok := args.Get(0).([]*models.Author); ok {
+		return authors, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
 func (mock *MockAuthorRepository) GetListAuthors(ctx context.Context, db *gorm.DB) ([]*models.Author, error) {
 	args := mock.Called(ctx, db)
 	if authors, ok := args.Get(0).([]*models.Author); ok {
@@ -28,6 +36,46 @@ func (mock *MockAuthorRepository) GetListAuthors(ctx context.Context, db *gorm.D
 	return nil, args.Error(1)
 }
 
+func (mock *MockAuthorRepository) SearchAuthors(ctx context.Context, db *gorm.DB, query string) ([]*models.Author, error) {
+	args := mock.Called(ctx, db, query)
+	if authors, ok := args.Get(0).([]*models.Author); ok {
+		return authors, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (mock *MockAuthorRepository) GetAuthorsPage(ctx context.Context, db *gorm.DB, page KeysetPage) ([]*models.Author, bool, int64, error) {
+	args := mock.Called(ctx, db, page)
+	if authors, ok := args.Get(0).([]*models.Author); ok {
+		return authors, args.Bool(1), args.Get(2).(int64), args.Error(3)
+	}
+	return nil, false, 0, args.Error(3)
+}
+
+func (mock *MockAuthorRepository) GetAuthorBooks(ctx context.Context, db *gorm.DB, id int, offset, limit int, order string) ([]*models.Book, int64, error) {
+	args := mock.Called(ctx, db, id, offset, limit, order)
+	if books, ok := args.Get(0).([]*models.Book); ok {
+		return books, args.Get(1).(int64), args.Error(2)
+	}
+	return nil, 0, args.Error(2)
+}
+
+func (mock *MockAuthorRepository) GetAuthorBooksPage(ctx context.Context, db *gorm.DB, id int, page KeysetPage) ([]*models.Book, bool, int64, error) {
+	args := mock.Called(ctx, db, id, page)
+	if books, ok := args.Get(0).([]*models.Book); ok {
+		return books, args.Bool(1), args.Get(2).(int64), args.Error(3)
+	}
+	return nil, false, 0, args.Error(3)
+}
+
+func (mock *MockAuthorRepository) GetAuthorStats(ctx context.Context, db *gorm.DB, id int) (*AuthorStats, error) {
+	args := mock.Called(ctx, db, id)
+	if stats, ok := args.Get(0).(*AuthorStats); ok {
+		return stats, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
 func (mock *MockAuthorRepository) CreateAuthor(ctx context.Context, db *gorm.DB, author *models.Author) error {
 	args := mock.Called(ctx, db, author)
 	return args.Error(0)
@@ -42,3 +90,54 @@ func (mock *MockAuthorRepository) DeleteAuthor(ctx context.Context, db *gorm.DB,
 	args := mock.Called(ctx, db, id)
 	return args.Error(0)
 }
+
+func (mock *MockAuthorRepository) ReassignBooks(ctx context.Context, db *gorm.DB, fromId, toId int) error {
+	args := mock.Called(ctx, db, fromId, toId)
+	return args.Error(0)
+}
+
+func (mock *MockAuthorRepository) ReassignAliases(ctx context.Context, db *gorm.DB, fromId, toId int) error {
+	args := mock.Called(ctx, db, fromId, toId)
+	return args.Error(0)
+}
+
+func (mock *MockAuthorRepository) ReassignPseudonyms(ctx context.Context, db *gorm.DB, fromId, toId int) error {
+	args := mock.Called(ctx, db, fromId, toId)
+	return args.Error(0)
+}
+
+func (mock *MockAuthorRepository) CreateAuthorAlias(ctx context.Context, db *gorm.DB, alias *models.AuthorAlias) error {
+	args := mock.Called(ctx, db, alias)
+	return args.Error(0)
+}
+
+func (mock *MockAuthorRepository) FindAuthorRedirect(ctx context.Context, db *gorm.DB, id int) (*models.AuthorRedirect, error) {
+	args := mock.Called(ctx, db, id)
+	if redirect, ok := args.Get(0).(*models.AuthorRedirect); ok {
+		return redirect, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (mock *MockAuthorRepository) ReassignRedirects(ctx context.Context, db *gorm.DB, fromId, toId int) error {
+	args := mock.Called(ctx, db, fromId, toId)
+	return args.Error(0)
+}
+
+func (mock *MockAuthorRepository) CreateAuthorRedirect(ctx context.Context, db *gorm.DB, redirect *models.AuthorRedirect) error {
+	args := mock.Called(ctx, db, redirect)
+	return args.Error(0)
+}
+
+func (mock *MockAuthorRepository) DeleteAuthorRedirect(ctx context.Context, db *gorm.DB, id int) error {
+	args := mock.Called(ctx, db, id)
+	return args.Error(0)
+}
+
+func (mock *MockAuthorRepository) FindAuthorByName(ctx context.Context, db *gorm.DB, name string) (*models.Author, error) {
+	args := mock.Called(ctx, db, name)
+	if author, ok := args.Get(0).(*models.Author); ok {
+		return author, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
@@ -4,16 +4,42 @@ import (
 	"context"
 	"errors"
 	"golang-backend-test/app/models"
+	"time"
 
 	"gorm.io/gorm"
 )
 
 type AuthorRepository interface {
 	FindAuthorById(ctx context.Context, db *gorm.DB, id int) (*models.Author, error)
+	FindAuthorByName(ctx context.Context, db *gorm.DB, name string) (*models.Author, error)
+	FindAuthorsByIds(ctx context.Context, db *gorm.DB, ids []int) ([]*models.Author, error)
 	GetListAuthors(ctx context.Context, db *gorm.DB) ([]*models.Author, error)
+	GetAuthorsPage(ctx context.Context, db *gorm.DB, page KeysetPage) ([]*models.Author, bool, int64, error)
+	SearchAuthors(ctx context.Context, db *gorm.DB, query string) ([]*models.Author, error)
+	GetAuthorBooks(ctx context.Context, db *gorm.DB, id int, offset, limit int, order string) ([]*models.Book, int64, error)
+	GetAuthorBooksPage(ctx context.Context, db *gorm.DB, id int, page KeysetPage) ([]*models.Book, bool, int64, error)
+	GetAuthorStats(ctx context.Context, db *gorm.DB, id int) (*AuthorStats, error)
 	CreateAuthor(ctx context.Context, db *gorm.DB, author *models.Author) error
 	UpdateAuthor(ctx context.Context, db *gorm.DB, author *models.Author) error
 	DeleteAuthor(ctx context.Context, db *gorm.DB, id int) error
+	ReassignBooks(ctx context.Context, db *gorm.DB, fromId, toId int) error
+	ReassignAliases(ctx context.Context, db *gorm.DB, fromId, toId int) error
+	ReassignPseudonyms(ctx context.Context, db *gorm.DB, fromId, toId int) error
+	CreateAuthorAlias(ctx context.Context, db *gorm.DB, alias *models.AuthorAlias) error
+	FindAuthorRedirect(ctx context.Context, db *gorm.DB, id int) (*models.AuthorRedirect, error)
+	ReassignRedirects(ctx context.Context, db *gorm.DB, fromId, toId int) error
+	CreateAuthorRedirect(ctx context.Context, db *gorm.DB, redirect *models.AuthorRedirect) error
+	DeleteAuthorRedirect(ctx context.Context, db *gorm.DB, id int) error
+}
+
+type AuthorStats struct {
+	TotalBooks            int64
+	TotalCopies           int64
+	TotalLoans            int64
+	AverageRating         float64
+	RatingCount           int64
+	FirstPublicationDate  *time.Time
+	LatestPublicationDate *time.Time
 }
 
 type AuthorRepositoryImpl struct {
@@ -25,7 +51,7 @@ func NewAuthorRepository() AuthorRepository {
 
 func (repository *AuthorRepositoryImpl) FindAuthorById(ctx context.Context, db *gorm.DB, id int) (*models.Author, error) {
 	var author models.Author
-	if err := db.WithContext(ctx).First(&author, id).Error; err != nil {
+	if err := db.WithContext(ctx).Preload("Aliases").Preload("Pseudonyms").First(&author, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("author not found")
 		}
@@ -33,13 +59,131 @@ func (repository *AuthorRepositoryImpl) FindAuthorById(ctx context.Context, db *
 	}
 	return &author, nil
 }
+func (repository *AuthorRepositoryImpl) FindAuthorByName(ctx context.Context, db *gorm.DB, name string) (*models.Author, error) {
+	var author models.Author
+	if err := db.WithContext(ctx).Where("LOWER(name) = LOWER(?)", name).Order("id").First(&author).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("author not found")
+		}
+		return nil, err
+	}
+	return &author, nil
+}
+func (repository *AuthorRepositoryImpl) FindAuthorsByIds(ctx context.Context, db *gorm.DB, ids []int) ([]*models.Author, error) {
+	var authors []*models.Author
+	if err := db.WithContext(ctx).Preload("Aliases").Preload("Pseudonyms").Where("id IN ?", ids).Find(&authors).Error; err != nil {
+		return nil, err
+	}
+	return authors, nil
+}
 func (repository *AuthorRepositoryImpl) GetListAuthors(ctx context.Context, db *gorm.DB) ([]*models.Author, error) {
 	var authors []*models.Author
-	if err := db.WithContext(ctx).Find(&authors).Error; err != nil {
+	if err := db.WithContext(ctx).Preload("Pseudonyms").Find(&authors).Error; err != nil {
 		return nil, err
 	}
 	return authors, nil
 }
+func (repository *AuthorRepositoryImpl) GetAuthorsPage(ctx context.Context, db *gorm.DB, page KeysetPage) ([]*models.Author, bool, int64, error) {
+	var authors []*models.Author
+	var total int64
+	query := db.WithContext(ctx).Model(&models.Author{}).Session(&gorm.Session{})
+	if err := query.Count(&total).Error; err != nil {
+		return nil, false, 0, err
+	}
+	if err := keysetQuery(query.Preload("Pseudonyms"), page).Find(&authors).Error; err != nil {
+		return nil, false, 0, err
+	}
+	authors, more := keysetRows(authors, page)
+	return authors, more, total, nil
+}
+func (repository *AuthorRepositoryImpl) SearchAuthors(ctx context.Context, db *gorm.DB, query string) ([]*models.Author, error) {
+	var authors []*models.Author
+	pattern := "%" + query + "%"
+	db = db.WithContext(ctx)
+	pseudonyms := db.Model(&models.AuthorPseudonym{}).Select("author_id").Where("name LIKE ?", pattern)
+	aliases := db.Model(&models.AuthorAlias{}).Select("author_id").Where("name LIKE ?", pattern)
+	if err := db.Preload("Pseudonyms").
+		Where("name LIKE ?", pattern).
+		Or("id IN (?)", pseudonyms).
+		Or("id IN (?)", aliases).
+		Find(&authors).Error; err != nil {
+		return nil, err
+	}
+	return authors, nil
+}
+func (repository *AuthorRepositoryImpl) GetAuthorBooks(ctx context.Context, db *gorm.DB, id int, offset, limit int, order string) ([]*models.Book, int64, error) {
+	var books []*models.Book
+	var total int64
+	query := db.WithContext(ctx).Model(&models.Book{}).Where("author_id = ?", id).Session(&gorm.Session{})
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.Order(order).Offset(offset).Limit(limit).Find(&books).Error; err != nil {
+		return nil, 0, err
+	}
+	return books, total, nil
+}
+func (repository *AuthorRepositoryImpl) GetAuthorBooksPage(ctx context.Context, db *gorm.DB, id int, page KeysetPage) ([]*models.Book, bool, int64, error) {
+	var books []*models.Book
+	var total int64
+	query := db.WithContext(ctx).Model(&models.Book{}).Where("author_id = ?", id).Session(&gorm.Session{})
+	if err := query.Count(&total).Error; err != nil {
+		return nil, false, 0, err
+	}
+	if err := keysetQuery(query, page).Find(&books).Error; err != nil {
+		return nil, false, 0, err
+	}
+	books, more := keysetRows(books, page)
+	return books, more, total, nil
+}
+func (repository *AuthorRepositoryImpl) GetAuthorStats(ctx context.Context, db *gorm.DB, id int) (*AuthorStats, error) {
+	var stats AuthorStats
+	db = db.WithContext(ctx)
+	if err := db.Model(&models.Book{}).Where("author_id = ?", id).Count(&stats.TotalBooks).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Model(&models.BookCopy{}).
+		Joins("JOIN books ON books.id = book_copies.book_id").
+		Where("books.author_id = ?", id).
+		Count(&stats.TotalCopies).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Model(&models.Loan{}).
+		Joins("JOIN book_copies ON book_copies.id = loans.book_copy_id").
+		Joins("JOIN books ON books.id = book_copies.book_id").
+		Where("books.author_id = ?", id).
+		Count(&stats.TotalLoans).Error; err != nil {
+		return nil, err
+	}
+
+	var rating struct {
+		Average float64
+		Count   int64
+	}
+	if err := db.Model(&models.BookRating{}).
+		Select("COALESCE(AVG(book_ratings.score), 0) AS average, COUNT(book_ratings.id) AS count").
+		Joins("JOIN books ON books.id = book_ratings.book_id").
+		Where("books.author_id = ?", id).
+		Scan(&rating).Error; err != nil {
+		return nil, err
+	}
+	stats.AverageRating = rating.Average
+	stats.RatingCount = rating.Count
+
+	var first, latest []*models.Book
+	published := db.Where("author_id = ? AND publication_date IS NOT NULL", id).Limit(1).Session(&gorm.Session{})
+	if err := published.Order("publication_date ASC").Find(&first).Error; err != nil {
+		return nil, err
+	}
+	if err := published.Order("publication_date DESC").Find(&latest).Error; err != nil {
+		return nil, err
+	}
+	if len(first) != 0 && len(latest) != 0 {
+		stats.FirstPublicationDate = first[0].PublicationDate
+		stats.LatestPublicationDate = latest[0].PublicationDate
+	}
+	return &stats, nil
+}
 func (repository *AuthorRepositoryImpl) CreateAuthor(ctx context.Context, db *gorm.DB, author *models.Author) error {
 	if err := db.WithContext(ctx).Create(author).Error; err != nil {
 		return err
@@ -47,17 +191,85 @@ func (repository *AuthorRepositoryImpl) CreateAuthor(ctx context.Context, db *go
 	return nil
 }
 func (repository *AuthorRepositoryImpl) UpdateAuthor(ctx context.Context, db *gorm.DB, author *models.Author) error {
-	if err := db.WithContext(ctx).Save(author).Error; err != nil {
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// pseudonyms are replaced as a whole
+		if err := tx.Where("author_id = ?", author.ID).Delete(&models.AuthorPseudonym{}).Error; err != nil {
+			return err
+		}
+		// an update never inserts, so a deleted author cannot come back
+		result := tx.Model(author).Select("*").Omit("Aliases").Updates(author)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("author not found")
+		}
+		return nil
+	})
+}
+func (repository *AuthorRepositoryImpl) DeleteAuthor(ctx context.Context, db *gorm.DB, id int) error {
+	result := db.WithContext(ctx).Delete(&models.Author{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("author not found")
+	}
+	return nil
+}
+func (repository *AuthorRepositoryImpl) ReassignBooks(ctx context.Context, db *gorm.DB, fromId, toId int) error {
+	if err := db.WithContext(ctx).Model(&models.Book{}).Where("author_id = ?", fromId).Update("author_id", toId).Error; err != nil {
 		return err
 	}
 	return nil
 }
-func (repository *AuthorRepositoryImpl) DeleteAuthor(ctx context.Context, db *gorm.DB, id int) error {
-	if err := db.WithContext(ctx).Delete(&models.Author{}, id).Error; err != nil {
+func (repository *AuthorRepositoryImpl) ReassignAliases(ctx context.Context, db *gorm.DB, fromId, toId int) error {
+	if err := db.WithContext(ctx).Model(&models.AuthorAlias{}).Where("author_id = ?", fromId).Update("author_id", toId).Error; err != nil {
+		return err
+	}
+	return nil
+}
+func (repository *AuthorRepositoryImpl) ReassignPseudonyms(ctx context.Context, db *gorm.DB, fromId, toId int) error {
+	if err := db.WithContext(ctx).Model(&models.AuthorPseudonym{}).Where("author_id = ?", fromId).Update("author_id", toId).Error; err != nil {
+		return err
+	}
+	return nil
+}
+func (repository *AuthorRepositoryImpl) CreateAuthorAlias(ctx context.Context, db *gorm.DB, alias *models.AuthorAlias) error {
+	if err := db.WithContext(ctx).Create(alias).Error; err != nil {
+		return err
+	}
+	return nil
+}
+func (repository *AuthorRepositoryImpl) FindAuthorRedirect(ctx context.Context, db *gorm.DB, id int) (*models.AuthorRedirect, error) {
+	var redirect models.AuthorRedirect
+	if err := db.WithContext(ctx).First(&redirect, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("author not found")
+			return nil, errors.New("author redirect not found")
 		}
+		return nil, err
+	}
+	return &redirect, nil
+}
+func (repository *AuthorRepositoryImpl) ReassignRedirects(ctx context.Context, db *gorm.DB, fromId, toId int) error {
+	if err := db.WithContext(ctx).Model(&models.AuthorRedirect{}).Where("author_id = ?", fromId).Update("author_id", toId).Error; err != nil {
+		return err
+	}
+	return nil
+}
+func (repository *AuthorRepositoryImpl) CreateAuthorRedirect(ctx context.Context, db *gorm.DB, redirect *models.AuthorRedirect) error {
+	if err := db.WithContext(ctx).Create(redirect).Error; err != nil {
 		return err
 	}
 	return nil
 }
+func (repository *AuthorRepositoryImpl) DeleteAuthorRedirect(ctx context.Context, db *gorm.DB, id int) error {
+	result := db.WithContext(ctx).Delete(&models.AuthorRedirect{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("author redirect not found")
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package repositories
+
+import (
+	"context"
+	"golang-backend-test/app/models"
+
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockBookCopyRepository struct {
+	mock.Mock
+}
+
+func (mock *MockBookCopyRepository) FindBookCopyById(ctx context.Context, db *gorm.DB, id int) (*models.BookCopy, error) {
+	args := mock.Called(ctx, db, id)
+	if bookCopy, ok := args.Get(0).(*models.BookCopy); ok {
+		return bookCopy, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (mock *MockBookCopyRepository) GetBookCopies(ctx context.Context, db *gorm.DB, bookId int) ([]*models.BookCopy, error) {
+	args := mock.Called(ctx, db, bookId)
+	if copies, ok := args.Get(0).([]*models.BookCopy); ok {
+		return copies, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (mock *MockBookCopyRepository) CreateBookCopy(ctx context.Context, db *gorm.DB, bookCopy *models.BookCopy) error {
+	args := mock.Called(ctx, db, bookCopy)
+	return args.Error(0)
+}
+
+func (mock *MockBookCopyRepository) UpdateBookCopy(ctx context.Context, db *gorm.DB, bookCopy *models.BookCopy) error {
+	args := mock.Called(ctx, db, bookCopy)
+	return args.Error(0)
+}
+
+func (mock *MockBookCopyRepository) MarkCopyLost(ctx context.Context, db *gorm.DB, id int) (bool, error) {
+	args := mock.Called(ctx, db, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (mock *MockBookCopyRepository) FindBookCopiesByBarcodes(ctx context.Context, db *gorm.DB, barcodes []string) ([]*models.BookCopy, error) {
+	args := mock.Called(ctx, db, barcodes)
+	if copies, ok := args.Get(0).([]*models.BookCopy); ok {
+		return copies, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (mock *MockBookCopyRepository) GetShelvedCopies(ctx context.Context, db *gorm.DB, branchId, shelfLocationId int) ([]*models.BookCopy, error) {
+	args := mock.Called(ctx, db, branchId, shelfLocationId)
+	if copies, ok := args.Get(0).([]*models.BookCopy); ok {
+		return copies, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
@@ -0,0 +1,105 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"golang-backend-test/app/models"
+
+	"gorm.io/gorm"
+)
+
+// ShelfOrder sorts book copies the way they stand on the shelf.
+const ShelfOrder = "call_number_scheme, call_number_sort_key, id"
+
+type BookCopyRepository interface {
+	FindBookCopyById(ctx context.Context, db *gorm.DB, id int) (*models.BookCopy, error)
+	GetBookCopies(ctx context.Context, db *gorm.DB, bookId int) ([]*models.BookCopy, error)
+	FindBookCopiesByBarcodes(ctx context.Context, db *gorm.DB, barcodes []string) ([]*models.BookCopy, error)
+	GetShelvedCopies(ctx context.Context, db *gorm.DB, branchId, shelfLocationId int) ([]*models.BookCopy, error)
+	CreateBookCopy(ctx context.Context, db *gorm.DB, bookCopy *models.BookCopy) error
+	UpdateBookCopy(ctx context.Context, db *gorm.DB, bookCopy *models.BookCopy) error
+	MarkCopyLost(ctx context.Context, db *gorm.DB, id int) (bool, error)
+}
+
+type BookCopyRepositoryImpl struct {
+}
+
+func NewBookCopyRepository() BookCopyRepository {
+	return &BookCopyRepositoryImpl{}
+}
+
+func (repository *BookCopyRepositoryImpl) FindBookCopyById(ctx context.Context, db *gorm.DB, id int) (*models.BookCopy, error) {
+	var bookCopy models.BookCopy
+	if err := db.WithContext(ctx).Preload("ShelfLocation.Branch").First(&bookCopy, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("book copy not found")
+		}
+		return nil, err
+	}
+	return &bookCopy, nil
+}
+func (repository *BookCopyRepositoryImpl) GetBookCopies(ctx context.Context, db *gorm.DB, bookId int) ([]*models.BookCopy, error) {
+	var copies []*models.BookCopy
+	if err := db.WithContext(ctx).Preload("ShelfLocation.Branch").
+		Where("book_id = ?", bookId).
+		Order(ShelfOrder).
+		Find(&copies).Error; err != nil {
+		return nil, err
+	}
+	return copies, nil
+}
+func (repository *BookCopyRepositoryImpl) FindBookCopiesByBarcodes(ctx context.Context, db *gorm.DB, barcodes []string) ([]*models.BookCopy, error) {
+	var copies []*models.BookCopy
+	if err := db.WithContext(ctx).Preload("ShelfLocation.Branch").Where("barcode IN ?", barcodes).Find(&copies).Error; err != nil {
+		return nil, err
+	}
+	return copies, nil
+}
+
+// GetShelvedCopies returns the copies that should stand on the shelves of a
+// branch, or on one shelf when shelfLocationId is not zero: available copies
+// that are not out on loan.
+func (repository *BookCopyRepositoryImpl) GetShelvedCopies(ctx context.Context, db *gorm.DB, branchId, shelfLocationId int) ([]*models.BookCopy, error) {
+	var copies []*models.BookCopy
+	db = db.WithContext(ctx)
+	onLoan := db.Model(&models.Loan{}).Select("book_copy_id").Where("returned_at IS NULL")
+	query := db.Preload("ShelfLocation.Branch").
+		Joins("JOIN shelf_locations ON shelf_locations.id = book_copies.shelf_location_id").
+		Where("shelf_locations.branch_id = ?", branchId).
+		Where("book_copies.status = ?", models.CopyAvailable).
+		Where("book_copies.id NOT IN (?)", onLoan)
+	if shelfLocationId != 0 {
+		query = query.Where("book_copies.shelf_location_id = ?", shelfLocationId)
+	}
+	if err := query.Order("book_copies.call_number_scheme, book_copies.call_number_sort_key, book_copies.id").Find(&copies).Error; err != nil {
+		return nil, err
+	}
+	return copies, nil
+}
+func (repository *BookCopyRepositoryImpl) CreateBookCopy(ctx context.Context, db *gorm.DB, bookCopy *models.BookCopy) error {
+	if err := db.WithContext(ctx).Omit("ShelfLocation").Create(bookCopy).Error; err != nil {
+		return err
+	}
+	return nil
+}
+func (repository *BookCopyRepositoryImpl) UpdateBookCopy(ctx context.Context, db *gorm.DB, bookCopy *models.BookCopy) error {
+	if err := db.WithContext(ctx).Omit("ShelfLocation").Save(bookCopy).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// MarkCopyLost marks a copy lost if it is available and not out on loan,
+// and tells whether it did.
+func (repository *BookCopyRepositoryImpl) MarkCopyLost(ctx context.Context, db *gorm.DB, id int) (bool, error) {
+	db = db.WithContext(ctx)
+	onLoan := db.Model(&models.Loan{}).Select("book_copy_id").Where("returned_at IS NULL")
+	result := db.Model(&models.BookCopy{}).
+		Where("id = ? AND status = ?", id, models.CopyAvailable).
+		Where("id NOT IN (?)", onLoan).
+		Update("status", models.CopyLost)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
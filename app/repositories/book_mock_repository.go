@@ -20,8 +20,40 @@ func (mock *MockBookRepository) FindBookById(ctx context.Context, db *gorm.DB, i
 	return nil, args.Error(1)
 }
 
-func (mock *MockBookRepository) GetListBooks(ctx context.Context, db *gorm.DB) ([]*models.Book, error) {
-	args := mock.Called(ctx, db)
+func (mock *MockBookRepository) LoadBookById(ctx context.Context, db *gorm.DB, id int, load BookLoad) (*models.Book, error) {
+	args := mock.Called(ctx, db, id, load)
+	if book, ok := args.Get(0).(*models.Book); ok {
+		return book, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (mock *MockBookRepository) GetListBooks(ctx context.Context, db *gorm.DB, load BookLoad) ([]*models.Book, error) {
+	args := mock.Called(ctx, db, load)
+	if books, ok := args.Get(0).([]*models.Book); ok {
+		return books, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (mock *MockBookRepository) GetBooksPage(ctx context.Context, db *gorm.DB, page KeysetPage, load BookLoad) ([]*models.Book, bool, int64, error) {
+	args := mock.Called(ctx, db, page, load)
+	if books, ok := args.Get(0).([]*models.Book); ok {
+		return books, args.Bool(1), args.Get(2).(int64), args.Error(3)
+	}
+	return nil, false, 0, args.Error(3)
+}
+
+func (mock *MockBookRepository) GetListBooksByBranch(ctx context.Context, db *gorm.DB, branchId int, load BookLoad) ([]*models.Book, error) {
+	args := mock.Called(ctx, db, branchId, load)
+	if books, ok := args.Get(0).([]*models.Book); ok {
+		return books, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (mock *MockBookRepository) FindBooksByIds(ctx context.Context, db *gorm.DB, ids []int) ([]*models.Book, error) {
+	args := mock.Called(ctx, db, ids)
 	if books, ok := args.Get(0).([]*models.Book); ok {
 		return books, args.Error(1)
 	}
@@ -42,3 +74,24 @@ func (mock *MockBookRepository) DeleteBook(ctx context.Context, db *gorm.DB, id
 	args := mock.Called(ctx, db, id)
 	return args.Error(0)
 }
+
+func (mock *MockBookRepository) FindBookByISBN(ctx context.Context, db *gorm.DB, isbn string) (*models.Book, error) {
+	args := mock.Called(ctx, db, isbn)
+	if book, ok := args.Get(0).(*models.Book); ok {
+		return book, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (mock *MockBookRepository) FindBookRating(ctx context.Context, db *gorm.DB, bookId, userId int) (*models.BookRating, error) {
+	args := mock.Called(ctx, db, bookId, userId)
+	if rating, ok := args.Get(0).(*models.BookRating); ok {
+		return rating, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (mock *MockBookRepository) SaveBookRating(ctx context.Context, db *gorm.DB, rating *models.BookRating) error {
+	args := mock.Called(ctx, db, rating)
+	return args.Error(0)
+}
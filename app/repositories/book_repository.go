@@ -10,10 +10,48 @@ import (
 
 type BookRepository interface {
 	FindBookById(ctx context.Context, db *gorm.DB, id int) (*models.Book, error)
-	GetListBooks(ctx context.Context, db *gorm.DB) ([]*models.Book, error)
+	LoadBookById(ctx context.Context, db *gorm.DB, id int, load BookLoad) (*models.Book, error)
+	FindBookByISBN(ctx context.Context, db *gorm.DB, isbn string) (*models.Book, error)
+	GetListBooks(ctx context.Context, db *gorm.DB, load BookLoad) ([]*models.Book, error)
+	GetBooksPage(ctx context.Context, db *gorm.DB, page KeysetPage, load BookLoad) ([]*models.Book, bool, int64, error)
+	GetListBooksByBranch(ctx context.Context, db *gorm.DB, branchId int, load BookLoad) ([]*models.Book, error)
+	FindBooksByIds(ctx context.Context, db *gorm.DB, ids []int) ([]*models.Book, error)
 	CreateBook(ctx context.Context, db *gorm.DB, book *models.Book) error
 	UpdateBook(ctx context.Context, db *gorm.DB, book *models.Book) error
 	DeleteBook(ctx context.Context, db *gorm.DB, id int) error
+	FindBookRating(ctx context.Context, db *gorm.DB, bookId, userId int) (*models.BookRating, error)
+	SaveBookRating(ctx context.Context, db *gorm.DB, rating *models.BookRating) error
+}
+
+// BookLoad tells which columns and relations of a book to load. Without
+// columns every column is loaded.
+type BookLoad struct {
+	Columns []string
+	Author  bool
+	Copies  bool
+}
+
+func (load BookLoad) apply(query *gorm.DB) *gorm.DB {
+	if len(load.Columns) > 0 {
+		columns := append([]string{"id"}, load.Columns...)
+		if load.Author {
+			columns = append(columns, "author_id")
+		}
+		// qualified, so the columns stay unambiguous in joins
+		for i, column := range columns {
+			columns[i] = "books." + column
+		}
+		query = query.Select(columns)
+	}
+	if load.Author {
+		query = query.Preload("Author")
+	}
+	if load.Copies {
+		query = query.Preload("Copies", func(db *gorm.DB) *gorm.DB {
+			return db.Order(ShelfOrder)
+		}).Preload("Copies.ShelfLocation.Branch")
+	}
+	return query
 }
 
 type BookRepositoryImpl struct {
@@ -25,7 +63,22 @@ func NewBookRepository() BookRepository {
 
 func (repositories *BookRepositoryImpl) FindBookById(ctx context.Context, db *gorm.DB, id int) (*models.Book, error) {
 	var book models.Book
-	if err := db.WithContext(ctx).Preload("Author").First(&book, id).Error; err != nil {
+	if err := db.WithContext(ctx).Preload("Author").
+		Preload("Copies", func(db *gorm.DB) *gorm.DB {
+			return db.Order(ShelfOrder)
+		}).
+		Preload("Copies.ShelfLocation.Branch").
+		First(&book, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New("book not found")
+		}
+		return nil, err
+	}
+	return &book, nil
+}
+func (repositories *BookRepositoryImpl) LoadBookById(ctx context.Context, db *gorm.DB, id int, load BookLoad) (*models.Book, error) {
+	var book models.Book
+	if err := load.apply(db.WithContext(ctx)).First(&book, id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, errors.New("book not found")
 		}
@@ -33,9 +86,57 @@ func (repositories *BookRepositoryImpl) FindBookById(ctx context.Context, db *go
 	}
 	return &book, nil
 }
-func (repositories *BookRepositoryImpl) GetListBooks(ctx context.Context, db *gorm.DB) ([]*models.Book, error) {
+func (repositories *BookRepositoryImpl) FindBookByISBN(ctx context.Context, db *gorm.DB, isbn string) (*models.Book, error) {
+	var book models.Book
+	if err := db.WithContext(ctx).Preload("Author").Where("isbn = ?", isbn).First(&book).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New("book not found")
+		}
+		return nil, err
+	}
+	return &book, nil
+}
+func (repositories *BookRepositoryImpl) GetListBooks(ctx context.Context, db *gorm.DB, load BookLoad) ([]*models.Book, error) {
 	var books []*models.Book
-	if err := db.WithContext(ctx).Preload("Author").Find(&books).Error; err != nil {
+	if err := load.apply(db.WithContext(ctx)).Find(&books).Error; err != nil {
+		return nil, err
+	}
+	return books, nil
+}
+func (repositories *BookRepositoryImpl) GetBooksPage(ctx context.Context, db *gorm.DB, page KeysetPage, load BookLoad) ([]*models.Book, bool, int64, error) {
+	var books []*models.Book
+	var total int64
+	query := db.WithContext(ctx).Model(&models.Book{}).Session(&gorm.Session{})
+	if err := query.Count(&total).Error; err != nil {
+		return nil, false, 0, err
+	}
+	if len(load.Columns) > 0 && page.Order.Column != "" {
+		// the cursors are made from the sort column
+		load.Columns = append(load.Columns, page.Order.Column)
+	}
+	if err := keysetQuery(load.apply(query), page).Find(&books).Error; err != nil {
+		return nil, false, 0, err
+	}
+	books, more := keysetRows(books, page)
+	return books, more, total, nil
+}
+func (repositories *BookRepositoryImpl) GetListBooksByBranch(ctx context.Context, db *gorm.DB, branchId int, load BookLoad) ([]*models.Book, error) {
+	var books []*models.Book
+	query := db.WithContext(ctx).Select("books.*")
+	if err := load.apply(query).
+		Joins("JOIN book_copies ON book_copies.book_id = books.id").
+		Joins("JOIN shelf_locations ON shelf_locations.id = book_copies.shelf_location_id").
+		Where("shelf_locations.branch_id = ?", branchId).
+		Group("books.id").
+		Order("MIN(book_copies.call_number_scheme), MIN(book_copies.call_number_sort_key), books.id").
+		Find(&books).Error; err != nil {
+		return nil, err
+	}
+	return books, nil
+}
+func (repositories *BookRepositoryImpl) FindBooksByIds(ctx context.Context, db *gorm.DB, ids []int) ([]*models.Book, error) {
+	var books []*models.Book
+	if err := db.WithContext(ctx).Preload("Author").Where("id IN ?", ids).Find(&books).Error; err != nil {
 		return nil, err
 	}
 	return books, nil
@@ -46,17 +147,41 @@ func (repositories *BookRepositoryImpl) CreateBook(ctx context.Context, db *gorm
 	}
 	return nil
 }
+
+// UpdateBook writes every column of book except created_at, which the
+// caller does not load.
 func (repositories *BookRepositoryImpl) UpdateBook(ctx context.Context, db *gorm.DB, book *models.Book) error {
-	if err := db.WithContext(ctx).Save(book).Error; err != nil {
-		return err
+	result := db.WithContext(ctx).Model(book).Select("*").Omit("created_at", "Author", "Copies").Updates(book)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("book not found")
 	}
 	return nil
 }
 func (repositories *BookRepositoryImpl) DeleteBook(ctx context.Context, db *gorm.DB, id int) error {
-	if err := db.WithContext(ctx).Delete(&models.Book{}, id).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return errors.New("book not found")
+	result := db.WithContext(ctx).Delete(&models.Book{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("book not found")
+	}
+	return nil
+}
+func (repositories *BookRepositoryImpl) FindBookRating(ctx context.Context, db *gorm.DB, bookId, userId int) (*models.BookRating, error) {
+	var rating models.BookRating
+	if err := db.WithContext(ctx).Where("book_id = ? AND user_id = ?", bookId, userId).First(&rating).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("book rating not found")
 		}
+		return nil, err
+	}
+	return &rating, nil
+}
+func (repositories *BookRepositoryImpl) SaveBookRating(ctx context.Context, db *gorm.DB, rating *models.BookRating) error {
+	if err := db.WithContext(ctx).Save(rating).Error; err != nil {
 		return err
 	}
 	return nil
@@ -0,0 +1,57 @@
+package repositories
+
+import (
+	"context"
+	"golang-backend-test/app/models"
+
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockBranchRepository struct {
+	mock.Mock
+}
+
+func (mock *MockBranchRepository) FindBranchById(ctx context.Context, db *gorm.DB, id int) (*models.Branch, error) {
+	args := mock.Called(ctx, db, id)
+	if branch, ok := args.Get(0).(*models.Branch); ok {
+		return branch, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (mock *MockBranchRepository) GetListBranches(ctx context.Context, db *gorm.DB) ([]*models.Branch, error) {
+	args := mock.Called(ctx, db)
+	if branches, ok := args.Get(0).([]*models.Branch); ok {
+		return branches, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (mock *MockBranchRepository) CreateBranch(ctx context.Context, db *gorm.DB, branch *models.Branch) error {
+	args := mock.Called(ctx, db, branch)
+	return args.Error(0)
+}
+
+func (mock *MockBranchRepository) UpdateBranch(ctx context.Context, db *gorm.DB, branch *models.Branch) error {
+	args := mock.Called(ctx, db, branch)
+	return args.Error(0)
+}
+
+func (mock *MockBranchRepository) DeleteBranch(ctx context.Context, db *gorm.DB, id int) error {
+	args := mock.Called(ctx, db, id)
+	return args.Error(0)
+}
+
+func (mock *MockBranchRepository) FindShelfLocationById(ctx context.Context, db *gorm.DB, id int) (*models.ShelfLocation, error) {
+	args := mock.Called(ctx, db, id)
+	if location, ok := args.Get(0).(*models.ShelfLocation); ok {
+		return location, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (mock *MockBranchRepository) CreateShelfLocation(ctx context.Context, db *gorm.DB, location *models.ShelfLocation) error {
+	args := mock.Called(ctx, db, location)
+	return args.Error(0)
+}
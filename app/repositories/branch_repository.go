@@ -0,0 +1,82 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"golang-backend-test/app/models"
+
+	"gorm.io/gorm"
+)
+
+type BranchRepository interface {
+	FindBranchById(ctx context.Context, db *gorm.DB, id int) (*models.Branch, error)
+	GetListBranches(ctx context.Context, db *gorm.DB) ([]*models.Branch, error)
+	CreateBranch(ctx context.Context, db *gorm.DB, branch *models.Branch) error
+	UpdateBranch(ctx context.Context, db *gorm.DB, branch *models.Branch) error
+	DeleteBranch(ctx context.Context, db *gorm.DB, id int) error
+	FindShelfLocationById(ctx context.Context, db *gorm.DB, id int) (*models.ShelfLocation, error)
+	CreateShelfLocation(ctx context.Context, db *gorm.DB, location *models.ShelfLocation) error
+}
+
+type BranchRepositoryImpl struct {
+}
+
+func NewBranchRepository() BranchRepository {
+	return &BranchRepositoryImpl{}
+}
+
+func (repository *BranchRepositoryImpl) FindBranchById(ctx context.Context, db *gorm.DB, id int) (*models.Branch, error) {
+	var branch models.Branch
+	if err := db.WithContext(ctx).Preload("Locations").First(&branch, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("branch not found")
+		}
+		return nil, err
+	}
+	return &branch, nil
+}
+func (repository *BranchRepositoryImpl) GetListBranches(ctx context.Context, db *gorm.DB) ([]*models.Branch, error) {
+	var branches []*models.Branch
+	if err := db.WithContext(ctx).Order("code").Find(&branches).Error; err != nil {
+		return nil, err
+	}
+	return branches, nil
+}
+func (repository *BranchRepositoryImpl) CreateBranch(ctx context.Context, db *gorm.DB, branch *models.Branch) error {
+	if err := db.WithContext(ctx).Create(branch).Error; err != nil {
+		return err
+	}
+	return nil
+}
+func (repository *BranchRepositoryImpl) UpdateBranch(ctx context.Context, db *gorm.DB, branch *models.Branch) error {
+	if err := db.WithContext(ctx).Omit("Locations").Save(branch).Error; err != nil {
+		return err
+	}
+	return nil
+}
+func (repository *BranchRepositoryImpl) DeleteBranch(ctx context.Context, db *gorm.DB, id int) error {
+	result := db.WithContext(ctx).Delete(&models.Branch{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("branch not found")
+	}
+	return nil
+}
+func (repository *BranchRepositoryImpl) FindShelfLocationById(ctx context.Context, db *gorm.DB, id int) (*models.ShelfLocation, error) {
+	var location models.ShelfLocation
+	if err := db.WithContext(ctx).Preload("Branch").First(&location, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("shelf location not found")
+		}
+		return nil, err
+	}
+	return &location, nil
+}
+func (repository *BranchRepositoryImpl) CreateShelfLocation(ctx context.Context, db *gorm.DB, location *models.ShelfLocation) error {
+	if err := db.WithContext(ctx).Omit("Branch").Create(location).Error; err != nil {
+		return err
+	}
+	return nil
+}
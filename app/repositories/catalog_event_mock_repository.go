@@ -0,0 +1,47 @@
+package repositories
+
+import (
+	"context"
+	"golang-backend-test/app/models"
+
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockCatalogEventRepository struct {
+	mock.Mock
+}
+
+func (mock *MockCatalogEventRepository) FindEventById(ctx context.Context, db *gorm.DB, id uint) (*models.CatalogEvent, error) {
+	args := mock.Called(ctx, db, id)
+	if event, ok := args.Get(0).(*models.CatalogEvent); ok {
+		return event, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (mock *MockCatalogEventRepository) CreateEvent(ctx context.Context, db *gorm.DB, event *models.CatalogEvent) error {
+	args := mock.Called(ctx, db, event)
+	return args.Error(0)
+}
+
+func (mock *MockCatalogEventRepository) GetEventsAfter(ctx context.Context, db *gorm.DB, id uint, entity string, limit int) ([]*models.CatalogEvent, error) {
+	args := mock.Called(ctx, db, id, entity, limit)
+	if events, ok := args.Get(0).([]*models.CatalogEvent); ok {
+		return events, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (mock *MockCatalogEventRepository) FindOldestEvent(ctx context.Context, db *gorm.DB) (*models.CatalogEvent, error) {
+	args := mock.Called(ctx, db)
+	if event, ok := args.Get(0).(*models.CatalogEvent); ok {
+		return event, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (mock *MockCatalogEventRepository) PruneEvents(ctx context.Context, db *gorm.DB, keep int) error {
+	args := mock.Called(ctx, db, keep)
+	return args.Error(0)
+}
@@ -0,0 +1,77 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"golang-backend-test/app/models"
+
+	"gorm.io/gorm"
+)
+
+type CatalogEventRepository interface {
+	FindEventById(ctx context.Context, db *gorm.DB, id uint) (*models.CatalogEvent, error)
+	CreateEvent(ctx context.Context, db *gorm.DB, event *models.CatalogEvent) error
+	GetEventsAfter(ctx context.Context, db *gorm.DB, id uint, entity string, limit int) ([]*models.CatalogEvent, error)
+	FindOldestEvent(ctx context.Context, db *gorm.DB) (*models.CatalogEvent, error)
+	PruneEvents(ctx context.Context, db *gorm.DB, keep int) error
+}
+
+type CatalogEventRepositoryImpl struct {
+}
+
+func NewCatalogEventRepository() CatalogEventRepository {
+	return &CatalogEventRepositoryImpl{}
+}
+
+func (repository *CatalogEventRepositoryImpl) FindEventById(ctx context.Context, db *gorm.DB, id uint) (*models.CatalogEvent, error) {
+	var event models.CatalogEvent
+	if err := db.WithContext(ctx).First(&event, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("catalog event not found")
+		}
+		return nil, err
+	}
+	return &event, nil
+}
+func (repository *CatalogEventRepositoryImpl) CreateEvent(ctx context.Context, db *gorm.DB, event *models.CatalogEvent) error {
+	if err := db.WithContext(ctx).Create(event).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetEventsAfter returns the events that came after the one with id, oldest
+// first. An empty entity matches every entity.
+func (repository *CatalogEventRepositoryImpl) GetEventsAfter(ctx context.Context, db *gorm.DB, id uint, entity string, limit int) ([]*models.CatalogEvent, error) {
+	var events []*models.CatalogEvent
+	query := db.WithContext(ctx).Where("id > ?", id)
+	if entity != "" {
+		query = query.Where("entity = ?", entity)
+	}
+	if err := query.Order("id").Limit(limit).Find(&events).Error; err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+func (repository *CatalogEventRepositoryImpl) FindOldestEvent(ctx context.Context, db *gorm.DB) (*models.CatalogEvent, error) {
+	var event models.CatalogEvent
+	if err := db.WithContext(ctx).Order("id").First(&event).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("catalog event not found")
+		}
+		return nil, err
+	}
+	return &event, nil
+}
+
+// PruneEvents deletes all but the newest keep events.
+func (repository *CatalogEventRepositoryImpl) PruneEvents(ctx context.Context, db *gorm.DB, keep int) error {
+	var ids []uint
+	if err := db.WithContext(ctx).Model(&models.CatalogEvent{}).Order("id DESC").Offset(keep).Limit(1).Pluck("id", &ids).Error; err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	return db.WithContext(ctx).Where("id <= ?", ids[0]).Delete(&models.CatalogEvent{}).Error
+}
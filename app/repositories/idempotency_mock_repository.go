@@ -0,0 +1,42 @@
+package repositories
+
+import (
+	"context"
+	"golang-backend-test/app/models"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockIdempotencyRepository struct {
+	mock.Mock
+}
+
+func (mock *MockIdempotencyRepository) FindIdempotencyKey(ctx context.Context, db *gorm.DB, userId int, key string) (*models.IdempotencyKey, error) {
+	args := mock.Called(ctx, db, userId, key)
+	if idempotencyKey, ok := args.Get(0).(*models.IdempotencyKey); ok {
+		return idempotencyKey, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (mock *MockIdempotencyRepository) CreateIdempotencyKey(ctx context.Context, db *gorm.DB, idempotencyKey *models.IdempotencyKey) error {
+	args := mock.Called(ctx, db, idempotencyKey)
+	return args.Error(0)
+}
+
+func (mock *MockIdempotencyRepository) UpdateIdempotencyKey(ctx context.Context, db *gorm.DB, idempotencyKey *models.IdempotencyKey) error {
+	args := mock.Called(ctx, db, idempotencyKey)
+	return args.Error(0)
+}
+
+func (mock *MockIdempotencyRepository) DeleteIdempotencyKey(ctx context.Context, db *gorm.DB, id uint) error {
+	args := mock.Called(ctx, db, id)
+	return args.Error(0)
+}
+
+func (mock *MockIdempotencyRepository) DeleteExpiredIdempotencyKeys(ctx context.Context, db *gorm.DB, now time.Time) error {
+	args := mock.Called(ctx, db, now)
+	return args.Error(0)
+}
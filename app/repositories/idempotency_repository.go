@@ -0,0 +1,60 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"golang-backend-test/app/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type IdempotencyRepository interface {
+	FindIdempotencyKey(ctx context.Context, db *gorm.DB, userId int, key string) (*models.IdempotencyKey, error)
+	CreateIdempotencyKey(ctx context.Context, db *gorm.DB, idempotencyKey *models.IdempotencyKey) error
+	UpdateIdempotencyKey(ctx context.Context, db *gorm.DB, idempotencyKey *models.IdempotencyKey) error
+	DeleteIdempotencyKey(ctx context.Context, db *gorm.DB, id uint) error
+	DeleteExpiredIdempotencyKeys(ctx context.Context, db *gorm.DB, now time.Time) error
+}
+
+type IdempotencyRepositoryImpl struct {
+}
+
+func NewIdempotencyRepository() IdempotencyRepository {
+	return &IdempotencyRepositoryImpl{}
+}
+
+func (repository *IdempotencyRepositoryImpl) FindIdempotencyKey(ctx context.Context, db *gorm.DB, userId int, key string) (*models.IdempotencyKey, error) {
+	var idempotencyKey models.IdempotencyKey
+	if err := db.WithContext(ctx).Where("user_id = ? AND key = ?", userId, key).First(&idempotencyKey).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("idempotency key not found")
+		}
+		return nil, err
+	}
+	return &idempotencyKey, nil
+}
+func (repository *IdempotencyRepositoryImpl) CreateIdempotencyKey(ctx context.Context, db *gorm.DB, idempotencyKey *models.IdempotencyKey) error {
+	if err := db.WithContext(ctx).Create(idempotencyKey).Error; err != nil {
+		return err
+	}
+	return nil
+}
+func (repository *IdempotencyRepositoryImpl) UpdateIdempotencyKey(ctx context.Context, db *gorm.DB, idempotencyKey *models.IdempotencyKey) error {
+	if err := db.WithContext(ctx).Save(idempotencyKey).Error; err != nil {
+		return err
+	}
+	return nil
+}
+func (repository *IdempotencyRepositoryImpl) DeleteIdempotencyKey(ctx context.Context, db *gorm.DB, id uint) error {
+	if err := db.WithContext(ctx).Delete(&models.IdempotencyKey{}, id).Error; err != nil {
+		return err
+	}
+	return nil
+}
+func (repository *IdempotencyRepositoryImpl) DeleteExpiredIdempotencyKeys(ctx context.Context, db *gorm.DB, now time.Time) error {
+	if err := db.WithContext(ctx).Where("expires_at <= ?", now).Delete(&models.IdempotencyKey{}).Error; err != nil {
+		return err
+	}
+	return nil
+}
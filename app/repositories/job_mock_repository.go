@@ -0,0 +1,53 @@
+package repositories
+
+import (
+	"context"
+	"golang-backend-test/app/models"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockJobRepository struct {
+	mock.Mock
+}
+
+func (mock *MockJobRepository) FindJobById(ctx context.Context, db *gorm.DB, id int) (*models.Job, error) {
+	args := mock.Called(ctx, db, id)
+	if job, ok := args.Get(0).(*models.Job); ok {
+		return job, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (mock *MockJobRepository) GetListJobs(ctx context.Context, db *gorm.DB, status, queue string, limit int) ([]*models.Job, error) {
+	args := mock.Called(ctx, db, status, queue, limit)
+	if jobs, ok := args.Get(0).([]*models.Job); ok {
+		return jobs, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (mock *MockJobRepository) CreateJob(ctx context.Context, db *gorm.DB, job *models.Job) error {
+	args := mock.Called(ctx, db, job)
+	return args.Error(0)
+}
+
+func (mock *MockJobRepository) UpdateJob(ctx context.Context, db *gorm.DB, job *models.Job, status string) error {
+	args := mock.Called(ctx, db, job, status)
+	return args.Error(0)
+}
+
+func (mock *MockJobRepository) ClaimJob(ctx context.Context, db *gorm.DB, queue string, now time.Time) (*models.Job, error) {
+	args := mock.Called(ctx, db, queue, now)
+	if job, ok := args.Get(0).(*models.Job); ok {
+		return job, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (mock *MockJobRepository) RequeueRunningJobs(ctx context.Context, db *gorm.DB) error {
+	args := mock.Called(ctx, db)
+	return args.Error(0)
+}
@@ -0,0 +1,112 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"golang-backend-test/app/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type JobRepository interface {
+	FindJobById(ctx context.Context, db *gorm.DB, id int) (*models.Job, error)
+	GetListJobs(ctx context.Context, db *gorm.DB, status, queue string, limit int) ([]*models.Job, error)
+	CreateJob(ctx context.Context, db *gorm.DB, job *models.Job) error
+	UpdateJob(ctx context.Context, db *gorm.DB, job *models.Job, status string) error
+	ClaimJob(ctx context.Context, db *gorm.DB, queue string, now time.Time) (*models.Job, error)
+	RequeueRunningJobs(ctx context.Context, db *gorm.DB) error
+}
+
+type JobRepositoryImpl struct {
+}
+
+func NewJobRepository() JobRepository {
+	return &JobRepositoryImpl{}
+}
+
+func (repository *JobRepositoryImpl) FindJobById(ctx context.Context, db *gorm.DB, id int) (*models.Job, error) {
+	var job models.Job
+	if err := db.WithContext(ctx).First(&job, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("job not found")
+		}
+		return nil, err
+	}
+	return &job, nil
+}
+
+// GetListJobs lists the newest jobs, with the given status and queue or any
+// of them when they are empty.
+func (repository *JobRepositoryImpl) GetListJobs(ctx context.Context, db *gorm.DB, status, queue string, limit int) ([]*models.Job, error) {
+	var jobs []*models.Job
+	query := db.WithContext(ctx)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if queue != "" {
+		query = query.Where("queue = ?", queue)
+	}
+	if err := query.Order("id DESC").Limit(limit).Find(&jobs).Error; err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+func (repository *JobRepositoryImpl) CreateJob(ctx context.Context, db *gorm.DB, job *models.Job) error {
+	if err := db.WithContext(ctx).Create(job).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// UpdateJob saves job only while it still has status in the database, so
+// that a worker finishing a job does not undo a cancel that came meanwhile.
+func (repository *JobRepositoryImpl) UpdateJob(ctx context.Context, db *gorm.DB, job *models.Job, status string) error {
+	result := db.WithContext(ctx).Model(job).Where("status = ?", status).Select("*").Omit("created_at").Updates(job)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("job not found")
+	}
+	return nil
+}
+
+// ClaimJob moves the pending job of queue that is due first to running and
+// returns it, or nil when there is none. A job another worker claimed
+// between the read and the update is left to it.
+func (repository *JobRepositoryImpl) ClaimJob(ctx context.Context, db *gorm.DB, queue string, now time.Time) (*models.Job, error) {
+	var job models.Job
+	err := db.WithContext(ctx).
+		Where("queue = ? AND status = ? AND run_at <= ?", queue, models.JobPending, now).
+		Order("run_at, id").
+		First(&job).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	job.Status = models.JobRunning
+	job.Attempts++
+	job.StartedAt = &now
+	result := db.WithContext(ctx).Model(&job).Where("status = ?", models.JobPending).Updates(map[string]interface{}{
+		"status":     job.Status,
+		"attempts":   job.Attempts,
+		"started_at": now,
+	})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &job, nil
+}
+
+// RequeueRunningJobs makes the jobs left running by a worker that stopped
+// before finishing them pending again.
+func (repository *JobRepositoryImpl) RequeueRunningJobs(ctx context.Context, db *gorm.DB) error {
+	return db.WithContext(ctx).Model(&models.Job{}).Where("status = ?", models.JobRunning).Update("status", models.JobPending).Error
+}
@@ -0,0 +1,81 @@
+package repositories
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// KeysetOrder sorts a list on Column with id breaking ties, or on id alone
+// when Column is empty. NULLs sort the way SQLite puts them: first when
+// ascending and last when descending.
+type KeysetOrder struct {
+	Column string
+	Desc   bool
+}
+
+// KeysetPage asks for up to Limit rows after the row with sort key Value and
+// ID, or before it when Before is set. A zero ID asks for the first page.
+type KeysetPage struct {
+	Order  KeysetOrder
+	Value  interface{}
+	ID     uint
+	Before bool
+	Limit  int
+}
+
+// Clause is the ORDER BY of the list.
+func (order KeysetOrder) Clause() string {
+	direction := "ASC"
+	if order.Desc {
+		direction = "DESC"
+	}
+	if order.Column == "" {
+		return "id " + direction
+	}
+	return fmt.Sprintf("%s %s, id %s", order.Column, direction, direction)
+}
+
+// keysetQuery limits query to the page, reading one row more than asked for
+// to tell whether more rows follow. Pages before a position are read in
+// reverse and put back in order by keysetRows.
+func keysetQuery(query *gorm.DB, page KeysetPage) *gorm.DB {
+	order := page.Order
+	order.Desc = order.Desc != page.Before
+	cmp := ">"
+	if order.Desc {
+		cmp = "<"
+	}
+
+	if page.ID != 0 {
+		column := order.Column
+		switch {
+		case column == "":
+			query = query.Where("id "+cmp+" ?", page.ID)
+		case page.Value == nil && order.Desc:
+			query = query.Where(fmt.Sprintf("(%s IS NULL AND id %s ?)", column, cmp), page.ID)
+		case page.Value == nil:
+			query = query.Where(fmt.Sprintf("((%s IS NULL AND id %s ?) OR %s IS NOT NULL)", column, cmp, column), page.ID)
+		case order.Desc:
+			query = query.Where(fmt.Sprintf("(%s %s ? OR (%s = ? AND id %s ?) OR %s IS NULL)", column, cmp, column, cmp, column), page.Value, page.Value, page.ID)
+		default:
+			query = query.Where(fmt.Sprintf("(%s %s ? OR (%s = ? AND id %s ?))", column, cmp, column, cmp), page.Value, page.Value, page.ID)
+		}
+	}
+	return query.Order(order.Clause()).Limit(page.Limit + 1)
+}
+
+// keysetRows trims the extra row read by keysetQuery and returns the rows in
+// list order, with whether more rows lie beyond the page.
+func keysetRows[T any](rows []T, page KeysetPage) ([]T, bool) {
+	more := len(rows) > page.Limit
+	if more {
+		rows = rows[:page.Limit]
+	}
+	if page.Before {
+		for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+			rows[i], rows[j] = rows[j], rows[i]
+		}
+	}
+	return rows, more
+}
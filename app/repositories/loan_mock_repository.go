@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"context"
+	"golang-backend-test/app/models"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockLoanRepository struct {
+	mock.Mock
+}
+
+func (mock *MockLoanRepository) FindLoanById(ctx context.Context, db *gorm.DB, id int) (*models.Loan, error) {
+	args := mock.Called(ctx, db, id)
+	if loan, ok := args.Get(0).(*models.Loan); ok {
+		return loan, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (mock *MockLoanRepository) HasOpenLoan(ctx context.Context, db *gorm.DB, bookCopyId int) (bool, error) {
+	args := mock.Called(ctx, db, bookCopyId)
+	return args.Bool(0), args.Error(1)
+}
+
+func (mock *MockLoanRepository) CreateLoan(ctx context.Context, db *gorm.DB, loan *models.Loan) error {
+	args := mock.Called(ctx, db, loan)
+	return args.Error(0)
+}
+
+func (mock *MockLoanRepository) ReturnLoan(ctx context.Context, db *gorm.DB, loan *models.Loan) error {
+	args := mock.Called(ctx, db, loan)
+	return args.Error(0)
+}
+
+func (mock *MockLoanRepository) GetOverdueLoans(ctx context.Context, db *gorm.DB, now time.Time) ([]*models.Loan, error) {
+	args := mock.Called(ctx, db, now)
+	if loans, ok := args.Get(0).([]*models.Loan); ok {
+		return loans, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (mock *MockLoanRepository) UpdateLoan(ctx context.Context, db *gorm.DB, loan *models.Loan) error {
+	args := mock.Called(ctx, db, loan)
+	return args.Error(0)
+}
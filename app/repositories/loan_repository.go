@@ -0,0 +1,90 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"golang-backend-test/app/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type LoanRepository interface {
+	FindLoanById(ctx context.Context, db *gorm.DB, id int) (*models.Loan, error)
+	HasOpenLoan(ctx context.Context, db *gorm.DB, bookCopyId int) (bool, error)
+	CreateLoan(ctx context.Context, db *gorm.DB, loan *models.Loan) error
+	ReturnLoan(ctx context.Context, db *gorm.DB, loan *models.Loan) error
+	GetOverdueLoans(ctx context.Context, db *gorm.DB, now time.Time) ([]*models.Loan, error)
+	UpdateLoan(ctx context.Context, db *gorm.DB, loan *models.Loan) error
+}
+
+type LoanRepositoryImpl struct {
+}
+
+func NewLoanRepository() LoanRepository {
+	return &LoanRepositoryImpl{}
+}
+
+func (repository *LoanRepositoryImpl) FindLoanById(ctx context.Context, db *gorm.DB, id int) (*models.Loan, error) {
+	var loan models.Loan
+	if err := db.WithContext(ctx).First(&loan, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("loan not found")
+		}
+		return nil, err
+	}
+	return &loan, nil
+}
+
+// HasOpenLoan tells whether the copy is out on a loan not returned yet.
+func (repository *LoanRepositoryImpl) HasOpenLoan(ctx context.Context, db *gorm.DB, bookCopyId int) (bool, error) {
+	var count int64
+	if err := db.WithContext(ctx).Model(&models.Loan{}).
+		Where("book_copy_id = ? AND returned_at IS NULL", bookCopyId).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (repository *LoanRepositoryImpl) CreateLoan(ctx context.Context, db *gorm.DB, loan *models.Loan) error {
+	if err := db.WithContext(ctx).Create(loan).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// ReturnLoan records the return of loan, provided it was not returned
+// meanwhile.
+func (repository *LoanRepositoryImpl) ReturnLoan(ctx context.Context, db *gorm.DB, loan *models.Loan) error {
+	result := db.WithContext(ctx).Model(&models.Loan{}).
+		Where("id = ? AND returned_at IS NULL", loan.ID).
+		Update("returned_at", loan.ReturnedAt)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("loan not found")
+	}
+	return nil
+}
+
+// GetOverdueLoans returns the loans past their due date that are not back
+// yet and were not reported overdue before.
+func (repository *LoanRepositoryImpl) GetOverdueLoans(ctx context.Context, db *gorm.DB, now time.Time) ([]*models.Loan, error) {
+	var loans []*models.Loan
+	if err := db.WithContext(ctx).
+		Where("due_at < ? AND returned_at IS NULL AND overdue_notified_at IS NULL", now).
+		Order("due_at, id").
+		Find(&loans).Error; err != nil {
+		return nil, err
+	}
+	return loans, nil
+}
+
+func (repository *LoanRepositoryImpl) UpdateLoan(ctx context.Context, db *gorm.DB, loan *models.Loan) error {
+	if err := db.WithContext(ctx).Save(loan).Error; err != nil {
+		return err
+	}
+	return nil
+}
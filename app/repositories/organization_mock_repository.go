@@ -0,0 +1,60 @@
+package repositories
+
+import (
+	"context"
+	"golang-backend-test/app/models"
+
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockOrganizationRepository struct {
+	mock.Mock
+}
+
+func (mock *MockOrganizationRepository) FindOrganizationById(ctx context.Context, db *gorm.DB, id int) (*models.Organization, error) {
+	args := mock.Called(ctx, db, id)
+	if organization, ok := args.Get(0).(*models.Organization); ok {
+		return organization, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (mock *MockOrganizationRepository) FindOrganizationBySlug(ctx context.Context, db *gorm.DB, slug string) (*models.Organization, error) {
+	args := mock.Called(ctx, db, slug)
+	if organization, ok := args.Get(0).(*models.Organization); ok {
+		return organization, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (mock *MockOrganizationRepository) CreateOrganization(ctx context.Context, db *gorm.DB, organization *models.Organization) error {
+	args := mock.Called(ctx, db, organization)
+	return args.Error(0)
+}
+
+func (mock *MockOrganizationRepository) GetListOrganizations(ctx context.Context, db *gorm.DB) ([]*models.Organization, error) {
+	args := mock.Called(ctx, db)
+	if organizations, ok := args.Get(0).([]*models.Organization); ok {
+		return organizations, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (mock *MockOrganizationRepository) CreateInvite(ctx context.Context, db *gorm.DB, invite *models.Invite) error {
+	args := mock.Called(ctx, db, invite)
+	return args.Error(0)
+}
+
+func (mock *MockOrganizationRepository) FindInviteByToken(ctx context.Context, db *gorm.DB, token string) (*models.Invite, error) {
+	args := mock.Called(ctx, db, token)
+	if invite, ok := args.Get(0).(*models.Invite); ok {
+		return invite, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (mock *MockOrganizationRepository) UseInvite(ctx context.Context, db *gorm.DB, invite *models.Invite) error {
+	args := mock.Called(ctx, db, invite)
+	return args.Error(0)
+}
@@ -0,0 +1,93 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"golang-backend-test/app/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type OrganizationRepository interface {
+	FindOrganizationById(ctx context.Context, db *gorm.DB, id int) (*models.Organization, error)
+	FindOrganizationBySlug(ctx context.Context, db *gorm.DB, slug string) (*models.Organization, error)
+	CreateOrganization(ctx context.Context, db *gorm.DB, organization *models.Organization) error
+	GetListOrganizations(ctx context.Context, db *gorm.DB) ([]*models.Organization, error)
+	CreateInvite(ctx context.Context, db *gorm.DB, invite *models.Invite) error
+	FindInviteByToken(ctx context.Context, db *gorm.DB, token string) (*models.Invite, error)
+	UseInvite(ctx context.Context, db *gorm.DB, invite *models.Invite) error
+}
+
+type OrganizationRepositoryImpl struct {
+}
+
+func NewOrganizationRepository() OrganizationRepository {
+	return &OrganizationRepositoryImpl{}
+}
+
+func (repository *OrganizationRepositoryImpl) FindOrganizationById(ctx context.Context, db *gorm.DB, id int) (*models.Organization, error) {
+	var organization models.Organization
+	if err := db.WithContext(ctx).First(&organization, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("organization not found")
+		}
+		return nil, err
+	}
+	return &organization, nil
+}
+func (repository *OrganizationRepositoryImpl) FindOrganizationBySlug(ctx context.Context, db *gorm.DB, slug string) (*models.Organization, error) {
+	var organization models.Organization
+	if err := db.WithContext(ctx).Where("slug = ?", slug).First(&organization).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("organization not found")
+		}
+		return nil, err
+	}
+	return &organization, nil
+}
+func (repository *OrganizationRepositoryImpl) CreateOrganization(ctx context.Context, db *gorm.DB, organization *models.Organization) error {
+	if err := db.WithContext(ctx).Create(organization).Error; err != nil {
+		return err
+	}
+	return nil
+}
+func (repository *OrganizationRepositoryImpl) GetListOrganizations(ctx context.Context, db *gorm.DB) ([]*models.Organization, error) {
+	var organizations []*models.Organization
+	if err := db.WithContext(ctx).Order("id").Find(&organizations).Error; err != nil {
+		return nil, err
+	}
+	return organizations, nil
+}
+func (repository *OrganizationRepositoryImpl) CreateInvite(ctx context.Context, db *gorm.DB, invite *models.Invite) error {
+	if err := db.WithContext(ctx).Create(invite).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// FindInviteByToken finds the invite with token that is neither used nor
+// expired.
+func (repository *OrganizationRepositoryImpl) FindInviteByToken(ctx context.Context, db *gorm.DB, token string) (*models.Invite, error) {
+	var invite models.Invite
+	if err := db.WithContext(ctx).Where("token = ? AND used_at IS NULL AND expires_at > ?", token, time.Now()).First(&invite).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("invite not found")
+		}
+		return nil, err
+	}
+	return &invite, nil
+}
+
+// UseInvite marks invite used, unless it was used meanwhile.
+func (repository *OrganizationRepositoryImpl) UseInvite(ctx context.Context, db *gorm.DB, invite *models.Invite) error {
+	now := time.Now()
+	result := db.WithContext(ctx).Model(invite).Where("used_at IS NULL").Update("used_at", &now)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("invite not found")
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package repositories
+
+import (
+	"context"
+	"golang-backend-test/app/models"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockOutboxRepository struct {
+	mock.Mock
+}
+
+func (mock *MockOutboxRepository) CreateEvent(ctx context.Context, db *gorm.DB, event *models.OutboxEvent) error {
+	args := mock.Called(ctx, db, event)
+	return args.Error(0)
+}
+
+func (mock *MockOutboxRepository) GetDueEvents(ctx context.Context, db *gorm.DB, now time.Time, limit int) ([]*models.OutboxEvent, error) {
+	args := mock.Called(ctx, db, now, limit)
+	if events, ok := args.Get(0).([]*models.OutboxEvent); ok {
+		return events, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (mock *MockOutboxRepository) UpdateEvent(ctx context.Context, db *gorm.DB, event *models.OutboxEvent) error {
+	args := mock.Called(ctx, db, event)
+	return args.Error(0)
+}
+
+func (mock *MockOutboxRepository) DeletePublishedEvents(ctx context.Context, db *gorm.DB, before time.Time) error {
+	args := mock.Called(ctx, db, before)
+	return args.Error(0)
+}
@@ -0,0 +1,53 @@
+package repositories
+
+import (
+	"context"
+	"golang-backend-test/app/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type OutboxRepository interface {
+	CreateEvent(ctx context.Context, db *gorm.DB, event *models.OutboxEvent) error
+	GetDueEvents(ctx context.Context, db *gorm.DB, now time.Time, limit int) ([]*models.OutboxEvent, error)
+	UpdateEvent(ctx context.Context, db *gorm.DB, event *models.OutboxEvent) error
+	DeletePublishedEvents(ctx context.Context, db *gorm.DB, before time.Time) error
+}
+
+type OutboxRepositoryImpl struct {
+}
+
+func NewOutboxRepository() OutboxRepository {
+	return &OutboxRepositoryImpl{}
+}
+
+func (repository *OutboxRepositoryImpl) CreateEvent(ctx context.Context, db *gorm.DB, event *models.OutboxEvent) error {
+	if err := db.WithContext(ctx).Create(event).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetDueEvents returns the events that are not published yet, oldest first,
+// leaving out the aggregates with an event waiting for a retry after now:
+// their later events wait behind it, and would otherwise fill the batch.
+func (repository *OutboxRepositoryImpl) GetDueEvents(ctx context.Context, db *gorm.DB, now time.Time, limit int) ([]*models.OutboxEvent, error) {
+	var events []*models.OutboxEvent
+	waiting := db.Table("outbox_events AS waiting").Select("1").
+		Where("waiting.tenant_id = outbox_events.tenant_id AND waiting.aggregate = outbox_events.aggregate AND waiting.aggregate_id = outbox_events.aggregate_id").
+		Where("waiting.id <= outbox_events.id AND waiting.published_at IS NULL AND waiting.next_attempt_at > ?", now)
+	if err := db.WithContext(ctx).Where("published_at IS NULL AND NOT EXISTS (?)", waiting).Order("id").Limit(limit).Find(&events).Error; err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+func (repository *OutboxRepositoryImpl) UpdateEvent(ctx context.Context, db *gorm.DB, event *models.OutboxEvent) error {
+	if err := db.WithContext(ctx).Save(event).Error; err != nil {
+		return err
+	}
+	return nil
+}
+func (repository *OutboxRepositoryImpl) DeletePublishedEvents(ctx context.Context, db *gorm.DB, before time.Time) error {
+	return db.WithContext(ctx).Where("published_at < ?", before).Delete(&models.OutboxEvent{}).Error
+}
@@ -0,0 +1,54 @@
+package repositories
+
+import (
+	"context"
+	"golang-backend-test/app/models"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockReportRepository struct {
+	mock.Mock
+}
+
+func (mock *MockReportRepository) BooksPerAuthor(ctx context.Context, db *gorm.DB, from, to time.Time) ([]*BooksPerAuthorRow, error) {
+	args := mock.Called(ctx, db, from, to)
+	if rows, ok := args.Get(0).([]*BooksPerAuthorRow); ok {
+		return rows, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (mock *MockReportRepository) BooksWithoutISBN(ctx context.Context, db *gorm.DB, from, to time.Time) ([]*models.Book, error) {
+	args := mock.Called(ctx, db, from, to)
+	if books, ok := args.Get(0).([]*models.Book); ok {
+		return books, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (mock *MockReportRepository) AuthorsWithoutBooks(ctx context.Context, db *gorm.DB, from, to time.Time) ([]*models.Author, error) {
+	args := mock.Called(ctx, db, from, to)
+	if authors, ok := args.Get(0).([]*models.Author); ok {
+		return authors, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (mock *MockReportRepository) BorrowedBooks(ctx context.Context, db *gorm.DB, from, to time.Time, ascending bool, limit int) ([]*BorrowedBookRow, error) {
+	args := mock.Called(ctx, db, from, to, ascending, limit)
+	if rows, ok := args.Get(0).([]*BorrowedBookRow); ok {
+		return rows, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (mock *MockReportRepository) AcquisitionsPerMonth(ctx context.Context, db *gorm.DB, from, to time.Time) ([]*AcquisitionRow, error) {
+	args := mock.Called(ctx, db, from, to)
+	if rows, ok := args.Get(0).([]*AcquisitionRow); ok {
+		return rows, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
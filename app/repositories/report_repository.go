@@ -0,0 +1,112 @@
+package repositories
+
+import (
+	"context"
+	"golang-backend-test/app/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ReportRepository interface {
+	BooksPerAuthor(ctx context.Context, db *gorm.DB, from, to time.Time) ([]*BooksPerAuthorRow, error)
+	BooksWithoutISBN(ctx context.Context, db *gorm.DB, from, to time.Time) ([]*models.Book, error)
+	AuthorsWithoutBooks(ctx context.Context, db *gorm.DB, from, to time.Time) ([]*models.Author, error)
+	BorrowedBooks(ctx context.Context, db *gorm.DB, from, to time.Time, ascending bool, limit int) ([]*BorrowedBookRow, error)
+	AcquisitionsPerMonth(ctx context.Context, db *gorm.DB, from, to time.Time) ([]*AcquisitionRow, error)
+}
+
+type BooksPerAuthorRow struct {
+	AuthorID   uint
+	AuthorName string
+	TotalBooks int64
+}
+
+type BorrowedBookRow struct {
+	BookID     uint
+	Title      string
+	ISBN       string
+	TotalLoans int64
+}
+
+type AcquisitionRow struct {
+	Month       string
+	TotalCopies int64
+	TotalTitles int64
+}
+
+type ReportRepositoryImpl struct {
+}
+
+func NewReportRepository() ReportRepository {
+	return &ReportRepositoryImpl{}
+}
+
+func (repository *ReportRepositoryImpl) BooksPerAuthor(ctx context.Context, db *gorm.DB, from, to time.Time) ([]*BooksPerAuthorRow, error) {
+	var rows []*BooksPerAuthorRow
+	if err := db.WithContext(ctx).Table("authors").
+		Select("authors.id AS author_id, authors.name AS author_name, COUNT(books.id) AS total_books").
+		Joins("JOIN books ON books.author_id = authors.id").
+		Where("books.created_at >= ? AND books.created_at < ?", from, to).
+		Group("authors.id, authors.name").
+		Order("total_books DESC, authors.id ASC").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+func (repository *ReportRepositoryImpl) BooksWithoutISBN(ctx context.Context, db *gorm.DB, from, to time.Time) ([]*models.Book, error) {
+	var books []*models.Book
+	if err := db.WithContext(ctx).Preload("Author").
+		Where("isbn IS NULL OR isbn = ''").
+		Where("created_at >= ? AND created_at < ?", from, to).
+		Order("id ASC").
+		Find(&books).Error; err != nil {
+		return nil, err
+	}
+	return books, nil
+}
+func (repository *ReportRepositoryImpl) AuthorsWithoutBooks(ctx context.Context, db *gorm.DB, from, to time.Time) ([]*models.Author, error) {
+	var authors []*models.Author
+	db = db.WithContext(ctx)
+	books := db.Table("books").Select("1").
+		Where("books.author_id = authors.id").
+		Where("books.created_at >= ? AND books.created_at < ?", from, to)
+	if err := db.
+		Where("NOT EXISTS (?)", books).
+		Order("id ASC").
+		Find(&authors).Error; err != nil {
+		return nil, err
+	}
+	return authors, nil
+}
+func (repository *ReportRepositoryImpl) BorrowedBooks(ctx context.Context, db *gorm.DB, from, to time.Time, ascending bool, limit int) ([]*BorrowedBookRow, error) {
+	var rows []*BorrowedBookRow
+	order := "total_loans DESC, books.id ASC"
+	if ascending {
+		order = "total_loans ASC, books.id ASC"
+	}
+	if err := db.WithContext(ctx).Table("books").
+		Select("books.id AS book_id, books.title AS title, books.isbn AS isbn, COUNT(loans.id) AS total_loans").
+		Joins("LEFT JOIN book_copies ON book_copies.book_id = books.id").
+		Joins("LEFT JOIN loans ON loans.book_copy_id = book_copies.id AND loans.borrowed_at >= ? AND loans.borrowed_at < ?", from, to).
+		Group("books.id, books.title, books.isbn").
+		Order(order).
+		Limit(limit).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+func (repository *ReportRepositoryImpl) AcquisitionsPerMonth(ctx context.Context, db *gorm.DB, from, to time.Time) ([]*AcquisitionRow, error) {
+	var rows []*AcquisitionRow
+	if err := db.WithContext(ctx).Table("book_copies").
+		Select("SUBSTR(book_copies.created_at, 1, 7) AS month, COUNT(book_copies.id) AS total_copies, COUNT(DISTINCT book_copies.book_id) AS total_titles").
+		Where("book_copies.created_at >= ? AND book_copies.created_at < ?", from, to).
+		Group("month").
+		Order("month ASC").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
@@ -0,0 +1,75 @@
+package repositories
+
+import (
+	"context"
+	"golang-backend-test/app/models"
+
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockStocktakeRepository struct {
+	mock.Mock
+}
+
+func (mock *MockStocktakeRepository) FindStocktakeById(ctx context.Context, db *gorm.DB, id int) (*models.Stocktake, error) {
+	args := mock.Called(ctx, db, id)
+	if stocktake, ok := args.Get(0).(*models.Stocktake); ok {
+		return stocktake, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (mock *MockStocktakeRepository) GetOpenStocktakes(ctx context.Context, db *gorm.DB, branchId int) ([]*models.Stocktake, error) {
+	args := mock.Called(ctx, db, branchId)
+	if stocktakes, ok := args.Get(0).([]*models.Stocktake); ok {
+		return stocktakes, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (mock *MockStocktakeRepository) CreateStocktake(ctx context.Context, db *gorm.DB, stocktake *models.Stocktake) error {
+	args := mock.Called(ctx, db, stocktake)
+	return args.Error(0)
+}
+
+func (mock *MockStocktakeRepository) UpdateStocktake(ctx context.Context, db *gorm.DB, stocktake *models.Stocktake, status string) error {
+	args := mock.Called(ctx, db, stocktake, status)
+	return args.Error(0)
+}
+
+func (mock *MockStocktakeRepository) CountStocktakeScans(ctx context.Context, db *gorm.DB, stocktakeId int) (int64, error) {
+	args := mock.Called(ctx, db, stocktakeId)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (mock *MockStocktakeRepository) GetStocktakeScans(ctx context.Context, db *gorm.DB, stocktakeId int) ([]*models.StocktakeScan, error) {
+	args := mock.Called(ctx, db, stocktakeId)
+	if scans, ok := args.Get(0).([]*models.StocktakeScan); ok {
+		return scans, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (mock *MockStocktakeRepository) CreateStocktakeScans(ctx context.Context, db *gorm.DB, scans []*models.StocktakeScan) error {
+	args := mock.Called(ctx, db, scans)
+	return args.Error(0)
+}
+
+func (mock *MockStocktakeRepository) GetStocktakeDiscrepancies(ctx context.Context, db *gorm.DB, stocktakeId int) ([]*models.StocktakeDiscrepancy, error) {
+	args := mock.Called(ctx, db, stocktakeId)
+	if discrepancies, ok := args.Get(0).([]*models.StocktakeDiscrepancy); ok {
+		return discrepancies, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (mock *MockStocktakeRepository) CreateStocktakeDiscrepancies(ctx context.Context, db *gorm.DB, discrepancies []*models.StocktakeDiscrepancy) error {
+	args := mock.Called(ctx, db, discrepancies)
+	return args.Error(0)
+}
+
+func (mock *MockStocktakeRepository) UpdateStocktakeDiscrepancy(ctx context.Context, db *gorm.DB, discrepancy *models.StocktakeDiscrepancy) error {
+	args := mock.Called(ctx, db, discrepancy)
+	return args.Error(0)
+}
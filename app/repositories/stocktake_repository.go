@@ -0,0 +1,115 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"golang-backend-test/app/models"
+
+	"gorm.io/gorm"
+)
+
+type StocktakeRepository interface {
+	FindStocktakeById(ctx context.Context, db *gorm.DB, id int) (*models.Stocktake, error)
+	GetOpenStocktakes(ctx context.Context, db *gorm.DB, branchId int) ([]*models.Stocktake, error)
+	CreateStocktake(ctx context.Context, db *gorm.DB, stocktake *models.Stocktake) error
+	UpdateStocktake(ctx context.Context, db *gorm.DB, stocktake *models.Stocktake, status string) error
+	CountStocktakeScans(ctx context.Context, db *gorm.DB, stocktakeId int) (int64, error)
+	GetStocktakeScans(ctx context.Context, db *gorm.DB, stocktakeId int) ([]*models.StocktakeScan, error)
+	CreateStocktakeScans(ctx context.Context, db *gorm.DB, scans []*models.StocktakeScan) error
+	GetStocktakeDiscrepancies(ctx context.Context, db *gorm.DB, stocktakeId int) ([]*models.StocktakeDiscrepancy, error)
+	CreateStocktakeDiscrepancies(ctx context.Context, db *gorm.DB, discrepancies []*models.StocktakeDiscrepancy) error
+	UpdateStocktakeDiscrepancy(ctx context.Context, db *gorm.DB, discrepancy *models.StocktakeDiscrepancy) error
+}
+
+type StocktakeRepositoryImpl struct {
+}
+
+func NewStocktakeRepository() StocktakeRepository {
+	return &StocktakeRepositoryImpl{}
+}
+
+func (repository *StocktakeRepositoryImpl) FindStocktakeById(ctx context.Context, db *gorm.DB, id int) (*models.Stocktake, error) {
+	var stocktake models.Stocktake
+	if err := db.WithContext(ctx).Preload("Branch").Preload("ShelfLocation.Branch").First(&stocktake, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("stocktake not found")
+		}
+		return nil, err
+	}
+	return &stocktake, nil
+}
+func (repository *StocktakeRepositoryImpl) GetOpenStocktakes(ctx context.Context, db *gorm.DB, branchId int) ([]*models.Stocktake, error) {
+	var stocktakes []*models.Stocktake
+	if err := db.WithContext(ctx).
+		Where("branch_id = ? AND status = ?", branchId, models.StocktakeOpen).
+		Find(&stocktakes).Error; err != nil {
+		return nil, err
+	}
+	return stocktakes, nil
+}
+func (repository *StocktakeRepositoryImpl) CreateStocktake(ctx context.Context, db *gorm.DB, stocktake *models.Stocktake) error {
+	if err := db.WithContext(ctx).Omit("Branch", "ShelfLocation").Create(stocktake).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// UpdateStocktake saves stocktake if it is still in status.
+func (repository *StocktakeRepositoryImpl) UpdateStocktake(ctx context.Context, db *gorm.DB, stocktake *models.Stocktake, status string) error {
+	result := db.WithContext(ctx).Model(stocktake).Where("status = ?", status).Select("*").Omit("Branch", "ShelfLocation").Updates(stocktake)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("stocktake not found")
+	}
+	return nil
+}
+func (repository *StocktakeRepositoryImpl) CountStocktakeScans(ctx context.Context, db *gorm.DB, stocktakeId int) (int64, error) {
+	var count int64
+	if err := db.WithContext(ctx).Model(&models.StocktakeScan{}).Where("stocktake_id = ?", stocktakeId).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+func (repository *StocktakeRepositoryImpl) GetStocktakeScans(ctx context.Context, db *gorm.DB, stocktakeId int) ([]*models.StocktakeScan, error) {
+	var scans []*models.StocktakeScan
+	if err := db.WithContext(ctx).Where("stocktake_id = ?", stocktakeId).Order("scanned_at, id").Find(&scans).Error; err != nil {
+		return nil, err
+	}
+	return scans, nil
+}
+func (repository *StocktakeRepositoryImpl) CreateStocktakeScans(ctx context.Context, db *gorm.DB, scans []*models.StocktakeScan) error {
+	if err := db.WithContext(ctx).Create(scans).Error; err != nil {
+		return err
+	}
+	return nil
+}
+func (repository *StocktakeRepositoryImpl) GetStocktakeDiscrepancies(ctx context.Context, db *gorm.DB, stocktakeId int) ([]*models.StocktakeDiscrepancy, error) {
+	var discrepancies []*models.StocktakeDiscrepancy
+	if err := db.WithContext(ctx).
+		Preload("BookCopy.ShelfLocation.Branch").
+		Preload("ExpectedLocation.Branch").
+		Preload("FoundLocation.Branch").
+		Where("stocktake_id = ?", stocktakeId).
+		Order("kind, barcode, id").
+		Find(&discrepancies).Error; err != nil {
+		return nil, err
+	}
+	return discrepancies, nil
+}
+func (repository *StocktakeRepositoryImpl) CreateStocktakeDiscrepancies(ctx context.Context, db *gorm.DB, discrepancies []*models.StocktakeDiscrepancy) error {
+	if len(discrepancies) == 0 {
+		return nil
+	}
+	if err := db.WithContext(ctx).Omit("BookCopy", "ExpectedLocation", "FoundLocation").Create(discrepancies).Error; err != nil {
+		return err
+	}
+	return nil
+}
+func (repository *StocktakeRepositoryImpl) UpdateStocktakeDiscrepancy(ctx context.Context, db *gorm.DB, discrepancy *models.StocktakeDiscrepancy) error {
+	if err := db.WithContext(ctx).Omit("BookCopy", "ExpectedLocation", "FoundLocation").Save(discrepancy).Error; err != nil {
+		return err
+	}
+	return nil
+}
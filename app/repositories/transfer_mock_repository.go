@@ -0,0 +1,47 @@
+package repositories
+
+import (
+	"context"
+	"golang-backend-test/app/models"
+
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockTransferRepository struct {
+	mock.Mock
+}
+
+func (mock *MockTransferRepository) FindTransferById(ctx context.Context, db *gorm.DB, id int) (*models.Transfer, error) {
+	args := mock.Called(ctx, db, id)
+	if transfer, ok := args.Get(0).(*models.Transfer); ok {
+		return transfer, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (mock *MockTransferRepository) FindOpenTransferByCopy(ctx context.Context, db *gorm.DB, bookCopyId int) (*models.Transfer, error) {
+	args := mock.Called(ctx, db, bookCopyId)
+	if transfer, ok := args.Get(0).(*models.Transfer); ok {
+		return transfer, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (mock *MockTransferRepository) GetPendingTransfers(ctx context.Context, db *gorm.DB, branchId int) ([]*models.Transfer, error) {
+	args := mock.Called(ctx, db, branchId)
+	if transfers, ok := args.Get(0).([]*models.Transfer); ok {
+		return transfers, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (mock *MockTransferRepository) CreateTransfer(ctx context.Context, db *gorm.DB, transfer *models.Transfer) error {
+	args := mock.Called(ctx, db, transfer)
+	return args.Error(0)
+}
+
+func (mock *MockTransferRepository) UpdateTransfer(ctx context.Context, db *gorm.DB, transfer *models.Transfer, status string) error {
+	args := mock.Called(ctx, db, transfer, status)
+	return args.Error(0)
+}
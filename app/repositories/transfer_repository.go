@@ -0,0 +1,78 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"golang-backend-test/app/models"
+
+	"gorm.io/gorm"
+)
+
+type TransferRepository interface {
+	FindTransferById(ctx context.Context, db *gorm.DB, id int) (*models.Transfer, error)
+	FindOpenTransferByCopy(ctx context.Context, db *gorm.DB, bookCopyId int) (*models.Transfer, error)
+	GetPendingTransfers(ctx context.Context, db *gorm.DB, branchId int) ([]*models.Transfer, error)
+	CreateTransfer(ctx context.Context, db *gorm.DB, transfer *models.Transfer) error
+	UpdateTransfer(ctx context.Context, db *gorm.DB, transfer *models.Transfer, status string) error
+}
+
+type TransferRepositoryImpl struct {
+}
+
+func NewTransferRepository() TransferRepository {
+	return &TransferRepositoryImpl{}
+}
+
+func (repository *TransferRepositoryImpl) FindTransferById(ctx context.Context, db *gorm.DB, id int) (*models.Transfer, error) {
+	var transfer models.Transfer
+	if err := db.WithContext(ctx).Preload("BookCopy.ShelfLocation.Branch").Preload("FromBranch").Preload("ToBranch").
+		First(&transfer, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("transfer not found")
+		}
+		return nil, err
+	}
+	return &transfer, nil
+}
+func (repository *TransferRepositoryImpl) FindOpenTransferByCopy(ctx context.Context, db *gorm.DB, bookCopyId int) (*models.Transfer, error) {
+	var transfer models.Transfer
+	if err := db.WithContext(ctx).
+		Where("book_copy_id = ? AND status IN ?", bookCopyId, []string{models.TransferRequested, models.TransferInTransit}).
+		First(&transfer).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("transfer not found")
+		}
+		return nil, err
+	}
+	return &transfer, nil
+}
+func (repository *TransferRepositoryImpl) GetPendingTransfers(ctx context.Context, db *gorm.DB, branchId int) ([]*models.Transfer, error) {
+	var transfers []*models.Transfer
+	if err := db.WithContext(ctx).Preload("BookCopy.ShelfLocation.Branch").Preload("FromBranch").Preload("ToBranch").
+		Where("status IN ?", []string{models.TransferRequested, models.TransferInTransit}).
+		Where("from_branch_id = ? OR to_branch_id = ?", branchId, branchId).
+		Order("requested_at, id").
+		Find(&transfers).Error; err != nil {
+		return nil, err
+	}
+	return transfers, nil
+}
+func (repository *TransferRepositoryImpl) CreateTransfer(ctx context.Context, db *gorm.DB, transfer *models.Transfer) error {
+	if err := db.WithContext(ctx).Omit("BookCopy", "FromBranch", "ToBranch").Create(transfer).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// UpdateTransfer saves transfer if it is still in status, so that two
+// concurrent transitions cannot both apply.
+func (repository *TransferRepositoryImpl) UpdateTransfer(ctx context.Context, db *gorm.DB, transfer *models.Transfer, status string) error {
+	result := db.WithContext(ctx).Model(transfer).Where("status = ?", status).Select("*").Omit("BookCopy", "FromBranch", "ToBranch").Updates(transfer)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("transfer not found")
+	}
+	return nil
+}
@@ -17,6 +17,14 @@ func (mock *MockUserRepository) CreateUser(ctx context.Context, db *gorm.DB, use
 	return args.Error(0)
 }
 
+func (mock *MockUserRepository) FindUserById(ctx context.Context, db *gorm.DB, id int) (*models.User, error) {
+	args := mock.Called(ctx, db, id)
+	if user, ok := args.Get(0).(*models.User); ok {
+		return user, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
 func (mock *MockUserRepository) FindUserByUsername(ctx context.Context, db *gorm.DB, username string) (*models.User, error) {
 	args := mock.Called(ctx, db, username)
 	if user, ok := args.Get(0).(*models.User); ok {
@@ -29,3 +37,8 @@ func (mock *MockUserRepository) HashPassword(password string) (string, error) {
 	args := mock.Called(password)
 	return args.String(0), args.Error(1)
 }
+
+func (mock *MockUserRepository) CountUsers(ctx context.Context, db *gorm.DB) (int64, error) {
+	args := mock.Called(ctx, db)
+	return args.Get(0).(int64), args.Error(1)
+}
@@ -9,8 +9,10 @@ import (
 )
 
 type UserRepository interface {
+	FindUserById(ctx context.Context, db *gorm.DB, id int) (*models.User, error)
 	FindUserByUsername(ctx context.Context, db *gorm.DB, username string) (*models.User, error)
 	CreateUser(ctx context.Context, db *gorm.DB, user *models.User) error
+	CountUsers(ctx context.Context, db *gorm.DB) (int64, error)
 }
 
 type UserRepositoryImpl struct {
@@ -20,6 +22,16 @@ func NewUserRepository() UserRepository {
 	return &UserRepositoryImpl{}
 }
 
+func (repositories *UserRepositoryImpl) FindUserById(ctx context.Context, db *gorm.DB, id int) (*models.User, error) {
+	var user models.User
+	if err := db.WithContext(ctx).First(&user, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("user not found")
+		}
+		return nil, err
+	}
+	return &user, nil
+}
 func (repositories *UserRepositoryImpl) FindUserByUsername(ctx context.Context, db *gorm.DB, username string) (*models.User, error) {
 	var user models.User
 	if err := db.WithContext(ctx).Where("username = ?", username).First(&user).Error; err != nil {
@@ -36,3 +48,10 @@ func (repositories *UserRepositoryImpl) CreateUser(ctx context.Context, db *gorm
 	}
 	return nil
 }
+func (repositories *UserRepositoryImpl) CountUsers(ctx context.Context, db *gorm.DB) (int64, error) {
+	var count int64
+	if err := db.WithContext(ctx).Model(&models.User{}).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
@@ -0,0 +1,87 @@
+package repositories
+
+import (
+	"context"
+	"golang-backend-test/app/models"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockWebhookRepository struct {
+	mock.Mock
+}
+
+func (mock *MockWebhookRepository) FindSubscriptionById(ctx context.Context, db *gorm.DB, id int) (*models.WebhookSubscription, error) {
+	args := mock.Called(ctx, db, id)
+	if subscription, ok := args.Get(0).(*models.WebhookSubscription); ok {
+		return subscription, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (mock *MockWebhookRepository) GetListSubscriptions(ctx context.Context, db *gorm.DB) ([]*models.WebhookSubscription, error) {
+	args := mock.Called(ctx, db)
+	if subscriptions, ok := args.Get(0).([]*models.WebhookSubscription); ok {
+		return subscriptions, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (mock *MockWebhookRepository) CreateSubscription(ctx context.Context, db *gorm.DB, subscription *models.WebhookSubscription) error {
+	args := mock.Called(ctx, db, subscription)
+	return args.Error(0)
+}
+
+func (mock *MockWebhookRepository) UpdateSubscription(ctx context.Context, db *gorm.DB, subscription *models.WebhookSubscription) error {
+	args := mock.Called(ctx, db, subscription)
+	return args.Error(0)
+}
+
+func (mock *MockWebhookRepository) DeleteSubscription(ctx context.Context, db *gorm.DB, id int) error {
+	args := mock.Called(ctx, db, id)
+	return args.Error(0)
+}
+
+func (mock *MockWebhookRepository) FindDeliveryById(ctx context.Context, db *gorm.DB, subscriptionId, id int) (*models.WebhookDelivery, error) {
+	args := mock.Called(ctx, db, subscriptionId, id)
+	if delivery, ok := args.Get(0).(*models.WebhookDelivery); ok {
+		return delivery, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (mock *MockWebhookRepository) FindDeliveryByEventId(ctx context.Context, db *gorm.DB, subscriptionId int, eventId string) (*models.WebhookDelivery, error) {
+	args := mock.Called(ctx, db, subscriptionId, eventId)
+	if delivery, ok := args.Get(0).(*models.WebhookDelivery); ok {
+		return delivery, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (mock *MockWebhookRepository) GetListDeliveries(ctx context.Context, db *gorm.DB, subscriptionId int, status string) ([]*models.WebhookDelivery, error) {
+	args := mock.Called(ctx, db, subscriptionId, status)
+	if deliveries, ok := args.Get(0).([]*models.WebhookDelivery); ok {
+		return deliveries, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (mock *MockWebhookRepository) GetDueDeliveries(ctx context.Context, db *gorm.DB, now time.Time, limit int) ([]*models.WebhookDelivery, error) {
+	args := mock.Called(ctx, db, now, limit)
+	if deliveries, ok := args.Get(0).([]*models.WebhookDelivery); ok {
+		return deliveries, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (mock *MockWebhookRepository) CreateDelivery(ctx context.Context, db *gorm.DB, delivery *models.WebhookDelivery) error {
+	args := mock.Called(ctx, db, delivery)
+	return args.Error(0)
+}
+
+func (mock *MockWebhookRepository) UpdateDelivery(ctx context.Context, db *gorm.DB, delivery *models.WebhookDelivery) error {
+	args := mock.Called(ctx, db, delivery)
+	return args.Error(0)
+}
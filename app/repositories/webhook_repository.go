@@ -0,0 +1,135 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"golang-backend-test/app/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type WebhookRepository interface {
+	FindSubscriptionById(ctx context.Context, db *gorm.DB, id int) (*models.WebhookSubscription, error)
+	GetListSubscriptions(ctx context.Context, db *gorm.DB) ([]*models.WebhookSubscription, error)
+	CreateSubscription(ctx context.Context, db *gorm.DB, subscription *models.WebhookSubscription) error
+	UpdateSubscription(ctx context.Context, db *gorm.DB, subscription *models.WebhookSubscription) error
+	DeleteSubscription(ctx context.Context, db *gorm.DB, id int) error
+	FindDeliveryById(ctx context.Context, db *gorm.DB, subscriptionId, id int) (*models.WebhookDelivery, error)
+	FindDeliveryByEventId(ctx context.Context, db *gorm.DB, subscriptionId int, eventId string) (*models.WebhookDelivery, error)
+	GetListDeliveries(ctx context.Context, db *gorm.DB, subscriptionId int, status string) ([]*models.WebhookDelivery, error)
+	GetDueDeliveries(ctx context.Context, db *gorm.DB, now time.Time, limit int) ([]*models.WebhookDelivery, error)
+	CreateDelivery(ctx context.Context, db *gorm.DB, delivery *models.WebhookDelivery) error
+	UpdateDelivery(ctx context.Context, db *gorm.DB, delivery *models.WebhookDelivery) error
+}
+
+type WebhookRepositoryImpl struct {
+}
+
+func NewWebhookRepository() WebhookRepository {
+	return &WebhookRepositoryImpl{}
+}
+
+func (repository *WebhookRepositoryImpl) FindSubscriptionById(ctx context.Context, db *gorm.DB, id int) (*models.WebhookSubscription, error) {
+	var subscription models.WebhookSubscription
+	if err := db.WithContext(ctx).First(&subscription, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("webhook subscription not found")
+		}
+		return nil, err
+	}
+	return &subscription, nil
+}
+func (repository *WebhookRepositoryImpl) GetListSubscriptions(ctx context.Context, db *gorm.DB) ([]*models.WebhookSubscription, error) {
+	var subscriptions []*models.WebhookSubscription
+	if err := db.WithContext(ctx).Order("id").Find(&subscriptions).Error; err != nil {
+		return nil, err
+	}
+	return subscriptions, nil
+}
+func (repository *WebhookRepositoryImpl) CreateSubscription(ctx context.Context, db *gorm.DB, subscription *models.WebhookSubscription) error {
+	if err := db.WithContext(ctx).Create(subscription).Error; err != nil {
+		return err
+	}
+	return nil
+}
+func (repository *WebhookRepositoryImpl) UpdateSubscription(ctx context.Context, db *gorm.DB, subscription *models.WebhookSubscription) error {
+	if err := db.WithContext(ctx).Save(subscription).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// DeleteSubscription deletes a subscription along with its delivery log.
+func (repository *WebhookRepositoryImpl) DeleteSubscription(ctx context.Context, db *gorm.DB, id int) error {
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&models.WebhookSubscription{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("webhook subscription not found")
+		}
+		return tx.Where("subscription_id = ?", id).Delete(&models.WebhookDelivery{}).Error
+	})
+}
+func (repository *WebhookRepositoryImpl) FindDeliveryById(ctx context.Context, db *gorm.DB, subscriptionId, id int) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	if err := db.WithContext(ctx).Where("subscription_id = ?", subscriptionId).First(&delivery, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("webhook delivery not found")
+		}
+		return nil, err
+	}
+	return &delivery, nil
+}
+func (repository *WebhookRepositoryImpl) FindDeliveryByEventId(ctx context.Context, db *gorm.DB, subscriptionId int, eventId string) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	if err := db.WithContext(ctx).Where("subscription_id = ? AND event_id = ?", subscriptionId, eventId).First(&delivery).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("webhook delivery not found")
+		}
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// GetListDeliveries lists the deliveries of a subscription, newest first,
+// with the given status or all of them when status is empty.
+func (repository *WebhookRepositoryImpl) GetListDeliveries(ctx context.Context, db *gorm.DB, subscriptionId int, status string) ([]*models.WebhookDelivery, error) {
+	var deliveries []*models.WebhookDelivery
+	query := db.WithContext(ctx).Where("subscription_id = ?", subscriptionId)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Order("id DESC").Find(&deliveries).Error; err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// GetDueDeliveries returns the pending deliveries whose next attempt is due,
+// oldest first, with their subscription.
+func (repository *WebhookRepositoryImpl) GetDueDeliveries(ctx context.Context, db *gorm.DB, now time.Time, limit int) ([]*models.WebhookDelivery, error) {
+	var deliveries []*models.WebhookDelivery
+	if err := db.WithContext(ctx).Preload("Subscription").
+		Where("status = ? AND next_attempt_at <= ?", models.WebhookDeliveryPending, now).
+		Order("next_attempt_at, id").
+		Limit(limit).
+		Find(&deliveries).Error; err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+func (repository *WebhookRepositoryImpl) CreateDelivery(ctx context.Context, db *gorm.DB, delivery *models.WebhookDelivery) error {
+	if err := db.WithContext(ctx).Omit("Subscription").Create(delivery).Error; err != nil {
+		return err
+	}
+	return nil
+}
+func (repository *WebhookRepositoryImpl) UpdateDelivery(ctx context.Context, db *gorm.DB, delivery *models.WebhookDelivery) error {
+	if err := db.WithContext(ctx).Omit("Subscription").Save(delivery).Error; err != nil {
+		return err
+	}
+	return nil
+}
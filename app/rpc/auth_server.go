@@ -0,0 +1,44 @@
+package rpc
+
+import (
+	"context"
+	"golang-backend-test/app/params"
+	"golang-backend-test/app/services"
+	catalogv1 "golang-backend-test/proto/catalog/v1"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type AuthServer struct {
+	catalogv1.UnimplementedAuthServiceServer
+	UserService services.UserService
+}
+
+func NewAuthServer(userService services.UserService) *AuthServer {
+	return &AuthServer{UserService: userService}
+}
+
+func (server *AuthServer) Register(ctx context.Context, req *catalogv1.RegisterRequest) (*emptypb.Empty, error) {
+	custErr := server.UserService.Register(ctx, &params.UserRequest{
+		Organization: req.GetOrganization(),
+		Username:     req.GetUsername(),
+		Password:     req.GetPassword(),
+		Invite:       req.GetInvite(),
+	})
+	if custErr != nil {
+		return nil, statusError(custErr)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (server *AuthServer) Login(ctx context.Context, req *catalogv1.LoginRequest) (*catalogv1.LoginResponse, error) {
+	user, custErr := server.UserService.Login(ctx, &params.UserRequest{
+		Organization: req.GetOrganization(),
+		Username:     req.GetUsername(),
+		Password:     req.GetPassword(),
+	})
+	if custErr != nil {
+		return nil, statusError(custErr)
+	}
+	return &catalogv1.LoginResponse{Token: user.Token}, nil
+}
@@ -0,0 +1,134 @@
+package rpc
+
+import (
+	"context"
+	"golang-backend-test/app/params"
+	"golang-backend-test/app/services"
+	catalogv1 "golang-backend-test/proto/catalog/v1"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type AuthorServer struct {
+	catalogv1.UnimplementedAuthorServiceServer
+	AuthorService services.AuthorService
+}
+
+func NewAuthorServer(authorService services.AuthorService) *AuthorServer {
+	return &AuthorServer{AuthorService: authorService}
+}
+
+func (server *AuthorServer) GetAuthor(ctx context.Context, req *catalogv1.GetAuthorRequest) (*catalogv1.Author, error) {
+	author, custErr := server.AuthorService.FindDetailAuthor(ctx, int(req.GetId()))
+	if custErr != nil {
+		return nil, statusError(custErr)
+	}
+	return authorMessage(author), nil
+}
+
+func (server *AuthorServer) ListAuthors(ctx context.Context, req *catalogv1.ListAuthorsRequest) (*catalogv1.ListAuthorsResponse, error) {
+	page, custErr := server.AuthorService.FindAuthorsPage(ctx, &params.PaginationRequest{
+		Limit:  int(req.GetLimit()),
+		Sort:   req.GetSort(),
+		Cursor: req.GetCursor(),
+	})
+	if custErr != nil {
+		return nil, statusError(custErr)
+	}
+
+	resp := &catalogv1.ListAuthorsResponse{
+		Total:      page.Total,
+		NextCursor: page.NextCursor,
+		PrevCursor: page.PrevCursor,
+	}
+	for _, author := range page.Items.([]*params.AuthorResponse) {
+		resp.Authors = append(resp.Authors, authorMessage(author))
+	}
+	return resp, nil
+}
+
+// StreamAuthors walks the pages of the author list by cursor, sending the
+// authors as each page is read.
+func (server *AuthorServer) StreamAuthors(req *catalogv1.StreamAuthorsRequest, stream catalogv1.AuthorService_StreamAuthorsServer) error {
+	pageReq := &params.PaginationRequest{Limit: streamPageSize, Sort: req.GetSort()}
+	for {
+		page, custErr := server.AuthorService.FindAuthorsPage(stream.Context(), pageReq)
+		if custErr != nil {
+			return statusError(custErr)
+		}
+		for _, author := range page.Items.([]*params.AuthorResponse) {
+			if err := stream.Send(authorMessage(author)); err != nil {
+				return err
+			}
+		}
+		if page.NextCursor == "" {
+			return nil
+		}
+		pageReq.Cursor = page.NextCursor
+	}
+}
+
+func (server *AuthorServer) CreateAuthor(ctx context.Context, req *catalogv1.CreateAuthorRequest) (*catalogv1.Author, error) {
+	author, custErr := server.AuthorService.CrateAuthor(ctx, authorRequest(req.GetAuthor()))
+	if custErr != nil {
+		return nil, statusError(custErr)
+	}
+	return authorMessage(author), nil
+}
+
+func (server *AuthorServer) UpdateAuthor(ctx context.Context, req *catalogv1.UpdateAuthorRequest) (*catalogv1.Author, error) {
+	author, custErr := server.AuthorService.UpdateAuthor(ctx, int(req.GetId()), authorRequest(req.GetAuthor()))
+	if custErr != nil {
+		return nil, statusError(custErr)
+	}
+	return authorMessage(author), nil
+}
+
+func (server *AuthorServer) DeleteAuthor(ctx context.Context, req *catalogv1.DeleteAuthorRequest) (*emptypb.Empty, error) {
+	custErr := server.AuthorService.DeleteAuthor(ctx, int(req.GetId()))
+	if custErr != nil {
+		return nil, statusError(custErr)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func authorRequest(author *catalogv1.AuthorInput) *params.AuthorRequest {
+	identifiers := author.GetIdentifiers()
+	return &params.AuthorRequest{
+		Name:        author.GetName(),
+		Birthdate:   author.GetBirthdate(),
+		DeathDate:   author.GetDeathDate(),
+		Biography:   author.GetBiography(),
+		Nationality: author.GetNationality(),
+		Identifiers: params.AuthorIdentifiers{
+			VIAF:     identifiers.GetViaf(),
+			ORCID:    identifiers.GetOrcid(),
+			Wikidata: identifiers.GetWikidata(),
+		},
+		Pseudonyms: author.GetPseudonyms(),
+	}
+}
+
+func authorMessage(author *params.AuthorResponse) *catalogv1.Author {
+	if author == nil {
+		return nil
+	}
+	message := &catalogv1.Author{
+		Id:          uint64(author.ID),
+		Name:        author.Name,
+		Birthdate:   author.Birthdate,
+		DeathDate:   author.DeathDate,
+		Biography:   author.Biography,
+		Nationality: author.Nationality,
+		Pseudonyms:  author.Pseudonyms,
+		Aliases:     author.Aliases,
+	}
+	if author.Identifiers != nil {
+		message.Identifiers = &catalogv1.AuthorIdentifiers{
+			Viaf:     author.Identifiers.VIAF,
+			Orcid:    author.Identifiers.ORCID,
+			Wikidata: author.Identifiers.Wikidata,
+		}
+	}
+	return message
+}
@@ -0,0 +1,128 @@
+package rpc
+
+import (
+	"context"
+	"golang-backend-test/app/params"
+	"golang-backend-test/app/services"
+	catalogv1 "golang-backend-test/proto/catalog/v1"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// streamPageSize is how many records a streaming list reads at a time.
+const streamPageSize = 100
+
+type BookServer struct {
+	catalogv1.UnimplementedBookServiceServer
+	BookService services.BookService
+}
+
+func NewBookServer(bookService services.BookService) *BookServer {
+	return &BookServer{BookService: bookService}
+}
+
+func (server *BookServer) GetBook(ctx context.Context, req *catalogv1.GetBookRequest) (*catalogv1.Book, error) {
+	book, custErr := server.BookService.FindDetailBook(ctx, int(req.GetId()), &params.FieldsetRequest{Expand: bookExpand()})
+	if custErr != nil {
+		return nil, statusError(custErr)
+	}
+	return bookMessage(book), nil
+}
+
+func (server *BookServer) ListBooks(ctx context.Context, req *catalogv1.ListBooksRequest) (*catalogv1.ListBooksResponse, error) {
+	page, custErr := server.BookService.FindBooksPage(ctx, &params.PaginationRequest{
+		Limit:  int(req.GetLimit()),
+		Sort:   req.GetSort(),
+		Cursor: req.GetCursor(),
+	}, nil)
+	if custErr != nil {
+		return nil, statusError(custErr)
+	}
+
+	resp := &catalogv1.ListBooksResponse{
+		Total:      page.Total,
+		NextCursor: page.NextCursor,
+		PrevCursor: page.PrevCursor,
+	}
+	for _, book := range page.Items.([]*params.BookResponse) {
+		resp.Books = append(resp.Books, bookMessage(book))
+	}
+	return resp, nil
+}
+
+// StreamBooks walks the pages of the book list by cursor, sending the books
+// as each page is read.
+func (server *BookServer) StreamBooks(req *catalogv1.StreamBooksRequest, stream catalogv1.BookService_StreamBooksServer) error {
+	pageReq := &params.PaginationRequest{Limit: streamPageSize, Sort: req.GetSort()}
+	for {
+		page, custErr := server.BookService.FindBooksPage(stream.Context(), pageReq, nil)
+		if custErr != nil {
+			return statusError(custErr)
+		}
+		for _, book := range page.Items.([]*params.BookResponse) {
+			if err := stream.Send(bookMessage(book)); err != nil {
+				return err
+			}
+		}
+		if page.NextCursor == "" {
+			return nil
+		}
+		pageReq.Cursor = page.NextCursor
+	}
+}
+
+func (server *BookServer) CreateBook(ctx context.Context, req *catalogv1.CreateBookRequest) (*catalogv1.Book, error) {
+	book, custErr := server.BookService.CrateBook(ctx, bookRequest(req.GetBook()))
+	if custErr != nil {
+		return nil, statusError(custErr)
+	}
+	return bookMessage(book), nil
+}
+
+func (server *BookServer) UpdateBook(ctx context.Context, req *catalogv1.UpdateBookRequest) (*catalogv1.Book, error) {
+	book, custErr := server.BookService.UpdateBook(ctx, int(req.GetId()), bookRequest(req.GetBook()))
+	if custErr != nil {
+		return nil, statusError(custErr)
+	}
+	return bookMessage(book), nil
+}
+
+func (server *BookServer) DeleteBook(ctx context.Context, req *catalogv1.DeleteBookRequest) (*emptypb.Empty, error) {
+	custErr := server.BookService.DeleteBook(ctx, int(req.GetId()))
+	if custErr != nil {
+		return nil, statusError(custErr)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// bookExpand loads the author of a single book but not its copies, which
+// the Book message has no room for.
+func bookExpand() *string {
+	expand := "author"
+	return &expand
+}
+
+func bookRequest(book *catalogv1.BookInput) *params.BookRequest {
+	return &params.BookRequest{
+		Title:           book.GetTitle(),
+		ISBN:            book.GetIsbn(),
+		PublicationDate: book.GetPublicationDate(),
+		AuthorID:        uint(book.GetAuthorId()),
+		Publisher:       book.GetPublisher(),
+		PageCount:       int(book.GetPageCount()),
+		CoverURL:        book.GetCoverUrl(),
+	}
+}
+
+func bookMessage(book *params.BookResponse) *catalogv1.Book {
+	return &catalogv1.Book{
+		Id:              uint64(book.ID),
+		Title:           book.Title,
+		Isbn:            book.ISBN,
+		PublicationDate: book.PublicationDate,
+		Publisher:       book.Publisher,
+		PageCount:       int32(book.PageCount),
+		CoverUrl:        book.CoverURL,
+		Author:          authorMessage(book.AuthorResponse),
+	}
+}
@@ -0,0 +1,80 @@
+package rpc
+
+import (
+	"context"
+	"golang-backend-test/pkg/tenant"
+	"golang-backend-test/pkg/token"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// authServiceName is the service callable without a token, as it hands the
+// tokens out.
+const authServiceName = "/catalog.v1.AuthService/"
+
+// AuthUnaryInterceptor checks the bearer token in the authorization metadata
+// the way CheckAuth does for HTTP, putting the tenant of the token in the
+// context of the call.
+func AuthUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if strings.HasPrefix(info.FullMethod, authServiceName) {
+			return handler(ctx, req)
+		}
+		ctx, err := authenticate(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// AuthStreamInterceptor is AuthUnaryInterceptor for streaming calls.
+func AuthStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if strings.HasPrefix(info.FullMethod, authServiceName) {
+			return handler(srv, stream)
+		}
+		ctx, err := authenticate(stream.Context())
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authenticatedStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+func authenticate(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) != 1 {
+		return nil, status.Error(codes.Unauthenticated, "authorization metadata must be given once")
+	}
+
+	bearerToken := strings.Split(values[0], "Bearer ")
+	if len(bearerToken) != 2 {
+		return nil, status.Error(codes.Unauthenticated, "len token must be 2")
+	}
+	payload, err := token.ValidateToken(bearerToken[1])
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	if payload.TenantId == 0 {
+		return nil, status.Error(codes.Unauthenticated, "token has no organization")
+	}
+
+	return tenant.WithID(ctx, uint(payload.TenantId)), nil
+}
+
+// authenticatedStream replaces the context of a stream with the one carrying
+// the tenant.
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (stream *authenticatedStream) Context() context.Context {
+	return stream.ctx
+}
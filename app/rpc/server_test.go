@@ -0,0 +1,224 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"golang-backend-test/app/models"
+	"golang-backend-test/app/repositories"
+	"golang-backend-test/app/services"
+	"golang-backend-test/pkg/tenant"
+	"golang-backend-test/pkg/token"
+	catalogv1 "golang-backend-test/proto/catalog/v1"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"gorm.io/gorm"
+)
+
+// dialServer serves the given services over an in-memory listener and
+// returns a connection to them.
+func dialServer(t *testing.T, register func(server *grpc.Server)) *grpc.ClientConn {
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(AuthUnaryInterceptor()),
+		grpc.StreamInterceptor(AuthStreamInterceptor()),
+	)
+	register(server)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	assert.Nil(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func withToken(t *testing.T, authId, tenantId int) context.Context {
+	tokenStr, err := token.GenerateToken(authId, tenantId)
+	assert.Nil(t, err)
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+tokenStr)
+}
+
+func hasTenant(id uint) interface{} {
+	return mock.MatchedBy(func(ctx context.Context) bool {
+		tenantId, ok := tenant.FromContext(ctx)
+		return ok && tenantId == id
+	})
+}
+
+func TestGetBook_Success(t *testing.T) {
+	bookRepo := new(repositories.MockBookRepository)
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := new(gorm.DB)
+
+	published := time.Date(1937, time.September, 21, 0, 0, 0, 0, time.UTC)
+	bookRepo.On("LoadBookById", hasTenant(7), db, 1, repositories.BookLoad{Author: true}).Return(&models.Book{
+		ID:              1,
+		Title:           "The Hobbit",
+		ISBN:            "9780547928227",
+		PublicationDate: &published,
+		AuthorID:        2,
+		Author:          models.Author{ID: 2, Name: "J.R.R. Tolkien"},
+	}, nil)
+	conn := dialServer(t, func(server *grpc.Server) {
+		catalogv1.RegisterBookServiceServer(server, NewBookServer(services.NewBookService(bookRepo, authorRepo, nil, nil, db)))
+	})
+
+	book, err := catalogv1.NewBookServiceClient(conn).GetBook(withToken(t, 3, 7), &catalogv1.GetBookRequest{Id: 1})
+
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1), book.Id)
+	assert.Equal(t, "The Hobbit", book.Title)
+	assert.Equal(t, "1937-09-21", book.PublicationDate)
+	assert.Equal(t, "J.R.R. Tolkien", book.Author.Name)
+
+	bookRepo.AssertExpectations(t)
+}
+
+func TestGetBook_MissingToken(t *testing.T) {
+	bookRepo := new(repositories.MockBookRepository)
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := new(gorm.DB)
+
+	conn := dialServer(t, func(server *grpc.Server) {
+		catalogv1.RegisterBookServiceServer(server, NewBookServer(services.NewBookService(bookRepo, authorRepo, nil, nil, db)))
+	})
+
+	_, err := catalogv1.NewBookServiceClient(conn).GetBook(context.Background(), &catalogv1.GetBookRequest{Id: 1})
+
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer not-a-token")
+	_, err = catalogv1.NewBookServiceClient(conn).GetBook(ctx, &catalogv1.GetBookRequest{Id: 1})
+
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	bookRepo.AssertNotCalled(t, "LoadBookById")
+}
+
+func TestGetAuthor_NotFound(t *testing.T) {
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := new(gorm.DB)
+
+	authorRepo.On("FindAuthorById", hasTenant(7), db, 5).Return(nil, errors.New("author not found"))
+	authorRepo.On("FindAuthorRedirect", hasTenant(7), db, 5).Return(nil, errors.New("author redirect not found"))
+	conn := dialServer(t, func(server *grpc.Server) {
+		catalogv1.RegisterAuthorServiceServer(server, NewAuthorServer(services.NewAuthorService(authorRepo, nil, db)))
+	})
+
+	_, err := catalogv1.NewAuthorServiceClient(conn).GetAuthor(withToken(t, 3, 7), &catalogv1.GetAuthorRequest{Id: 5})
+
+	st := status.Convert(err)
+	assert.Equal(t, codes.NotFound, st.Code())
+	assert.Equal(t, "NOT FOUND ERROR", st.Message())
+	assert.Len(t, st.Details(), 1)
+	assert.Equal(t, "ERR0003", st.Details()[0].(*errdetails.ErrorInfo).Reason)
+
+	authorRepo.AssertExpectations(t)
+}
+
+func TestCreateAuthor_ValidationError(t *testing.T) {
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := new(gorm.DB)
+
+	conn := dialServer(t, func(server *grpc.Server) {
+		catalogv1.RegisterAuthorServiceServer(server, NewAuthorServer(services.NewAuthorService(authorRepo, nil, db)))
+	})
+
+	_, err := catalogv1.NewAuthorServiceClient(conn).CreateAuthor(withToken(t, 3, 7), &catalogv1.CreateAuthorRequest{
+		Author: &catalogv1.AuthorInput{Name: "Ursula K. Le Guin"},
+	})
+
+	st := status.Convert(err)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	assert.Contains(t, st.Message(), "error Birthdate on tag required")
+	authorRepo.AssertNotCalled(t, "CreateAuthor")
+}
+
+func TestStreamAuthors_WalksPages(t *testing.T) {
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := new(gorm.DB)
+
+	var firstPage, lastPage []*models.Author
+	for id := uint(1); id <= streamPageSize; id++ {
+		firstPage = append(firstPage, &models.Author{ID: id, Name: fmt.Sprintf("Author %d", id)})
+	}
+	lastPage = []*models.Author{{ID: streamPageSize + 1, Name: "Last Author"}}
+
+	authorRepo.On("GetAuthorsPage", hasTenant(7), db, mock.MatchedBy(func(page repositories.KeysetPage) bool { return page.ID == 0 })).
+		Return(firstPage, true, int64(streamPageSize+1), nil).Once()
+	authorRepo.On("GetAuthorsPage", hasTenant(7), db, mock.MatchedBy(func(page repositories.KeysetPage) bool { return page.ID == streamPageSize })).
+		Return(lastPage, false, int64(streamPageSize+1), nil).Once()
+	conn := dialServer(t, func(server *grpc.Server) {
+		catalogv1.RegisterAuthorServiceServer(server, NewAuthorServer(services.NewAuthorService(authorRepo, nil, db)))
+	})
+
+	stream, err := catalogv1.NewAuthorServiceClient(conn).StreamAuthors(withToken(t, 3, 7), &catalogv1.StreamAuthorsRequest{})
+	assert.Nil(t, err)
+
+	var names []string
+	for {
+		author, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		assert.Nil(t, err)
+		names = append(names, author.Name)
+	}
+
+	assert.Len(t, names, streamPageSize+1)
+	assert.Equal(t, "Author 1", names[0])
+	assert.Equal(t, "Last Author", names[streamPageSize])
+
+	authorRepo.AssertExpectations(t)
+}
+
+func TestStreamBooks_MissingToken(t *testing.T) {
+	bookRepo := new(repositories.MockBookRepository)
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := new(gorm.DB)
+
+	conn := dialServer(t, func(server *grpc.Server) {
+		catalogv1.RegisterBookServiceServer(server, NewBookServer(services.NewBookService(bookRepo, authorRepo, nil, nil, db)))
+	})
+
+	stream, err := catalogv1.NewBookServiceClient(conn).StreamBooks(context.Background(), &catalogv1.StreamBooksRequest{})
+	assert.Nil(t, err)
+	_, err = stream.Recv()
+
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	bookRepo.AssertNotCalled(t, "GetBooksPage")
+}
+
+func TestRegister_WithoutToken(t *testing.T) {
+	userRepo := new(repositories.MockUserRepository)
+	organizationRepo := new(repositories.MockOrganizationRepository)
+	db := new(gorm.DB)
+
+	conn := dialServer(t, func(server *grpc.Server) {
+		catalogv1.RegisterAuthServiceServer(server, NewAuthServer(services.NewUserService(userRepo, organizationRepo, nil, db)))
+	})
+
+	_, err := catalogv1.NewAuthServiceClient(conn).Register(context.Background(), &catalogv1.RegisterRequest{Username: "reader"})
+
+	// the call gets past the interceptor and fails validation
+	st := status.Convert(err)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	assert.Contains(t, st.Message(), "BAD REQUEST ERROR")
+}
@@ -0,0 +1,45 @@
+package rpc
+
+import (
+	"fmt"
+	"golang-backend-test/app/commons/response"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// statusCodes maps the codes of response.CustomError to gRPC codes. Codes
+// not listed become codes.Internal.
+var statusCodes = map[string]codes.Code{
+	"ERR0003": codes.NotFound,
+	"ERR0004": codes.Unauthenticated,
+	"ERR0005": codes.InvalidArgument,
+	"ERR0006": codes.InvalidArgument,
+	"ERR0007": codes.Aborted,
+	"ERR0008": codes.FailedPrecondition,
+}
+
+// statusError turns a service error into a gRPC status. The additional info
+// is added to the message, and the error code of the REST API is kept in an
+// ErrorInfo detail so that clients can tell errors apart the same way.
+func statusError(custErr *response.CustomError) error {
+	code, ok := statusCodes[custErr.Code]
+	if !ok {
+		code = codes.Internal
+	}
+	message := custErr.Message
+	if custErr.AdditionalInfo != nil {
+		message = fmt.Sprintf("%s: %v", message, custErr.AdditionalInfo)
+	}
+
+	st := status.New(code, message)
+	detailed, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason: custErr.Code,
+		Domain: "catalog",
+	})
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
@@ -0,0 +1,488 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"golang-backend-test/app/commons/response"
+	"golang-backend-test/app/models"
+	"golang-backend-test/app/params"
+	"golang-backend-test/app/repositories"
+	"time"
+
+	"github.com/go-playground/validator"
+	"gorm.io/gorm"
+)
+
+type AcquisitionService interface {
+	FindAllVendors(ctx context.Context) ([]*params.VendorResponse, *response.CustomError)
+	CreateVendor(ctx context.Context, req *params.VendorRequest) (*params.VendorResponse, *response.CustomError)
+	FindAllFunds(ctx context.Context) ([]*params.FundResponse, *response.CustomError)
+	FindDetailFund(ctx context.Context, id int) (*params.FundResponse, *response.CustomError)
+	CreateFund(ctx context.Context, req *params.FundRequest) (*params.FundResponse, *response.CustomError)
+	FindAllPurchaseOrders(ctx context.Context, status string) ([]*params.PurchaseOrderResponse, *response.CustomError)
+	FindDetailPurchaseOrder(ctx context.Context, id int) (*params.PurchaseOrderResponse, *response.CustomError)
+	CreatePurchaseOrder(ctx context.Context, userId int, req *params.PurchaseOrderRequest) (*params.PurchaseOrderResponse, *response.CustomError)
+	SubmitPurchaseOrder(ctx context.Context, userId, id int) (*params.PurchaseOrderResponse, *response.CustomError)
+	CancelPurchaseOrder(ctx context.Context, id int) (*params.PurchaseOrderResponse, *response.CustomError)
+	ReceivePurchaseOrder(ctx context.Context, id int, req *params.PurchaseOrderReceiveRequest) (*params.PurchaseOrderReceiveResponse, *response.CustomError)
+}
+
+type AcquisitionServiceImpl struct {
+	AcquisitionRepository repositories.AcquisitionRepository
+	BookRepository        repositories.BookRepository
+	AuthorRepository      repositories.AuthorRepository
+	BookCopyRepository    repositories.BookCopyRepository
+	BranchRepository      repositories.BranchRepository
+	Events                EventRecorder
+	DB                    *gorm.DB
+}
+
+// NewAcquisitionService builds the acquisition service. events may be nil,
+// in which case no events are recorded.
+func NewAcquisitionService(acquisitionRepository repositories.AcquisitionRepository, bookRepository repositories.BookRepository, authorRepository repositories.AuthorRepository, bookCopyRepository repositories.BookCopyRepository, branchRepository repositories.BranchRepository, events EventRecorder, db *gorm.DB) AcquisitionService {
+	return &AcquisitionServiceImpl{
+		AcquisitionRepository: acquisitionRepository,
+		BookRepository:        bookRepository,
+		AuthorRepository:      authorRepository,
+		BookCopyRepository:    bookCopyRepository,
+		BranchRepository:      branchRepository,
+		Events:                events,
+		DB:                    db,
+	}
+}
+
+// purchaseOrderTransitions lists, for each target status, the statuses a
+// purchase order may move from.
+var purchaseOrderTransitions = map[string][]string{
+	models.PurchaseOrderOrdered:   {models.PurchaseOrderDraft},
+	models.PurchaseOrderCancelled: {models.PurchaseOrderDraft, models.PurchaseOrderOrdered},
+}
+
+func (service *AcquisitionServiceImpl) FindAllVendors(ctx context.Context) ([]*params.VendorResponse, *response.CustomError) {
+	vendors, err := service.AcquisitionRepository.GetListVendors(ctx, service.DB)
+	if err != nil {
+		return nil, response.RepositoryError()
+	}
+	var vendorResponses []*params.VendorResponse
+	for _, vendor := range vendors {
+		vendorResponses = append(vendorResponses, vendorResponse(vendor))
+	}
+	return vendorResponses, nil
+}
+
+func (service *AcquisitionServiceImpl) CreateVendor(ctx context.Context, req *params.VendorRequest) (*params.VendorResponse, *response.CustomError) {
+	val := validator.New()
+	err := val.Struct(req)
+	if err != nil {
+		validationErrors := err.(validator.ValidationErrors)
+		var errors []interface{}
+		for _, fieldError := range validationErrors {
+			error := "error " + fieldError.Field() + " on tag " + fieldError.Tag()
+			errors = append(errors, error)
+		}
+		return nil, response.BadRequestErrorWithAdditionalInfo(errors)
+	}
+
+	var vendor = new(models.Vendor)
+	vendor.Name = req.Name
+	vendor.Email = req.Email
+	vendor.Phone = req.Phone
+	if err := service.AcquisitionRepository.CreateVendor(ctx, service.DB, vendor); err != nil {
+		return nil, response.BadRequestError()
+	}
+
+	return vendorResponse(vendor), nil
+}
+
+func (service *AcquisitionServiceImpl) FindAllFunds(ctx context.Context) ([]*params.FundResponse, *response.CustomError) {
+	funds, err := service.AcquisitionRepository.GetListFunds(ctx, service.DB)
+	if err != nil {
+		return nil, response.RepositoryError()
+	}
+	var ids []uint
+	for _, fund := range funds {
+		ids = append(ids, fund.ID)
+	}
+	spending := make(map[uint]*repositories.FundSpending)
+	if len(ids) > 0 {
+		spending, err = service.AcquisitionRepository.GetFundSpending(ctx, service.DB, ids)
+		if err != nil {
+			return nil, response.RepositoryError()
+		}
+	}
+
+	var fundResponses []*params.FundResponse
+	for _, fund := range funds {
+		fundResponses = append(fundResponses, fundBudgetResponse(fund, spending[fund.ID]))
+	}
+	return fundResponses, nil
+}
+
+func (service *AcquisitionServiceImpl) FindDetailFund(ctx context.Context, id int) (*params.FundResponse, *response.CustomError) {
+	fund, err := service.AcquisitionRepository.FindFundById(ctx, service.DB, id)
+	if err != nil {
+		return nil, response.NotFoundError()
+	}
+	spending, err := service.AcquisitionRepository.GetFundSpending(ctx, service.DB, []uint{fund.ID})
+	if err != nil {
+		return nil, response.RepositoryError()
+	}
+
+	return fundBudgetResponse(fund, spending[fund.ID]), nil
+}
+
+func (service *AcquisitionServiceImpl) CreateFund(ctx context.Context, req *params.FundRequest) (*params.FundResponse, *response.CustomError) {
+	val := validator.New()
+	err := val.Struct(req)
+	if err != nil {
+		validationErrors := err.(validator.ValidationErrors)
+		var errors []interface{}
+		for _, fieldError := range validationErrors {
+			error := "error " + fieldError.Field() + " on tag " + fieldError.Tag()
+			errors = append(errors, error)
+		}
+		return nil, response.BadRequestErrorWithAdditionalInfo(errors)
+	}
+
+	var fund = new(models.Fund)
+	fund.Code = req.Code
+	fund.Name = req.Name
+	fund.Allocation = req.Allocation
+	if err := service.AcquisitionRepository.CreateFund(ctx, service.DB, fund); err != nil {
+		return nil, response.BadRequestError()
+	}
+
+	return fundBudgetResponse(fund, nil), nil
+}
+
+func (service *AcquisitionServiceImpl) FindAllPurchaseOrders(ctx context.Context, status string) ([]*params.PurchaseOrderResponse, *response.CustomError) {
+	orders, err := service.AcquisitionRepository.GetListPurchaseOrders(ctx, service.DB, status)
+	if err != nil {
+		return nil, response.RepositoryError()
+	}
+	var orderResponses []*params.PurchaseOrderResponse
+	for _, order := range orders {
+		orderResponses = append(orderResponses, purchaseOrderResponse(order))
+	}
+	return orderResponses, nil
+}
+
+func (service *AcquisitionServiceImpl) FindDetailPurchaseOrder(ctx context.Context, id int) (*params.PurchaseOrderResponse, *response.CustomError) {
+	order, err := service.AcquisitionRepository.FindPurchaseOrderById(ctx, service.DB, id)
+	if err != nil {
+		return nil, response.NotFoundError()
+	}
+
+	return purchaseOrderResponse(order), nil
+}
+
+func (service *AcquisitionServiceImpl) CreatePurchaseOrder(ctx context.Context, userId int, req *params.PurchaseOrderRequest) (*params.PurchaseOrderResponse, *response.CustomError) {
+	val := validator.New()
+	err := val.Struct(req)
+	if err != nil {
+		validationErrors := err.(validator.ValidationErrors)
+		var errors []interface{}
+		for _, fieldError := range validationErrors {
+			error := "error " + fieldError.Field() + " on tag " + fieldError.Tag()
+			errors = append(errors, error)
+		}
+		return nil, response.BadRequestErrorWithAdditionalInfo(errors)
+	}
+
+	vendor, err := service.AcquisitionRepository.FindVendorById(ctx, service.DB, int(req.VendorID))
+	if err != nil {
+		return nil, response.BadRequestErrorWithAdditionalInfo(err.Error())
+	}
+	fund, err := service.AcquisitionRepository.FindFundById(ctx, service.DB, int(req.FundID))
+	if err != nil {
+		return nil, response.BadRequestErrorWithAdditionalInfo(err.Error())
+	}
+
+	var order = new(models.PurchaseOrder)
+	order.VendorID = vendor.ID
+	order.FundID = fund.ID
+	order.Status = models.PurchaseOrderDraft
+	order.CreatedAt = time.Now()
+	order.CreatedBy = uint(userId)
+	for _, line := range req.Lines {
+		order.Lines = append(order.Lines, models.PurchaseOrderLine{
+			ISBN:       line.ISBN,
+			Title:      line.Title,
+			AuthorName: line.AuthorName,
+			Quantity:   line.Quantity,
+			UnitPrice:  line.UnitPrice,
+		})
+	}
+	if err := service.AcquisitionRepository.CreatePurchaseOrder(ctx, service.DB, order); err != nil {
+		return nil, response.RepositoryError()
+	}
+	order.Vendor = *vendor
+	order.Fund = *fund
+
+	return purchaseOrderResponse(order), nil
+}
+
+func (service *AcquisitionServiceImpl) SubmitPurchaseOrder(ctx context.Context, userId, id int) (*params.PurchaseOrderResponse, *response.CustomError) {
+	order, custErr := service.findPurchaseOrderForTransition(ctx, id, models.PurchaseOrderOrdered)
+	if custErr != nil {
+		return nil, custErr
+	}
+
+	now := time.Now()
+	by := uint(userId)
+	order.Status = models.PurchaseOrderOrdered
+	order.OrderedAt = &now
+	order.OrderedBy = &by
+	if err := service.AcquisitionRepository.UpdatePurchaseOrder(ctx, service.DB, order); err != nil {
+		return nil, response.RepositoryError()
+	}
+
+	return purchaseOrderResponse(order), nil
+}
+
+func (service *AcquisitionServiceImpl) CancelPurchaseOrder(ctx context.Context, id int) (*params.PurchaseOrderResponse, *response.CustomError) {
+	order, custErr := service.findPurchaseOrderForTransition(ctx, id, models.PurchaseOrderCancelled)
+	if custErr != nil {
+		return nil, custErr
+	}
+
+	order.Status = models.PurchaseOrderCancelled
+	if err := service.AcquisitionRepository.UpdatePurchaseOrder(ctx, service.DB, order); err != nil {
+		return nil, response.RepositoryError()
+	}
+
+	return purchaseOrderResponse(order), nil
+}
+
+func (service *AcquisitionServiceImpl) ReceivePurchaseOrder(ctx context.Context, id int, req *params.PurchaseOrderReceiveRequest) (*params.PurchaseOrderReceiveResponse, *response.CustomError) {
+	val := validator.New()
+	err := val.Struct(req)
+	if err != nil {
+		validationErrors := err.(validator.ValidationErrors)
+		var errors []interface{}
+		for _, fieldError := range validationErrors {
+			error := "error " + fieldError.Field() + " on tag " + fieldError.Tag()
+			errors = append(errors, error)
+		}
+		return nil, response.BadRequestErrorWithAdditionalInfo(errors)
+	}
+
+	var location *models.ShelfLocation
+	if req.ShelfLocationID != nil {
+		location, err = service.BranchRepository.FindShelfLocationById(ctx, service.DB, int(*req.ShelfLocationID))
+		if err != nil {
+			return nil, response.BadRequestErrorWithAdditionalInfo(err.Error())
+		}
+	}
+
+	var order *models.PurchaseOrder
+	var copies []*models.BookCopy
+	var custErr *response.CustomError
+	err = service.DB.Transaction(func(tx *gorm.DB) error {
+		// the order is read and checked inside the transaction, and each line
+		// only takes what it still has outstanding when it is written, so two
+		// receipts of the same copies cannot both go through
+		var err error
+		order, err = service.AcquisitionRepository.FindPurchaseOrderById(ctx, tx, id)
+		if err != nil {
+			custErr = response.NotFoundError()
+			return err
+		}
+		var lines map[uint]*models.PurchaseOrderLine
+		lines, custErr = receiptLines(order, req)
+		if custErr != nil {
+			return fmt.Errorf("%v", custErr.AdditionalInfo)
+		}
+
+		for _, item := range req.Lines {
+			line := lines[item.LineID]
+			book, err := service.catalogBook(ctx, tx, line)
+			if err != nil {
+				return err
+			}
+			for i := 0; i < item.Quantity; i++ {
+				var bookCopy = new(models.BookCopy)
+				bookCopy.BookID = book.ID
+				bookCopy.Barcode = fmt.Sprintf("PO%d-%d-%d", order.ID, line.ID, line.ReceivedQuantity+i+1)
+				if len(item.Barcodes) > 0 {
+					bookCopy.Barcode = item.Barcodes[i]
+				}
+				bookCopy.Status = models.CopyAvailable
+				if location != nil {
+					bookCopy.ShelfLocationID = &location.ID
+				}
+				if err := service.BookCopyRepository.CreateBookCopy(ctx, tx, bookCopy); err != nil {
+					return err
+				}
+				bookCopy.ShelfLocation = location
+				copies = append(copies, bookCopy)
+			}
+			line.BookID = &book.ID
+			if err := service.AcquisitionRepository.ReceivePurchaseOrderLine(ctx, tx, line, item.Quantity); err != nil {
+				custErr = response.ConflictErrorWithAdditionalInfo(fmt.Sprintf("line %d was received meanwhile", line.ID))
+				return err
+			}
+			line.ReceivedQuantity += item.Quantity
+		}
+
+		order.Status = models.PurchaseOrderReceived
+		for _, line := range order.Lines {
+			if line.ReceivedQuantity < line.Quantity {
+				order.Status = models.PurchaseOrderPartiallyReceived
+			}
+		}
+		return service.AcquisitionRepository.UpdatePurchaseOrder(ctx, tx, order)
+	})
+	if custErr != nil {
+		return nil, custErr
+	}
+	if err != nil {
+		return nil, response.RepositoryErrorWithAdditionalInfo(err.Error())
+	}
+
+	result := &params.PurchaseOrderReceiveResponse{
+		PurchaseOrder: purchaseOrderResponse(order),
+		Copies:        []*params.BookCopyResponse{},
+	}
+	for _, bookCopy := range copies {
+		result.Copies = append(result.Copies, bookCopyResponse(bookCopy))
+	}
+	return result, nil
+}
+
+// receiptLines checks that order can receive the lines of req, and returns
+// the lines of the order by id.
+func receiptLines(order *models.PurchaseOrder, req *params.PurchaseOrderReceiveRequest) (map[uint]*models.PurchaseOrderLine, *response.CustomError) {
+	if order.Status != models.PurchaseOrderOrdered && order.Status != models.PurchaseOrderPartiallyReceived {
+		return nil, response.BadRequestErrorWithAdditionalInfo(fmt.Sprintf("cannot receive a %s purchase order", order.Status))
+	}
+
+	lines := make(map[uint]*models.PurchaseOrderLine)
+	for i := range order.Lines {
+		lines[order.Lines[i].ID] = &order.Lines[i]
+	}
+	received := make(map[uint]bool)
+	for _, item := range req.Lines {
+		line, ok := lines[item.LineID]
+		if !ok {
+			return nil, response.BadRequestErrorWithAdditionalInfo(fmt.Sprintf("line %d is not on this purchase order", item.LineID))
+		}
+		if received[item.LineID] {
+			return nil, response.BadRequestErrorWithAdditionalInfo(fmt.Sprintf("line %d is received twice", item.LineID))
+		}
+		received[item.LineID] = true
+		if outstanding := line.Quantity - line.ReceivedQuantity; item.Quantity > outstanding {
+			return nil, response.BadRequestErrorWithAdditionalInfo(fmt.Sprintf("line %d has only %d copies outstanding", item.LineID, outstanding))
+		}
+		if len(item.Barcodes) > 0 && len(item.Barcodes) != item.Quantity {
+			return nil, response.BadRequestErrorWithAdditionalInfo(fmt.Sprintf("line %d needs one barcode per copy", item.LineID))
+		}
+	}
+	return lines, nil
+}
+
+// catalogBook returns the book a purchase order line is for, adding it and
+// its author to the catalog with tx when they are not there yet, along with
+// their events.
+func (service *AcquisitionServiceImpl) catalogBook(ctx context.Context, tx *gorm.DB, line *models.PurchaseOrderLine) (*models.Book, error) {
+	if book, err := service.BookRepository.FindBookByISBN(ctx, tx, line.ISBN); err == nil {
+		return book, nil
+	}
+
+	author, err := service.AuthorRepository.FindAuthorByName(ctx, tx, line.AuthorName)
+	if err != nil {
+		author = &models.Author{Name: line.AuthorName}
+		if err := service.AuthorRepository.CreateAuthor(ctx, tx, author); err != nil {
+			return nil, err
+		}
+		if err := record(ctx, tx, service.Events, EventAuthorCreated, author.ID, authorResponse(author)); err != nil {
+			return nil, err
+		}
+	}
+
+	var book = new(models.Book)
+	book.Title = line.Title
+	book.ISBN = line.ISBN
+	book.AuthorID = author.ID
+	if err := service.BookRepository.CreateBook(ctx, tx, book); err != nil {
+		return nil, err
+	}
+	book.Author = *author
+	if err := record(ctx, tx, service.Events, EventBookCreated, book.ID, bookResponse(book, nil, repositories.BookLoad{Author: true})); err != nil {
+		return nil, err
+	}
+	return book, nil
+}
+
+func (service *AcquisitionServiceImpl) findPurchaseOrderForTransition(ctx context.Context, id int, status string) (*models.PurchaseOrder, *response.CustomError) {
+	order, err := service.AcquisitionRepository.FindPurchaseOrderById(ctx, service.DB, id)
+	if err != nil {
+		return nil, response.NotFoundError()
+	}
+	for _, from := range purchaseOrderTransitions[status] {
+		if order.Status == from {
+			return order, nil
+		}
+	}
+	return nil, response.BadRequestErrorWithAdditionalInfo(fmt.Sprintf("cannot move a %s purchase order to %s", order.Status, status))
+}
+
+func vendorResponse(vendor *models.Vendor) *params.VendorResponse {
+	return &params.VendorResponse{
+		ID:    vendor.ID,
+		Name:  vendor.Name,
+		Email: vendor.Email,
+		Phone: vendor.Phone,
+	}
+}
+
+func fundResponse(fund *models.Fund) *params.FundResponse {
+	return &params.FundResponse{
+		ID:   fund.ID,
+		Code: fund.Code,
+		Name: fund.Name,
+	}
+}
+
+func fundBudgetResponse(fund *models.Fund, spending *repositories.FundSpending) *params.FundResponse {
+	if spending == nil {
+		spending = &repositories.FundSpending{FundID: fund.ID}
+	}
+	result := fundResponse(fund)
+	result.Budget = &params.FundBudgetResponse{
+		Allocation: fund.Allocation,
+		Committed:  spending.Committed,
+		Spent:      spending.Spent,
+		Available:  fund.Allocation - spending.Committed,
+	}
+	return result
+}
+
+func purchaseOrderResponse(order *models.PurchaseOrder) *params.PurchaseOrderResponse {
+	result := &params.PurchaseOrderResponse{
+		ID:      order.ID,
+		Status:  order.Status,
+		Vendor:  vendorResponse(&order.Vendor),
+		Fund:    fundResponse(&order.Fund),
+		Lines:   []*params.PurchaseOrderLineResponse{},
+		Created: &params.StepResponse{At: order.CreatedAt.Format(time.RFC3339), By: order.CreatedBy},
+		Ordered: stepResponse(order.OrderedAt, order.OrderedBy),
+	}
+	for _, line := range order.Lines {
+		total := int64(line.Quantity) * line.UnitPrice
+		result.Total += total
+		result.Lines = append(result.Lines, &params.PurchaseOrderLineResponse{
+			ID:               line.ID,
+			ISBN:             line.ISBN,
+			Title:            line.Title,
+			AuthorName:       line.AuthorName,
+			Quantity:         line.Quantity,
+			ReceivedQuantity: line.ReceivedQuantity,
+			Outstanding:      line.Quantity - line.ReceivedQuantity,
+			UnitPrice:        line.UnitPrice,
+			Total:            total,
+			BookID:           line.BookID,
+		})
+	}
+	return result
+}
@@ -0,0 +1,204 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"golang-backend-test/app/models"
+	"golang-backend-test/app/params"
+	"golang-backend-test/app/repositories"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func newTestAcquisitionService(db *gorm.DB) (AcquisitionService, *repositories.MockAcquisitionRepository, *repositories.MockBookRepository, *repositories.MockAuthorRepository, *repositories.MockBookCopyRepository) {
+	acquisitionRepo := new(repositories.MockAcquisitionRepository)
+	bookRepo := new(repositories.MockBookRepository)
+	authorRepo := new(repositories.MockAuthorRepository)
+	bookCopyRepo := new(repositories.MockBookCopyRepository)
+	branchRepo := new(repositories.MockBranchRepository)
+	service := NewAcquisitionService(acquisitionRepo, bookRepo, authorRepo, bookCopyRepo, branchRepo, nil, db)
+	return service, acquisitionRepo, bookRepo, authorRepo, bookCopyRepo
+}
+
+func newTestPurchaseOrder(status string) *models.PurchaseOrder {
+	return &models.PurchaseOrder{
+		ID:     1,
+		Status: status,
+		Lines: []models.PurchaseOrderLine{
+			{ID: 1, PurchaseOrderID: 1, ISBN: "9780000000011", Title: "Known", AuthorName: "Known Author", Quantity: 2, UnitPrice: 1500},
+			{ID: 2, PurchaseOrderID: 1, ISBN: "9780000000028", Title: "New", AuthorName: "New Author", Quantity: 3, UnitPrice: 2000},
+		},
+	}
+}
+
+func TestCreatePurchaseOrder_ValidationError(t *testing.T) {
+	service, acquisitionRepo, _, _, _ := newTestAcquisitionService(new(gorm.DB))
+
+	result, err := service.CreatePurchaseOrder(context.Background(), 7, &params.PurchaseOrderRequest{VendorID: 1, FundID: 1})
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "BAD REQUEST ERROR", err.Message)
+	acquisitionRepo.AssertNotCalled(t, "CreatePurchaseOrder", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSubmitPurchaseOrder_InvalidState(t *testing.T) {
+	db := new(gorm.DB)
+	service, acquisitionRepo, _, _, _ := newTestAcquisitionService(db)
+
+	acquisitionRepo.On("FindPurchaseOrderById", mock.Anything, db, 1).Return(newTestPurchaseOrder(models.PurchaseOrderReceived), nil)
+
+	result, err := service.SubmitPurchaseOrder(context.Background(), 7, 1)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "cannot move a received purchase order to ordered", err.AdditionalInfo)
+	acquisitionRepo.AssertNotCalled(t, "UpdatePurchaseOrder", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestReceivePurchaseOrder_OverOutstanding(t *testing.T) {
+	db, errDB := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	assert.Nil(t, errDB)
+	service, acquisitionRepo, _, _, bookCopyRepo := newTestAcquisitionService(db)
+	order := newTestPurchaseOrder(models.PurchaseOrderPartiallyReceived)
+	order.Lines[1].ReceivedQuantity = 2
+
+	acquisitionRepo.On("FindPurchaseOrderById", mock.Anything, mock.Anything, 1).Return(order, nil)
+
+	result, err := service.ReceivePurchaseOrder(context.Background(), 1, &params.PurchaseOrderReceiveRequest{
+		Lines: []params.PurchaseOrderReceiveLineRequest{{LineID: 2, Quantity: 2}},
+	})
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "line 2 has only 1 copies outstanding", err.AdditionalInfo)
+	bookCopyRepo.AssertNotCalled(t, "CreateBookCopy", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestReceivePurchaseOrder_CatalogsNewBook(t *testing.T) {
+	db, errDB := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	assert.Nil(t, errDB)
+	service, acquisitionRepo, bookRepo, authorRepo, bookCopyRepo := newTestAcquisitionService(db)
+	order := newTestPurchaseOrder(models.PurchaseOrderOrdered)
+
+	acquisitionRepo.On("FindPurchaseOrderById", mock.Anything, mock.Anything, 1).Return(order, nil)
+	bookRepo.On("FindBookByISBN", mock.Anything, mock.Anything, "9780000000011").Return(&models.Book{ID: 4, ISBN: "9780000000011"}, nil)
+	bookRepo.On("FindBookByISBN", mock.Anything, mock.Anything, "9780000000028").Return(nil, errors.New("book not found"))
+	authorRepo.On("FindAuthorByName", mock.Anything, mock.Anything, "New Author").Return(nil, errors.New("author not found"))
+	authorRepo.On("CreateAuthor", mock.Anything, mock.Anything, mock.MatchedBy(func(author *models.Author) bool {
+		return author.Name == "New Author"
+	})).Run(func(args mock.Arguments) {
+		args.Get(2).(*models.Author).ID = 9
+	}).Return(nil)
+	bookRepo.On("CreateBook", mock.Anything, mock.Anything, mock.MatchedBy(func(book *models.Book) bool {
+		return book.ISBN == "9780000000028" && book.Title == "New" && book.AuthorID == 9
+	})).Run(func(args mock.Arguments) {
+		args.Get(2).(*models.Book).ID = 5
+	}).Return(nil)
+	bookCopyRepo.On("CreateBookCopy", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	acquisitionRepo.On("ReceivePurchaseOrderLine", mock.Anything, mock.Anything, &order.Lines[0], 2).Return(nil)
+	acquisitionRepo.On("ReceivePurchaseOrderLine", mock.Anything, mock.Anything, &order.Lines[1], 1).Return(nil)
+	acquisitionRepo.On("UpdatePurchaseOrder", mock.Anything, mock.Anything, order).Return(nil)
+
+	result, err := service.ReceivePurchaseOrder(context.Background(), 1, &params.PurchaseOrderReceiveRequest{
+		Lines: []params.PurchaseOrderReceiveLineRequest{
+			{LineID: 1, Quantity: 2, Barcodes: []string{"B1", "B2"}},
+			{LineID: 2, Quantity: 1},
+		},
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, models.PurchaseOrderPartiallyReceived, result.PurchaseOrder.Status)
+	assert.Len(t, result.Copies, 3)
+	assert.Equal(t, "B1", result.Copies[0].Barcode)
+	assert.Equal(t, "PO1-2-1", result.Copies[2].Barcode)
+	assert.Equal(t, uint(4), *order.Lines[0].BookID)
+	assert.Equal(t, uint(5), *order.Lines[1].BookID)
+	assert.Equal(t, 1, order.Lines[1].ReceivedQuantity)
+	bookRepo.AssertExpectations(t)
+	authorRepo.AssertExpectations(t)
+	bookCopyRepo.AssertNumberOfCalls(t, "CreateBookCopy", 3)
+}
+
+func TestReceivePurchaseOrder_RecordsCatalogEvents(t *testing.T) {
+	db, errDB := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	assert.Nil(t, errDB)
+	acquisitionRepo := new(repositories.MockAcquisitionRepository)
+	bookRepo := new(repositories.MockBookRepository)
+	authorRepo := new(repositories.MockAuthorRepository)
+	bookCopyRepo := new(repositories.MockBookCopyRepository)
+	events := new(MockEventRecorder)
+	service := NewAcquisitionService(acquisitionRepo, bookRepo, authorRepo, bookCopyRepo, new(repositories.MockBranchRepository), events, db)
+	order := newTestPurchaseOrder(models.PurchaseOrderOrdered)
+
+	acquisitionRepo.On("FindPurchaseOrderById", mock.Anything, mock.Anything, 1).Return(order, nil)
+	bookRepo.On("FindBookByISBN", mock.Anything, mock.Anything, "9780000000028").Return(nil, errors.New("book not found"))
+	authorRepo.On("FindAuthorByName", mock.Anything, mock.Anything, "New Author").Return(nil, errors.New("author not found"))
+	authorRepo.On("CreateAuthor", mock.Anything, mock.Anything, mock.AnythingOfType("*models.Author")).Run(func(args mock.Arguments) {
+		args.Get(2).(*models.Author).ID = 9
+	}).Return(nil)
+	bookRepo.On("CreateBook", mock.Anything, mock.Anything, mock.AnythingOfType("*models.Book")).Run(func(args mock.Arguments) {
+		args.Get(2).(*models.Book).ID = 5
+	}).Return(nil)
+	events.On("Record", mock.Anything, mock.Anything, EventAuthorCreated, uint(9), mock.MatchedBy(func(author *params.AuthorResponse) bool {
+		return author.Name == "New Author"
+	})).Return(nil)
+	events.On("Record", mock.Anything, mock.Anything, EventBookCreated, uint(5), mock.MatchedBy(func(book *params.BookResponse) bool {
+		return book.ISBN == "9780000000028" && book.AuthorResponse.Name == "New Author"
+	})).Return(nil)
+	bookCopyRepo.On("CreateBookCopy", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	acquisitionRepo.On("ReceivePurchaseOrderLine", mock.Anything, mock.Anything, &order.Lines[1], 1).Return(nil)
+	acquisitionRepo.On("UpdatePurchaseOrder", mock.Anything, mock.Anything, order).Return(nil)
+
+	_, err := service.ReceivePurchaseOrder(context.Background(), 1, &params.PurchaseOrderReceiveRequest{
+		Lines: []params.PurchaseOrderReceiveLineRequest{{LineID: 2, Quantity: 1}},
+	})
+
+	assert.Nil(t, err)
+	events.AssertExpectations(t)
+}
+
+func TestReceivePurchaseOrder_ReceivedMeanwhile(t *testing.T) {
+	db, errDB := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	assert.Nil(t, errDB)
+	service, acquisitionRepo, bookRepo, _, bookCopyRepo := newTestAcquisitionService(db)
+	order := newTestPurchaseOrder(models.PurchaseOrderOrdered)
+
+	// another receipt took the outstanding copies after the order was read
+	acquisitionRepo.On("FindPurchaseOrderById", mock.Anything, mock.Anything, 1).Return(order, nil)
+	bookRepo.On("FindBookByISBN", mock.Anything, mock.Anything, "9780000000011").Return(&models.Book{ID: 4, ISBN: "9780000000011"}, nil)
+	bookCopyRepo.On("CreateBookCopy", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	acquisitionRepo.On("ReceivePurchaseOrderLine", mock.Anything, mock.Anything, &order.Lines[0], 2).Return(errors.New("purchase order line not found"))
+
+	result, err := service.ReceivePurchaseOrder(context.Background(), 1, &params.PurchaseOrderReceiveRequest{
+		Lines: []params.PurchaseOrderReceiveLineRequest{{LineID: 1, Quantity: 2}},
+	})
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, 409, err.StatusCode)
+	assert.Equal(t, "line 1 was received meanwhile", err.AdditionalInfo)
+	acquisitionRepo.AssertNotCalled(t, "UpdatePurchaseOrder", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestFindDetailFund_Budget(t *testing.T) {
+	db := new(gorm.DB)
+	service, acquisitionRepo, _, _, _ := newTestAcquisitionService(db)
+
+	acquisitionRepo.On("FindFundById", mock.Anything, db, 1).Return(&models.Fund{ID: 1, Code: "FIC", Name: "Fiction", Allocation: 10000}, nil)
+	acquisitionRepo.On("GetFundSpending", mock.Anything, db, []uint{1}).Return(map[uint]*repositories.FundSpending{
+		1: {FundID: 1, Committed: 7500, Spent: 3000},
+	}, nil)
+
+	result, err := service.FindDetailFund(context.Background(), 1)
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(10000), result.Budget.Allocation)
+	assert.Equal(t, int64(7500), result.Budget.Committed)
+	assert.Equal(t, int64(3000), result.Budget.Spent)
+	assert.Equal(t, int64(2500), result.Budget.Available)
+}
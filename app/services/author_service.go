@@ -2,11 +2,16 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"golang-backend-test/app/commons/response"
 	"golang-backend-test/app/models"
 	"golang-backend-test/app/params"
 	"golang-backend-test/app/repositories"
+	"golang-backend-test/pkg/identifier"
+	"golang-backend-test/pkg/patch"
+	"golang-backend-test/pkg/similarity"
+	"sort"
 	"time"
 
 	"github.com/go-playground/validator"
@@ -16,37 +21,73 @@ import (
 type AuthorService interface {
 	FindDetailAuthor(ctx context.Context, id int) (*params.AuthorResponse, *response.CustomError)
 	FindAllAuthors(ctx context.Context) ([]*params.AuthorResponse, *response.CustomError)
-	CrateAuthor(ctx context.Context, req *params.AuthorRequest) *response.CustomError
+	FindAuthorsByIds(ctx context.Context, ids []int) ([]*params.AuthorResponse, *response.CustomError)
+	FindAuthorsPage(ctx context.Context, req *params.PaginationRequest) (*params.PaginationResponse, *response.CustomError)
+	SearchAuthors(ctx context.Context, query string) ([]*params.AuthorResponse, *response.CustomError)
+	CrateAuthor(ctx context.Context, req *params.AuthorRequest) (*params.AuthorResponse, *response.CustomError)
 	UpdateAuthor(ctx context.Context, id int, req *params.AuthorRequest) (*params.AuthorResponse, *response.CustomError)
+	PatchAuthor(ctx context.Context, id int, patchType string, patchDoc []byte) (*params.AuthorResponse, *response.CustomError)
 	DeleteAuthor(ctx context.Context, id int) *response.CustomError
+	FindDuplicateAuthors(ctx context.Context, minScore float64) ([]*params.AuthorDuplicateResponse, *response.CustomError)
+	MergeAuthor(ctx context.Context, id int, req *params.AuthorMergeRequest) (*params.AuthorResponse, *response.CustomError)
+	FindAuthorBooks(ctx context.Context, id int, req *params.PaginationRequest) (*params.PaginationResponse, *response.CustomError)
+	FindAuthorStats(ctx context.Context, id int) (*params.AuthorStatsResponse, *response.CustomError)
+}
+
+var authorSorts = keysetSorts{
+	"id":         {order: repositories.KeysetOrder{}},
+	"-id":        {order: repositories.KeysetOrder{Desc: true}},
+	"name":       {order: repositories.KeysetOrder{Column: "name"}},
+	"-name":      {order: repositories.KeysetOrder{Column: "name", Desc: true}},
+	"birthdate":  {order: repositories.KeysetOrder{Column: "birthdate"}, time: true},
+	"-birthdate": {order: repositories.KeysetOrder{Column: "birthdate", Desc: true}, time: true},
+}
+
+var authorBooksSorts = keysetSorts{
+	"publication_date":  {order: repositories.KeysetOrder{Column: "publication_date"}, time: true},
+	"-publication_date": {order: repositories.KeysetOrder{Column: "publication_date", Desc: true}, time: true},
 }
 
 type AuthorServiceImpl struct {
 	AuthorRepository repositories.AuthorRepository
+	Events           EventRecorder
 	DB               *gorm.DB
 }
 
-func NewAuthorService(authorRepository repositories.AuthorRepository, db *gorm.DB) AuthorService {
+// NewAuthorService builds the author service. events may be nil, in which
+// case no events are recorded.
+func NewAuthorService(authorRepository repositories.AuthorRepository, events EventRecorder, db *gorm.DB) AuthorService {
 	return &AuthorServiceImpl{
 		AuthorRepository: authorRepository,
+		Events:           events,
 		DB:               db,
 	}
 }
 
 func (service *AuthorServiceImpl) FindDetailAuthor(ctx context.Context, id int) (*params.AuthorResponse, *response.CustomError) {
-	author, err := service.AuthorRepository.FindAuthorById(ctx, service.DB, id)
+	author, err := service.findAuthor(ctx, id)
 	if err != nil {
 		return nil, response.NotFoundError()
 	}
 
-	return &params.AuthorResponse{
-		ID:        author.ID,
-		Name:      author.Name,
-		Birthdate: author.Birthdate.Format("2006-01-02"),
-	}, nil
+	return authorResponse(author), nil
 
 }
 
+// findAuthor returns the author with id or, when it was merged, the author
+// it was merged into.
+func (service *AuthorServiceImpl) findAuthor(ctx context.Context, id int) (*models.Author, error) {
+	author, err := service.AuthorRepository.FindAuthorById(ctx, service.DB, id)
+	if err == nil {
+		return author, nil
+	}
+	redirect, errRedirect := service.AuthorRepository.FindAuthorRedirect(ctx, service.DB, id)
+	if errRedirect != nil {
+		return nil, err
+	}
+	return service.AuthorRepository.FindAuthorById(ctx, service.DB, int(redirect.AuthorID))
+}
+
 func (service *AuthorServiceImpl) FindAllAuthors(ctx context.Context) ([]*params.AuthorResponse, *response.CustomError) {
 	authors, err := service.AuthorRepository.GetListAuthors(ctx, service.DB)
 	if err != nil {
@@ -54,16 +95,26 @@ func (service *AuthorServiceImpl) FindAllAuthors(ctx context.Context) ([]*params
 	}
 	var AuthorResponses []*params.AuthorResponse
 	for _, author := range authors {
-		AuthorResponses = append(AuthorResponses, &params.AuthorResponse{
-			ID:        author.ID,
-			Name:      author.Name,
-			Birthdate: author.Birthdate.Format("2006-01-02"),
-		})
+		AuthorResponses = append(AuthorResponses, authorResponse(author))
 	}
 	return AuthorResponses, nil
 }
 
-func (service *AuthorServiceImpl) CrateAuthor(ctx context.Context, req *params.AuthorRequest) *response.CustomError {
+// FindAuthorsByIds returns the authors found among ids, in no particular
+// order. Missing ids are left out.
+func (service *AuthorServiceImpl) FindAuthorsByIds(ctx context.Context, ids []int) ([]*params.AuthorResponse, *response.CustomError) {
+	authors, err := service.AuthorRepository.FindAuthorsByIds(ctx, service.DB, ids)
+	if err != nil {
+		return nil, response.RepositoryError()
+	}
+	authorResponses := []*params.AuthorResponse{}
+	for _, author := range authors {
+		authorResponses = append(authorResponses, authorResponse(author))
+	}
+	return authorResponses, nil
+}
+
+func (service *AuthorServiceImpl) FindAuthorsPage(ctx context.Context, req *params.PaginationRequest) (*params.PaginationResponse, *response.CustomError) {
 	val := validator.New()
 	err := val.Struct(req)
 	if err != nil {
@@ -73,24 +124,284 @@ func (service *AuthorServiceImpl) CrateAuthor(ctx context.Context, req *params.A
 			error := "error " + fieldError.Field() + " on tag " + fieldError.Tag()
 			errors = append(errors, error)
 		}
-		return response.BadRequestErrorWithAdditionalInfo(errors)
+		return nil, response.BadRequestErrorWithAdditionalInfo(errors)
+	}
+	if custErr := cursorOnly(req); custErr != nil {
+		return nil, custErr
+	}
+	if req.Limit == 0 {
+		req.Limit = 10
+	}
+	if req.Sort == "" {
+		req.Sort = "id"
+	}
+	sort, custErr := authorSorts.find(req.Sort)
+	if custErr != nil {
+		return nil, custErr
+	}
+	page, custErr := keysetPage(req, sort)
+	if custErr != nil {
+		return nil, custErr
 	}
 
-	var author = new(models.Author)
-	author.Name = req.Name
-	birthdate, err := time.Parse("2006-01-02", req.Birthdate)
+	authors, more, total, err := service.AuthorRepository.GetAuthorsPage(ctx, service.DB, page)
 	if err != nil {
-		return response.BadRequestErrorWithAdditionalInfo(fmt.Sprintf("Invalid date format: %s", err.Error()))
+		return nil, response.RepositoryError()
 	}
-	author.Birthdate = birthdate
-	if err := service.AuthorRepository.CreateAuthor(ctx, service.DB, author); err != nil {
-		return response.BadRequestError()
+	authorResponses := []*params.AuthorResponse{}
+	var rows []keysetRow
+	for _, author := range authors {
+		authorResponses = append(authorResponses, authorResponse(author))
+		rows = append(rows, keysetRow{value: authorSortValue(author, sort.order.Column), id: author.ID})
 	}
 
-	return nil
+	result := &params.PaginationResponse{
+		Items: authorResponses,
+		Limit: req.Limit,
+		Total: total,
+	}
+	hasNext, hasPrev := keysetNeighbours(page, more)
+	result.NextCursor, result.PrevCursor = pageCursors(req.Sort, rows, hasNext, hasPrev)
+	return result, nil
+}
+
+func (service *AuthorServiceImpl) SearchAuthors(ctx context.Context, query string) ([]*params.AuthorResponse, *response.CustomError) {
+	authors, err := service.AuthorRepository.SearchAuthors(ctx, service.DB, query)
+	if err != nil {
+		return nil, response.BadRequestError()
+	}
+	var AuthorResponses []*params.AuthorResponse
+	for _, author := range authors {
+		AuthorResponses = append(AuthorResponses, authorResponse(author))
+	}
+	return AuthorResponses, nil
+}
+
+func (service *AuthorServiceImpl) CrateAuthor(ctx context.Context, req *params.AuthorRequest) (*params.AuthorResponse, *response.CustomError) {
+	author, custErr := parseAuthorRequest(req)
+	if custErr != nil {
+		return nil, custErr
+	}
+
+	result, err := service.saveAuthor(ctx, EventAuthorCreated, author, func(tx *gorm.DB) error {
+		return service.AuthorRepository.CreateAuthor(ctx, tx, author)
+	})
+	if err != nil {
+		return nil, response.BadRequestError()
+	}
+	return result, nil
 }
 
 func (service *AuthorServiceImpl) UpdateAuthor(ctx context.Context, id int, req *params.AuthorRequest) (*params.AuthorResponse, *response.CustomError) {
+	author, custErr := parseAuthorRequest(req)
+	if custErr != nil {
+		return nil, custErr
+	}
+
+	// a merged author is updated through the author it was merged into
+	current, err := service.findAuthor(ctx, id)
+	if err != nil {
+		return nil, response.NotFoundError()
+	}
+
+	author.ID = current.ID
+	result, err := service.saveAuthor(ctx, EventAuthorUpdated, author, func(tx *gorm.DB) error {
+		return service.AuthorRepository.UpdateAuthor(ctx, tx, author)
+	})
+	if err != nil {
+		return nil, response.BadRequestError()
+	}
+	return result, nil
+}
+
+func (service *AuthorServiceImpl) PatchAuthor(ctx context.Context, id int, patchType string, patchDoc []byte) (*params.AuthorResponse, *response.CustomError) {
+	current, err := service.findAuthor(ctx, id)
+	if err != nil {
+		return nil, response.NotFoundError()
+	}
+
+	var req = new(params.AuthorRequest)
+	if err := patch.Apply(patchType, authorRequest(current), patchDoc, req); err != nil {
+		if errors.Is(err, patch.ErrUnsupportedMediaType) {
+			return nil, response.UnsupportedMediaTypeErrorWithAdditionalInfo(patchType)
+		}
+		return nil, response.BadRequestErrorWithAdditionalInfo(err.Error())
+	}
+
+	author, custErr := parseAuthorRequest(req)
+	if custErr != nil {
+		return nil, custErr
+	}
+
+	author.ID = current.ID
+	result, err := service.saveAuthor(ctx, EventAuthorUpdated, author, func(tx *gorm.DB) error {
+		return service.AuthorRepository.UpdateAuthor(ctx, tx, author)
+	})
+	if err != nil {
+		return nil, response.BadRequestError()
+	}
+	return result, nil
+}
+
+func (service *AuthorServiceImpl) DeleteAuthor(ctx context.Context, id int) *response.CustomError {
+	err := inTransaction(service.DB, service.Events, func(tx *gorm.DB) error {
+		if err := service.AuthorRepository.DeleteAuthor(ctx, tx, id); err != nil {
+			return err
+		}
+		return record(ctx, tx, service.Events, EventAuthorDeleted, uint(id), &params.DeletedResponse{ID: uint(id)})
+	})
+	if err != nil {
+		// deleting a merged author drops its redirect and leaves the author
+		// it was merged into alone
+		if errRedirect := service.AuthorRepository.DeleteAuthorRedirect(ctx, service.DB, id); errRedirect == nil {
+			return nil
+		}
+		return response.NotFoundError()
+	}
+
+	return nil
+}
+
+// saveAuthor saves author with save and records event about it in the same
+// transaction.
+func (service *AuthorServiceImpl) saveAuthor(ctx context.Context, event string, author *models.Author, save func(tx *gorm.DB) error) (*params.AuthorResponse, error) {
+	var result *params.AuthorResponse
+	err := inTransaction(service.DB, service.Events, func(tx *gorm.DB) error {
+		if err := save(tx); err != nil {
+			return err
+		}
+		result = authorResponse(author)
+		return record(ctx, tx, service.Events, event, author.ID, result)
+	})
+	return result, err
+}
+
+func (service *AuthorServiceImpl) FindDuplicateAuthors(ctx context.Context, minScore float64) ([]*params.AuthorDuplicateResponse, *response.CustomError) {
+	authors, err := service.AuthorRepository.GetListAuthors(ctx, service.DB)
+	if err != nil {
+		return nil, response.BadRequestError()
+	}
+
+	// names are normalized once and only authors sharing a blocking key are
+	// scored against each other
+	names := make([]string, len(authors))
+	blocks := map[string][]int{}
+	for i, author := range authors {
+		names[i] = similarity.NormalizeName(author.Name)
+		for _, key := range similarity.NameKeys(names[i]) {
+			blocks[key] = append(blocks[key], i)
+		}
+	}
+
+	var duplicates []*params.AuthorDuplicateResponse
+	for i := range authors {
+		candidates := map[int]bool{}
+		for _, key := range similarity.NameKeys(names[i]) {
+			for _, j := range blocks[key] {
+				if j > i {
+					candidates[j] = true
+				}
+			}
+		}
+		others := make([]int, 0, len(candidates))
+		for j := range candidates {
+			others = append(others, j)
+		}
+		sort.Ints(others)
+
+		for _, j := range others {
+			score := duplicateScore(authors[i], authors[j], names[i], names[j])
+			if score < minScore {
+				continue
+			}
+			duplicates = append(duplicates, &params.AuthorDuplicateResponse{
+				Author:    authorResponse(authors[i]),
+				Duplicate: authorResponse(authors[j]),
+				Score:     score,
+			})
+		}
+	}
+	sort.SliceStable(duplicates, func(i, j int) bool {
+		return duplicates[i].Score > duplicates[j].Score
+	})
+	return duplicates, nil
+}
+
+// duplicateScore weighs the similarity of the normalized names against
+// birthdate agreement. An unknown birthdate on either side neither confirms
+// nor rules out a match.
+func duplicateScore(a, b *models.Author, aName, bName string) float64 {
+	birthdateScore := 0.5
+	if !a.Birthdate.IsZero() && !b.Birthdate.IsZero() {
+		birthdateScore = 0
+		if a.Birthdate.Equal(b.Birthdate) {
+			birthdateScore = 1
+		}
+	}
+	return similarity.NormalizedSimilarity(aName, bName)*0.8 + birthdateScore*0.2
+}
+
+func (service *AuthorServiceImpl) MergeAuthor(ctx context.Context, id int, req *params.AuthorMergeRequest) (*params.AuthorResponse, *response.CustomError) {
+	val := validator.New()
+	err := val.Struct(req)
+	if err != nil {
+		validationErrors := err.(validator.ValidationErrors)
+		var errors []interface{}
+		for _, fieldError := range validationErrors {
+			error := "error " + fieldError.Field() + " on tag " + fieldError.Tag()
+			errors = append(errors, error)
+		}
+		return nil, response.BadRequestErrorWithAdditionalInfo(errors)
+	}
+	if int(req.DuplicateID) == id {
+		return nil, response.BadRequestErrorWithAdditionalInfo("author cannot be merged into itself")
+	}
+
+	survivor, err := service.AuthorRepository.FindAuthorById(ctx, service.DB, id)
+	if err != nil {
+		return nil, response.NotFoundError()
+	}
+	duplicate, err := service.AuthorRepository.FindAuthorById(ctx, service.DB, int(req.DuplicateID))
+	if err != nil {
+		return nil, response.NotFoundError()
+	}
+
+	err = service.DB.Transaction(func(tx *gorm.DB) error {
+		if err := service.AuthorRepository.ReassignBooks(ctx, tx, int(duplicate.ID), int(survivor.ID)); err != nil {
+			return err
+		}
+		if err := service.AuthorRepository.ReassignAliases(ctx, tx, int(duplicate.ID), int(survivor.ID)); err != nil {
+			return err
+		}
+		if err := service.AuthorRepository.ReassignPseudonyms(ctx, tx, int(duplicate.ID), int(survivor.ID)); err != nil {
+			return err
+		}
+		if duplicate.Name != survivor.Name {
+			alias := &models.AuthorAlias{AuthorID: survivor.ID, Name: duplicate.Name}
+			if err := service.AuthorRepository.CreateAuthorAlias(ctx, tx, alias); err != nil {
+				return err
+			}
+		}
+		if err := service.AuthorRepository.ReassignRedirects(ctx, tx, int(duplicate.ID), int(survivor.ID)); err != nil {
+			return err
+		}
+		redirect := &models.AuthorRedirect{ID: duplicate.ID, AuthorID: survivor.ID}
+		if err := service.AuthorRepository.CreateAuthorRedirect(ctx, tx, redirect); err != nil {
+			return err
+		}
+		if err := service.AuthorRepository.DeleteAuthor(ctx, tx, int(duplicate.ID)); err != nil {
+			return err
+		}
+		return record(ctx, tx, service.Events, EventAuthorDeleted, duplicate.ID, &params.DeletedResponse{ID: duplicate.ID, MergedInto: survivor.ID})
+	})
+	if err != nil {
+		return nil, response.RepositoryErrorWithAdditionalInfo(err.Error())
+	}
+
+	return service.FindDetailAuthor(ctx, int(survivor.ID))
+}
+
+func (service *AuthorServiceImpl) FindAuthorBooks(ctx context.Context, id int, req *params.PaginationRequest) (*params.PaginationResponse, *response.CustomError) {
 	val := validator.New()
 	err := val.Struct(req)
 	if err != nil {
@@ -102,31 +413,207 @@ func (service *AuthorServiceImpl) UpdateAuthor(ctx context.Context, id int, req
 		}
 		return nil, response.BadRequestErrorWithAdditionalInfo(errors)
 	}
+	if req.Page == 0 && req.Cursor == "" {
+		req.Page = 1
+	}
+	if req.Limit == 0 {
+		req.Limit = 10
+	}
+	if req.Sort == "" {
+		req.Sort = "publication_date"
+	}
+	sort, custErr := authorBooksSorts.find(req.Sort)
+	if custErr != nil {
+		return nil, custErr
+	}
+	page, custErr := keysetPage(req, sort)
+	if custErr != nil {
+		return nil, custErr
+	}
+
+	// a merged author's books are those of the author it was merged into
+	author, err := service.findAuthor(ctx, id)
+	if err != nil {
+		return nil, response.NotFoundError()
+	}
+	id = int(author.ID)
+
+	var books []*models.Book
+	var total int64
+	var hasNext, hasPrev bool
+	if req.Cursor != "" {
+		// a cursor takes precedence over the page number
+		req.Page = 0
+		var more bool
+		books, more, total, err = service.AuthorRepository.GetAuthorBooksPage(ctx, service.DB, id, page)
+		hasNext, hasPrev = keysetNeighbours(page, more)
+	} else {
+		// numbered pages get cursors too, so clients can switch to cursors
+		// at any page
+		offset := (req.Page - 1) * req.Limit
+		books, total, err = service.AuthorRepository.GetAuthorBooks(ctx, service.DB, id, offset, req.Limit, sort.order.Clause())
+		hasNext, hasPrev = int64(offset+len(books)) < total, offset > 0
+	}
+	if err != nil {
+		return nil, response.RepositoryError()
+	}
+	bookResponses := []*params.BookResponse{}
+	var rows []keysetRow
+	for _, book := range books {
+		bookResponses = append(bookResponses, &params.BookResponse{
+			ID:              book.ID,
+			Title:           book.Title,
+			ISBN:            book.ISBN,
+			PublicationDate: formatOptionalDate(book.PublicationDate),
+			AuthorID:        book.AuthorID,
+		})
+		rows = append(rows, keysetRow{value: bookSortValue(book, sort.order.Column), id: book.ID})
+	}
+
+	result := &params.PaginationResponse{
+		Items: bookResponses,
+		Page:  req.Page,
+		Limit: req.Limit,
+		Total: total,
+	}
+	result.NextCursor, result.PrevCursor = pageCursors(req.Sort, rows, hasNext, hasPrev)
+	return result, nil
+}
+
+func (service *AuthorServiceImpl) FindAuthorStats(ctx context.Context, id int) (*params.AuthorStatsResponse, *response.CustomError) {
+	author, err := service.findAuthor(ctx, id)
+	if err != nil {
+		return nil, response.NotFoundError()
+	}
+
+	stats, err := service.AuthorRepository.GetAuthorStats(ctx, service.DB, int(author.ID))
+	if err != nil {
+		return nil, response.RepositoryError()
+	}
+
+	resp := &params.AuthorStatsResponse{
+		AuthorID:              author.ID,
+		TotalBooks:            stats.TotalBooks,
+		TotalCopies:           stats.TotalCopies,
+		TotalLoans:            stats.TotalLoans,
+		AverageRating:         stats.AverageRating,
+		RatingCount:           stats.RatingCount,
+		FirstPublicationDate:  formatOptionalDate(stats.FirstPublicationDate),
+		LatestPublicationDate: formatOptionalDate(stats.LatestPublicationDate),
+	}
+	if stats.FirstPublicationDate != nil && stats.LatestPublicationDate != nil {
+		resp.ActiveYears = stats.LatestPublicationDate.Year() - stats.FirstPublicationDate.Year() + 1
+	}
+	return resp, nil
+}
+
+// parseAuthorRequest validates req and maps it onto a new author model.
+func parseAuthorRequest(req *params.AuthorRequest) (*models.Author, *response.CustomError) {
+	val := validator.New()
+	val.RegisterValidation("viaf", func(fl validator.FieldLevel) bool {
+		return identifier.ValidVIAF(fl.Field().String())
+	})
+	val.RegisterValidation("orcid", func(fl validator.FieldLevel) bool {
+		return identifier.ValidORCID(fl.Field().String())
+	})
+	val.RegisterValidation("wikidata", func(fl validator.FieldLevel) bool {
+		return identifier.ValidWikidata(fl.Field().String())
+	})
+	err := val.Struct(req)
+	if err != nil {
+		validationErrors := err.(validator.ValidationErrors)
+		var errors []interface{}
+		for _, fieldError := range validationErrors {
+			error := "error " + fieldError.Field() + " on tag " + fieldError.Tag()
+			errors = append(errors, error)
+		}
+		return nil, response.BadRequestErrorWithAdditionalInfo(errors)
+	}
 
 	var author = new(models.Author)
-	author.ID = uint(id)
 	author.Name = req.Name
-	birthdate, _ := time.Parse("2006-01-02", req.Birthdate)
-	// if err != nil {
-	// 	return nil, response.BadRequestErrorWithAdditionalInfo(fmt.Sprintf("Invalid date format: %s", err.Error()))
-	// }
+	birthdate, err := time.Parse("2006-01-02", req.Birthdate)
+	if err != nil {
+		return nil, response.BadRequestErrorWithAdditionalInfo(fmt.Sprintf("Invalid date format: %s", err.Error()))
+	}
 	author.Birthdate = birthdate
-	if err := service.AuthorRepository.UpdateAuthor(ctx, service.DB, author); err != nil {
-		return nil, response.BadRequestError()
+	if req.DeathDate != "" {
+		deathDate, err := time.Parse("2006-01-02", req.DeathDate)
+		if err != nil {
+			return nil, response.BadRequestErrorWithAdditionalInfo(fmt.Sprintf("Invalid date format: %s", err.Error()))
+		}
+		if !deathDate.After(birthdate) {
+			return nil, response.BadRequestErrorWithAdditionalInfo("death_date must be after birthdate")
+		}
+		author.DeathDate = &deathDate
 	}
-
-	return &params.AuthorResponse{
-		ID:        author.ID,
-		Name:      author.Name,
-		Birthdate: author.Birthdate.Format("2006-01-02"),
-	}, nil
+	author.Biography = req.Biography
+	author.Nationality = req.Nationality
+	author.Identifiers = models.AuthorIdentifiers{
+		VIAF:     req.Identifiers.VIAF,
+		ORCID:    req.Identifiers.ORCID,
+		Wikidata: req.Identifiers.Wikidata,
+	}
+	for _, pseudonym := range req.Pseudonyms {
+		author.Pseudonyms = append(author.Pseudonyms, models.AuthorPseudonym{Name: pseudonym})
+	}
+	return author, nil
 }
 
-func (service *AuthorServiceImpl) DeleteAuthor(ctx context.Context, id int) *response.CustomError {
-	err := service.AuthorRepository.DeleteAuthor(ctx, service.DB, id)
-	if err != nil {
-		return response.NotFoundError()
+// authorRequest is the inverse of parseAuthorRequest, used as the document
+// that PATCH requests are applied to.
+func authorRequest(author *models.Author) *params.AuthorRequest {
+	req := &params.AuthorRequest{
+		Name:        author.Name,
+		Birthdate:   author.Birthdate.Format("2006-01-02"),
+		DeathDate:   formatOptionalDate(author.DeathDate),
+		Biography:   author.Biography,
+		Nationality: author.Nationality,
+		Identifiers: params.AuthorIdentifiers{
+			VIAF:     author.Identifiers.VIAF,
+			ORCID:    author.Identifiers.ORCID,
+			Wikidata: author.Identifiers.Wikidata,
+		},
+		Pseudonyms: []string{},
+	}
+	for _, pseudonym := range author.Pseudonyms {
+		req.Pseudonyms = append(req.Pseudonyms, pseudonym.Name)
 	}
+	return req
+}
 
+// authorSortValue is the sort key of author in a list sorted on column.
+func authorSortValue(author *models.Author, column string) interface{} {
+	switch column {
+	case "name":
+		return author.Name
+	case "birthdate":
+		return author.Birthdate
+	}
 	return nil
 }
+
+func authorResponse(author *models.Author) *params.AuthorResponse {
+	resp := &params.AuthorResponse{
+		ID:          author.ID,
+		Name:        author.Name,
+		Birthdate:   author.Birthdate.Format("2006-01-02"),
+		DeathDate:   formatOptionalDate(author.DeathDate),
+		Biography:   author.Biography,
+		Nationality: author.Nationality,
+	}
+	if author.Identifiers != (models.AuthorIdentifiers{}) {
+		resp.Identifiers = &params.AuthorIdentifiers{
+			VIAF:     author.Identifiers.VIAF,
+			ORCID:    author.Identifiers.ORCID,
+			Wikidata: author.Identifiers.Wikidata,
+		}
+	}
+	for _, pseudonym := range author.Pseudonyms {
+		resp.Pseudonyms = append(resp.Pseudonyms, pseudonym.Name)
+	}
+	for _, alias := range author.Aliases {
+		resp.Aliases = append(resp.Aliases, alias.Name)
+	}
+	return resp
+}
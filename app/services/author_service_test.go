@@ -6,11 +6,13 @@ import (
 	"golang-backend-test/app/models"
 	"golang-backend-test/app/params"
 	"golang-backend-test/app/repositories"
+	"golang-backend-test/pkg/cursor"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
 
@@ -26,7 +28,7 @@ func TestFindDetailAuthor_Success(t *testing.T) {
 	}
 
 	authorRepo.On("FindAuthorById", mock.Anything, db, int(authorID)).Return(Author, nil)
-	service := NewAuthorService(authorRepo, db)
+	service := NewAuthorService(authorRepo, nil, db)
 
 	result, err := service.FindDetailAuthor(context.Background(), int(authorID))
 
@@ -45,7 +47,8 @@ func TestFindDetailAuthor_AuthorNotFound(t *testing.T) {
 	authorID := uint(1)
 
 	authorRepo.On("FindAuthorById", mock.Anything, db, int(authorID)).Return(nil, errors.New("author not found"))
-	service := NewAuthorService(authorRepo, db)
+	authorRepo.On("FindAuthorRedirect", mock.Anything, db, int(authorID)).Return(nil, errors.New("author redirect not found"))
+	service := NewAuthorService(authorRepo, nil, db)
 
 	result, err := service.FindDetailAuthor(context.Background(), int(authorID))
 
@@ -57,6 +60,29 @@ func TestFindDetailAuthor_AuthorNotFound(t *testing.T) {
 	authorRepo.AssertExpectations(t)
 }
 
+func TestFindDetailAuthor_MergedAuthorRedirect(t *testing.T) {
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := new(gorm.DB)
+
+	authorRepo.On("FindAuthorById", mock.Anything, db, 2).Return(nil, errors.New("author not found"))
+	authorRepo.On("FindAuthorRedirect", mock.Anything, db, 2).Return(&models.AuthorRedirect{ID: 2, AuthorID: 1}, nil)
+	authorRepo.On("FindAuthorById", mock.Anything, db, 1).Return(&models.Author{
+		ID:        1,
+		Name:      "J.R.R. Tolkien",
+		Birthdate: time.Date(1892, time.January, 3, 0, 0, 0, 0, time.UTC),
+		Aliases:   []models.AuthorAlias{{ID: 1, AuthorID: 1, Name: "Tolkien, J. R. R."}},
+	}, nil)
+	service := NewAuthorService(authorRepo, nil, db)
+
+	result, err := service.FindDetailAuthor(context.Background(), 2)
+
+	assert.Nil(t, err)
+	assert.NotNil(t, result)
+	assert.Equal(t, uint(1), result.ID)
+	assert.Equal(t, []string{"Tolkien, J. R. R."}, result.Aliases)
+	authorRepo.AssertExpectations(t)
+}
+
 func TestFindAllAuthor_Success(t *testing.T) {
 	authorRepo := new(repositories.MockAuthorRepository)
 	db := new(gorm.DB)
@@ -70,7 +96,7 @@ func TestFindAllAuthor_Success(t *testing.T) {
 	}
 
 	authorRepo.On("GetListAuthors", mock.Anything, db).Return(Authors, nil)
-	service := NewAuthorService(authorRepo, db)
+	service := NewAuthorService(authorRepo, nil, db)
 
 	result, err := service.FindAllAuthors(context.Background())
 
@@ -86,7 +112,7 @@ func TestFindAllAuthor_Success(t *testing.T) {
 func TestFindAllAuthors_RepositoryError(t *testing.T) {
 	authorRepo := new(repositories.MockAuthorRepository)
 	db := new(gorm.DB)
-	service := NewAuthorService(authorRepo, db)
+	service := NewAuthorService(authorRepo, nil, db)
 
 	authorRepo.On("GetListAuthors", mock.Anything, db).Return(nil, errors.New("db error"))
 
@@ -100,7 +126,7 @@ func TestFindAllAuthors_RepositoryError(t *testing.T) {
 func TestCreateAuthor_Success(t *testing.T) {
 	authorRepo := new(repositories.MockAuthorRepository)
 	db := new(gorm.DB)
-	service := NewAuthorService(authorRepo, db)
+	service := NewAuthorService(authorRepo, nil, db)
 
 	validRequest := &params.AuthorRequest{
 		Name:      "Test Author",
@@ -109,7 +135,7 @@ func TestCreateAuthor_Success(t *testing.T) {
 
 	authorRepo.On("CreateAuthor", mock.Anything, db, mock.AnythingOfType("*models.Author")).Return(nil)
 
-	errCust := service.CrateAuthor(context.Background(), validRequest)
+	_, errCust := service.CrateAuthor(context.Background(), validRequest)
 
 	assert.Nil(t, errCust)
 	authorRepo.AssertExpectations(t)
@@ -118,14 +144,14 @@ func TestCreateAuthor_Success(t *testing.T) {
 func TestCreateAuthor_ValidationError(t *testing.T) {
 	authorRepo := new(repositories.MockAuthorRepository)
 	db := new(gorm.DB)
-	service := NewAuthorService(authorRepo, db)
+	service := NewAuthorService(authorRepo, nil, db)
 
 	invalidRequest := &params.AuthorRequest{
 		Name:      "Test Author",
 		Birthdate: "",
 	}
 
-	err := service.CrateAuthor(context.Background(), invalidRequest)
+	_, err := service.CrateAuthor(context.Background(), invalidRequest)
 
 	assert.NotNil(t, err)
 	assert.Equal(t, "BAD REQUEST ERROR", err.Message)
@@ -134,7 +160,7 @@ func TestCreateAuthor_ValidationError(t *testing.T) {
 func TestCreateAuthor_RepositoryError(t *testing.T) {
 	authorRepo := new(repositories.MockAuthorRepository)
 	db := new(gorm.DB)
-	service := NewAuthorService(authorRepo, db)
+	service := NewAuthorService(authorRepo, nil, db)
 
 	validRequest := &params.AuthorRequest{
 		Name:      "Test Author",
@@ -143,23 +169,119 @@ func TestCreateAuthor_RepositoryError(t *testing.T) {
 
 	authorRepo.On("CreateAuthor", mock.Anything, db, mock.AnythingOfType("*models.Author")).Return(errors.New("db error"))
 
-	err := service.CrateAuthor(context.Background(), validRequest)
+	_, err := service.CrateAuthor(context.Background(), validRequest)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "BAD REQUEST ERROR", err.Message)
+	authorRepo.AssertExpectations(t)
+}
+
+func TestCreateAuthor_FullRecord(t *testing.T) {
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := new(gorm.DB)
+	service := NewAuthorService(authorRepo, nil, db)
+
+	validRequest := &params.AuthorRequest{
+		Name:        "Eric Arthur Blair",
+		Birthdate:   "1903-06-25",
+		DeathDate:   "1950-01-21",
+		Biography:   "English novelist and *essayist*.",
+		Nationality: "British",
+		Identifiers: params.AuthorIdentifiers{
+			VIAF:     "104724000",
+			ORCID:    "0000-0002-1825-0097",
+			Wikidata: "Q3335",
+		},
+		Pseudonyms: []string{"George Orwell"},
+	}
+
+	authorRepo.On("CreateAuthor", mock.Anything, db, mock.MatchedBy(func(author *models.Author) bool {
+		return author.DeathDate != nil &&
+			author.DeathDate.Equal(time.Date(1950, time.January, 21, 0, 0, 0, 0, time.UTC)) &&
+			author.Identifiers.Wikidata == "Q3335" &&
+			len(author.Pseudonyms) == 1 && author.Pseudonyms[0].Name == "George Orwell"
+	})).Return(nil)
+
+	_, errCust := service.CrateAuthor(context.Background(), validRequest)
+
+	assert.Nil(t, errCust)
+	authorRepo.AssertExpectations(t)
+}
+
+func TestCreateAuthor_DeathDateBeforeBirthdate(t *testing.T) {
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := new(gorm.DB)
+	service := NewAuthorService(authorRepo, nil, db)
+
+	invalidRequest := &params.AuthorRequest{
+		Name:      "Test Author",
+		Birthdate: "1985-04-05",
+		DeathDate: "1960-01-01",
+	}
+
+	_, err := service.CrateAuthor(context.Background(), invalidRequest)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "BAD REQUEST ERROR", err.Message)
+	assert.Equal(t, "death_date must be after birthdate", err.AdditionalInfo)
+	authorRepo.AssertNotCalled(t, "CreateAuthor", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCreateAuthor_InvalidIdentifier(t *testing.T) {
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := new(gorm.DB)
+	service := NewAuthorService(authorRepo, nil, db)
+
+	invalidRequest := &params.AuthorRequest{
+		Name:      "Test Author",
+		Birthdate: "1985-04-05",
+		Identifiers: params.AuthorIdentifiers{
+			ORCID: "0000-0002-1825-0098",
+		},
+	}
+
+	_, err := service.CrateAuthor(context.Background(), invalidRequest)
 
 	assert.NotNil(t, err)
 	assert.Equal(t, "BAD REQUEST ERROR", err.Message)
+	assert.Equal(t, []interface{}{"error ORCID on tag orcid"}, err.AdditionalInfo)
+	authorRepo.AssertNotCalled(t, "CreateAuthor", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSearchAuthors_ByPseudonym(t *testing.T) {
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := new(gorm.DB)
+	service := NewAuthorService(authorRepo, nil, db)
+
+	authorRepo.On("SearchAuthors", mock.Anything, db, "Orwell").Return([]*models.Author{
+		{
+			ID:         1,
+			Name:       "Eric Arthur Blair",
+			Birthdate:  time.Date(1903, time.June, 25, 0, 0, 0, 0, time.UTC),
+			Pseudonyms: []models.AuthorPseudonym{{ID: 1, AuthorID: 1, Name: "George Orwell"}},
+		},
+	}, nil)
+
+	result, err := service.SearchAuthors(context.Background(), "Orwell")
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(result))
+	assert.Equal(t, "Eric Arthur Blair", result[0].Name)
+	assert.Equal(t, []string{"George Orwell"}, result[0].Pseudonyms)
 	authorRepo.AssertExpectations(t)
 }
 
 func TestUpdateAuthor_Success(t *testing.T) {
 	authorRepo := new(repositories.MockAuthorRepository)
 	db := new(gorm.DB)
-	service := NewAuthorService(authorRepo, db)
+	service := NewAuthorService(authorRepo, nil, db)
 
 	validRequest := &params.AuthorRequest{
 		Name:      "Update Author",
 		Birthdate: "1985-04-05",
 	}
 
+	authorRepo.On("FindAuthorById", mock.Anything, db, 1).Return(&models.Author{ID: 1, Name: "Test Author"}, nil)
 	authorRepo.On("UpdateAuthor", mock.Anything, db, mock.AnythingOfType("*models.Author")).Return(nil)
 
 	result, err := service.UpdateAuthor(context.Background(), 1, validRequest)
@@ -173,7 +295,7 @@ func TestUpdateAuthor_Success(t *testing.T) {
 func TestUpdateAuthor_ValidationError(t *testing.T) {
 	authorRepo := new(repositories.MockAuthorRepository)
 	db := new(gorm.DB)
-	service := NewAuthorService(authorRepo, db)
+	service := NewAuthorService(authorRepo, nil, db)
 
 	invalidRequest := &params.AuthorRequest{
 		Name:      "",
@@ -190,13 +312,14 @@ func TestUpdateAuthor_ValidationError(t *testing.T) {
 func TestUpdateAuthor_RepositoryError(t *testing.T) {
 	authorRepo := new(repositories.MockAuthorRepository)
 	db := new(gorm.DB)
-	service := NewAuthorService(authorRepo, db)
+	service := NewAuthorService(authorRepo, nil, db)
 
 	validRequest := &params.AuthorRequest{
 		Name:      "Update Author",
 		Birthdate: "1985-04-05",
 	}
 
+	authorRepo.On("FindAuthorById", mock.Anything, db, 1).Return(&models.Author{ID: 1, Name: "Test Author"}, nil)
 	authorRepo.On("UpdateAuthor", mock.Anything, db, mock.AnythingOfType("*models.Author")).Return(errors.New("db error"))
 
 	result, err := service.UpdateAuthor(context.Background(), 1, validRequest)
@@ -207,10 +330,142 @@ func TestUpdateAuthor_RepositoryError(t *testing.T) {
 	authorRepo.AssertExpectations(t)
 }
 
+func TestUpdateAuthor_MergedAuthorUpdatesSurvivor(t *testing.T) {
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := new(gorm.DB)
+	service := NewAuthorService(authorRepo, nil, db)
+
+	authorRepo.On("FindAuthorById", mock.Anything, db, 2).Return(nil, errors.New("author not found"))
+	authorRepo.On("FindAuthorRedirect", mock.Anything, db, 2).Return(&models.AuthorRedirect{ID: 2, AuthorID: 1}, nil)
+	authorRepo.On("FindAuthorById", mock.Anything, db, 1).Return(&models.Author{ID: 1, Name: "J.R.R. Tolkien"}, nil)
+	authorRepo.On("UpdateAuthor", mock.Anything, db, mock.MatchedBy(func(author *models.Author) bool {
+		return author.ID == 1 && author.Name == "J. R. R. Tolkien"
+	})).Return(nil)
+
+	result, err := service.UpdateAuthor(context.Background(), 2, &params.AuthorRequest{Name: "J. R. R. Tolkien", Birthdate: "1892-01-03"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, uint(1), result.ID)
+	authorRepo.AssertExpectations(t)
+}
+
+func TestUpdateAuthor_AuthorNotFound(t *testing.T) {
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := new(gorm.DB)
+	service := NewAuthorService(authorRepo, nil, db)
+
+	authorRepo.On("FindAuthorById", mock.Anything, db, 1).Return(nil, errors.New("author not found"))
+	authorRepo.On("FindAuthorRedirect", mock.Anything, db, 1).Return(nil, errors.New("author redirect not found"))
+
+	result, err := service.UpdateAuthor(context.Background(), 1, &params.AuthorRequest{Name: "Update Author", Birthdate: "1985-04-05"})
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "NOT FOUND ERROR", err.Message)
+	authorRepo.AssertNotCalled(t, "UpdateAuthor", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUpdateAuthor_InvalidBirthdate(t *testing.T) {
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := new(gorm.DB)
+	service := NewAuthorService(authorRepo, nil, db)
+
+	invalidRequest := &params.AuthorRequest{
+		Name:      "Update Author",
+		Birthdate: "05-04-1985",
+	}
+
+	result, err := service.UpdateAuthor(context.Background(), 1, invalidRequest)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "BAD REQUEST ERROR", err.Message)
+	authorRepo.AssertNotCalled(t, "UpdateAuthor", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPatchAuthor_MergePatchSuccess(t *testing.T) {
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := new(gorm.DB)
+	service := NewAuthorService(authorRepo, nil, db)
+
+	authorRepo.On("FindAuthorById", mock.Anything, db, 1).Return(&models.Author{
+		ID:        1,
+		Name:      "Test Author",
+		Birthdate: time.Date(1985, time.April, 5, 0, 0, 0, 0, time.UTC),
+	}, nil)
+	authorRepo.On("UpdateAuthor", mock.Anything, db, mock.MatchedBy(func(author *models.Author) bool {
+		return author.Name == "Patched Author" && author.Birthdate.Equal(time.Date(1985, time.April, 5, 0, 0, 0, 0, time.UTC))
+	})).Return(nil)
+
+	result, err := service.PatchAuthor(context.Background(), 1, "application/merge-patch+json", []byte(`{"name":"Patched Author"}`))
+
+	assert.Nil(t, err)
+	assert.NotNil(t, result)
+	assert.Equal(t, "Patched Author", result.Name)
+	assert.Equal(t, "1985-04-05", result.Birthdate)
+	authorRepo.AssertExpectations(t)
+}
+
+func TestPatchAuthor_JSONPatchSuccess(t *testing.T) {
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := new(gorm.DB)
+	service := NewAuthorService(authorRepo, nil, db)
+
+	authorRepo.On("FindAuthorById", mock.Anything, db, 1).Return(&models.Author{
+		ID:        1,
+		Name:      "Test Author",
+		Birthdate: time.Date(1985, time.April, 5, 0, 0, 0, 0, time.UTC),
+	}, nil)
+	authorRepo.On("UpdateAuthor", mock.Anything, db, mock.AnythingOfType("*models.Author")).Return(nil)
+
+	result, err := service.PatchAuthor(context.Background(), 1, "application/json-patch+json", []byte(`[{"op":"replace","path":"/birthdate","value":"1990-01-02"}]`))
+
+	assert.Nil(t, err)
+	assert.NotNil(t, result)
+	assert.Equal(t, "Test Author", result.Name)
+	assert.Equal(t, "1990-01-02", result.Birthdate)
+	authorRepo.AssertExpectations(t)
+}
+
+func TestPatchAuthor_InvalidBirthdate(t *testing.T) {
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := new(gorm.DB)
+	service := NewAuthorService(authorRepo, nil, db)
+
+	authorRepo.On("FindAuthorById", mock.Anything, db, 1).Return(&models.Author{
+		ID:        1,
+		Name:      "Test Author",
+		Birthdate: time.Date(1985, time.April, 5, 0, 0, 0, 0, time.UTC),
+	}, nil)
+
+	result, err := service.PatchAuthor(context.Background(), 1, "application/merge-patch+json", []byte(`{"birthdate":"not a date"}`))
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "BAD REQUEST ERROR", err.Message)
+	authorRepo.AssertNotCalled(t, "UpdateAuthor", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPatchAuthor_AuthorNotFound(t *testing.T) {
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := new(gorm.DB)
+	service := NewAuthorService(authorRepo, nil, db)
+
+	authorRepo.On("FindAuthorById", mock.Anything, db, 1).Return(nil, errors.New("author not found"))
+	authorRepo.On("FindAuthorRedirect", mock.Anything, db, 1).Return(nil, errors.New("author redirect not found"))
+
+	result, err := service.PatchAuthor(context.Background(), 1, "application/merge-patch+json", []byte(`{"name":"Patched Author"}`))
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "NOT FOUND ERROR", err.Message)
+	authorRepo.AssertExpectations(t)
+}
+
 func TestDeleteAuthor_Success(t *testing.T) {
 	authorRepo := new(repositories.MockAuthorRepository)
 	db := new(gorm.DB)
-	service := NewAuthorService(authorRepo, db)
+	service := NewAuthorService(authorRepo, nil, db)
 
 	authorRepo.On("DeleteAuthor", mock.Anything, db, 1).Return(nil)
 
@@ -220,12 +475,29 @@ func TestDeleteAuthor_Success(t *testing.T) {
 	authorRepo.AssertExpectations(t)
 }
 
+func TestDeleteAuthor_RecordsEvent(t *testing.T) {
+	authorRepo := new(repositories.MockAuthorRepository)
+	events := new(MockEventRecorder)
+	db, errDB := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	assert.Nil(t, errDB)
+	service := NewAuthorService(authorRepo, events, db)
+
+	authorRepo.On("DeleteAuthor", mock.Anything, mock.Anything, 1).Return(nil)
+	events.On("Record", mock.Anything, mock.Anything, EventAuthorDeleted, uint(1), &params.DeletedResponse{ID: 1}).Return(nil)
+
+	err := service.DeleteAuthor(context.Background(), 1)
+
+	assert.Nil(t, err)
+	events.AssertExpectations(t)
+}
+
 func TestDeleteAuthor_RepositoryError(t *testing.T) {
 	authorRepo := new(repositories.MockAuthorRepository)
 	db := new(gorm.DB)
-	service := NewAuthorService(authorRepo, db)
+	service := NewAuthorService(authorRepo, nil, db)
 
 	authorRepo.On("DeleteAuthor", mock.Anything, db, 1).Return(errors.New("author not found"))
+	authorRepo.On("DeleteAuthorRedirect", mock.Anything, db, 1).Return(errors.New("author redirect not found"))
 
 	err := service.DeleteAuthor(context.Background(), 1)
 
@@ -233,3 +505,320 @@ func TestDeleteAuthor_RepositoryError(t *testing.T) {
 	assert.Equal(t, "NOT FOUND ERROR", err.Message)
 	authorRepo.AssertExpectations(t)
 }
+
+func TestDeleteAuthor_MergedAuthorDropsRedirect(t *testing.T) {
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := new(gorm.DB)
+	service := NewAuthorService(authorRepo, nil, db)
+
+	authorRepo.On("DeleteAuthor", mock.Anything, db, 2).Return(errors.New("author not found"))
+	authorRepo.On("DeleteAuthorRedirect", mock.Anything, db, 2).Return(nil)
+
+	err := service.DeleteAuthor(context.Background(), 2)
+
+	assert.Nil(t, err)
+	authorRepo.AssertNotCalled(t, "DeleteAuthor", mock.Anything, mock.Anything, 1)
+	authorRepo.AssertExpectations(t)
+}
+
+func TestFindDuplicateAuthors_Success(t *testing.T) {
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := new(gorm.DB)
+	service := NewAuthorService(authorRepo, nil, db)
+
+	authorRepo.On("GetListAuthors", mock.Anything, db).Return([]*models.Author{
+		{ID: 1, Name: "J.R.R. Tolkien", Birthdate: time.Date(1892, time.January, 3, 0, 0, 0, 0, time.UTC)},
+		{ID: 2, Name: "Tolkien, J. R. R.", Birthdate: time.Date(1892, time.January, 3, 0, 0, 0, 0, time.UTC)},
+		{ID: 3, Name: "Ursula K. Le Guin", Birthdate: time.Date(1929, time.October, 21, 0, 0, 0, 0, time.UTC)},
+	}, nil)
+
+	result, err := service.FindDuplicateAuthors(context.Background(), 0.75)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(result))
+	assert.Equal(t, uint(1), result[0].Author.ID)
+	assert.Equal(t, uint(2), result[0].Duplicate.ID)
+	assert.Equal(t, 1.0, result[0].Score)
+	authorRepo.AssertExpectations(t)
+}
+
+func TestFindDuplicateAuthors_DifferentBirthdateScoresLower(t *testing.T) {
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := new(gorm.DB)
+	service := NewAuthorService(authorRepo, nil, db)
+
+	authorRepo.On("GetListAuthors", mock.Anything, db).Return([]*models.Author{
+		{ID: 1, Name: "John Smith", Birthdate: time.Date(1950, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{ID: 2, Name: "Smith, John", Birthdate: time.Date(1980, time.June, 1, 0, 0, 0, 0, time.UTC)},
+	}, nil)
+
+	result, err := service.FindDuplicateAuthors(context.Background(), 0.9)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(result))
+	authorRepo.AssertExpectations(t)
+}
+
+func TestFindDuplicateAuthors_OnlyComparesSharedKeys(t *testing.T) {
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := new(gorm.DB)
+	service := NewAuthorService(authorRepo, nil, db)
+
+	authorRepo.On("GetListAuthors", mock.Anything, db).Return([]*models.Author{
+		{ID: 1, Name: "J.R.R. Tolkien"},
+		{ID: 2, Name: "Ursula K. Le Guin"},
+		{ID: 3, Name: "J. R. R. Tolkein"},
+		{ID: 4, Name: "Le Guin, Ursula"},
+	}, nil)
+
+	result, err := service.FindDuplicateAuthors(context.Background(), 0)
+
+	// the misspelt surname still shares the initials, and the authors
+	// sharing no key at all are never paired
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(result))
+	pairs := map[[2]uint]bool{}
+	for _, duplicate := range result {
+		pairs[[2]uint{duplicate.Author.ID, duplicate.Duplicate.ID}] = true
+	}
+	assert.Equal(t, map[[2]uint]bool{{1, 3}: true, {2, 4}: true}, pairs)
+	authorRepo.AssertExpectations(t)
+}
+
+func TestMergeAuthor_Success(t *testing.T) {
+	authorRepo := new(repositories.MockAuthorRepository)
+	db, errDB := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	assert.Nil(t, errDB)
+	service := NewAuthorService(authorRepo, nil, db)
+
+	survivor := &models.Author{ID: 1, Name: "J.R.R. Tolkien"}
+	duplicate := &models.Author{ID: 2, Name: "Tolkien, J. R. R."}
+
+	authorRepo.On("FindAuthorById", mock.Anything, db, 1).Return(survivor, nil)
+	authorRepo.On("FindAuthorById", mock.Anything, db, 2).Return(duplicate, nil)
+	authorRepo.On("ReassignBooks", mock.Anything, mock.Anything, 2, 1).Return(nil)
+	authorRepo.On("ReassignAliases", mock.Anything, mock.Anything, 2, 1).Return(nil)
+	authorRepo.On("ReassignPseudonyms", mock.Anything, mock.Anything, 2, 1).Return(nil)
+	authorRepo.On("CreateAuthorAlias", mock.Anything, mock.Anything, &models.AuthorAlias{AuthorID: 1, Name: "Tolkien, J. R. R."}).Return(nil)
+	authorRepo.On("ReassignRedirects", mock.Anything, mock.Anything, 2, 1).Return(nil)
+	authorRepo.On("CreateAuthorRedirect", mock.Anything, mock.Anything, &models.AuthorRedirect{ID: 2, AuthorID: 1}).Return(nil)
+	authorRepo.On("DeleteAuthor", mock.Anything, mock.Anything, 2).Return(nil)
+
+	result, err := service.MergeAuthor(context.Background(), 1, &params.AuthorMergeRequest{DuplicateID: 2})
+
+	assert.Nil(t, err)
+	assert.NotNil(t, result)
+	assert.Equal(t, uint(1), result.ID)
+	authorRepo.AssertExpectations(t)
+}
+
+func TestMergeAuthor_RepositoryError(t *testing.T) {
+	authorRepo := new(repositories.MockAuthorRepository)
+	db, errDB := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	assert.Nil(t, errDB)
+	service := NewAuthorService(authorRepo, nil, db)
+
+	authorRepo.On("FindAuthorById", mock.Anything, db, 1).Return(&models.Author{ID: 1, Name: "J.R.R. Tolkien"}, nil)
+	authorRepo.On("FindAuthorById", mock.Anything, db, 2).Return(&models.Author{ID: 2, Name: "Tolkien, J. R. R."}, nil)
+	authorRepo.On("ReassignBooks", mock.Anything, mock.Anything, 2, 1).Return(errors.New("db error"))
+
+	result, err := service.MergeAuthor(context.Background(), 1, &params.AuthorMergeRequest{DuplicateID: 2})
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "REPOSITORY ERROR", err.Message)
+	authorRepo.AssertNotCalled(t, "DeleteAuthor", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMergeAuthor_IntoItself(t *testing.T) {
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := new(gorm.DB)
+	service := NewAuthorService(authorRepo, nil, db)
+
+	result, err := service.MergeAuthor(context.Background(), 1, &params.AuthorMergeRequest{DuplicateID: 1})
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "BAD REQUEST ERROR", err.Message)
+}
+
+func TestFindAuthorsByIds_Success(t *testing.T) {
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := new(gorm.DB)
+	service := NewAuthorService(authorRepo, nil, db)
+
+	authorRepo.On("FindAuthorsByIds", mock.Anything, db, []int{1, 2}).Return([]*models.Author{
+		{ID: 2, Name: "Bob", Pseudonyms: []models.AuthorPseudonym{{Name: "B."}}},
+		{ID: 1, Name: "Ann"},
+	}, nil)
+
+	result, err := service.FindAuthorsByIds(context.Background(), []int{1, 2})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(result))
+	assert.Equal(t, "Bob", result[0].Name)
+	assert.Equal(t, []string{"B."}, result[0].Pseudonyms)
+	authorRepo.AssertExpectations(t)
+}
+
+func TestFindAuthorBooks_Success(t *testing.T) {
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := new(gorm.DB)
+	service := NewAuthorService(authorRepo, nil, db)
+
+	published := time.Date(1937, time.September, 21, 0, 0, 0, 0, time.UTC)
+	authorRepo.On("FindAuthorById", mock.Anything, db, 1).Return(&models.Author{ID: 1, Name: "J.R.R. Tolkien"}, nil)
+	authorRepo.On("GetAuthorBooks", mock.Anything, db, 1, 10, 10, "publication_date DESC, id DESC").Return([]*models.Book{
+		{ID: 3, Title: "The Hobbit", ISBN: "123456789", PublicationDate: &published, AuthorID: 1},
+	}, int64(11), nil)
+
+	result, err := service.FindAuthorBooks(context.Background(), 1, &params.PaginationRequest{Page: 2, Sort: "-publication_date"})
+
+	assert.Nil(t, err)
+	assert.NotNil(t, result)
+	assert.Equal(t, 2, result.Page)
+	assert.Equal(t, 10, result.Limit)
+	assert.Equal(t, int64(11), result.Total)
+	books := result.Items.([]*params.BookResponse)
+	assert.Equal(t, 1, len(books))
+	assert.Equal(t, "1937-09-21", books[0].PublicationDate)
+	authorRepo.AssertExpectations(t)
+}
+
+func TestFindAuthorBooks_WithCursor(t *testing.T) {
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := new(gorm.DB)
+	service := NewAuthorService(authorRepo, nil, db)
+
+	token := cursor.Encode(cursor.Position{Sort: "publication_date", ID: 4})
+	page := repositories.KeysetPage{Order: repositories.KeysetOrder{Column: "publication_date"}, ID: 4, Limit: 2}
+	published := time.Date(1937, time.September, 21, 0, 0, 0, 0, time.UTC)
+	authorRepo.On("FindAuthorById", mock.Anything, db, 1).Return(&models.Author{ID: 1, Name: "J.R.R. Tolkien"}, nil)
+	authorRepo.On("GetAuthorBooksPage", mock.Anything, db, 1, page).Return([]*models.Book{
+		{ID: 3, Title: "The Hobbit", PublicationDate: &published, AuthorID: 1},
+	}, true, int64(5), nil)
+
+	result, err := service.FindAuthorBooks(context.Background(), 1, &params.PaginationRequest{Limit: 2, Cursor: token})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 0, result.Page)
+	next, errDecode := cursor.Decode(result.NextCursor)
+	assert.Nil(t, errDecode)
+	assert.Equal(t, uint(3), next.ID)
+	assert.Equal(t, "1937-09-21T00:00:00Z", next.Value)
+	assert.NotEmpty(t, result.PrevCursor)
+	authorRepo.AssertExpectations(t)
+}
+
+func TestFindAuthorBooks_InvalidSort(t *testing.T) {
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := new(gorm.DB)
+	service := NewAuthorService(authorRepo, nil, db)
+
+	result, err := service.FindAuthorBooks(context.Background(), 1, &params.PaginationRequest{Sort: "title"})
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "BAD REQUEST ERROR", err.Message)
+}
+
+func TestFindAuthorBooks_AuthorNotFound(t *testing.T) {
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := new(gorm.DB)
+	service := NewAuthorService(authorRepo, nil, db)
+
+	authorRepo.On("FindAuthorById", mock.Anything, db, 1).Return(nil, errors.New("author not found"))
+	authorRepo.On("FindAuthorRedirect", mock.Anything, db, 1).Return(nil, errors.New("author redirect not found"))
+
+	result, err := service.FindAuthorBooks(context.Background(), 1, &params.PaginationRequest{})
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "NOT FOUND ERROR", err.Message)
+	authorRepo.AssertExpectations(t)
+}
+
+func TestFindAuthorBooks_MergedAuthor(t *testing.T) {
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := new(gorm.DB)
+	service := NewAuthorService(authorRepo, nil, db)
+
+	authorRepo.On("FindAuthorById", mock.Anything, db, 2).Return(nil, errors.New("author not found"))
+	authorRepo.On("FindAuthorRedirect", mock.Anything, db, 2).Return(&models.AuthorRedirect{ID: 2, AuthorID: 1}, nil)
+	authorRepo.On("FindAuthorById", mock.Anything, db, 1).Return(&models.Author{ID: 1, Name: "J.R.R. Tolkien"}, nil)
+	authorRepo.On("GetAuthorBooks", mock.Anything, db, 1, 0, 10, mock.Anything).Return([]*models.Book{
+		{ID: 3, Title: "The Hobbit", AuthorID: 1},
+	}, int64(1), nil)
+
+	result, err := service.FindAuthorBooks(context.Background(), 2, &params.PaginationRequest{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), result.Total)
+	authorRepo.AssertExpectations(t)
+}
+
+func TestFindAuthorStats_Success(t *testing.T) {
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := new(gorm.DB)
+	service := NewAuthorService(authorRepo, nil, db)
+
+	first := time.Date(1937, time.September, 21, 0, 0, 0, 0, time.UTC)
+	latest := time.Date(1955, time.October, 20, 0, 0, 0, 0, time.UTC)
+	authorRepo.On("FindAuthorById", mock.Anything, db, 1).Return(&models.Author{ID: 1, Name: "J.R.R. Tolkien"}, nil)
+	authorRepo.On("GetAuthorStats", mock.Anything, db, 1).Return(&repositories.AuthorStats{
+		TotalBooks:            4,
+		TotalCopies:           9,
+		TotalLoans:            30,
+		AverageRating:         4.5,
+		RatingCount:           12,
+		FirstPublicationDate:  &first,
+		LatestPublicationDate: &latest,
+	}, nil)
+
+	result, err := service.FindAuthorStats(context.Background(), 1)
+
+	assert.Nil(t, err)
+	assert.NotNil(t, result)
+	assert.Equal(t, int64(4), result.TotalBooks)
+	assert.Equal(t, int64(9), result.TotalCopies)
+	assert.Equal(t, int64(30), result.TotalLoans)
+	assert.Equal(t, 4.5, result.AverageRating)
+	assert.Equal(t, "1937-09-21", result.FirstPublicationDate)
+	assert.Equal(t, "1955-10-20", result.LatestPublicationDate)
+	assert.Equal(t, 19, result.ActiveYears)
+	authorRepo.AssertExpectations(t)
+}
+
+func TestFindAuthorStats_AuthorNotFound(t *testing.T) {
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := new(gorm.DB)
+	service := NewAuthorService(authorRepo, nil, db)
+
+	authorRepo.On("FindAuthorById", mock.Anything, db, 1).Return(nil, errors.New("author not found"))
+	authorRepo.On("FindAuthorRedirect", mock.Anything, db, 1).Return(nil, errors.New("author redirect not found"))
+
+	result, err := service.FindAuthorStats(context.Background(), 1)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "NOT FOUND ERROR", err.Message)
+	authorRepo.AssertExpectations(t)
+}
+
+func TestFindAuthorStats_MergedAuthor(t *testing.T) {
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := new(gorm.DB)
+	service := NewAuthorService(authorRepo, nil, db)
+
+	authorRepo.On("FindAuthorById", mock.Anything, db, 2).Return(nil, errors.New("author not found"))
+	authorRepo.On("FindAuthorRedirect", mock.Anything, db, 2).Return(&models.AuthorRedirect{ID: 2, AuthorID: 1}, nil)
+	authorRepo.On("FindAuthorById", mock.Anything, db, 1).Return(&models.Author{ID: 1, Name: "J.R.R. Tolkien"}, nil)
+	authorRepo.On("GetAuthorStats", mock.Anything, db, 1).Return(&repositories.AuthorStats{TotalBooks: 4}, nil)
+
+	result, err := service.FindAuthorStats(context.Background(), 2)
+
+	assert.Nil(t, err)
+	assert.Equal(t, uint(1), result.AuthorID)
+	assert.Equal(t, int64(4), result.TotalBooks)
+	authorRepo.AssertExpectations(t)
+}
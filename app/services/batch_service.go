@@ -0,0 +1,263 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"golang-backend-test/app/commons/response"
+	"golang-backend-test/app/params"
+	"golang-backend-test/app/repositories"
+	"golang-backend-test/pkg/metadata"
+
+	"github.com/go-playground/validator"
+	"gorm.io/gorm"
+)
+
+type BatchService interface {
+	RunBatch(ctx context.Context, req *params.BatchRequest) (*params.BatchResponse, *response.CustomError)
+}
+
+type BatchServiceImpl struct {
+	BookRepository   repositories.BookRepository
+	AuthorRepository repositories.AuthorRepository
+	MetadataProvider metadata.Provider
+	Events           EventRecorder
+	DB               *gorm.DB
+}
+
+// NewBatchService builds the batch service. events may be nil, in which
+// case no events are recorded.
+func NewBatchService(bookRepository repositories.BookRepository, authorRepository repositories.AuthorRepository, metadataProvider metadata.Provider, events EventRecorder, db *gorm.DB) BatchService {
+	return &BatchServiceImpl{
+		BookRepository:   bookRepository,
+		AuthorRepository: authorRepository,
+		MetadataProvider: metadataProvider,
+		Events:           events,
+		DB:               db,
+	}
+}
+
+const (
+	BatchOk         = "ok"
+	BatchFailed     = "failed"
+	BatchRolledBack = "rolled_back"
+	BatchSkipped    = "skipped"
+)
+
+// errBatchOperation rolls back the transaction or savepoint of a failed
+// operation; the operation's own error is kept in its result.
+var errBatchOperation = errors.New("batch operation failed")
+
+// RunBatch runs the operations through the book and author services, bound
+// to one transaction. Without partial mode the first failure rolls back the
+// whole batch and is returned with the results as additional info.
+func (service *BatchServiceImpl) RunBatch(ctx context.Context, req *params.BatchRequest) (*params.BatchResponse, *response.CustomError) {
+	val := validator.New()
+	err := val.Struct(req)
+	if err != nil {
+		validationErrors := err.(validator.ValidationErrors)
+		var errors []interface{}
+		for _, fieldError := range validationErrors {
+			error := "error " + fieldError.Field() + " on tag " + fieldError.Tag()
+			errors = append(errors, error)
+		}
+		return nil, response.BadRequestErrorWithAdditionalInfo(errors)
+	}
+	refs := make(map[string]bool)
+	for i, op := range req.Operations {
+		if op.Ref == "" {
+			continue
+		}
+		if refs[op.Ref] {
+			return nil, response.BadRequestErrorWithAdditionalInfo(fmt.Sprintf("operation %d reuses ref %s", i, op.Ref))
+		}
+		refs[op.Ref] = true
+	}
+
+	result := &params.BatchResponse{Committed: true}
+	ids := make(map[string]uint)
+	var failure *response.CustomError
+	err = service.DB.Transaction(func(tx *gorm.DB) error {
+		for i, op := range req.Operations {
+			opResult := &params.BatchOperationResponse{Index: i, Ref: op.Ref}
+			result.Results = append(result.Results, opResult)
+			if failure != nil && !req.Partial {
+				opResult.Status = BatchSkipped
+				continue
+			}
+
+			var custErr *response.CustomError
+			run := func(db *gorm.DB) error {
+				opResult.ID, opResult.Data, custErr = service.runBatchOperation(ctx, db, op, ids)
+				if custErr != nil {
+					return errBatchOperation
+				}
+				return nil
+			}
+			var errRun error
+			if req.Partial {
+				// a savepoint per operation undoes just the failed one
+				errRun = tx.Transaction(run)
+			} else {
+				errRun = run(tx)
+			}
+			if errRun != nil && !errors.Is(errRun, errBatchOperation) {
+				return errRun
+			}
+			if custErr != nil {
+				opResult.Status = BatchFailed
+				opResult.Error = custErr
+				if failure == nil {
+					failure = custErr
+				}
+				continue
+			}
+			opResult.Status = BatchOk
+			if op.Ref != "" {
+				ids[op.Ref] = opResult.ID
+			}
+		}
+		if failure != nil && !req.Partial {
+			return errBatchOperation
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errBatchOperation) {
+		return nil, response.RepositoryErrorWithAdditionalInfo(err.Error())
+	}
+
+	if failure != nil && !req.Partial {
+		result.Committed = false
+		for _, opResult := range result.Results {
+			if opResult.Status == BatchOk {
+				opResult.Status = BatchRolledBack
+			}
+		}
+		batchErr := *failure
+		batchErr.AdditionalInfo = result
+		return nil, &batchErr
+	}
+	return result, nil
+}
+
+func (service *BatchServiceImpl) runBatchOperation(ctx context.Context, db *gorm.DB, op params.BatchOperationRequest, ids map[string]uint) (uint, interface{}, *response.CustomError) {
+	id, err := resolveBatchID(op.ID, ids)
+	if err != nil {
+		return 0, nil, response.BadRequestErrorWithAdditionalInfo(err.Error())
+	}
+	if op.Action != "create" && id == 0 {
+		return 0, nil, response.BadRequestErrorWithAdditionalInfo(fmt.Sprintf("%s needs an id", op.Action))
+	}
+	body, err := resolveBatchRefs(op.Body, ids)
+	if err != nil {
+		return 0, nil, response.BadRequestErrorWithAdditionalInfo(err.Error())
+	}
+
+	// the events are recorded in the transaction of the batch, so they are
+	// rolled back with it
+	switch op.Resource {
+	case "book":
+		bookService := NewBookService(service.BookRepository, service.AuthorRepository, service.MetadataProvider, service.Events, db)
+		if op.Action == "delete" {
+			return id, nil, bookService.DeleteBook(ctx, int(id))
+		}
+		var req = new(params.BookRequest)
+		if err := json.Unmarshal(body, req); err != nil {
+			return 0, nil, response.BadRequestErrorWithAdditionalInfo(err.Error())
+		}
+		if op.Action == "create" {
+			result, custErr := bookService.CrateBook(ctx, req)
+			if custErr != nil {
+				return 0, nil, custErr
+			}
+			return result.ID, result, nil
+		}
+		result, custErr := bookService.UpdateBook(ctx, int(id), req)
+		return id, result, custErr
+	default:
+		authorService := NewAuthorService(service.AuthorRepository, service.Events, db)
+		if op.Action == "delete" {
+			return id, nil, authorService.DeleteAuthor(ctx, int(id))
+		}
+		var req = new(params.AuthorRequest)
+		if err := json.Unmarshal(body, req); err != nil {
+			return 0, nil, response.BadRequestErrorWithAdditionalInfo(err.Error())
+		}
+		if op.Action == "create" {
+			result, custErr := authorService.CrateAuthor(ctx, req)
+			if custErr != nil {
+				return 0, nil, custErr
+			}
+			return result.ID, result, nil
+		}
+		result, custErr := authorService.UpdateAuthor(ctx, int(id), req)
+		return id, result, custErr
+	}
+}
+
+// resolveBatchID reads the id of an operation, which is either a number or a
+// reference to an earlier operation.
+func resolveBatchID(raw json.RawMessage, ids map[string]uint) (uint, error) {
+	if len(raw) == 0 {
+		return 0, nil
+	}
+	resolved, err := resolveBatchRefs(raw, ids)
+	if err != nil {
+		return 0, err
+	}
+	var id uint
+	if err := json.Unmarshal(resolved, &id); err != nil {
+		return 0, errors.New("id must be a positive number or a reference")
+	}
+	return id, nil
+}
+
+// resolveBatchRefs replaces every {"$ref": "name"} object in raw with the ID
+// the operation with that ref created.
+func resolveBatchRefs(raw json.RawMessage, ids map[string]uint) (json.RawMessage, error) {
+	if len(raw) == 0 {
+		return raw, nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	value, err := replaceBatchRefs(value, ids)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(value)
+}
+
+func replaceBatchRefs(value interface{}, ids map[string]uint) (interface{}, error) {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		if name, ok := typed["$ref"].(string); ok && len(typed) == 1 {
+			id, ok := ids[name]
+			if !ok {
+				return nil, fmt.Errorf("unknown reference %s", name)
+			}
+			return id, nil
+		}
+		for key, item := range typed {
+			replaced, err := replaceBatchRefs(item, ids)
+			if err != nil {
+				return nil, err
+			}
+			typed[key] = replaced
+		}
+	case []interface{}:
+		for i, item := range typed {
+			replaced, err := replaceBatchRefs(item, ids)
+			if err != nil {
+				return nil, err
+			}
+			typed[i] = replaced
+		}
+	}
+	return value, nil
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"golang-backend-test/app/commons/response"
+	"golang-backend-test/app/models"
+	"golang-backend-test/app/params"
+	"golang-backend-test/app/repositories"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func newTestBatchDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	assert.Nil(t, err)
+	return db
+}
+
+func TestRunBatch_ResolvesReferences(t *testing.T) {
+	bookRepo := new(repositories.MockBookRepository)
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := newTestBatchDB(t)
+	service := NewBatchService(bookRepo, authorRepo, nil, nil, db)
+
+	authorRepo.On("CreateAuthor", mock.Anything, mock.Anything, mock.AnythingOfType("*models.Author")).Run(func(args mock.Arguments) {
+		args.Get(2).(*models.Author).ID = 5
+	}).Return(nil)
+	authorRepo.On("FindAuthorById", mock.Anything, mock.Anything, 5).Return(&models.Author{ID: 5, Name: "Ann"}, nil)
+	bookRepo.On("CreateBook", mock.Anything, mock.Anything, mock.MatchedBy(func(book *models.Book) bool {
+		return book.AuthorID == 5
+	})).Run(func(args mock.Arguments) {
+		args.Get(2).(*models.Book).ID = 9
+	}).Return(nil)
+	bookRepo.On("DeleteBook", mock.Anything, mock.Anything, 9).Return(nil)
+
+	result, err := service.RunBatch(context.Background(), &params.BatchRequest{Operations: []params.BatchOperationRequest{
+		{Ref: "ann", Action: "create", Resource: "author", Body: json.RawMessage(`{"name":"Ann","birthdate":"1950-01-01"}`)},
+		{Ref: "book", Action: "create", Resource: "book", Body: json.RawMessage(`{"title":"B","author_id":{"$ref":"ann"}}`)},
+		{Action: "delete", Resource: "book", ID: json.RawMessage(`{"$ref":"book"}`)},
+	}})
+
+	assert.Nil(t, err)
+	assert.True(t, result.Committed)
+	assert.Len(t, result.Results, 3)
+	assert.Equal(t, uint(5), result.Results[0].ID)
+	assert.Equal(t, uint(9), result.Results[1].ID)
+	assert.Equal(t, BatchOk, result.Results[2].Status)
+	bookRepo.AssertExpectations(t)
+}
+
+func TestRunBatch_RollsBackOnFailure(t *testing.T) {
+	bookRepo := new(repositories.MockBookRepository)
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := newTestBatchDB(t)
+	service := NewBatchService(bookRepo, authorRepo, nil, nil, db)
+
+	authorRepo.On("CreateAuthor", mock.Anything, mock.Anything, mock.AnythingOfType("*models.Author")).Return(nil)
+	authorRepo.On("FindAuthorById", mock.Anything, mock.Anything, 3).Return(nil, errors.New("author not found"))
+
+	result, err := service.RunBatch(context.Background(), &params.BatchRequest{Operations: []params.BatchOperationRequest{
+		{Action: "create", Resource: "author", Body: json.RawMessage(`{"name":"Ann","birthdate":"1950-01-01"}`)},
+		{Action: "create", Resource: "book", Body: json.RawMessage(`{"title":"B","author_id":3}`)},
+		{Action: "delete", Resource: "author", ID: json.RawMessage(`1`)},
+	}})
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	batch := err.AdditionalInfo.(*params.BatchResponse)
+	assert.False(t, batch.Committed)
+	assert.Equal(t, BatchRolledBack, batch.Results[0].Status)
+	assert.Equal(t, BatchFailed, batch.Results[1].Status)
+	assert.Equal(t, BatchSkipped, batch.Results[2].Status)
+	authorRepo.AssertNotCalled(t, "DeleteAuthor", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRunBatch_PartialKeepsGoing(t *testing.T) {
+	bookRepo := new(repositories.MockBookRepository)
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := newTestBatchDB(t)
+	service := NewBatchService(bookRepo, authorRepo, nil, nil, db)
+
+	authorRepo.On("DeleteAuthor", mock.Anything, mock.Anything, 2).Return(nil)
+
+	result, err := service.RunBatch(context.Background(), &params.BatchRequest{Partial: true, Operations: []params.BatchOperationRequest{
+		{Action: "update", Resource: "book", ID: json.RawMessage(`{"$ref":"missing"}`), Body: json.RawMessage(`{}`)},
+		{Action: "delete", Resource: "author", ID: json.RawMessage(`2`)},
+	}})
+
+	assert.Nil(t, err)
+	assert.True(t, result.Committed)
+	assert.Equal(t, BatchFailed, result.Results[0].Status)
+	assert.Equal(t, "unknown reference missing", result.Results[0].Error.(*response.CustomError).AdditionalInfo)
+	assert.Equal(t, BatchOk, result.Results[1].Status)
+	authorRepo.AssertExpectations(t)
+}
+
+func TestRunBatch_DuplicateRef(t *testing.T) {
+	bookRepo := new(repositories.MockBookRepository)
+	authorRepo := new(repositories.MockAuthorRepository)
+	service := NewBatchService(bookRepo, authorRepo, nil, nil, new(gorm.DB))
+
+	result, err := service.RunBatch(context.Background(), &params.BatchRequest{Operations: []params.BatchOperationRequest{
+		{Ref: "a", Action: "delete", Resource: "book", ID: json.RawMessage(`1`)},
+		{Ref: "a", Action: "delete", Resource: "book", ID: json.RawMessage(`2`)},
+	}})
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "operation 1 reuses ref a", err.AdditionalInfo)
+}
+
+func TestRunBatch_DeleteMissingBookFails(t *testing.T) {
+	db, acme, _ := newTenantTestDB(t)
+	service := NewBatchService(repositories.NewBookRepository(), repositories.NewAuthorRepository(), nil, nil, db)
+
+	result, err := service.RunBatch(acme, &params.BatchRequest{Partial: true, Operations: []params.BatchOperationRequest{
+		{Action: "delete", Resource: "book", ID: json.RawMessage(`99`)},
+		{Action: "delete", Resource: "book", ID: json.RawMessage(`1`)},
+	}})
+
+	assert.Nil(t, err)
+	assert.Equal(t, BatchFailed, result.Results[0].Status)
+	assert.Equal(t, BatchOk, result.Results[1].Status)
+}
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"golang-backend-test/app/commons/response"
+	"golang-backend-test/app/models"
+	"golang-backend-test/app/params"
+	"golang-backend-test/app/repositories"
+	"golang-backend-test/pkg/callnumber"
+
+	"github.com/go-playground/validator"
+	"gorm.io/gorm"
+)
+
+type BookCopyService interface {
+	FindBookCopies(ctx context.Context, bookId int) ([]*params.BookCopyResponse, *response.CustomError)
+	CreateBookCopy(ctx context.Context, bookId int, req *params.BookCopyRequest) (*params.BookCopyResponse, *response.CustomError)
+	UpdateBookCopy(ctx context.Context, bookId, id int, req *params.BookCopyRequest) (*params.BookCopyResponse, *response.CustomError)
+}
+
+type BookCopyServiceImpl struct {
+	BookCopyRepository repositories.BookCopyRepository
+	BookRepository     repositories.BookRepository
+	BranchRepository   repositories.BranchRepository
+	DB                 *gorm.DB
+}
+
+func NewBookCopyService(bookCopyRepository repositories.BookCopyRepository, bookRepository repositories.BookRepository, branchRepository repositories.BranchRepository, db *gorm.DB) BookCopyService {
+	return &BookCopyServiceImpl{
+		BookCopyRepository: bookCopyRepository,
+		BookRepository:     bookRepository,
+		BranchRepository:   branchRepository,
+		DB:                 db,
+	}
+}
+
+func (service *BookCopyServiceImpl) FindBookCopies(ctx context.Context, bookId int) ([]*params.BookCopyResponse, *response.CustomError) {
+	if _, err := service.BookRepository.FindBookById(ctx, service.DB, bookId); err != nil {
+		return nil, response.NotFoundError()
+	}
+
+	copies, err := service.BookCopyRepository.GetBookCopies(ctx, service.DB, bookId)
+	if err != nil {
+		return nil, response.RepositoryError()
+	}
+	var copyResponses []*params.BookCopyResponse
+	for _, bookCopy := range copies {
+		copyResponses = append(copyResponses, bookCopyResponse(bookCopy))
+	}
+	return copyResponses, nil
+}
+
+func (service *BookCopyServiceImpl) CreateBookCopy(ctx context.Context, bookId int, req *params.BookCopyRequest) (*params.BookCopyResponse, *response.CustomError) {
+	book, err := service.BookRepository.FindBookById(ctx, service.DB, bookId)
+	if err != nil {
+		return nil, response.NotFoundError()
+	}
+
+	var bookCopy = new(models.BookCopy)
+	bookCopy.BookID = book.ID
+	bookCopy.Status = models.CopyAvailable
+	if custErr := service.applyBookCopyRequest(ctx, bookCopy, req); custErr != nil {
+		return nil, custErr
+	}
+	if err := service.BookCopyRepository.CreateBookCopy(ctx, service.DB, bookCopy); err != nil {
+		return nil, response.BadRequestError()
+	}
+
+	return bookCopyResponse(bookCopy), nil
+}
+
+func (service *BookCopyServiceImpl) UpdateBookCopy(ctx context.Context, bookId, id int, req *params.BookCopyRequest) (*params.BookCopyResponse, *response.CustomError) {
+	bookCopy, err := service.BookCopyRepository.FindBookCopyById(ctx, service.DB, id)
+	if err != nil || int(bookCopy.BookID) != bookId {
+		return nil, response.NotFoundError()
+	}
+	if bookCopy.Status == models.CopyInTransit && !sameLocation(bookCopy.ShelfLocationID, req.ShelfLocationID) {
+		return nil, response.BadRequestErrorWithAdditionalInfo("book copy is in transit")
+	}
+
+	if custErr := service.applyBookCopyRequest(ctx, bookCopy, req); custErr != nil {
+		return nil, custErr
+	}
+	if err := service.BookCopyRepository.UpdateBookCopy(ctx, service.DB, bookCopy); err != nil {
+		return nil, response.BadRequestError()
+	}
+
+	return bookCopyResponse(bookCopy), nil
+}
+
+// applyBookCopyRequest validates req and copies it onto bookCopy, resolving
+// the shelf location and deriving the shelf-order sort key of the call number.
+func (service *BookCopyServiceImpl) applyBookCopyRequest(ctx context.Context, bookCopy *models.BookCopy, req *params.BookCopyRequest) *response.CustomError {
+	val := validator.New()
+	err := val.Struct(req)
+	if err != nil {
+		validationErrors := err.(validator.ValidationErrors)
+		var errors []interface{}
+		for _, fieldError := range validationErrors {
+			error := "error " + fieldError.Field() + " on tag " + fieldError.Tag()
+			errors = append(errors, error)
+		}
+		return response.BadRequestErrorWithAdditionalInfo(errors)
+	}
+
+	bookCopy.Barcode = req.Barcode
+	bookCopy.ShelfLocationID = nil
+	bookCopy.ShelfLocation = nil
+	if req.ShelfLocationID != nil {
+		location, err := service.BranchRepository.FindShelfLocationById(ctx, service.DB, int(*req.ShelfLocationID))
+		if err != nil {
+			return response.BadRequestErrorWithAdditionalInfo(err.Error())
+		}
+		bookCopy.ShelfLocationID = &location.ID
+		bookCopy.ShelfLocation = location
+	}
+
+	bookCopy.CallNumber = req.CallNumber
+	bookCopy.CallNumberScheme = req.CallNumberScheme
+	bookCopy.CallNumberSortKey = ""
+	if req.CallNumber != "" {
+		bookCopy.CallNumberSortKey, err = callnumber.SortKey(req.CallNumberScheme, req.CallNumber)
+		if err != nil {
+			return response.BadRequestErrorWithAdditionalInfo(err.Error())
+		}
+	}
+	return nil
+}
+
+func sameLocation(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func bookCopyResponse(bookCopy *models.BookCopy) *params.BookCopyResponse {
+	result := &params.BookCopyResponse{
+		ID:               bookCopy.ID,
+		Barcode:          bookCopy.Barcode,
+		CallNumber:       bookCopy.CallNumber,
+		CallNumberScheme: bookCopy.CallNumberScheme,
+		Status:           bookCopy.Status,
+		Available:        bookCopy.Status == models.CopyAvailable,
+	}
+	if bookCopy.ShelfLocation != nil {
+		result.Location = shelfLocationResponse(bookCopy.ShelfLocation)
+	}
+	return result
+}
@@ -0,0 +1,242 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"golang-backend-test/app/models"
+	"golang-backend-test/app/params"
+	"golang-backend-test/app/repositories"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestFindBookCopies_Success(t *testing.T) {
+	bookCopyRepo := new(repositories.MockBookCopyRepository)
+	bookRepo := new(repositories.MockBookRepository)
+	branchRepo := new(repositories.MockBranchRepository)
+	db := new(gorm.DB)
+
+	copies := []*models.BookCopy{
+		{ID: 2, BookID: 1, Barcode: "C0002", CallNumber: "5.133 J38", CallNumberScheme: "dewey"},
+		{ID: 1, BookID: 1, Barcode: "C0001", CallNumber: "823.912 TOL", CallNumberScheme: "dewey"},
+	}
+
+	bookRepo.On("FindBookById", mock.Anything, db, 1).Return(&models.Book{ID: 1}, nil)
+	bookCopyRepo.On("GetBookCopies", mock.Anything, db, 1).Return(copies, nil)
+	service := NewBookCopyService(bookCopyRepo, bookRepo, branchRepo, db)
+
+	result, err := service.FindBookCopies(context.Background(), 1)
+
+	assert.Nil(t, err)
+	assert.Len(t, result, 2)
+	assert.Equal(t, "C0002", result[0].Barcode)
+
+	bookRepo.AssertExpectations(t)
+	bookCopyRepo.AssertExpectations(t)
+}
+
+func TestFindBookCopies_BookNotFound(t *testing.T) {
+	bookCopyRepo := new(repositories.MockBookCopyRepository)
+	bookRepo := new(repositories.MockBookRepository)
+	branchRepo := new(repositories.MockBranchRepository)
+	db := new(gorm.DB)
+
+	bookRepo.On("FindBookById", mock.Anything, db, 1).Return(nil, errors.New("book not found"))
+	service := NewBookCopyService(bookCopyRepo, bookRepo, branchRepo, db)
+
+	result, err := service.FindBookCopies(context.Background(), 1)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "NOT FOUND ERROR", err.Message)
+
+	bookRepo.AssertExpectations(t)
+}
+
+func TestCreateBookCopy_Success(t *testing.T) {
+	bookCopyRepo := new(repositories.MockBookCopyRepository)
+	bookRepo := new(repositories.MockBookRepository)
+	branchRepo := new(repositories.MockBranchRepository)
+	db := new(gorm.DB)
+	locationID := uint(3)
+
+	req := &params.BookCopyRequest{
+		Barcode:          "C0001",
+		ShelfLocationID:  &locationID,
+		CallNumber:       "823.912 tol",
+		CallNumberScheme: "dewey",
+	}
+
+	bookRepo.On("FindBookById", mock.Anything, db, 1).Return(&models.Book{ID: 1}, nil)
+	branchRepo.On("FindShelfLocationById", mock.Anything, db, 3).Return(&models.ShelfLocation{
+		ID:     locationID,
+		Name:   "Fiction A",
+		Branch: models.Branch{ID: 1, Code: "MAIN", Name: "Main Library"},
+	}, nil)
+	bookCopyRepo.On("CreateBookCopy", mock.Anything, db, mock.MatchedBy(func(bookCopy *models.BookCopy) bool {
+		return bookCopy.BookID == 1 && *bookCopy.ShelfLocationID == 3 && bookCopy.CallNumberSortKey == "823.912 TOL"
+	})).Return(nil)
+	service := NewBookCopyService(bookCopyRepo, bookRepo, branchRepo, db)
+
+	result, err := service.CreateBookCopy(context.Background(), 1, req)
+
+	assert.Nil(t, err)
+	assert.NotNil(t, result)
+	assert.Equal(t, "823.912 tol", result.CallNumber)
+	assert.Equal(t, "MAIN", result.Location.Branch.Code)
+
+	bookRepo.AssertExpectations(t)
+	branchRepo.AssertExpectations(t)
+	bookCopyRepo.AssertExpectations(t)
+}
+
+func TestCreateBookCopy_ValidationError(t *testing.T) {
+	bookCopyRepo := new(repositories.MockBookCopyRepository)
+	bookRepo := new(repositories.MockBookRepository)
+	branchRepo := new(repositories.MockBranchRepository)
+	db := new(gorm.DB)
+
+	req := &params.BookCopyRequest{Barcode: "C0001", CallNumber: "823.912 TOL"}
+
+	bookRepo.On("FindBookById", mock.Anything, db, 1).Return(&models.Book{ID: 1}, nil)
+	service := NewBookCopyService(bookCopyRepo, bookRepo, branchRepo, db)
+
+	result, err := service.CreateBookCopy(context.Background(), 1, req)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "BAD REQUEST ERROR", err.Message)
+
+	bookRepo.AssertExpectations(t)
+}
+
+func TestCreateBookCopy_InvalidCallNumber(t *testing.T) {
+	bookCopyRepo := new(repositories.MockBookCopyRepository)
+	bookRepo := new(repositories.MockBookRepository)
+	branchRepo := new(repositories.MockBranchRepository)
+	db := new(gorm.DB)
+
+	req := &params.BookCopyRequest{Barcode: "C0001", CallNumber: "823.912 TOL", CallNumberScheme: "lcc"}
+
+	bookRepo.On("FindBookById", mock.Anything, db, 1).Return(&models.Book{ID: 1}, nil)
+	service := NewBookCopyService(bookCopyRepo, bookRepo, branchRepo, db)
+
+	result, err := service.CreateBookCopy(context.Background(), 1, req)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "BAD REQUEST ERROR", err.Message)
+	assert.Equal(t, "invalid call number", err.AdditionalInfo)
+
+	bookRepo.AssertExpectations(t)
+}
+
+func TestCreateBookCopy_ShelfLocationNotFound(t *testing.T) {
+	bookCopyRepo := new(repositories.MockBookCopyRepository)
+	bookRepo := new(repositories.MockBookRepository)
+	branchRepo := new(repositories.MockBranchRepository)
+	db := new(gorm.DB)
+	locationID := uint(3)
+
+	req := &params.BookCopyRequest{Barcode: "C0001", ShelfLocationID: &locationID}
+
+	bookRepo.On("FindBookById", mock.Anything, db, 1).Return(&models.Book{ID: 1}, nil)
+	branchRepo.On("FindShelfLocationById", mock.Anything, db, 3).Return(nil, errors.New("shelf location not found"))
+	service := NewBookCopyService(bookCopyRepo, bookRepo, branchRepo, db)
+
+	result, err := service.CreateBookCopy(context.Background(), 1, req)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "BAD REQUEST ERROR", err.Message)
+
+	bookRepo.AssertExpectations(t)
+	branchRepo.AssertExpectations(t)
+}
+
+func TestUpdateBookCopy_MovesCopy(t *testing.T) {
+	bookCopyRepo := new(repositories.MockBookCopyRepository)
+	bookRepo := new(repositories.MockBookRepository)
+	branchRepo := new(repositories.MockBranchRepository)
+	db := new(gorm.DB)
+	oldLocationID := uint(3)
+	newLocationID := uint(4)
+
+	current := &models.BookCopy{
+		ID:              1,
+		BookID:          1,
+		Barcode:         "C0001",
+		ShelfLocationID: &oldLocationID,
+		ShelfLocation:   &models.ShelfLocation{ID: oldLocationID, Name: "Fiction A"},
+	}
+	req := &params.BookCopyRequest{
+		Barcode:          "C0001",
+		ShelfLocationID:  &newLocationID,
+		CallNumber:       "PR6039.O32 L6 1954",
+		CallNumberScheme: "lcc",
+	}
+
+	bookCopyRepo.On("FindBookCopyById", mock.Anything, db, 1).Return(current, nil)
+	branchRepo.On("FindShelfLocationById", mock.Anything, db, 4).Return(&models.ShelfLocation{
+		ID:     newLocationID,
+		Name:   "Stacks",
+		Branch: models.Branch{ID: 2, Code: "EAST", Name: "East Branch"},
+	}, nil)
+	bookCopyRepo.On("UpdateBookCopy", mock.Anything, db, current).Return(nil)
+	service := NewBookCopyService(bookCopyRepo, bookRepo, branchRepo, db)
+
+	result, err := service.UpdateBookCopy(context.Background(), 1, 1, req)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "Stacks", result.Location.Name)
+	assert.Equal(t, "EAST", result.Location.Branch.Code)
+	assert.Equal(t, "PR 6039 O32 L6 001954", current.CallNumberSortKey)
+
+	branchRepo.AssertExpectations(t)
+	bookCopyRepo.AssertExpectations(t)
+}
+
+func TestUpdateBookCopy_CopyOfAnotherBook(t *testing.T) {
+	bookCopyRepo := new(repositories.MockBookCopyRepository)
+	bookRepo := new(repositories.MockBookRepository)
+	branchRepo := new(repositories.MockBranchRepository)
+	db := new(gorm.DB)
+
+	bookCopyRepo.On("FindBookCopyById", mock.Anything, db, 1).Return(&models.BookCopy{ID: 1, BookID: 2}, nil)
+	service := NewBookCopyService(bookCopyRepo, bookRepo, branchRepo, db)
+
+	result, err := service.UpdateBookCopy(context.Background(), 1, 1, &params.BookCopyRequest{Barcode: "C0001"})
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "NOT FOUND ERROR", err.Message)
+
+	bookCopyRepo.AssertExpectations(t)
+}
+
+func TestUpdateBookCopy_InTransit(t *testing.T) {
+	bookCopyRepo := new(repositories.MockBookCopyRepository)
+	bookRepo := new(repositories.MockBookRepository)
+	branchRepo := new(repositories.MockBranchRepository)
+	db := new(gorm.DB)
+	locationID := uint(3)
+	newLocationID := uint(4)
+
+	bookCopyRepo.On("FindBookCopyById", mock.Anything, db, 1).Return(&models.BookCopy{
+		ID:              1,
+		BookID:          1,
+		ShelfLocationID: &locationID,
+		Status:          models.CopyInTransit,
+	}, nil)
+	service := NewBookCopyService(bookCopyRepo, bookRepo, branchRepo, db)
+
+	result, err := service.UpdateBookCopy(context.Background(), 1, 1, &params.BookCopyRequest{Barcode: "C0001", ShelfLocationID: &newLocationID})
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "book copy is in transit", err.AdditionalInfo)
+	bookCopyRepo.AssertNotCalled(t, "UpdateBookCopy", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -2,78 +2,156 @@ package services
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"golang-backend-test/app/commons/response"
 	"golang-backend-test/app/models"
 	"golang-backend-test/app/params"
 	"golang-backend-test/app/repositories"
+	"golang-backend-test/pkg/metadata"
+	"golang-backend-test/pkg/patch"
+	"strings"
+	"time"
 
 	"github.com/go-playground/validator"
 	"gorm.io/gorm"
 )
 
 type BookService interface {
-	FindDetailBook(ctx context.Context, id int) (*params.BookResponse, *response.CustomError)
-	FindAllBooks(ctx context.Context) ([]*params.BookResponse, *response.CustomError)
-	CrateBook(ctx context.Context, req *params.BookRequest) *response.CustomError
+	FindDetailBook(ctx context.Context, id int, fieldset *params.FieldsetRequest) (*params.BookResponse, *response.CustomError)
+	FindAllBooks(ctx context.Context, fieldset *params.FieldsetRequest) ([]*params.BookResponse, *response.CustomError)
+	FindBooksPage(ctx context.Context, req *params.PaginationRequest, fieldset *params.FieldsetRequest) (*params.PaginationResponse, *response.CustomError)
+	FindBranchBooks(ctx context.Context, branchId int, fieldset *params.FieldsetRequest) ([]*params.BookResponse, *response.CustomError)
+	CrateBook(ctx context.Context, req *params.BookRequest) (*params.BookResponse, *response.CustomError)
 	UpdateBook(ctx context.Context, id int, req *params.BookRequest) (*params.BookResponse, *response.CustomError)
+	PatchBook(ctx context.Context, id int, patchType string, patchDoc []byte) (*params.BookResponse, *response.CustomError)
 	DeleteBook(ctx context.Context, id int) *response.CustomError
+	ImportBooks(ctx context.Context, req *params.BookImportRequest) error
+	RateBook(ctx context.Context, userId, id int, req *params.BookRatingRequest) (*params.BookRatingResponse, *response.CustomError)
 }
 
+// JobImportBooks is the job type of book imports, whose payload is a
+// params.BookImportRequest.
+const JobImportBooks = "books.import"
+
 type BookServiceImpl struct {
 	BookRepository   repositories.BookRepository
 	AuthorRepository repositories.AuthorRepository
+	MetadataProvider metadata.Provider
+	Events           EventRecorder
 	DB               *gorm.DB
 }
 
-func NewBookService(bookRepository repositories.BookRepository, authorRepository repositories.AuthorRepository, db *gorm.DB) BookService {
+// NewBookService builds the book service. metadataProvider may be nil, in
+// which case books are never enriched, and events may be nil, in which case
+// no events are recorded.
+func NewBookService(bookRepository repositories.BookRepository, authorRepository repositories.AuthorRepository, metadataProvider metadata.Provider, events EventRecorder, db *gorm.DB) BookService {
 	return &BookServiceImpl{
 		BookRepository:   bookRepository,
 		AuthorRepository: authorRepository,
+		MetadataProvider: metadataProvider,
+		Events:           events,
 		DB:               db,
 	}
 }
 
-func (service *BookServiceImpl) FindDetailBook(ctx context.Context, id int) (*params.BookResponse, *response.CustomError) {
-	book, err := service.BookRepository.FindBookById(ctx, service.DB, id)
-	if err != nil {
-		return nil, response.NotFoundError()
+// bookFieldset lists the fields and relations a book response can be asked
+// for. The publisher is stored on the book itself, so expanding it only
+// makes sure the publisher field is written and costs no query.
+var bookFieldset = fieldsetSpec{
+	fields: map[string]string{
+		"id":               "id",
+		"title":            "title",
+		"isbn":             "isbn",
+		"publication_date": "publication_date",
+		"publisher":        "publisher",
+		"page_count":       "page_count",
+		"cover_url":        "cover_url",
+	},
+	relations: []string{"author", "copies", "publisher"},
+}
+
+// readBookFieldset reads what a book response is asked for and the load that
+// gets it, expanding defaults when the request does not say.
+func readBookFieldset(req *params.FieldsetRequest, defaults ...string) (params.Fieldset, repositories.BookLoad, *response.CustomError) {
+	fields, expand, custErr := bookFieldset.read(req, defaults...)
+	if custErr != nil {
+		return nil, repositories.BookLoad{}, custErr
+	}
+	load := repositories.BookLoad{
+		Columns: bookFieldset.columns(fields),
+		Author:  expand["author"],
+		Copies:  expand["copies"],
 	}
+	return fields, load, nil
+}
 
-	return &params.BookResponse{
-		ID:    book.ID,
-		Title: book.Title,
-		ISBN:  book.ISBN,
-		AuthorResponse: &params.AuthorResponse{
+// bookResponse writes book with the relations load loaded.
+func bookResponse(book *models.Book, fields params.Fieldset, load repositories.BookLoad) *params.BookResponse {
+	result := &params.BookResponse{
+		ID:              book.ID,
+		Title:           book.Title,
+		ISBN:            book.ISBN,
+		PublicationDate: formatOptionalDate(book.PublicationDate),
+		Publisher:       book.Publisher,
+		PageCount:       book.PageCount,
+		CoverURL:        book.CoverURL,
+		AuthorID:        book.AuthorID,
+		Fields:          fields,
+	}
+	if load.Author {
+		result.AuthorResponse = &params.AuthorResponse{
 			ID:        book.AuthorID,
 			Name:      book.Author.Name,
 			Birthdate: book.Author.Birthdate.Format("2006-01-02"),
-		},
-	}, nil
+		}
+	}
+	if load.Copies {
+		for i := range book.Copies {
+			result.Copies = append(result.Copies, bookCopyResponse(&book.Copies[i]))
+		}
+	}
+	return result
+}
 
+func (service *BookServiceImpl) FindDetailBook(ctx context.Context, id int, fieldset *params.FieldsetRequest) (*params.BookResponse, *response.CustomError) {
+	fields, load, custErr := readBookFieldset(fieldset, "author", "copies")
+	if custErr != nil {
+		return nil, custErr
+	}
+	book, err := service.BookRepository.LoadBookById(ctx, service.DB, id, load)
+	if err != nil {
+		return nil, response.NotFoundError()
+	}
+	return bookResponse(book, fields, load), nil
 }
 
-func (service *BookServiceImpl) FindAllBooks(ctx context.Context) ([]*params.BookResponse, *response.CustomError) {
-	books, err := service.BookRepository.GetListBooks(ctx, service.DB)
+func (service *BookServiceImpl) FindAllBooks(ctx context.Context, fieldset *params.FieldsetRequest) ([]*params.BookResponse, *response.CustomError) {
+	fields, load, custErr := readBookFieldset(fieldset, "author")
+	if custErr != nil {
+		return nil, custErr
+	}
+	books, err := service.BookRepository.GetListBooks(ctx, service.DB, load)
 	if err != nil {
 		return nil, response.BadRequestError()
 	}
 	var bookResponses []*params.BookResponse
 	for _, book := range books {
-		bookResponses = append(bookResponses, &params.BookResponse{
-			ID:    book.ID,
-			Title: book.Title,
-			ISBN:  book.ISBN,
-			AuthorResponse: &params.AuthorResponse{
-				ID:        book.AuthorID,
-				Name:      book.Author.Name,
-				Birthdate: book.Author.Birthdate.Format("2006-01-02"),
-			},
-		})
+		bookResponses = append(bookResponses, bookResponse(book, fields, load))
 	}
 	return bookResponses, nil
 }
 
-func (service *BookServiceImpl) CrateBook(ctx context.Context, req *params.BookRequest) *response.CustomError {
+var bookSorts = keysetSorts{
+	"id":                {order: repositories.KeysetOrder{}},
+	"-id":               {order: repositories.KeysetOrder{Desc: true}},
+	"title":             {order: repositories.KeysetOrder{Column: "title"}},
+	"-title":            {order: repositories.KeysetOrder{Column: "title", Desc: true}},
+	"publication_date":  {order: repositories.KeysetOrder{Column: "publication_date"}, time: true},
+	"-publication_date": {order: repositories.KeysetOrder{Column: "publication_date", Desc: true}, time: true},
+}
+
+func (service *BookServiceImpl) FindBooksPage(ctx context.Context, req *params.PaginationRequest, fieldset *params.FieldsetRequest) (*params.PaginationResponse, *response.CustomError) {
 	val := validator.New()
 	err := val.Struct(req)
 	if err != nil {
@@ -83,18 +161,127 @@ func (service *BookServiceImpl) CrateBook(ctx context.Context, req *params.BookR
 			error := "error " + fieldError.Field() + " on tag " + fieldError.Tag()
 			errors = append(errors, error)
 		}
-		return response.BadRequestErrorWithAdditionalInfo(errors)
+		return nil, response.BadRequestErrorWithAdditionalInfo(errors)
+	}
+	if custErr := cursorOnly(req); custErr != nil {
+		return nil, custErr
+	}
+	if req.Limit == 0 {
+		req.Limit = 10
+	}
+	if req.Sort == "" {
+		req.Sort = "id"
+	}
+	sort, custErr := bookSorts.find(req.Sort)
+	if custErr != nil {
+		return nil, custErr
+	}
+	page, custErr := keysetPage(req, sort)
+	if custErr != nil {
+		return nil, custErr
+	}
+
+	fields, load, custErr := readBookFieldset(fieldset, "author")
+	if custErr != nil {
+		return nil, custErr
+	}
+
+	books, more, total, err := service.BookRepository.GetBooksPage(ctx, service.DB, page, load)
+	if err != nil {
+		return nil, response.RepositoryError()
+	}
+	bookResponses := []*params.BookResponse{}
+	var rows []keysetRow
+	for _, book := range books {
+		bookResponses = append(bookResponses, bookResponse(book, fields, load))
+		rows = append(rows, keysetRow{value: bookSortValue(book, sort.order.Column), id: book.ID})
+	}
+
+	result := &params.PaginationResponse{
+		Items: bookResponses,
+		Limit: req.Limit,
+		Total: total,
+	}
+	hasNext, hasPrev := keysetNeighbours(page, more)
+	result.NextCursor, result.PrevCursor = pageCursors(req.Sort, rows, hasNext, hasPrev)
+	return result, nil
+}
+
+func (service *BookServiceImpl) FindBranchBooks(ctx context.Context, branchId int, fieldset *params.FieldsetRequest) ([]*params.BookResponse, *response.CustomError) {
+	fields, load, custErr := readBookFieldset(fieldset, "author")
+	if custErr != nil {
+		return nil, custErr
+	}
+	books, err := service.BookRepository.GetListBooksByBranch(ctx, service.DB, branchId, load)
+	if err != nil {
+		return nil, response.BadRequestError()
+	}
+	var bookResponses []*params.BookResponse
+	for _, book := range books {
+		bookResponses = append(bookResponses, bookResponse(book, fields, load))
+	}
+	return bookResponses, nil
+}
+
+func (service *BookServiceImpl) CrateBook(ctx context.Context, req *params.BookRequest) (*params.BookResponse, *response.CustomError) {
+	newAuthor, custErr := service.enrichBook(ctx, req)
+	if custErr != nil {
+		return nil, custErr
+	}
+
+	val := validator.New()
+	err := val.Struct(req)
+	if newAuthor != nil {
+		// the book gets the author id once the author is added
+		err = val.StructExcept(req, "AuthorID")
+	}
+	if err != nil {
+		validationErrors := err.(validator.ValidationErrors)
+		var errors []interface{}
+		for _, fieldError := range validationErrors {
+			error := "error " + fieldError.Field() + " on tag " + fieldError.Tag()
+			errors = append(errors, error)
+		}
+		return nil, response.BadRequestErrorWithAdditionalInfo(errors)
+	}
+
+	// the author must be visible to the caller, so a book can never point at
+	// another organization's author
+	author := newAuthor
+	if author == nil {
+		author, err = service.AuthorRepository.FindAuthorById(ctx, service.DB, int(req.AuthorID))
+		if err != nil {
+			return nil, response.BadRequestErrorWithAdditionalInfo("author not found")
+		}
 	}
 
 	var book = new(models.Book)
 	book.Title = req.Title
 	book.ISBN = req.ISBN
-	book.AuthorID = req.AuthorID
-	if err := service.BookRepository.CreateBook(ctx, service.DB, book); err != nil {
-		return response.BadRequestError()
+	book.PublicationDate, err = parseOptionalDate(req.PublicationDate)
+	if err != nil {
+		return nil, response.BadRequestErrorWithAdditionalInfo(fmt.Sprintf("Invalid date format: %s", err.Error()))
 	}
-
-	return nil
+	book.AuthorID = author.ID
+	book.Publisher = req.Publisher
+	book.PageCount = req.PageCount
+	book.CoverURL = req.CoverURL
+	result, err := service.saveBook(ctx, EventBookCreated, book, author, func(tx *gorm.DB) error {
+		if newAuthor != nil {
+			if err := service.AuthorRepository.CreateAuthor(ctx, tx, newAuthor); err != nil {
+				return err
+			}
+			if err := record(ctx, tx, service.Events, EventAuthorCreated, newAuthor.ID, authorResponse(newAuthor)); err != nil {
+				return err
+			}
+			book.AuthorID = newAuthor.ID
+		}
+		return service.BookRepository.CreateBook(ctx, tx, book)
+	})
+	if err != nil {
+		return nil, response.BadRequestError()
+	}
+	return result, nil
 }
 
 func (service *BookServiceImpl) UpdateBook(ctx context.Context, id int, req *params.BookRequest) (*params.BookResponse, *response.CustomError) {
@@ -119,29 +306,276 @@ func (service *BookServiceImpl) UpdateBook(ctx context.Context, id int, req *par
 	book.ID = uint(id)
 	book.Title = req.Title
 	book.ISBN = req.ISBN
+	book.PublicationDate, err = parseOptionalDate(req.PublicationDate)
+	if err != nil {
+		return nil, response.BadRequestErrorWithAdditionalInfo(fmt.Sprintf("Invalid date format: %s", err.Error()))
+	}
 	book.AuthorID = newAuthor.ID
+	book.Publisher = req.Publisher
+	book.PageCount = req.PageCount
+	book.CoverURL = req.CoverURL
 
-	if err := service.BookRepository.UpdateBook(ctx, service.DB, book); err != nil {
+	result, err := service.saveBook(ctx, EventBookUpdated, book, newAuthor, func(tx *gorm.DB) error {
+		return service.BookRepository.UpdateBook(ctx, tx, book)
+	})
+	if err != nil {
 		return nil, response.BadRequestError()
 	}
+	return result, nil
+}
+
+func (service *BookServiceImpl) PatchBook(ctx context.Context, id int, patchType string, patchDoc []byte) (*params.BookResponse, *response.CustomError) {
+	current, err := service.BookRepository.FindBookById(ctx, service.DB, id)
+	if err != nil {
+		return nil, response.NotFoundError()
+	}
 
-	return &params.BookResponse{
-		ID:    book.ID,
-		Title: book.Title,
-		ISBN:  book.ISBN,
-		AuthorResponse: &params.AuthorResponse{
-			ID:        newAuthor.ID,
-			Name:      newAuthor.Name,
-			Birthdate: newAuthor.Birthdate.Format("2006-01-02"),
-		},
-	}, nil
+	var req = new(params.BookRequest)
+	original := &params.BookRequest{
+		Title:           current.Title,
+		ISBN:            current.ISBN,
+		PublicationDate: formatOptionalDate(current.PublicationDate),
+		AuthorID:        current.AuthorID,
+		Publisher:       current.Publisher,
+		PageCount:       current.PageCount,
+		CoverURL:        current.CoverURL,
+	}
+	if err := patch.Apply(patchType, original, patchDoc, req); err != nil {
+		if errors.Is(err, patch.ErrUnsupportedMediaType) {
+			return nil, response.UnsupportedMediaTypeErrorWithAdditionalInfo(patchType)
+		}
+		return nil, response.BadRequestErrorWithAdditionalInfo(err.Error())
+	}
+
+	val := validator.New()
+	err = val.Struct(req)
+	if err != nil {
+		validationErrors := err.(validator.ValidationErrors)
+		var errors []interface{}
+		for _, fieldError := range validationErrors {
+			error := "error " + fieldError.Field() + " on tag " + fieldError.Tag()
+			errors = append(errors, error)
+		}
+		return nil, response.BadRequestErrorWithAdditionalInfo(errors)
+	}
+
+	author := &current.Author
+	if req.AuthorID != current.AuthorID {
+		author, err = service.AuthorRepository.FindAuthorById(ctx, service.DB, int(req.AuthorID))
+		if err != nil {
+			return nil, response.BadRequestError()
+		}
+	}
+
+	var book = new(models.Book)
+	book.ID = current.ID
+	book.Title = req.Title
+	book.ISBN = req.ISBN
+	book.PublicationDate, err = parseOptionalDate(req.PublicationDate)
+	if err != nil {
+		return nil, response.BadRequestErrorWithAdditionalInfo(fmt.Sprintf("Invalid date format: %s", err.Error()))
+	}
+	book.AuthorID = author.ID
+	book.Publisher = req.Publisher
+	book.PageCount = req.PageCount
+	book.CoverURL = req.CoverURL
+
+	result, err := service.saveBook(ctx, EventBookUpdated, book, author, func(tx *gorm.DB) error {
+		return service.BookRepository.UpdateBook(ctx, tx, book)
+	})
+	if err != nil {
+		return nil, response.BadRequestError()
+	}
+	return result, nil
 }
 
 func (service *BookServiceImpl) DeleteBook(ctx context.Context, id int) *response.CustomError {
-	err := service.BookRepository.DeleteBook(ctx, service.DB, id)
+	err := inTransaction(service.DB, service.Events, func(tx *gorm.DB) error {
+		if err := service.BookRepository.DeleteBook(ctx, tx, id); err != nil {
+			return err
+		}
+		return record(ctx, tx, service.Events, EventBookDeleted, uint(id), &params.DeletedResponse{ID: uint(id)})
+	})
 	if err != nil {
 		return response.NotFoundError()
 	}
 
 	return nil
 }
+
+// RateBook records the score the user gives the book, replacing the one
+// they gave before.
+func (service *BookServiceImpl) RateBook(ctx context.Context, userId, id int, req *params.BookRatingRequest) (*params.BookRatingResponse, *response.CustomError) {
+	val := validator.New()
+	err := val.Struct(req)
+	if err != nil {
+		validationErrors := err.(validator.ValidationErrors)
+		var errors []interface{}
+		for _, fieldError := range validationErrors {
+			error := "error " + fieldError.Field() + " on tag " + fieldError.Tag()
+			errors = append(errors, error)
+		}
+		return nil, response.BadRequestErrorWithAdditionalInfo(errors)
+	}
+	if _, err := service.BookRepository.FindBookById(ctx, service.DB, id); err != nil {
+		return nil, response.NotFoundError()
+	}
+
+	rating, err := service.BookRepository.FindBookRating(ctx, service.DB, id, userId)
+	if err != nil {
+		rating = &models.BookRating{BookID: uint(id), UserID: uint(userId)}
+	}
+	rating.Score = req.Score
+	rating.CreatedAt = time.Now()
+	if err := service.BookRepository.SaveBookRating(ctx, service.DB, rating); err != nil {
+		return nil, response.RepositoryError()
+	}
+	return &params.BookRatingResponse{BookID: rating.BookID, UserID: rating.UserID, Score: rating.Score}, nil
+}
+
+// ValidateBookImport checks req before its import is queued.
+func ValidateBookImport(req *params.BookImportRequest) *response.CustomError {
+	val := validator.New()
+	err := val.Struct(req)
+	if err != nil {
+		validationErrors := err.(validator.ValidationErrors)
+		var errors []interface{}
+		for _, fieldError := range validationErrors {
+			error := "error " + fieldError.Field() + " on tag " + fieldError.Tag()
+			errors = append(errors, error)
+		}
+		return response.BadRequestErrorWithAdditionalInfo(errors)
+	}
+	return nil
+}
+
+// ImportBooks is the handler of JobImportBooks. It adds every ISBN not yet
+// in the catalog as CrateBook would with only the ISBN given. A failed
+// metadata lookup has the job tried again, skipping the books added before
+// it; the ISBNs that cannot be added fail the job once the others are in.
+func (service *BookServiceImpl) ImportBooks(ctx context.Context, req *params.BookImportRequest) error {
+	if custErr := ValidateBookImport(req); custErr != nil {
+		return PermanentJobError(fmt.Errorf("%s: %v", custErr.Message, custErr.AdditionalInfo))
+	}
+	if service.MetadataProvider == nil {
+		return PermanentJobError(errors.New("book enrichment is off"))
+	}
+
+	var rejected []string
+	for _, isbn := range req.ISBNs {
+		if _, err := service.BookRepository.FindBookByISBN(ctx, service.DB, isbn); err == nil {
+			continue
+		}
+		_, custErr := service.CrateBook(ctx, &params.BookRequest{ISBN: isbn})
+		if custErr == nil {
+			continue
+		}
+		if custErr.StatusCode >= 500 {
+			return fmt.Errorf("isbn %s: %s: %v", isbn, custErr.Message, custErr.AdditionalInfo)
+		}
+		rejected = append(rejected, fmt.Sprintf("isbn %s: %s: %v", isbn, custErr.Message, custErr.AdditionalInfo))
+	}
+	if len(rejected) > 0 {
+		return PermanentJobError(fmt.Errorf("%d of %d books not imported: %s", len(rejected), len(req.ISBNs), strings.Join(rejected, "; ")))
+	}
+	return nil
+}
+
+// saveBook saves book with save and records event about it in the same
+// transaction.
+func (service *BookServiceImpl) saveBook(ctx context.Context, event string, book *models.Book, author *models.Author, save func(tx *gorm.DB) error) (*params.BookResponse, error) {
+	var result *params.BookResponse
+	err := inTransaction(service.DB, service.Events, func(tx *gorm.DB) error {
+		if err := save(tx); err != nil {
+			return err
+		}
+		result = &params.BookResponse{
+			ID:              book.ID,
+			Title:           book.Title,
+			ISBN:            book.ISBN,
+			PublicationDate: formatOptionalDate(book.PublicationDate),
+			Publisher:       book.Publisher,
+			PageCount:       book.PageCount,
+			CoverURL:        book.CoverURL,
+			AuthorID:        book.AuthorID,
+			AuthorResponse: &params.AuthorResponse{
+				ID:        author.ID,
+				Name:      author.Name,
+				Birthdate: author.Birthdate.Format("2006-01-02"),
+			},
+		}
+		return record(ctx, tx, service.Events, event, book.ID, result)
+	})
+	return result, err
+}
+
+// enrichBook fills the fields of a request that only names an ISBN from the
+// metadata provider. Fields the caller did give are kept. The author is
+// matched by name; when it is not in the catalog yet, enrichBook returns it
+// for the book to add along with itself.
+func (service *BookServiceImpl) enrichBook(ctx context.Context, req *params.BookRequest) (*models.Author, *response.CustomError) {
+	if service.MetadataProvider == nil || req.ISBN == "" || (req.Title != "" && req.AuthorID != 0) {
+		return nil, nil
+	}
+
+	record, err := service.MetadataProvider.Lookup(ctx, req.ISBN)
+	if err != nil {
+		if errors.Is(err, metadata.ErrNotFound) {
+			return nil, response.BadRequestErrorWithAdditionalInfo(fmt.Sprintf("no metadata found for isbn %s", req.ISBN))
+		}
+		return nil, response.GeneralErrorWithAdditionalInfo(fmt.Sprintf("metadata lookup failed: %s", err.Error()))
+	}
+
+	if req.Title == "" {
+		req.Title = record.Title
+	}
+	if req.PublicationDate == "" {
+		req.PublicationDate = formatOptionalDate(record.PublicationDate)
+	}
+	if req.Publisher == "" {
+		req.Publisher = record.Publisher
+	}
+	if req.PageCount == 0 {
+		req.PageCount = record.PageCount
+	}
+	if req.CoverURL == "" {
+		req.CoverURL = record.CoverURL
+	}
+	if req.AuthorID == 0 && len(record.Authors) > 0 {
+		author, err := service.AuthorRepository.FindAuthorByName(ctx, service.DB, record.Authors[0])
+		if err != nil {
+			return &models.Author{Name: record.Authors[0]}, nil
+		}
+		req.AuthorID = author.ID
+	}
+	return nil, nil
+}
+
+// bookSortValue is the sort key of book in a list sorted on column.
+func bookSortValue(book *models.Book, column string) interface{} {
+	switch column {
+	case "title":
+		return book.Title
+	case "publication_date":
+		return optionalTime(book.PublicationDate)
+	}
+	return nil
+}
+
+func parseOptionalDate(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, err
+	}
+	return &date, nil
+}
+
+func formatOptionalDate(date *time.Time) string {
+	if date == nil {
+		return ""
+	}
+	return date.Format("2006-01-02")
+}
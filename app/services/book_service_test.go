@@ -2,15 +2,21 @@ package services
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"golang-backend-test/app/models"
 	"golang-backend-test/app/params"
 	"golang-backend-test/app/repositories"
+	"golang-backend-test/pkg/cursor"
+	"golang-backend-test/pkg/metadata"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
 
@@ -33,10 +39,10 @@ func TestFindDetailBook_Success(t *testing.T) {
 		},
 	}
 
-	bookRepo.On("FindBookById", mock.Anything, db, int(bookID)).Return(book, nil)
-	service := NewBookService(bookRepo, authorRepo, db)
+	bookRepo.On("LoadBookById", mock.Anything, db, int(bookID), repositories.BookLoad{Author: true, Copies: true}).Return(book, nil)
+	service := NewBookService(bookRepo, authorRepo, nil, nil, db)
 
-	result, err := service.FindDetailBook(context.Background(), int(bookID))
+	result, err := service.FindDetailBook(context.Background(), int(bookID), nil)
 
 	assert.Nil(t, err)
 	assert.NotNil(t, result)
@@ -49,16 +55,59 @@ func TestFindDetailBook_Success(t *testing.T) {
 	bookRepo.AssertExpectations(t)
 }
 
+func TestFindDetailBook_WithCopyLocations(t *testing.T) {
+	bookRepo := new(repositories.MockBookRepository)
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := new(gorm.DB)
+	locationID := uint(3)
+
+	book := &models.Book{
+		ID:       1,
+		Title:    "Test Book",
+		AuthorID: 1,
+		Author:   models.Author{ID: 1, Name: "Test Author"},
+		Copies: []models.BookCopy{
+			{
+				ID:               1,
+				Barcode:          "C0001",
+				CallNumber:       "823.912 TOL",
+				CallNumberScheme: "dewey",
+				ShelfLocationID:  &locationID,
+				ShelfLocation: &models.ShelfLocation{
+					ID:     locationID,
+					Name:   "Fiction A",
+					Branch: models.Branch{ID: 2, Code: "MAIN", Name: "Main Library"},
+				},
+			},
+			{ID: 2, Barcode: "C0002"},
+		},
+	}
+
+	bookRepo.On("LoadBookById", mock.Anything, db, 1, repositories.BookLoad{Author: true, Copies: true}).Return(book, nil)
+	service := NewBookService(bookRepo, authorRepo, nil, nil, db)
+
+	result, err := service.FindDetailBook(context.Background(), 1, nil)
+
+	assert.Nil(t, err)
+	assert.Len(t, result.Copies, 2)
+	assert.Equal(t, "823.912 TOL", result.Copies[0].CallNumber)
+	assert.Equal(t, "Fiction A", result.Copies[0].Location.Name)
+	assert.Equal(t, "MAIN", result.Copies[0].Location.Branch.Code)
+	assert.Nil(t, result.Copies[1].Location)
+
+	bookRepo.AssertExpectations(t)
+}
+
 func TestFindDetailBook_BookNotFound(t *testing.T) {
 	bookRepo := new(repositories.MockBookRepository)
 	authorRepo := new(repositories.MockAuthorRepository)
 	db := new(gorm.DB)
 	bookID := uint(1)
 
-	bookRepo.On("FindBookById", mock.Anything, db, int(bookID)).Return(nil, errors.New("book not found"))
-	service := NewBookService(bookRepo, authorRepo, db)
+	bookRepo.On("LoadBookById", mock.Anything, db, int(bookID), repositories.BookLoad{Author: true, Copies: true}).Return(nil, errors.New("book not found"))
+	service := NewBookService(bookRepo, authorRepo, nil, nil, db)
 
-	result, err := service.FindDetailBook(context.Background(), int(bookID))
+	result, err := service.FindDetailBook(context.Background(), int(bookID), nil)
 
 	assert.NotNil(t, err)
 	assert.Nil(t, result)
@@ -87,10 +136,10 @@ func TestFindAllBook_Success(t *testing.T) {
 		},
 	}
 
-	bookRepo.On("GetListBooks", mock.Anything, db).Return(books, nil)
-	service := NewBookService(bookRepo, authorRepo, db)
+	bookRepo.On("GetListBooks", mock.Anything, db, repositories.BookLoad{Author: true}).Return(books, nil)
+	service := NewBookService(bookRepo, authorRepo, nil, nil, db)
 
-	result, err := service.FindAllBooks(context.Background())
+	result, err := service.FindAllBooks(context.Background(), nil)
 
 	assert.Nil(t, err)
 	assert.NotNil(t, result)
@@ -103,26 +152,175 @@ func TestFindAllBook_Success(t *testing.T) {
 	bookRepo.AssertExpectations(t)
 }
 
+func TestFindAllBooks_SparseFields(t *testing.T) {
+	bookRepo := new(repositories.MockBookRepository)
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := new(gorm.DB)
+	service := NewBookService(bookRepo, authorRepo, nil, nil, db)
+
+	// no expansion, so the author is neither loaded nor written
+	bookRepo.On("GetListBooks", mock.Anything, db, repositories.BookLoad{Columns: []string{"title"}}).Return([]*models.Book{
+		{ID: 1, Title: "Test Book"},
+	}, nil)
+	expand := ""
+
+	result, err := service.FindAllBooks(context.Background(), &params.FieldsetRequest{Fields: "id,title", Expand: &expand})
+
+	assert.Nil(t, err)
+	assert.Nil(t, result[0].AuthorResponse)
+	encoded, errMarshal := json.Marshal(result[0])
+	assert.Nil(t, errMarshal)
+	assert.JSONEq(t, `{"id":1,"title":"Test Book"}`, string(encoded))
+	bookRepo.AssertExpectations(t)
+}
+
+func TestFindAllBooks_Expand(t *testing.T) {
+	bookRepo := new(repositories.MockBookRepository)
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := new(gorm.DB)
+	service := NewBookService(bookRepo, authorRepo, nil, nil, db)
+
+	bookRepo.On("GetListBooks", mock.Anything, db, repositories.BookLoad{Columns: []string{"isbn"}, Author: true}).Return([]*models.Book{
+		{ID: 1, ISBN: "123456789", AuthorID: 2, Author: models.Author{ID: 2, Name: "Test Author"}},
+	}, nil)
+	expand := "author"
+
+	result, err := service.FindAllBooks(context.Background(), &params.FieldsetRequest{Fields: "isbn", Expand: &expand})
+
+	assert.Nil(t, err)
+	encoded, errMarshal := json.Marshal(result[0])
+	assert.Nil(t, errMarshal)
+	assert.JSONEq(t, `{"isbn":"123456789","author":{"id":2,"name":"Test Author","birthdate":"0001-01-01"}}`, string(encoded))
+	bookRepo.AssertExpectations(t)
+}
+
+func TestFindAllBooks_ExpandPublisher(t *testing.T) {
+	bookRepo := new(repositories.MockBookRepository)
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := new(gorm.DB)
+	service := NewBookService(bookRepo, authorRepo, nil, nil, db)
+
+	// the publisher is a column of the book, so only the column is selected
+	bookRepo.On("GetListBooks", mock.Anything, db, repositories.BookLoad{Columns: []string{"publisher", "title"}}).Return([]*models.Book{
+		{ID: 1, Title: "Test Book", Publisher: "Test Press"},
+	}, nil)
+	expand := "publisher"
+
+	result, err := service.FindAllBooks(context.Background(), &params.FieldsetRequest{Fields: "title", Expand: &expand})
+
+	assert.Nil(t, err)
+	encoded, errMarshal := json.Marshal(result[0])
+	assert.Nil(t, errMarshal)
+	assert.JSONEq(t, `{"title":"Test Book","publisher":"Test Press"}`, string(encoded))
+	bookRepo.AssertExpectations(t)
+}
+
+func TestFindAllBooks_UnknownField(t *testing.T) {
+	bookRepo := new(repositories.MockBookRepository)
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := new(gorm.DB)
+	service := NewBookService(bookRepo, authorRepo, nil, nil, db)
+
+	result, err := service.FindAllBooks(context.Background(), &params.FieldsetRequest{Fields: "id,subtitle"})
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "unknown field subtitle", err.AdditionalInfo)
+
+	expand := "reviews"
+	result, err = service.FindAllBooks(context.Background(), &params.FieldsetRequest{Expand: &expand})
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "expand must be any of author, copies, publisher", err.AdditionalInfo)
+	bookRepo.AssertNotCalled(t, "GetListBooks")
+}
+
 func TestFindAllBooks_RepositoryError(t *testing.T) {
 	bookRepo := new(repositories.MockBookRepository)
 	authorRepo := new(repositories.MockAuthorRepository)
 	db := new(gorm.DB)
-	service := NewBookService(bookRepo, authorRepo, db)
+	service := NewBookService(bookRepo, authorRepo, nil, nil, db)
 
-	bookRepo.On("GetListBooks", mock.Anything, db).Return(nil, errors.New("db error"))
+	bookRepo.On("GetListBooks", mock.Anything, db, repositories.BookLoad{Author: true}).Return(nil, errors.New("db error"))
 
-	result, err := service.FindAllBooks(context.Background())
+	result, err := service.FindAllBooks(context.Background(), nil)
 
 	assert.NotNil(t, err)
 	assert.Nil(t, result)
 	bookRepo.AssertExpectations(t)
 }
 
+func TestFindBranchBooks_Success(t *testing.T) {
+	bookRepo := new(repositories.MockBookRepository)
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := new(gorm.DB)
+
+	books := []*models.Book{
+		{ID: 2, Title: "Programming", AuthorID: 1, Author: models.Author{ID: 1, Name: "Test Author"}},
+		{ID: 1, Title: "Fiction", AuthorID: 1, Author: models.Author{ID: 1, Name: "Test Author"}},
+	}
+
+	bookRepo.On("GetListBooksByBranch", mock.Anything, db, 2, repositories.BookLoad{Author: true}).Return(books, nil)
+	service := NewBookService(bookRepo, authorRepo, nil, nil, db)
+
+	result, err := service.FindBranchBooks(context.Background(), 2, nil)
+
+	assert.Nil(t, err)
+	assert.Len(t, result, 2)
+	assert.Equal(t, uint(2), result[0].ID)
+	assert.Equal(t, uint(1), result[1].ID)
+
+	bookRepo.AssertExpectations(t)
+}
+
+func TestFindBranchBooks_Fieldset(t *testing.T) {
+	bookRepo := new(repositories.MockBookRepository)
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := new(gorm.DB)
+
+	bookRepo.On("GetListBooksByBranch", mock.Anything, db, 2, repositories.BookLoad{Columns: []string{"title"}}).Return([]*models.Book{
+		{ID: 2, Title: "Programming"},
+	}, nil)
+	service := NewBookService(bookRepo, authorRepo, nil, nil, db)
+
+	expand := ""
+	result, err := service.FindBranchBooks(context.Background(), 2, &params.FieldsetRequest{Fields: "id,title", Expand: &expand})
+
+	assert.Nil(t, err)
+	assert.Len(t, result, 1)
+	assert.Nil(t, result[0].AuthorResponse)
+
+	result, err = service.FindBranchBooks(context.Background(), 2, &params.FieldsetRequest{Fields: "bogus"})
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "unknown field bogus", err.AdditionalInfo)
+	bookRepo.AssertExpectations(t)
+}
+
+func TestFindBranchBooks_RepositoryError(t *testing.T) {
+	bookRepo := new(repositories.MockBookRepository)
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := new(gorm.DB)
+
+	bookRepo.On("GetListBooksByBranch", mock.Anything, db, 2, repositories.BookLoad{Author: true}).Return(nil, errors.New("database error"))
+	service := NewBookService(bookRepo, authorRepo, nil, nil, db)
+
+	result, err := service.FindBranchBooks(context.Background(), 2, nil)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "BAD REQUEST ERROR", err.Message)
+
+	bookRepo.AssertExpectations(t)
+}
+
 func TestCreateBook_Success(t *testing.T) {
 	bookRepo := new(repositories.MockBookRepository)
 	authorRepo := new(repositories.MockAuthorRepository)
 	db := new(gorm.DB)
-	service := NewBookService(bookRepo, authorRepo, db)
+	service := NewBookService(bookRepo, authorRepo, nil, nil, db)
 
 	validRequest := &params.BookRequest{
 		Title:    "Test Book",
@@ -130,36 +328,99 @@ func TestCreateBook_Success(t *testing.T) {
 		AuthorID: 1,
 	}
 
+	authorRepo.On("FindAuthorById", mock.Anything, db, 1).Return(&models.Author{ID: 1, TenantID: 1}, nil)
 	bookRepo.On("CreateBook", mock.Anything, db, mock.AnythingOfType("*models.Book")).Return(nil)
 
-	err := service.CrateBook(context.Background(), validRequest)
+	_, err := service.CrateBook(context.Background(), validRequest)
 
 	assert.Nil(t, err)
 	bookRepo.AssertExpectations(t)
 }
 
+func TestCreateBook_RecordsEvent(t *testing.T) {
+	bookRepo := new(repositories.MockBookRepository)
+	authorRepo := new(repositories.MockAuthorRepository)
+	events := new(MockEventRecorder)
+	db, errDB := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	assert.Nil(t, errDB)
+	service := NewBookService(bookRepo, authorRepo, nil, events, db)
+
+	authorRepo.On("FindAuthorById", mock.Anything, db, 1).Return(&models.Author{ID: 1, TenantID: 1}, nil)
+	bookRepo.On("CreateBook", mock.Anything, mock.Anything, mock.AnythingOfType("*models.Book")).Run(func(args mock.Arguments) {
+		args.Get(2).(*models.Book).ID = 5
+	}).Return(nil)
+	events.On("Record", mock.Anything, mock.Anything, EventBookCreated, uint(5), mock.MatchedBy(func(book *params.BookResponse) bool {
+		return book.Title == "Test Book"
+	})).Return(nil)
+
+	_, err := service.CrateBook(context.Background(), &params.BookRequest{Title: "Test Book", AuthorID: 1})
+
+	assert.Nil(t, err)
+	events.AssertExpectations(t)
+}
+
+func TestCreateBook_RecordFailureFailsChange(t *testing.T) {
+	bookRepo := new(repositories.MockBookRepository)
+	authorRepo := new(repositories.MockAuthorRepository)
+	events := new(MockEventRecorder)
+	db, errDB := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	assert.Nil(t, errDB)
+	service := NewBookService(bookRepo, authorRepo, nil, events, db)
+
+	authorRepo.On("FindAuthorById", mock.Anything, db, 1).Return(&models.Author{ID: 1, TenantID: 1}, nil)
+	bookRepo.On("CreateBook", mock.Anything, mock.Anything, mock.AnythingOfType("*models.Book")).Return(nil)
+	events.On("Record", mock.Anything, mock.Anything, EventBookCreated, mock.Anything, mock.Anything).Return(errors.New("db error"))
+
+	result, err := service.CrateBook(context.Background(), &params.BookRequest{Title: "Test Book", AuthorID: 1})
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	events.AssertExpectations(t)
+}
+
 func TestCreateBook_ValidationError(t *testing.T) {
 	bookRepo := new(repositories.MockBookRepository)
 	authorRepo := new(repositories.MockAuthorRepository)
 	db := new(gorm.DB)
-	service := NewBookService(bookRepo, authorRepo, db)
+	service := NewBookService(bookRepo, authorRepo, nil, nil, db)
 
 	invalidRequest := &params.BookRequest{
 		Title: "",
 		ISBN:  "123456789",
 	}
 
-	err := service.CrateBook(context.Background(), invalidRequest)
+	_, err := service.CrateBook(context.Background(), invalidRequest)
 
 	assert.NotNil(t, err)
 	assert.Equal(t, "BAD REQUEST ERROR", err.Message)
 }
 
+func TestCreateBook_AuthorNotFound(t *testing.T) {
+	bookRepo := new(repositories.MockBookRepository)
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := new(gorm.DB)
+	service := NewBookService(bookRepo, authorRepo, nil, nil, db)
+
+	validRequest := &params.BookRequest{
+		Title:    "Test Book",
+		ISBN:     "123456789",
+		AuthorID: 2,
+	}
+
+	authorRepo.On("FindAuthorById", mock.Anything, db, 2).Return(nil, errors.New("author not found"))
+
+	_, err := service.CrateBook(context.Background(), validRequest)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "author not found", err.AdditionalInfo)
+	bookRepo.AssertNotCalled(t, "CreateBook", mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestCreateBook_RepositoryError(t *testing.T) {
 	bookRepo := new(repositories.MockBookRepository)
 	authorRepo := new(repositories.MockAuthorRepository)
 	db := new(gorm.DB)
-	service := NewBookService(bookRepo, authorRepo, db)
+	service := NewBookService(bookRepo, authorRepo, nil, nil, db)
 
 	validRequest := &params.BookRequest{
 		Title:    "Test Book",
@@ -167,20 +428,122 @@ func TestCreateBook_RepositoryError(t *testing.T) {
 		AuthorID: 1,
 	}
 
+	authorRepo.On("FindAuthorById", mock.Anything, db, 1).Return(&models.Author{ID: 1, TenantID: 1}, nil)
 	bookRepo.On("CreateBook", mock.Anything, db, mock.AnythingOfType("*models.Book")).Return(errors.New("db error"))
 
-	err := service.CrateBook(context.Background(), validRequest)
+	_, err := service.CrateBook(context.Background(), validRequest)
 
 	assert.NotNil(t, err)
 	assert.Equal(t, "BAD REQUEST ERROR", err.Message)
 	bookRepo.AssertExpectations(t)
 }
 
+func newTestMetadataProvider(t *testing.T) metadata.Provider {
+	path := filepath.Join(t.TempDir(), "metadata.json")
+	records := `[{"isbn":"978-0-06-093546-7","title":"To Kill a Mockingbird","authors":["Harper Lee"],"publisher":"Harper Perennial","page_count":336,"cover_url":"https://covers.example/1.jpg","publication_date":"2002-03-05T00:00:00Z"}]`
+	assert.Nil(t, os.WriteFile(path, []byte(records), 0o600))
+	provider, err := metadata.NewFileProvider(path)
+	assert.Nil(t, err)
+	return provider
+}
+
+func TestCreateBook_EnrichedFromISBN(t *testing.T) {
+	bookRepo := new(repositories.MockBookRepository)
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := new(gorm.DB)
+	service := NewBookService(bookRepo, authorRepo, newTestMetadataProvider(t), nil, db)
+
+	authorRepo.On("FindAuthorByName", mock.Anything, db, "Harper Lee").Return(nil, errors.New("author not found"))
+	authorRepo.On("CreateAuthor", mock.Anything, db, mock.MatchedBy(func(author *models.Author) bool {
+		return author.Name == "Harper Lee"
+	})).Run(func(args mock.Arguments) {
+		args.Get(2).(*models.Author).ID = 4
+	}).Return(nil)
+	bookRepo.On("CreateBook", mock.Anything, db, mock.MatchedBy(func(book *models.Book) bool {
+		return book.Title == "To Kill a Mockingbird" && book.AuthorID == 4 && book.Publisher == "Harper Perennial" &&
+			book.PageCount == 336 && formatOptionalDate(book.PublicationDate) == "2002-03-05"
+	})).Return(nil)
+
+	_, err := service.CrateBook(context.Background(), &params.BookRequest{ISBN: "9780060935467"})
+
+	assert.Nil(t, err)
+	bookRepo.AssertExpectations(t)
+	authorRepo.AssertExpectations(t)
+}
+
+func TestCreateBook_EnrichedAuthorRecordsEvent(t *testing.T) {
+	bookRepo := new(repositories.MockBookRepository)
+	authorRepo := new(repositories.MockAuthorRepository)
+	events := new(MockEventRecorder)
+	db, errDB := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	assert.Nil(t, errDB)
+	service := NewBookService(bookRepo, authorRepo, newTestMetadataProvider(t), events, db)
+
+	// the author is added in the transaction of the book, with its event
+	authorRepo.On("FindAuthorByName", mock.Anything, db, "Harper Lee").Return(nil, errors.New("author not found"))
+	authorRepo.On("CreateAuthor", mock.Anything, mock.MatchedBy(func(tx *gorm.DB) bool {
+		return tx != db
+	}), mock.AnythingOfType("*models.Author")).Run(func(args mock.Arguments) {
+		args.Get(2).(*models.Author).ID = 4
+	}).Return(nil)
+	events.On("Record", mock.Anything, mock.Anything, EventAuthorCreated, uint(4), mock.MatchedBy(func(author *params.AuthorResponse) bool {
+		return author.Name == "Harper Lee"
+	})).Return(nil)
+	bookRepo.On("CreateBook", mock.Anything, mock.Anything, mock.MatchedBy(func(book *models.Book) bool {
+		return book.AuthorID == 4
+	})).Run(func(args mock.Arguments) {
+		args.Get(2).(*models.Book).ID = 5
+	}).Return(nil)
+	events.On("Record", mock.Anything, mock.Anything, EventBookCreated, uint(5), mock.MatchedBy(func(book *params.BookResponse) bool {
+		return book.AuthorID == 4 && book.AuthorResponse.Name == "Harper Lee"
+	})).Return(nil)
+
+	result, err := service.CrateBook(context.Background(), &params.BookRequest{ISBN: "9780060935467"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, uint(4), result.AuthorID)
+	authorRepo.AssertNotCalled(t, "FindAuthorById", mock.Anything, mock.Anything, mock.Anything)
+	authorRepo.AssertExpectations(t)
+	events.AssertExpectations(t)
+}
+
+func TestCreateBook_EnrichKeepsGivenFields(t *testing.T) {
+	bookRepo := new(repositories.MockBookRepository)
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := new(gorm.DB)
+	service := NewBookService(bookRepo, authorRepo, newTestMetadataProvider(t), nil, db)
+
+	authorRepo.On("FindAuthorByName", mock.Anything, db, "Harper Lee").Return(&models.Author{ID: 2, Name: "Harper Lee"}, nil)
+	authorRepo.On("FindAuthorById", mock.Anything, db, 2).Return(&models.Author{ID: 2, Name: "Harper Lee"}, nil)
+	bookRepo.On("CreateBook", mock.Anything, db, mock.MatchedBy(func(book *models.Book) bool {
+		return book.Title == "Mockingbird" && book.AuthorID == 2 && book.Publisher == "Harper Perennial"
+	})).Return(nil)
+
+	_, err := service.CrateBook(context.Background(), &params.BookRequest{ISBN: "9780060935467", Title: "Mockingbird"})
+
+	assert.Nil(t, err)
+	authorRepo.AssertNotCalled(t, "CreateAuthor", mock.Anything, mock.Anything, mock.Anything)
+	bookRepo.AssertExpectations(t)
+}
+
+func TestCreateBook_NoMetadataForISBN(t *testing.T) {
+	bookRepo := new(repositories.MockBookRepository)
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := new(gorm.DB)
+	service := NewBookService(bookRepo, authorRepo, newTestMetadataProvider(t), nil, db)
+
+	_, err := service.CrateBook(context.Background(), &params.BookRequest{ISBN: "9780000000001"})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "no metadata found for isbn 9780000000001", err.AdditionalInfo)
+	bookRepo.AssertNotCalled(t, "CreateBook", mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestUpdateBook_Success(t *testing.T) {
 	bookRepo := new(repositories.MockBookRepository)
 	authorRepo := new(repositories.MockAuthorRepository)
 	db := new(gorm.DB)
-	service := NewBookService(bookRepo, authorRepo, db)
+	service := NewBookService(bookRepo, authorRepo, nil, nil, db)
 
 	validRequest := &params.BookRequest{
 		Title:    "Updated Book",
@@ -208,7 +571,7 @@ func TestUpdateBook_ValidationError(t *testing.T) {
 	bookRepo := new(repositories.MockBookRepository)
 	authorRepo := new(repositories.MockAuthorRepository)
 	db := new(gorm.DB)
-	service := NewBookService(bookRepo, authorRepo, db)
+	service := NewBookService(bookRepo, authorRepo, nil, nil, db)
 
 	invalidRequest := &params.BookRequest{
 		Title: "",
@@ -226,7 +589,7 @@ func TestUpdateBook_AuthorNotFound(t *testing.T) {
 	bookRepo := new(repositories.MockBookRepository)
 	authorRepo := new(repositories.MockAuthorRepository)
 	db := new(gorm.DB)
-	service := NewBookService(bookRepo, authorRepo, db)
+	service := NewBookService(bookRepo, authorRepo, nil, nil, db)
 
 	invalidRequest := &params.BookRequest{
 		Title:    "Update Book",
@@ -248,7 +611,7 @@ func TestUpdateBook_RepositoryError(t *testing.T) {
 	bookRepo := new(repositories.MockBookRepository)
 	authorRepo := new(repositories.MockAuthorRepository)
 	db := new(gorm.DB)
-	service := NewBookService(bookRepo, authorRepo, db)
+	service := NewBookService(bookRepo, authorRepo, nil, nil, db)
 
 	validRequest := &params.BookRequest{
 		Title:    "Updated Book",
@@ -272,11 +635,160 @@ func TestUpdateBook_RepositoryError(t *testing.T) {
 	bookRepo.AssertExpectations(t)
 }
 
+func TestPatchBook_MergePatchSuccess(t *testing.T) {
+	bookRepo := new(repositories.MockBookRepository)
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := new(gorm.DB)
+	service := NewBookService(bookRepo, authorRepo, nil, nil, db)
+
+	bookRepo.On("FindBookById", mock.Anything, db, 1).Return(&models.Book{
+		ID:       1,
+		Title:    "Test Book",
+		ISBN:     "123456789",
+		AuthorID: 1,
+		Author: models.Author{
+			ID:        1,
+			Name:      "Test Author",
+			Birthdate: time.Date(1985, time.April, 5, 0, 0, 0, 0, time.UTC),
+		},
+	}, nil)
+	bookRepo.On("UpdateBook", mock.Anything, db, mock.MatchedBy(func(book *models.Book) bool {
+		return book.Title == "Patched Book" && book.ISBN == "123456789" && book.AuthorID == 1
+	})).Return(nil)
+
+	result, err := service.PatchBook(context.Background(), 1, "application/merge-patch+json", []byte(`{"title":"Patched Book"}`))
+
+	assert.Nil(t, err)
+	assert.NotNil(t, result)
+	assert.Equal(t, "Patched Book", result.Title)
+	assert.Equal(t, "123456789", result.ISBN)
+	assert.Equal(t, "Test Author", result.AuthorResponse.Name)
+	bookRepo.AssertExpectations(t)
+	authorRepo.AssertExpectations(t)
+}
+
+func TestPatchBook_JSONPatchChangeAuthor(t *testing.T) {
+	bookRepo := new(repositories.MockBookRepository)
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := new(gorm.DB)
+	service := NewBookService(bookRepo, authorRepo, nil, nil, db)
+
+	bookRepo.On("FindBookById", mock.Anything, db, 1).Return(&models.Book{
+		ID:       1,
+		Title:    "Test Book",
+		ISBN:     "123456789",
+		AuthorID: 1,
+	}, nil)
+	authorRepo.On("FindAuthorById", mock.Anything, db, 2).Return(&models.Author{
+		ID:        2,
+		Name:      "Author 2",
+		Birthdate: time.Date(1990, time.May, 1, 0, 0, 0, 0, time.UTC),
+	}, nil)
+	bookRepo.On("UpdateBook", mock.Anything, db, mock.MatchedBy(func(book *models.Book) bool {
+		return book.Title == "Test Book" && book.AuthorID == 2
+	})).Return(nil)
+
+	result, err := service.PatchBook(context.Background(), 1, "application/json-patch+json", []byte(`[{"op":"replace","path":"/author_id","value":2}]`))
+
+	assert.Nil(t, err)
+	assert.NotNil(t, result)
+	assert.Equal(t, uint(2), result.AuthorResponse.ID)
+	assert.Equal(t, "Author 2", result.AuthorResponse.Name)
+	bookRepo.AssertExpectations(t)
+	authorRepo.AssertExpectations(t)
+}
+
+func TestPatchBook_ValidationError(t *testing.T) {
+	bookRepo := new(repositories.MockBookRepository)
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := new(gorm.DB)
+	service := NewBookService(bookRepo, authorRepo, nil, nil, db)
+
+	bookRepo.On("FindBookById", mock.Anything, db, 1).Return(&models.Book{
+		ID:       1,
+		Title:    "Test Book",
+		ISBN:     "123456789",
+		AuthorID: 1,
+	}, nil)
+
+	result, err := service.PatchBook(context.Background(), 1, "application/json-patch+json", []byte(`[{"op":"remove","path":"/title"}]`))
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "BAD REQUEST ERROR", err.Message)
+	bookRepo.AssertNotCalled(t, "UpdateBook", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPatchBook_UnknownField(t *testing.T) {
+	bookRepo := new(repositories.MockBookRepository)
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := new(gorm.DB)
+	service := NewBookService(bookRepo, authorRepo, nil, nil, db)
+
+	bookRepo.On("FindBookById", mock.Anything, db, 1).Return(&models.Book{ID: 1, Title: "Test Book", AuthorID: 1}, nil)
+
+	result, err := service.PatchBook(context.Background(), 1, "application/merge-patch+json", []byte(`{"subtitle":"Someone"}`))
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "BAD REQUEST ERROR", err.Message)
+}
+
+func TestPatchBook_UnsupportedMediaType(t *testing.T) {
+	bookRepo := new(repositories.MockBookRepository)
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := new(gorm.DB)
+	service := NewBookService(bookRepo, authorRepo, nil, nil, db)
+
+	bookRepo.On("FindBookById", mock.Anything, db, 1).Return(&models.Book{ID: 1, Title: "Test Book", AuthorID: 1}, nil)
+
+	result, err := service.PatchBook(context.Background(), 1, "application/json", []byte(`{"title":"Patched Book"}`))
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, 415, err.StatusCode)
+}
+
+func TestPatchBook_BookNotFound(t *testing.T) {
+	bookRepo := new(repositories.MockBookRepository)
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := new(gorm.DB)
+	service := NewBookService(bookRepo, authorRepo, nil, nil, db)
+
+	bookRepo.On("FindBookById", mock.Anything, db, 1).Return(nil, errors.New("book not found"))
+
+	result, err := service.PatchBook(context.Background(), 1, "application/merge-patch+json", []byte(`{"title":"Patched Book"}`))
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "NOT FOUND ERROR", err.Message)
+	bookRepo.AssertExpectations(t)
+}
+
+func TestUpdateBook_KeepsCreatedAt(t *testing.T) {
+	db, acme, _ := newTenantTestDB(t)
+	service := NewBookService(repositories.NewBookRepository(), repositories.NewAuthorRepository(), nil, nil, db)
+
+	var created models.Book
+	assert.Nil(t, db.WithContext(acme).First(&created, 1).Error)
+	assert.False(t, created.CreatedAt.IsZero())
+
+	_, err := service.UpdateBook(acme, 1, &params.BookRequest{Title: "Updated", ISBN: "9780000000001", AuthorID: 1})
+	assert.Nil(t, err)
+	_, err = service.PatchBook(acme, 1, "application/merge-patch+json", []byte(`{"title":"Patched"}`))
+	assert.Nil(t, err)
+
+	var book models.Book
+	assert.Nil(t, db.WithContext(acme).First(&book, 1).Error)
+	assert.Equal(t, "Patched", book.Title)
+	assert.True(t, created.CreatedAt.Equal(book.CreatedAt))
+}
+
 func TestDeleteBook_Success(t *testing.T) {
 	bookRepo := new(repositories.MockBookRepository)
 	authorRepo := new(repositories.MockAuthorRepository)
 	db := new(gorm.DB)
-	service := NewBookService(bookRepo, authorRepo, db)
+	service := NewBookService(bookRepo, authorRepo, nil, nil, db)
 
 	bookRepo.On("DeleteBook", mock.Anything, db, 1).Return(nil)
 
@@ -290,7 +802,7 @@ func TestDeleteBook_RepositoryError(t *testing.T) {
 	bookRepo := new(repositories.MockBookRepository)
 	authorRepo := new(repositories.MockAuthorRepository)
 	db := new(gorm.DB)
-	service := NewBookService(bookRepo, authorRepo, db)
+	service := NewBookService(bookRepo, authorRepo, nil, nil, db)
 
 	bookRepo.On("DeleteBook", mock.Anything, db, 1).Return(errors.New("book not found"))
 
@@ -300,3 +812,220 @@ func TestDeleteBook_RepositoryError(t *testing.T) {
 	assert.Equal(t, "NOT FOUND ERROR", err.Message)
 	bookRepo.AssertExpectations(t)
 }
+
+func TestDeleteBook_MissingRecordsNoEvent(t *testing.T) {
+	db, acme, _ := newTenantTestDB(t)
+	events := new(MockEventRecorder)
+	service := NewBookService(repositories.NewBookRepository(), repositories.NewAuthorRepository(), nil, events, db)
+
+	// globex's book is as missing to acme as one that never existed
+	assert.NotNil(t, service.DeleteBook(acme, 2))
+	assert.NotNil(t, service.DeleteBook(acme, 99))
+
+	events.AssertNotCalled(t, "Record", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestFindBooksPage_Cursors(t *testing.T) {
+	bookRepo := new(repositories.MockBookRepository)
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := new(gorm.DB)
+	service := NewBookService(bookRepo, authorRepo, nil, nil, db)
+
+	page := repositories.KeysetPage{Order: repositories.KeysetOrder{Column: "title"}, Limit: 2}
+	bookRepo.On("GetBooksPage", mock.Anything, db, page, repositories.BookLoad{Author: true}).Return([]*models.Book{
+		{ID: 4, Title: "A"},
+		{ID: 2, Title: "B"},
+	}, true, int64(5), nil)
+
+	result, err := service.FindBooksPage(context.Background(), &params.PaginationRequest{Limit: 2, Sort: "title"}, nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(5), result.Total)
+	assert.Equal(t, 2, len(result.Items.([]*params.BookResponse)))
+	assert.Empty(t, result.PrevCursor)
+	next, errDecode := cursor.Decode(result.NextCursor)
+	assert.Nil(t, errDecode)
+	assert.Equal(t, cursor.Position{Sort: "title", Value: "B", ID: 2}, *next)
+	bookRepo.AssertExpectations(t)
+}
+
+func TestFindBooksPage_TimeCursor(t *testing.T) {
+	bookRepo := new(repositories.MockBookRepository)
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := new(gorm.DB)
+	service := NewBookService(bookRepo, authorRepo, nil, nil, db)
+
+	published := time.Date(2002, time.January, 1, 0, 0, 0, 0, time.UTC)
+	token := cursor.Encode(cursor.Position{Sort: "-publication_date", Value: published, ID: 3})
+	page := repositories.KeysetPage{
+		Order: repositories.KeysetOrder{Column: "publication_date", Desc: true},
+		Value: published,
+		ID:    3,
+		Limit: 10,
+	}
+	bookRepo.On("GetBooksPage", mock.Anything, db, page, repositories.BookLoad{Author: true}).Return([]*models.Book{{ID: 1, Title: "E"}}, false, int64(5), nil)
+
+	result, err := service.FindBooksPage(context.Background(), &params.PaginationRequest{Sort: "-publication_date", Cursor: token}, nil)
+
+	assert.Nil(t, err)
+	assert.Empty(t, result.NextCursor)
+	assert.NotEmpty(t, result.PrevCursor)
+	bookRepo.AssertExpectations(t)
+}
+
+func TestFindBooksPage_InvalidCursor(t *testing.T) {
+	bookRepo := new(repositories.MockBookRepository)
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := new(gorm.DB)
+	service := NewBookService(bookRepo, authorRepo, nil, nil, db)
+
+	token := cursor.Encode(cursor.Position{Sort: "id", ID: 3})
+
+	result, err := service.FindBooksPage(context.Background(), &params.PaginationRequest{Cursor: "x" + token}, nil)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "invalid cursor", err.AdditionalInfo)
+	bookRepo.AssertNotCalled(t, "GetBooksPage")
+}
+
+func TestFindBooksPage_PageNumber(t *testing.T) {
+	bookRepo := new(repositories.MockBookRepository)
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := new(gorm.DB)
+	service := NewBookService(bookRepo, authorRepo, nil, nil, db)
+
+	result, err := service.FindBooksPage(context.Background(), &params.PaginationRequest{Page: 2}, nil)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "page numbers are not supported, follow next_cursor instead", err.AdditionalInfo)
+	bookRepo.AssertNotCalled(t, "GetBooksPage")
+}
+
+func TestFindBooksPage_CursorForOtherSort(t *testing.T) {
+	bookRepo := new(repositories.MockBookRepository)
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := new(gorm.DB)
+	service := NewBookService(bookRepo, authorRepo, nil, nil, db)
+
+	token := cursor.Encode(cursor.Position{Sort: "title", Value: "B", ID: 2})
+
+	result, err := service.FindBooksPage(context.Background(), &params.PaginationRequest{Sort: "id", Cursor: token}, nil)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "cursor is for sort title", err.AdditionalInfo)
+}
+
+func TestImportBooks_SkipsBooksInCatalog(t *testing.T) {
+	bookRepo := new(repositories.MockBookRepository)
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := new(gorm.DB)
+	service := NewBookService(bookRepo, authorRepo, newTestMetadataProvider(t), nil, db)
+
+	bookRepo.On("FindBookByISBN", mock.Anything, db, "9780547773742").Return(&models.Book{ID: 1, ISBN: "9780547773742"}, nil)
+	bookRepo.On("FindBookByISBN", mock.Anything, db, "9780060935467").Return(nil, errors.New("book not found"))
+	authorRepo.On("FindAuthorByName", mock.Anything, db, "Harper Lee").Return(&models.Author{ID: 4, Name: "Harper Lee"}, nil)
+	authorRepo.On("FindAuthorById", mock.Anything, db, 4).Return(&models.Author{ID: 4, Name: "Harper Lee"}, nil)
+	bookRepo.On("CreateBook", mock.Anything, db, mock.MatchedBy(func(book *models.Book) bool {
+		return book.ISBN == "9780060935467" && book.Title == "To Kill a Mockingbird" && book.AuthorID == 4
+	})).Return(nil).Once()
+
+	err := service.ImportBooks(context.Background(), &params.BookImportRequest{ISBNs: []string{"9780547773742", "9780060935467"}})
+
+	assert.Nil(t, err)
+	bookRepo.AssertExpectations(t)
+	authorRepo.AssertExpectations(t)
+}
+
+func TestImportBooks_UnknownISBNFailsPermanently(t *testing.T) {
+	bookRepo := new(repositories.MockBookRepository)
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := new(gorm.DB)
+	service := NewBookService(bookRepo, authorRepo, newTestMetadataProvider(t), nil, db)
+
+	bookRepo.On("FindBookByISBN", mock.Anything, db, "9780000000000").Return(nil, errors.New("book not found"))
+
+	err := service.ImportBooks(context.Background(), &params.BookImportRequest{ISBNs: []string{"9780000000000"}})
+
+	var permanent permanentJobError
+	assert.True(t, errors.As(err, &permanent))
+	assert.Contains(t, err.Error(), "1 of 1 books not imported")
+	assert.Contains(t, err.Error(), "no metadata found for isbn 9780000000000")
+	bookRepo.AssertNotCalled(t, "CreateBook", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestImportBooks_EnrichmentOff(t *testing.T) {
+	bookRepo := new(repositories.MockBookRepository)
+	authorRepo := new(repositories.MockAuthorRepository)
+	service := NewBookService(bookRepo, authorRepo, nil, nil, new(gorm.DB))
+
+	err := service.ImportBooks(context.Background(), &params.BookImportRequest{ISBNs: []string{"9780060935467"}})
+
+	var permanent permanentJobError
+	assert.True(t, errors.As(err, &permanent))
+	bookRepo.AssertNotCalled(t, "FindBookByISBN", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRateBook_ReplacesScore(t *testing.T) {
+	bookRepo := new(repositories.MockBookRepository)
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := new(gorm.DB)
+	service := NewBookService(bookRepo, authorRepo, nil, nil, db)
+
+	bookRepo.On("FindBookById", mock.Anything, db, 1).Return(&models.Book{ID: 1}, nil)
+	bookRepo.On("FindBookRating", mock.Anything, db, 1, 7).Return(&models.BookRating{ID: 2, BookID: 1, UserID: 7, Score: 2}, nil)
+	bookRepo.On("SaveBookRating", mock.Anything, db, mock.MatchedBy(func(rating *models.BookRating) bool {
+		return rating.ID == 2 && rating.Score == 5
+	})).Return(nil)
+
+	result, err := service.RateBook(context.Background(), 7, 1, &params.BookRatingRequest{Score: 5})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 5, result.Score)
+	bookRepo.AssertExpectations(t)
+}
+
+func TestRateBook_FirstRating(t *testing.T) {
+	bookRepo := new(repositories.MockBookRepository)
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := new(gorm.DB)
+	service := NewBookService(bookRepo, authorRepo, nil, nil, db)
+
+	bookRepo.On("FindBookById", mock.Anything, db, 1).Return(&models.Book{ID: 1}, nil)
+	bookRepo.On("FindBookRating", mock.Anything, db, 1, 7).Return(nil, errors.New("book rating not found"))
+	bookRepo.On("SaveBookRating", mock.Anything, db, mock.MatchedBy(func(rating *models.BookRating) bool {
+		return rating.ID == 0 && rating.BookID == 1 && rating.UserID == 7 && rating.Score == 4
+	})).Return(nil)
+
+	_, err := service.RateBook(context.Background(), 7, 1, &params.BookRatingRequest{Score: 4})
+
+	assert.Nil(t, err)
+	bookRepo.AssertExpectations(t)
+}
+
+func TestRateBook_BookNotFound(t *testing.T) {
+	bookRepo := new(repositories.MockBookRepository)
+	authorRepo := new(repositories.MockAuthorRepository)
+	db := new(gorm.DB)
+	service := NewBookService(bookRepo, authorRepo, nil, nil, db)
+
+	bookRepo.On("FindBookById", mock.Anything, db, 9).Return(nil, errors.New("book not found"))
+
+	result, err := service.RateBook(context.Background(), 7, 9, &params.BookRatingRequest{Score: 4})
+
+	assert.Nil(t, result)
+	assert.Equal(t, "NOT FOUND ERROR", err.Message)
+}
+
+func TestRateBook_ValidationError(t *testing.T) {
+	bookRepo := new(repositories.MockBookRepository)
+	authorRepo := new(repositories.MockAuthorRepository)
+	service := NewBookService(bookRepo, authorRepo, nil, nil, new(gorm.DB))
+
+	result, err := service.RateBook(context.Background(), 7, 1, &params.BookRatingRequest{Score: 6})
+
+	assert.Nil(t, result)
+	assert.Equal(t, []interface{}{"error Score on tag max"}, err.AdditionalInfo)
+}
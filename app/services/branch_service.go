@@ -0,0 +1,167 @@
+package services
+
+import (
+	"context"
+	"golang-backend-test/app/commons/response"
+	"golang-backend-test/app/models"
+	"golang-backend-test/app/params"
+	"golang-backend-test/app/repositories"
+
+	"github.com/go-playground/validator"
+	"gorm.io/gorm"
+)
+
+type BranchService interface {
+	FindDetailBranch(ctx context.Context, id int) (*params.BranchResponse, *response.CustomError)
+	FindAllBranches(ctx context.Context) ([]*params.BranchResponse, *response.CustomError)
+	CreateBranch(ctx context.Context, req *params.BranchRequest) *response.CustomError
+	UpdateBranch(ctx context.Context, id int, req *params.BranchRequest) (*params.BranchResponse, *response.CustomError)
+	DeleteBranch(ctx context.Context, id int) *response.CustomError
+	CreateShelfLocation(ctx context.Context, branchId int, req *params.ShelfLocationRequest) (*params.ShelfLocationResponse, *response.CustomError)
+}
+
+type BranchServiceImpl struct {
+	BranchRepository repositories.BranchRepository
+	DB               *gorm.DB
+}
+
+func NewBranchService(branchRepository repositories.BranchRepository, db *gorm.DB) BranchService {
+	return &BranchServiceImpl{
+		BranchRepository: branchRepository,
+		DB:               db,
+	}
+}
+
+func (service *BranchServiceImpl) FindDetailBranch(ctx context.Context, id int) (*params.BranchResponse, *response.CustomError) {
+	branch, err := service.BranchRepository.FindBranchById(ctx, service.DB, id)
+	if err != nil {
+		return nil, response.NotFoundError()
+	}
+
+	result := branchResponse(branch)
+	for i := range branch.Locations {
+		result.Locations = append(result.Locations, &params.ShelfLocationResponse{
+			ID:   branch.Locations[i].ID,
+			Name: branch.Locations[i].Name,
+		})
+	}
+	return result, nil
+}
+
+func (service *BranchServiceImpl) FindAllBranches(ctx context.Context) ([]*params.BranchResponse, *response.CustomError) {
+	branches, err := service.BranchRepository.GetListBranches(ctx, service.DB)
+	if err != nil {
+		return nil, response.BadRequestError()
+	}
+	var branchResponses []*params.BranchResponse
+	for _, branch := range branches {
+		branchResponses = append(branchResponses, branchResponse(branch))
+	}
+	return branchResponses, nil
+}
+
+func (service *BranchServiceImpl) CreateBranch(ctx context.Context, req *params.BranchRequest) *response.CustomError {
+	val := validator.New()
+	err := val.Struct(req)
+	if err != nil {
+		validationErrors := err.(validator.ValidationErrors)
+		var errors []interface{}
+		for _, fieldError := range validationErrors {
+			error := "error " + fieldError.Field() + " on tag " + fieldError.Tag()
+			errors = append(errors, error)
+		}
+		return response.BadRequestErrorWithAdditionalInfo(errors)
+	}
+
+	var branch = new(models.Branch)
+	branch.Code = req.Code
+	branch.Name = req.Name
+	branch.Address = req.Address
+	if err := service.BranchRepository.CreateBranch(ctx, service.DB, branch); err != nil {
+		return response.BadRequestError()
+	}
+
+	return nil
+}
+
+func (service *BranchServiceImpl) UpdateBranch(ctx context.Context, id int, req *params.BranchRequest) (*params.BranchResponse, *response.CustomError) {
+	val := validator.New()
+	err := val.Struct(req)
+	if err != nil {
+		validationErrors := err.(validator.ValidationErrors)
+		var errors []interface{}
+		for _, fieldError := range validationErrors {
+			error := "error " + fieldError.Field() + " on tag " + fieldError.Tag()
+			errors = append(errors, error)
+		}
+		return nil, response.BadRequestErrorWithAdditionalInfo(errors)
+	}
+
+	branch, err := service.BranchRepository.FindBranchById(ctx, service.DB, id)
+	if err != nil {
+		return nil, response.NotFoundError()
+	}
+	branch.Code = req.Code
+	branch.Name = req.Name
+	branch.Address = req.Address
+	if err := service.BranchRepository.UpdateBranch(ctx, service.DB, branch); err != nil {
+		return nil, response.BadRequestError()
+	}
+
+	return branchResponse(branch), nil
+}
+
+func (service *BranchServiceImpl) DeleteBranch(ctx context.Context, id int) *response.CustomError {
+	err := service.BranchRepository.DeleteBranch(ctx, service.DB, id)
+	if err != nil {
+		return response.NotFoundError()
+	}
+
+	return nil
+}
+
+func (service *BranchServiceImpl) CreateShelfLocation(ctx context.Context, branchId int, req *params.ShelfLocationRequest) (*params.ShelfLocationResponse, *response.CustomError) {
+	val := validator.New()
+	err := val.Struct(req)
+	if err != nil {
+		validationErrors := err.(validator.ValidationErrors)
+		var errors []interface{}
+		for _, fieldError := range validationErrors {
+			error := "error " + fieldError.Field() + " on tag " + fieldError.Tag()
+			errors = append(errors, error)
+		}
+		return nil, response.BadRequestErrorWithAdditionalInfo(errors)
+	}
+
+	branch, err := service.BranchRepository.FindBranchById(ctx, service.DB, branchId)
+	if err != nil {
+		return nil, response.NotFoundError()
+	}
+
+	var location = new(models.ShelfLocation)
+	location.BranchID = branch.ID
+	location.Name = req.Name
+	if err := service.BranchRepository.CreateShelfLocation(ctx, service.DB, location); err != nil {
+		return nil, response.BadRequestError()
+	}
+	location.Branch = *branch
+
+	return shelfLocationResponse(location), nil
+}
+
+func branchResponse(branch *models.Branch) *params.BranchResponse {
+	return &params.BranchResponse{
+		ID:      branch.ID,
+		Code:    branch.Code,
+		Name:    branch.Name,
+		Address: branch.Address,
+	}
+}
+
+func shelfLocationResponse(location *models.ShelfLocation) *params.ShelfLocationResponse {
+	return &params.ShelfLocationResponse{
+		ID:     location.ID,
+		Name:   location.Name,
+		Branch: branchResponse(&location.Branch),
+	}
+}
@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"golang-backend-test/app/models"
+	"golang-backend-test/app/params"
+	"golang-backend-test/app/repositories"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestFindDetailBranch_Success(t *testing.T) {
+	branchRepo := new(repositories.MockBranchRepository)
+	db := new(gorm.DB)
+
+	branch := &models.Branch{
+		ID:   1,
+		Code: "MAIN",
+		Name: "Main Library",
+		Locations: []models.ShelfLocation{
+			{ID: 1, BranchID: 1, Name: "Fiction A"},
+			{ID: 2, BranchID: 1, Name: "Reference"},
+		},
+	}
+
+	branchRepo.On("FindBranchById", mock.Anything, db, 1).Return(branch, nil)
+	service := NewBranchService(branchRepo, db)
+
+	result, err := service.FindDetailBranch(context.Background(), 1)
+
+	assert.Nil(t, err)
+	assert.NotNil(t, result)
+	assert.Equal(t, "MAIN", result.Code)
+	assert.Len(t, result.Locations, 2)
+	assert.Equal(t, "Reference", result.Locations[1].Name)
+
+	branchRepo.AssertExpectations(t)
+}
+
+func TestFindDetailBranch_BranchNotFound(t *testing.T) {
+	branchRepo := new(repositories.MockBranchRepository)
+	db := new(gorm.DB)
+
+	branchRepo.On("FindBranchById", mock.Anything, db, 1).Return(nil, errors.New("branch not found"))
+	service := NewBranchService(branchRepo, db)
+
+	result, err := service.FindDetailBranch(context.Background(), 1)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "NOT FOUND ERROR", err.Message)
+
+	branchRepo.AssertExpectations(t)
+}
+
+func TestFindAllBranches_Success(t *testing.T) {
+	branchRepo := new(repositories.MockBranchRepository)
+	db := new(gorm.DB)
+
+	branches := []*models.Branch{
+		{ID: 2, Code: "EAST", Name: "East Branch"},
+		{ID: 1, Code: "MAIN", Name: "Main Library"},
+	}
+
+	branchRepo.On("GetListBranches", mock.Anything, db).Return(branches, nil)
+	service := NewBranchService(branchRepo, db)
+
+	result, err := service.FindAllBranches(context.Background())
+
+	assert.Nil(t, err)
+	assert.Len(t, result, 2)
+	assert.Equal(t, "EAST", result[0].Code)
+
+	branchRepo.AssertExpectations(t)
+}
+
+func TestCreateBranch_Success(t *testing.T) {
+	branchRepo := new(repositories.MockBranchRepository)
+	db := new(gorm.DB)
+
+	req := &params.BranchRequest{Code: "MAIN", Name: "Main Library", Address: "1 Library Street"}
+
+	branchRepo.On("CreateBranch", mock.Anything, db, mock.AnythingOfType("*models.Branch")).Return(nil)
+	service := NewBranchService(branchRepo, db)
+
+	err := service.CreateBranch(context.Background(), req)
+
+	assert.Nil(t, err)
+
+	branchRepo.AssertExpectations(t)
+}
+
+func TestCreateBranch_ValidationError(t *testing.T) {
+	branchRepo := new(repositories.MockBranchRepository)
+	db := new(gorm.DB)
+
+	req := &params.BranchRequest{Name: "Main Library"}
+
+	service := NewBranchService(branchRepo, db)
+
+	err := service.CreateBranch(context.Background(), req)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "BAD REQUEST ERROR", err.Message)
+}
+
+func TestUpdateBranch_Success(t *testing.T) {
+	branchRepo := new(repositories.MockBranchRepository)
+	db := new(gorm.DB)
+
+	req := &params.BranchRequest{Code: "MAIN", Name: "Central Library"}
+
+	branchRepo.On("FindBranchById", mock.Anything, db, 1).Return(&models.Branch{ID: 1, Code: "MAIN", Name: "Main Library"}, nil)
+	branchRepo.On("UpdateBranch", mock.Anything, db, mock.AnythingOfType("*models.Branch")).Return(nil)
+	service := NewBranchService(branchRepo, db)
+
+	result, err := service.UpdateBranch(context.Background(), 1, req)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "Central Library", result.Name)
+
+	branchRepo.AssertExpectations(t)
+}
+
+func TestDeleteBranch_BranchNotFound(t *testing.T) {
+	branchRepo := new(repositories.MockBranchRepository)
+	db := new(gorm.DB)
+
+	branchRepo.On("DeleteBranch", mock.Anything, db, 1).Return(errors.New("branch not found"))
+	service := NewBranchService(branchRepo, db)
+
+	err := service.DeleteBranch(context.Background(), 1)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "NOT FOUND ERROR", err.Message)
+
+	branchRepo.AssertExpectations(t)
+}
+
+func TestCreateShelfLocation_Success(t *testing.T) {
+	branchRepo := new(repositories.MockBranchRepository)
+	db := new(gorm.DB)
+
+	branchRepo.On("FindBranchById", mock.Anything, db, 1).Return(&models.Branch{ID: 1, Code: "MAIN", Name: "Main Library"}, nil)
+	branchRepo.On("CreateShelfLocation", mock.Anything, db, mock.MatchedBy(func(location *models.ShelfLocation) bool {
+		return location.BranchID == 1 && location.Name == "Fiction A"
+	})).Return(nil)
+	service := NewBranchService(branchRepo, db)
+
+	result, err := service.CreateShelfLocation(context.Background(), 1, &params.ShelfLocationRequest{Name: "Fiction A"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "Fiction A", result.Name)
+	assert.Equal(t, "MAIN", result.Branch.Code)
+
+	branchRepo.AssertExpectations(t)
+}
+
+func TestCreateShelfLocation_BranchNotFound(t *testing.T) {
+	branchRepo := new(repositories.MockBranchRepository)
+	db := new(gorm.DB)
+
+	branchRepo.On("FindBranchById", mock.Anything, db, 1).Return(nil, errors.New("branch not found"))
+	service := NewBranchService(branchRepo, db)
+
+	result, err := service.CreateShelfLocation(context.Background(), 1, &params.ShelfLocationRequest{Name: "Fiction A"})
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "NOT FOUND ERROR", err.Message)
+
+	branchRepo.AssertExpectations(t)
+}
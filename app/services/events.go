@@ -0,0 +1,36 @@
+package services
+
+import (
+	"context"
+	"golang-backend-test/app/commons/response"
+	"golang-backend-test/app/params"
+	"log"
+)
+
+// The events services record in the outbox. Their data is the resource in
+// the form the API answers with, or a params.DeletedResponse for deletions.
+const (
+	EventBookCreated   = "book.created"
+	EventBookUpdated   = "book.updated"
+	EventBookDeleted   = "book.deleted"
+	EventAuthorCreated = "author.created"
+	EventAuthorUpdated = "author.updated"
+	EventAuthorDeleted = "author.deleted"
+	EventUserCreated   = "user.created"
+	EventLoanOverdue   = "loan.overdue"
+)
+
+// EventPublisher is a sink the outbox relays events to. An event can come
+// more than once, with the same id, when relaying it was cut short.
+type EventPublisher interface {
+	Publish(ctx context.Context, message *params.EventMessage) *response.CustomError
+}
+
+// LogPublisher writes every event to the log.
+type LogPublisher struct {
+}
+
+func (publisher LogPublisher) Publish(ctx context.Context, message *params.EventMessage) *response.CustomError {
+	log.Printf("event %d %s %s/%d %s", message.ID, message.Event, message.Aggregate, message.AggregateID, message.Data)
+	return nil
+}
@@ -0,0 +1,31 @@
+package services
+
+import (
+	"context"
+	"golang-backend-test/app/commons/response"
+	"golang-backend-test/app/params"
+
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockEventPublisher struct {
+	mock.Mock
+}
+
+func (mock *MockEventPublisher) Publish(ctx context.Context, message *params.EventMessage) *response.CustomError {
+	args := mock.Called(ctx, message)
+	if custErr, ok := args.Get(0).(*response.CustomError); ok {
+		return custErr
+	}
+	return nil
+}
+
+type MockEventRecorder struct {
+	mock.Mock
+}
+
+func (mock *MockEventRecorder) Record(ctx context.Context, tx *gorm.DB, event string, aggregateId uint, data interface{}) error {
+	args := mock.Called(ctx, tx, event, aggregateId, data)
+	return args.Error(0)
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"fmt"
+	"golang-backend-test/app/commons/response"
+	"golang-backend-test/app/params"
+	"sort"
+	"strings"
+)
+
+// fieldsetSpec lists what a response can be asked for: its fields, mapped to
+// their columns, and the relations it can be expanded with.
+type fieldsetSpec struct {
+	fields    map[string]string
+	relations []string
+}
+
+// read checks the fields and expansions of req. The fieldset is nil when
+// req asks for no fields in particular; expand falls back to defaults when
+// req leaves it out.
+func (spec fieldsetSpec) read(req *params.FieldsetRequest, defaults ...string) (params.Fieldset, map[string]bool, *response.CustomError) {
+	if req == nil {
+		req = new(params.FieldsetRequest)
+	}
+
+	expand := map[string]bool{}
+	names := defaults
+	if req.Expand != nil {
+		names = splitList(*req.Expand)
+	}
+	for _, name := range names {
+		if !contains(spec.relations, name) {
+			return nil, nil, response.BadRequestErrorWithAdditionalInfo(fmt.Sprintf("expand must be any of %s", strings.Join(spec.relations, ", ")))
+		}
+		expand[name] = true
+	}
+
+	if req.Fields == "" {
+		return nil, expand, nil
+	}
+	fields := params.Fieldset{}
+	for _, name := range splitList(req.Fields) {
+		if _, ok := spec.fields[name]; !ok {
+			return nil, nil, response.BadRequestErrorWithAdditionalInfo(fmt.Sprintf("unknown field %s", name))
+		}
+		fields[name] = true
+	}
+	for name := range expand {
+		fields[name] = true
+	}
+	return fields, expand, nil
+}
+
+// columns returns the columns behind fields, nil for every column.
+func (spec fieldsetSpec) columns(fields params.Fieldset) []string {
+	if fields == nil {
+		return nil
+	}
+	var columns []string
+	for name, column := range spec.fields {
+		if fields[name] && column != "id" {
+			columns = append(columns, column)
+		}
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+func splitList(list string) []string {
+	var names []string
+	for _, name := range strings.Split(list, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func contains(names []string, name string) bool {
+	for _, candidate := range names {
+		if candidate == name {
+			return true
+		}
+	}
+	return false
+}
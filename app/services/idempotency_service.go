@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"golang-backend-test/app/commons/response"
+	"golang-backend-test/app/models"
+	"golang-backend-test/app/repositories"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type IdempotencyService interface {
+	Reserve(ctx context.Context, userId int, key, method, path string, body []byte) (*models.IdempotencyKey, *response.CustomError)
+	Complete(ctx context.Context, idempotencyKey *models.IdempotencyKey, statusCode int, contentType string, body []byte) *response.CustomError
+	Release(ctx context.Context, idempotencyKey *models.IdempotencyKey) *response.CustomError
+}
+
+type IdempotencyServiceImpl struct {
+	IdempotencyRepository repositories.IdempotencyRepository
+	TTL                   time.Duration
+	DB                    *gorm.DB
+}
+
+// NewIdempotencyService builds the idempotency service. Keys are forgotten
+// ttl after their first use.
+func NewIdempotencyService(idempotencyRepository repositories.IdempotencyRepository, ttl time.Duration, db *gorm.DB) IdempotencyService {
+	return &IdempotencyServiceImpl{
+		IdempotencyRepository: idempotencyRepository,
+		TTL:                   ttl,
+		DB:                    db,
+	}
+}
+
+const maxIdempotencyKeyLength = 255
+
+// Reserve claims key for a request. When the key was already used for the
+// same request the stored key is returned, and a non-zero StatusCode on it
+// means its response should be replayed.
+func (service *IdempotencyServiceImpl) Reserve(ctx context.Context, userId int, key, method, path string, body []byte) (*models.IdempotencyKey, *response.CustomError) {
+	if key == "" || len(key) > maxIdempotencyKeyLength {
+		return nil, response.BadRequestErrorWithAdditionalInfo("Idempotency-Key must be between 1 and 255 characters")
+	}
+	fingerprint := requestFingerprint(method, path, body)
+	now := time.Now()
+
+	existing, err := service.IdempotencyRepository.FindIdempotencyKey(ctx, service.DB, userId, key)
+	if err == nil && now.Before(existing.ExpiresAt) {
+		if existing.Fingerprint != fingerprint {
+			return nil, response.UnprocessableEntityErrorWithAdditionalInfo("idempotency key was already used for a different request")
+		}
+		if existing.StatusCode == 0 {
+			return nil, response.ConflictErrorWithAdditionalInfo("a request with this idempotency key is still in progress")
+		}
+		return existing, nil
+	}
+
+	if err := service.IdempotencyRepository.DeleteExpiredIdempotencyKeys(ctx, service.DB, now); err != nil {
+		return nil, response.RepositoryError()
+	}
+	var idempotencyKey = new(models.IdempotencyKey)
+	idempotencyKey.UserID = uint(userId)
+	idempotencyKey.Key = key
+	idempotencyKey.Method = method
+	idempotencyKey.Path = path
+	idempotencyKey.Fingerprint = fingerprint
+	idempotencyKey.CreatedAt = now
+	idempotencyKey.ExpiresAt = now.Add(service.TTL)
+	if err := service.IdempotencyRepository.CreateIdempotencyKey(ctx, service.DB, idempotencyKey); err != nil {
+		// another request claimed the key between the lookup and the insert
+		return nil, response.ConflictErrorWithAdditionalInfo("a request with this idempotency key is still in progress")
+	}
+	return idempotencyKey, nil
+}
+
+func (service *IdempotencyServiceImpl) Complete(ctx context.Context, idempotencyKey *models.IdempotencyKey, statusCode int, contentType string, body []byte) *response.CustomError {
+	idempotencyKey.StatusCode = statusCode
+	idempotencyKey.ContentType = contentType
+	idempotencyKey.Body = body
+	if err := service.IdempotencyRepository.UpdateIdempotencyKey(ctx, service.DB, idempotencyKey); err != nil {
+		return response.RepositoryError()
+	}
+	return nil
+}
+
+// Release forgets a reserved key, so the request can be retried with it.
+func (service *IdempotencyServiceImpl) Release(ctx context.Context, idempotencyKey *models.IdempotencyKey) *response.CustomError {
+	if err := service.IdempotencyRepository.DeleteIdempotencyKey(ctx, service.DB, idempotencyKey.ID); err != nil {
+		return response.RepositoryError()
+	}
+	return nil
+}
+
+func requestFingerprint(method, path string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method + " " + path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"golang-backend-test/app/models"
+	"golang-backend-test/app/repositories"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestReserve_NewKey(t *testing.T) {
+	idempotencyRepo := new(repositories.MockIdempotencyRepository)
+	db := new(gorm.DB)
+	service := NewIdempotencyService(idempotencyRepo, time.Hour, db)
+
+	idempotencyRepo.On("FindIdempotencyKey", mock.Anything, db, 7, "key-1").Return(nil, errors.New("idempotency key not found"))
+	idempotencyRepo.On("DeleteExpiredIdempotencyKeys", mock.Anything, db, mock.Anything).Return(nil)
+	idempotencyRepo.On("CreateIdempotencyKey", mock.Anything, db, mock.MatchedBy(func(idempotencyKey *models.IdempotencyKey) bool {
+		return idempotencyKey.UserID == 7 && idempotencyKey.Key == "key-1" && idempotencyKey.StatusCode == 0 &&
+			idempotencyKey.Fingerprint == requestFingerprint("POST", "/books/", []byte(`{"title":"A"}`))
+	})).Return(nil)
+
+	result, err := service.Reserve(context.Background(), 7, "key-1", "POST", "/books/", []byte(`{"title":"A"}`))
+
+	assert.Nil(t, err)
+	assert.Equal(t, 0, result.StatusCode)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), result.ExpiresAt, time.Minute)
+	idempotencyRepo.AssertExpectations(t)
+}
+
+func TestReserve_Replay(t *testing.T) {
+	idempotencyRepo := new(repositories.MockIdempotencyRepository)
+	db := new(gorm.DB)
+	service := NewIdempotencyService(idempotencyRepo, time.Hour, db)
+	stored := &models.IdempotencyKey{
+		ID:          1,
+		Key:         "key-1",
+		Fingerprint: requestFingerprint("POST", "/books/", []byte(`{"title":"A"}`)),
+		StatusCode:  201,
+		Body:        []byte(`{"status":true}`),
+		ExpiresAt:   time.Now().Add(time.Minute),
+	}
+
+	idempotencyRepo.On("FindIdempotencyKey", mock.Anything, db, 7, "key-1").Return(stored, nil)
+
+	result, err := service.Reserve(context.Background(), 7, "key-1", "POST", "/books/", []byte(`{"title":"A"}`))
+
+	assert.Nil(t, err)
+	assert.Equal(t, 201, result.StatusCode)
+	assert.Equal(t, `{"status":true}`, string(result.Body))
+	idempotencyRepo.AssertNotCalled(t, "CreateIdempotencyKey", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestReserve_DifferentRequest(t *testing.T) {
+	idempotencyRepo := new(repositories.MockIdempotencyRepository)
+	db := new(gorm.DB)
+	service := NewIdempotencyService(idempotencyRepo, time.Hour, db)
+
+	idempotencyRepo.On("FindIdempotencyKey", mock.Anything, db, 7, "key-1").Return(&models.IdempotencyKey{
+		ID:          1,
+		Fingerprint: requestFingerprint("POST", "/books/", []byte(`{"title":"A"}`)),
+		StatusCode:  201,
+		ExpiresAt:   time.Now().Add(time.Minute),
+	}, nil)
+
+	result, err := service.Reserve(context.Background(), 7, "key-1", "POST", "/books/", []byte(`{"title":"B"}`))
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, 422, err.StatusCode)
+}
+
+func TestReserve_InProgress(t *testing.T) {
+	idempotencyRepo := new(repositories.MockIdempotencyRepository)
+	db := new(gorm.DB)
+	service := NewIdempotencyService(idempotencyRepo, time.Hour, db)
+
+	idempotencyRepo.On("FindIdempotencyKey", mock.Anything, db, 7, "key-1").Return(&models.IdempotencyKey{
+		ID:          1,
+		Fingerprint: requestFingerprint("POST", "/books/", nil),
+		ExpiresAt:   time.Now().Add(time.Minute),
+	}, nil)
+
+	result, err := service.Reserve(context.Background(), 7, "key-1", "POST", "/books/", nil)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, 409, err.StatusCode)
+}
+
+func TestReserve_ExpiredKeyIsReused(t *testing.T) {
+	idempotencyRepo := new(repositories.MockIdempotencyRepository)
+	db := new(gorm.DB)
+	service := NewIdempotencyService(idempotencyRepo, time.Hour, db)
+
+	idempotencyRepo.On("FindIdempotencyKey", mock.Anything, db, 7, "key-1").Return(&models.IdempotencyKey{
+		ID:          1,
+		Fingerprint: requestFingerprint("POST", "/books/", []byte(`{"title":"A"}`)),
+		StatusCode:  201,
+		ExpiresAt:   time.Now().Add(-time.Minute),
+	}, nil)
+	idempotencyRepo.On("DeleteExpiredIdempotencyKeys", mock.Anything, db, mock.Anything).Return(nil)
+	idempotencyRepo.On("CreateIdempotencyKey", mock.Anything, db, mock.Anything).Return(nil)
+
+	result, err := service.Reserve(context.Background(), 7, "key-1", "POST", "/books/", []byte(`{"title":"B"}`))
+
+	assert.Nil(t, err)
+	assert.Equal(t, 0, result.StatusCode)
+	idempotencyRepo.AssertExpectations(t)
+}
+
+func TestReserve_KeyTooLong(t *testing.T) {
+	idempotencyRepo := new(repositories.MockIdempotencyRepository)
+	db := new(gorm.DB)
+	service := NewIdempotencyService(idempotencyRepo, time.Hour, db)
+
+	result, err := service.Reserve(context.Background(), 7, strings.Repeat("k", 256), "POST", "/books/", nil)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "BAD REQUEST ERROR", err.Message)
+	idempotencyRepo.AssertNotCalled(t, "FindIdempotencyKey", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,442 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"golang-backend-test/app/commons/response"
+	"golang-backend-test/app/models"
+	"golang-backend-test/app/params"
+	"golang-backend-test/app/repositories"
+	"golang-backend-test/pkg/tenant"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-playground/validator"
+	"gorm.io/gorm"
+)
+
+// JobHandler runs a job. A handler that returns an error has the job tried
+// again later, unless the error is a PermanentJobError.
+type JobHandler func(ctx context.Context, job *models.Job) error
+
+type permanentJobError struct {
+	err error
+}
+
+func (e permanentJobError) Error() string {
+	return e.err.Error()
+}
+
+// PermanentJobError marks err as one that trying again does not help, so
+// that the job fails at once.
+func PermanentJobError(err error) error {
+	return permanentJobError{err: err}
+}
+
+// TypedJobHandler adapts handle to a JobHandler that decodes the payload of
+// the job into a T first. A payload that does not decode fails the job.
+func TypedJobHandler[T any](handle func(ctx context.Context, payload *T) error) JobHandler {
+	return func(ctx context.Context, job *models.Job) error {
+		payload := new(T)
+		if err := json.Unmarshal(job.Payload, payload); err != nil {
+			return PermanentJobError(err)
+		}
+		return handle(ctx, payload)
+	}
+}
+
+// JobService queues jobs in the database and runs them in the background.
+// Every job type is handled in one queue, and the jobs of a queue run as
+// many at a time as its concurrency allows, oldest due first.
+type JobService interface {
+	Handle(jobType, queue string, handler JobHandler)
+	Enqueue(ctx context.Context, req *params.JobRequest) (*params.JobResponse, *response.CustomError)
+	FindAllJobs(ctx context.Context, req *params.JobListRequest) ([]*params.JobResponse, *response.CustomError)
+	FindDetailJob(ctx context.Context, id int) (*params.JobResponse, *response.CustomError)
+	RetryJob(ctx context.Context, id int) (*params.JobResponse, *response.CustomError)
+	CancelJob(ctx context.Context, id int) (*params.JobResponse, *response.CustomError)
+	Run(ctx context.Context)
+}
+
+type jobHandling struct {
+	queue   string
+	handler JobHandler
+}
+
+type JobServiceImpl struct {
+	JobRepository          repositories.JobRepository
+	OrganizationRepository repositories.OrganizationRepository
+	// Concurrency is how many jobs of each queue run at once; the queues it
+	// leaves out run one job at a time
+	Concurrency map[string]int
+	// PollInterval is how often idle workers look for jobs that came due
+	PollInterval time.Duration
+	// Backoff is how long a failed job waits, twice as long after each
+	// further failure up to maxJobBackoff
+	Backoff time.Duration
+	DB      *gorm.DB
+
+	mutex   sync.Mutex
+	types   map[string]jobHandling
+	wake    map[string]chan struct{}
+	running map[uint]context.CancelFunc
+	// turn rotates the organization workers look at first, so that one
+	// with many jobs does not hold up the others
+	turn uint64
+}
+
+const (
+	defaultJobAttempts     = 5
+	defaultJobPollInterval = time.Second
+	defaultJobBackoff      = 10 * time.Second
+	maxJobBackoff          = time.Hour
+	defaultJobListLimit    = 50
+)
+
+// jobTransitions lists, for each target status, the statuses an admin may
+// move a job from.
+var jobTransitions = map[string][]string{
+	models.JobPending:  {models.JobFailed, models.JobCanceled},
+	models.JobCanceled: {models.JobPending, models.JobRunning},
+}
+
+func NewJobService(jobRepository repositories.JobRepository, organizationRepository repositories.OrganizationRepository, concurrency map[string]int, db *gorm.DB) JobService {
+	return &JobServiceImpl{
+		JobRepository:          jobRepository,
+		OrganizationRepository: organizationRepository,
+		Concurrency:            concurrency,
+		PollInterval:           defaultJobPollInterval,
+		Backoff:                defaultJobBackoff,
+		DB:                     db,
+		types:                  map[string]jobHandling{},
+		wake:                   map[string]chan struct{}{},
+		running:                map[uint]context.CancelFunc{},
+	}
+}
+
+// Handle runs the jobs of jobType with handler in queue. The handlers are
+// all given before Run.
+func (service *JobServiceImpl) Handle(jobType, queue string, handler JobHandler) {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+	service.types[jobType] = jobHandling{queue: queue, handler: handler}
+	if service.wake[queue] == nil {
+		service.wake[queue] = make(chan struct{}, 1)
+	}
+}
+
+func (service *JobServiceImpl) Enqueue(ctx context.Context, req *params.JobRequest) (*params.JobResponse, *response.CustomError) {
+	val := validator.New()
+	err := val.Struct(req)
+	if err != nil {
+		validationErrors := err.(validator.ValidationErrors)
+		var errors []interface{}
+		for _, fieldError := range validationErrors {
+			error := "error " + fieldError.Field() + " on tag " + fieldError.Tag()
+			errors = append(errors, error)
+		}
+		return nil, response.BadRequestErrorWithAdditionalInfo(errors)
+	}
+	service.mutex.Lock()
+	handled, ok := service.types[req.Type]
+	service.mutex.Unlock()
+	if !ok {
+		return nil, response.BadRequestErrorWithAdditionalInfo(fmt.Sprintf("no handler for job type %s", req.Type))
+	}
+	payload, err := json.Marshal(req.Payload)
+	if err != nil {
+		return nil, response.BadRequestErrorWithAdditionalInfo(err.Error())
+	}
+
+	now := time.Now()
+	job := &models.Job{
+		Queue:       handled.queue,
+		Type:        req.Type,
+		Payload:     payload,
+		Status:      models.JobPending,
+		MaxAttempts: req.MaxAttempts,
+		RunAt:       now,
+		CreatedAt:   now,
+	}
+	if job.MaxAttempts == 0 {
+		job.MaxAttempts = defaultJobAttempts
+	}
+	if req.RunAt != nil && req.RunAt.After(now) {
+		job.RunAt = *req.RunAt
+	}
+	if err := service.JobRepository.CreateJob(ctx, service.DB, job); err != nil {
+		return nil, response.RepositoryError()
+	}
+	if !job.RunAt.After(now) {
+		service.notify(job.Queue)
+	}
+	return jobResponse(job), nil
+}
+
+func (service *JobServiceImpl) FindAllJobs(ctx context.Context, req *params.JobListRequest) ([]*params.JobResponse, *response.CustomError) {
+	val := validator.New()
+	err := val.Struct(req)
+	if err != nil {
+		validationErrors := err.(validator.ValidationErrors)
+		var errors []interface{}
+		for _, fieldError := range validationErrors {
+			error := "error " + fieldError.Field() + " on tag " + fieldError.Tag()
+			errors = append(errors, error)
+		}
+		return nil, response.BadRequestErrorWithAdditionalInfo(errors)
+	}
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultJobListLimit
+	}
+
+	jobs, err := service.JobRepository.GetListJobs(ctx, service.DB, req.Status, req.Queue, limit)
+	if err != nil {
+		return nil, response.RepositoryError()
+	}
+	result := []*params.JobResponse{}
+	for _, job := range jobs {
+		result = append(result, jobResponse(job))
+	}
+	return result, nil
+}
+
+func (service *JobServiceImpl) FindDetailJob(ctx context.Context, id int) (*params.JobResponse, *response.CustomError) {
+	job, err := service.JobRepository.FindJobById(ctx, service.DB, id)
+	if err != nil {
+		return nil, response.NotFoundError()
+	}
+	return jobResponse(job), nil
+}
+
+// RetryJob makes a failed or canceled job pending again with all of its
+// attempts.
+func (service *JobServiceImpl) RetryJob(ctx context.Context, id int) (*params.JobResponse, *response.CustomError) {
+	job, custErr := service.findJobForTransition(ctx, id, models.JobPending)
+	if custErr != nil {
+		return nil, custErr
+	}
+
+	from := job.Status
+	job.Status = models.JobPending
+	job.Attempts = 0
+	job.RunAt = time.Now()
+	job.LastError = ""
+	job.StartedAt = nil
+	job.FinishedAt = nil
+	if err := service.JobRepository.UpdateJob(ctx, service.DB, job, from); err != nil {
+		return nil, response.ConflictErrorWithAdditionalInfo("the job changed meanwhile")
+	}
+	service.notify(job.Queue)
+	return jobResponse(job), nil
+}
+
+// CancelJob cancels a pending job, or a running one, whose handler then sees
+// its context canceled.
+func (service *JobServiceImpl) CancelJob(ctx context.Context, id int) (*params.JobResponse, *response.CustomError) {
+	job, custErr := service.findJobForTransition(ctx, id, models.JobCanceled)
+	if custErr != nil {
+		return nil, custErr
+	}
+
+	from := job.Status
+	now := time.Now()
+	job.Status = models.JobCanceled
+	job.FinishedAt = &now
+	if err := service.JobRepository.UpdateJob(ctx, service.DB, job, from); err != nil {
+		return nil, response.ConflictErrorWithAdditionalInfo("the job changed meanwhile")
+	}
+	service.mutex.Lock()
+	if cancel, ok := service.running[job.ID]; ok {
+		cancel()
+	}
+	service.mutex.Unlock()
+	return jobResponse(job), nil
+}
+
+func (service *JobServiceImpl) findJobForTransition(ctx context.Context, id int, status string) (*models.Job, *response.CustomError) {
+	job, err := service.JobRepository.FindJobById(ctx, service.DB, id)
+	if err != nil {
+		return nil, response.NotFoundError()
+	}
+	for _, from := range jobTransitions[status] {
+		if job.Status == from {
+			return job, nil
+		}
+	}
+	return nil, response.BadRequestErrorWithAdditionalInfo(fmt.Sprintf("cannot move a %s job to %s", job.Status, status))
+}
+
+// notify wakes an idle worker of queue.
+func (service *JobServiceImpl) notify(queue string) {
+	service.mutex.Lock()
+	wake := service.wake[queue]
+	service.mutex.Unlock()
+	select {
+	case wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run runs the workers of every queue that has handlers until ctx is done,
+// and then waits for the jobs that are running to finish. Jobs a previous
+// run left running are pending again first.
+func (service *JobServiceImpl) Run(ctx context.Context) {
+	organizations, err := service.OrganizationRepository.GetListOrganizations(ctx, service.DB)
+	if err != nil {
+		log.Printf("requeueing running jobs: %v", err)
+	}
+	for _, organization := range organizations {
+		if err := service.JobRepository.RequeueRunningJobs(tenant.WithID(ctx, organization.ID), service.DB); err != nil {
+			log.Printf("requeueing running jobs: %v", err)
+		}
+	}
+
+	var workers sync.WaitGroup
+	service.mutex.Lock()
+	for queue, wake := range service.wake {
+		concurrency := service.Concurrency[queue]
+		if concurrency < 1 {
+			concurrency = 1
+		}
+		for i := 0; i < concurrency; i++ {
+			workers.Add(1)
+			go func(queue string, wake chan struct{}) {
+				defer workers.Done()
+				service.work(ctx, queue, wake)
+			}(queue, wake)
+		}
+	}
+	service.mutex.Unlock()
+	workers.Wait()
+}
+
+func (service *JobServiceImpl) work(ctx context.Context, queue string, wake chan struct{}) {
+	for ctx.Err() == nil {
+		job, err := service.claim(ctx, queue)
+		if err != nil {
+			log.Printf("claiming a job of %s: %v", queue, err)
+		}
+		if job != nil {
+			service.execute(job)
+			continue
+		}
+		select {
+		case <-ctx.Done():
+		case <-wake:
+		case <-time.After(service.PollInterval):
+		}
+	}
+}
+
+// claim takes the next due job of queue from the first organization that
+// has one.
+func (service *JobServiceImpl) claim(ctx context.Context, queue string) (*models.Job, error) {
+	organizations, err := service.OrganizationRepository.GetListOrganizations(ctx, service.DB)
+	if err != nil || len(organizations) == 0 {
+		return nil, err
+	}
+	first := int(atomic.AddUint64(&service.turn, 1) % uint64(len(organizations)))
+	for i := range organizations {
+		organization := organizations[(first+i)%len(organizations)]
+		job, err := service.JobRepository.ClaimJob(tenant.WithID(ctx, organization.ID), service.DB, queue, time.Now())
+		if err != nil || job != nil {
+			return job, err
+		}
+	}
+	return nil, nil
+}
+
+// execute runs a claimed job and records how it went. The job runs to the
+// end even when the workers are stopping; only canceling it stops it.
+func (service *JobServiceImpl) execute(job *models.Job) {
+	ctx, cancel := context.WithCancel(tenant.WithID(context.Background(), job.TenantID))
+	defer cancel()
+	service.mutex.Lock()
+	service.running[job.ID] = cancel
+	handled, ok := service.types[job.Type]
+	service.mutex.Unlock()
+	defer func() {
+		service.mutex.Lock()
+		delete(service.running, job.ID)
+		service.mutex.Unlock()
+	}()
+
+	var err error
+	if ok {
+		err = service.call(ctx, handled.handler, job)
+	} else {
+		err = PermanentJobError(fmt.Errorf("no handler for job type %s", job.Type))
+	}
+	// a job canceled while it ran stays canceled
+	if ctx.Err() != nil {
+		return
+	}
+
+	now := time.Now()
+	var permanent permanentJobError
+	switch {
+	case err == nil:
+		job.Status = models.JobSucceeded
+		job.LastError = ""
+		job.FinishedAt = &now
+	case errors.As(err, &permanent) || job.Attempts >= job.MaxAttempts:
+		job.Status = models.JobFailed
+		job.LastError = truncate(err.Error(), 1024)
+		job.FinishedAt = &now
+	default:
+		job.Status = models.JobPending
+		job.LastError = truncate(err.Error(), 1024)
+		job.RunAt = now.Add(service.backoff(job.Attempts))
+	}
+	if err := service.JobRepository.UpdateJob(ctx, service.DB, job, models.JobRunning); err != nil {
+		log.Printf("finishing job %d: %v", job.ID, err)
+	}
+}
+
+// call runs handler, turning a panic into an error.
+func (service *JobServiceImpl) call(ctx context.Context, handler JobHandler, job *models.Job) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("panic: %v", recovered)
+		}
+	}()
+	return handler(ctx, job)
+}
+
+func (service *JobServiceImpl) backoff(attempts int) time.Duration {
+	delay := service.Backoff
+	for i := 1; i < attempts && delay < maxJobBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxJobBackoff {
+		delay = maxJobBackoff
+	}
+	return delay
+}
+
+func jobResponse(job *models.Job) *params.JobResponse {
+	result := &params.JobResponse{
+		ID:          job.ID,
+		Queue:       job.Queue,
+		Type:        job.Type,
+		Payload:     job.Payload,
+		Status:      job.Status,
+		Attempts:    job.Attempts,
+		MaxAttempts: job.MaxAttempts,
+		RunAt:       job.RunAt.UTC().Format(time.RFC3339),
+		LastError:   job.LastError,
+		CreatedAt:   job.CreatedAt.UTC().Format(time.RFC3339),
+	}
+	if job.StartedAt != nil {
+		result.StartedAt = job.StartedAt.UTC().Format(time.RFC3339)
+	}
+	if job.FinishedAt != nil {
+		result.FinishedAt = job.FinishedAt.UTC().Format(time.RFC3339)
+	}
+	return result
+}
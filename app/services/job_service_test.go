@@ -0,0 +1,340 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"golang-backend-test/app/models"
+	"golang-backend-test/app/params"
+	"golang-backend-test/app/repositories"
+	"golang-backend-test/pkg/tenant"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type testEmail struct {
+	To string `json:"to"`
+}
+
+func newTestJobService() (*JobServiceImpl, *repositories.MockJobRepository, *gorm.DB) {
+	jobRepo := new(repositories.MockJobRepository)
+	organizationRepo := new(repositories.MockOrganizationRepository)
+	db := new(gorm.DB)
+	service := NewJobService(jobRepo, organizationRepo, nil, db).(*JobServiceImpl)
+	return service, jobRepo, db
+}
+
+func TestEnqueue_QueuesJob(t *testing.T) {
+	service, jobRepo, db := newTestJobService()
+	service.Handle("emails.send", "emails", TypedJobHandler(func(ctx context.Context, email *testEmail) error { return nil }))
+
+	jobRepo.On("CreateJob", mock.Anything, db, mock.MatchedBy(func(job *models.Job) bool {
+		return job.Queue == "emails" && job.Type == "emails.send" && string(job.Payload) == `{"to":"reader@example.com"}` &&
+			job.Status == models.JobPending && job.MaxAttempts == defaultJobAttempts
+	})).Return(nil)
+
+	result, err := service.Enqueue(context.Background(), &params.JobRequest{Type: "emails.send", Payload: &testEmail{To: "reader@example.com"}})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "emails", result.Queue)
+	assert.Equal(t, models.JobPending, result.Status)
+	jobRepo.AssertExpectations(t)
+}
+
+func TestEnqueue_ScheduledForLater(t *testing.T) {
+	service, jobRepo, db := newTestJobService()
+	service.Handle("emails.send", "emails", TypedJobHandler(func(ctx context.Context, email *testEmail) error { return nil }))
+	runAt := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	jobRepo.On("CreateJob", mock.Anything, db, mock.MatchedBy(func(job *models.Job) bool {
+		return job.RunAt.Equal(runAt) && job.MaxAttempts == 2
+	})).Return(nil)
+
+	_, err := service.Enqueue(context.Background(), &params.JobRequest{Type: "emails.send", RunAt: &runAt, MaxAttempts: 2})
+
+	assert.Nil(t, err)
+	jobRepo.AssertExpectations(t)
+}
+
+func TestEnqueue_UnknownType(t *testing.T) {
+	service, jobRepo, _ := newTestJobService()
+
+	_, err := service.Enqueue(context.Background(), &params.JobRequest{Type: "thumbnails.generate"})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, 400, err.StatusCode)
+	jobRepo.AssertNotCalled(t, "CreateJob", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestEnqueue_ValidationError(t *testing.T) {
+	service, jobRepo, _ := newTestJobService()
+
+	_, err := service.Enqueue(context.Background(), &params.JobRequest{Type: "emails.send", MaxAttempts: 500})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, []interface{}{"error MaxAttempts on tag max"}, err.AdditionalInfo)
+	jobRepo.AssertNotCalled(t, "CreateJob", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestFindAllJobs_ValidationError(t *testing.T) {
+	service, jobRepo, _ := newTestJobService()
+
+	_, err := service.FindAllJobs(context.Background(), &params.JobListRequest{Status: "done"})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, []interface{}{"error Status on tag oneof"}, err.AdditionalInfo)
+	jobRepo.AssertNotCalled(t, "GetListJobs", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestExecute_Succeeds(t *testing.T) {
+	service, jobRepo, db := newTestJobService()
+	var sent string
+	service.Handle("emails.send", "emails", TypedJobHandler(func(ctx context.Context, email *testEmail) error {
+		sent = email.To
+		return nil
+	}))
+	job := &models.Job{ID: 1, TenantID: 1, Type: "emails.send", Payload: []byte(`{"to":"reader@example.com"}`), Status: models.JobRunning, Attempts: 1, MaxAttempts: 5}
+
+	jobRepo.On("UpdateJob", mock.Anything, db, job, models.JobRunning).Return(nil)
+
+	service.execute(job)
+
+	assert.Equal(t, "reader@example.com", sent)
+	assert.Equal(t, models.JobSucceeded, job.Status)
+	assert.NotNil(t, job.FinishedAt)
+	jobRepo.AssertExpectations(t)
+}
+
+func TestExecute_RetriesWithBackoff(t *testing.T) {
+	service, jobRepo, db := newTestJobService()
+	service.Handle("emails.send", "emails", func(ctx context.Context, job *models.Job) error {
+		return errors.New("smtp unavailable")
+	})
+	job := &models.Job{ID: 1, TenantID: 1, Type: "emails.send", Status: models.JobRunning, Attempts: 2, MaxAttempts: 5}
+
+	jobRepo.On("UpdateJob", mock.Anything, db, job, models.JobRunning).Return(nil)
+
+	before := time.Now()
+	service.execute(job)
+
+	assert.Equal(t, models.JobPending, job.Status)
+	assert.Equal(t, "smtp unavailable", job.LastError)
+	assert.False(t, job.RunAt.Before(before.Add(2*service.Backoff)))
+	assert.Nil(t, job.FinishedAt)
+}
+
+func TestExecute_FailsAfterLastAttempt(t *testing.T) {
+	service, jobRepo, db := newTestJobService()
+	service.Handle("emails.send", "emails", func(ctx context.Context, job *models.Job) error {
+		return errors.New("smtp unavailable")
+	})
+	job := &models.Job{ID: 1, TenantID: 1, Type: "emails.send", Status: models.JobRunning, Attempts: 5, MaxAttempts: 5}
+
+	jobRepo.On("UpdateJob", mock.Anything, db, job, models.JobRunning).Return(nil)
+
+	service.execute(job)
+
+	assert.Equal(t, models.JobFailed, job.Status)
+	assert.NotNil(t, job.FinishedAt)
+}
+
+func TestExecute_PermanentFailures(t *testing.T) {
+	service, jobRepo, db := newTestJobService()
+	service.Handle("emails.send", "emails", TypedJobHandler(func(ctx context.Context, email *testEmail) error { return nil }))
+	service.Handle("reports.build", "reports", func(ctx context.Context, job *models.Job) error {
+		panic("nil report")
+	})
+	jobRepo.On("UpdateJob", mock.Anything, db, mock.AnythingOfType("*models.Job"), models.JobRunning).Return(nil)
+
+	for _, job := range []*models.Job{
+		{ID: 1, TenantID: 1, Type: "emails.send", Payload: []byte(`"not an email"`)},
+		{ID: 2, TenantID: 1, Type: "thumbnails.generate"},
+	} {
+		job.Status, job.Attempts, job.MaxAttempts = models.JobRunning, 1, 5
+		service.execute(job)
+		assert.Equal(t, models.JobFailed, job.Status, job.Type)
+	}
+
+	job := &models.Job{ID: 3, TenantID: 1, Type: "reports.build", Status: models.JobRunning, Attempts: 1, MaxAttempts: 5}
+	service.execute(job)
+	assert.Equal(t, models.JobPending, job.Status)
+	assert.Equal(t, "panic: nil report", job.LastError)
+}
+
+func TestRetryJob_Success(t *testing.T) {
+	service, jobRepo, db := newTestJobService()
+	finishedAt := time.Now()
+	job := &models.Job{ID: 1, Queue: "emails", Status: models.JobFailed, Attempts: 5, MaxAttempts: 5, LastError: "smtp unavailable", FinishedAt: &finishedAt}
+
+	jobRepo.On("FindJobById", mock.Anything, db, 1).Return(job, nil)
+	jobRepo.On("UpdateJob", mock.Anything, db, job, models.JobFailed).Return(nil)
+
+	result, err := service.RetryJob(context.Background(), 1)
+
+	assert.Nil(t, err)
+	assert.Equal(t, models.JobPending, result.Status)
+	assert.Equal(t, 0, result.Attempts)
+	assert.Empty(t, result.LastError)
+	assert.Empty(t, result.FinishedAt)
+	jobRepo.AssertExpectations(t)
+}
+
+func TestRetryJob_Succeeded(t *testing.T) {
+	service, jobRepo, db := newTestJobService()
+
+	jobRepo.On("FindJobById", mock.Anything, db, 1).Return(&models.Job{ID: 1, Status: models.JobSucceeded}, nil)
+
+	_, err := service.RetryJob(context.Background(), 1)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, 400, err.StatusCode)
+	assert.Equal(t, "cannot move a succeeded job to pending", err.AdditionalInfo)
+}
+
+func TestCancelJob_ChangedMeanwhile(t *testing.T) {
+	service, jobRepo, db := newTestJobService()
+	job := &models.Job{ID: 1, Status: models.JobRunning}
+
+	jobRepo.On("FindJobById", mock.Anything, db, 1).Return(job, nil)
+	jobRepo.On("UpdateJob", mock.Anything, db, job, models.JobRunning).Return(errors.New("job not found"))
+
+	_, err := service.CancelJob(context.Background(), 1)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, 409, err.StatusCode)
+}
+
+// newJobTestDB opens an in-memory database with the jobs table and two
+// organizations.
+func newJobTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	assert.Nil(t, err)
+	sqlDB, err := db.DB()
+	assert.Nil(t, err)
+	// every connection would get a database of its own
+	sqlDB.SetMaxOpenConns(1)
+	assert.Nil(t, db.AutoMigrate(&models.Organization{}, &models.Job{}))
+	assert.Nil(t, tenant.Register(db, &models.Job{}))
+	assert.Nil(t, db.Create(&[]*models.Organization{{Name: "Acme", Slug: "acme"}, {Name: "Globex", Slug: "globex"}}).Error)
+	return db
+}
+
+func newRunningJobService(t *testing.T, db *gorm.DB) (*JobServiceImpl, func()) {
+	service := NewJobService(repositories.NewJobRepository(), repositories.NewOrganizationRepository(), map[string]int{"emails": 2}, db).(*JobServiceImpl)
+	service.PollInterval = 10 * time.Millisecond
+	service.Backoff = 10 * time.Millisecond
+	return service, func() {
+		ctx, stop := context.WithCancel(context.Background())
+		drained := make(chan struct{})
+		go func() {
+			service.Run(ctx)
+			close(drained)
+		}()
+		t.Cleanup(func() {
+			stop()
+			<-drained
+		})
+	}
+}
+
+func jobStatus(service JobService, ctx context.Context, id uint) func() bool {
+	return func() bool {
+		job, err := service.FindDetailJob(ctx, int(id))
+		return err == nil && job.Status == models.JobSucceeded
+	}
+}
+
+func TestRun_RunsAndRetriesJobs(t *testing.T) {
+	db := newJobTestDB(t)
+	service, start := newRunningJobService(t, db)
+	failures := 1
+	sent := make(chan string, 2)
+	service.Handle("emails.send", "emails", TypedJobHandler(func(ctx context.Context, email *testEmail) error {
+		if email.To == "flaky@example.com" && failures > 0 {
+			failures--
+			return errors.New("smtp unavailable")
+		}
+		sent <- email.To
+		return nil
+	}))
+	start()
+
+	acme := tenant.WithID(context.Background(), 1)
+	globex := tenant.WithID(context.Background(), 2)
+	first, err := service.Enqueue(acme, &params.JobRequest{Type: "emails.send", Payload: &testEmail{To: "reader@example.com"}})
+	assert.Nil(t, err)
+	second, err := service.Enqueue(globex, &params.JobRequest{Type: "emails.send", Payload: &testEmail{To: "flaky@example.com"}})
+	assert.Nil(t, err)
+
+	assert.Eventually(t, jobStatus(service, acme, first.ID), time.Second, 10*time.Millisecond)
+	assert.Eventually(t, jobStatus(service, globex, second.ID), time.Second, 10*time.Millisecond)
+	result, _ := service.FindDetailJob(globex, int(second.ID))
+	assert.Equal(t, 2, result.Attempts)
+	assert.ElementsMatch(t, []string{"reader@example.com", "flaky@example.com"}, []string{<-sent, <-sent})
+}
+
+func TestRun_DrainsRunningJobs(t *testing.T) {
+	db := newJobTestDB(t)
+	service := NewJobService(repositories.NewJobRepository(), repositories.NewOrganizationRepository(), nil, db).(*JobServiceImpl)
+	service.PollInterval = 10 * time.Millisecond
+	started := make(chan struct{})
+	release := make(chan struct{})
+	service.Handle("exports.build", "exports", func(ctx context.Context, job *models.Job) error {
+		close(started)
+		<-release
+		return nil
+	})
+	ctx, stop := context.WithCancel(context.Background())
+	drained := make(chan struct{})
+	go func() {
+		service.Run(ctx)
+		close(drained)
+	}()
+
+	acme := tenant.WithID(context.Background(), 1)
+	job, err := service.Enqueue(acme, &params.JobRequest{Type: "exports.build"})
+	assert.Nil(t, err)
+	<-started
+	stop()
+
+	select {
+	case <-drained:
+		t.Fatal("stopped before the running job finished")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(release)
+	<-drained
+	assert.True(t, jobStatus(service, acme, job.ID)())
+}
+
+func TestRun_CancelStopsRunningJob(t *testing.T) {
+	db := newJobTestDB(t)
+	service, start := newRunningJobService(t, db)
+	started := make(chan struct{})
+	stopped := make(chan error, 1)
+	service.Handle("thumbnails.generate", "thumbnails", func(ctx context.Context, job *models.Job) error {
+		close(started)
+		<-ctx.Done()
+		stopped <- ctx.Err()
+		return ctx.Err()
+	})
+	start()
+
+	acme := tenant.WithID(context.Background(), 1)
+	job, err := service.Enqueue(acme, &params.JobRequest{Type: "thumbnails.generate"})
+	assert.Nil(t, err)
+	<-started
+
+	result, custErr := service.CancelJob(acme, int(job.ID))
+	assert.Nil(t, custErr)
+	assert.Equal(t, models.JobCanceled, result.Status)
+	assert.Equal(t, context.Canceled, <-stopped)
+
+	time.Sleep(20 * time.Millisecond)
+	result, _ = service.FindDetailJob(acme, int(job.ID))
+	assert.Equal(t, models.JobCanceled, result.Status)
+}
@@ -0,0 +1,183 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"golang-backend-test/app/commons/response"
+	"golang-backend-test/app/models"
+	"golang-backend-test/app/params"
+	"golang-backend-test/app/repositories"
+	"golang-backend-test/pkg/label"
+
+	"github.com/go-playground/validator"
+	"gorm.io/gorm"
+)
+
+type LabelService interface {
+	BookBarcode(ctx context.Context, id int, req *params.BarcodeRequest) (*params.FileResponse, *response.CustomError)
+	BookQRCode(ctx context.Context, id int, detailURL string, req *params.BarcodeRequest) (*params.FileResponse, *response.CustomError)
+	SpineLabels(ctx context.Context, req *params.SpineLabelRequest) (*params.FileResponse, *response.CustomError)
+}
+
+type LabelServiceImpl struct {
+	BookRepository repositories.BookRepository
+	DB             *gorm.DB
+}
+
+func NewLabelService(bookRepository repositories.BookRepository, db *gorm.DB) LabelService {
+	return &LabelServiceImpl{
+		BookRepository: bookRepository,
+		DB:             db,
+	}
+}
+
+func (service *LabelServiceImpl) BookBarcode(ctx context.Context, id int, req *params.BarcodeRequest) (*params.FileResponse, *response.CustomError) {
+	if custErr := validateBarcodeRequest(req); custErr != nil {
+		return nil, custErr
+	}
+	if req.Symbology == "" {
+		req.Symbology = label.SymbologyEAN13
+	}
+
+	book, err := service.BookRepository.FindBookById(ctx, service.DB, id)
+	if err != nil {
+		return nil, response.NotFoundError()
+	}
+
+	content := bookLabelCode(book)
+	if req.Symbology == label.SymbologyEAN13 {
+		content, err = label.EAN13FromISBN(book.ISBN)
+		if err != nil {
+			return nil, response.BadRequestErrorWithAdditionalInfo(err.Error())
+		}
+	}
+	code, err := label.Encode(req.Symbology, content)
+	if err != nil {
+		return nil, response.BadRequestErrorWithAdditionalInfo(err.Error())
+	}
+	body, contentType, err := label.Render(code, req.Format, req.Scale, 40*req.Scale)
+	if err != nil {
+		return nil, response.GeneralErrorWithAdditionalInfo(err.Error())
+	}
+
+	return &params.FileResponse{
+		Filename:    fmt.Sprintf("book-%d-%s.%s", book.ID, req.Symbology, req.Format),
+		ContentType: contentType,
+		Body:        body,
+	}, nil
+}
+
+func (service *LabelServiceImpl) BookQRCode(ctx context.Context, id int, detailURL string, req *params.BarcodeRequest) (*params.FileResponse, *response.CustomError) {
+	if custErr := validateBarcodeRequest(req); custErr != nil {
+		return nil, custErr
+	}
+
+	book, err := service.BookRepository.FindBookById(ctx, service.DB, id)
+	if err != nil {
+		return nil, response.NotFoundError()
+	}
+
+	code, err := label.Encode(label.SymbologyQR, detailURL)
+	if err != nil {
+		return nil, response.BadRequestErrorWithAdditionalInfo(err.Error())
+	}
+	body, contentType, err := label.Render(code, req.Format, req.Scale, 0)
+	if err != nil {
+		return nil, response.GeneralErrorWithAdditionalInfo(err.Error())
+	}
+
+	return &params.FileResponse{
+		Filename:    fmt.Sprintf("book-%d-qr.%s", book.ID, req.Format),
+		ContentType: contentType,
+		Body:        body,
+	}, nil
+}
+
+func (service *LabelServiceImpl) SpineLabels(ctx context.Context, req *params.SpineLabelRequest) (*params.FileResponse, *response.CustomError) {
+	val := validator.New()
+	err := val.Struct(req)
+	if err != nil {
+		validationErrors := err.(validator.ValidationErrors)
+		var errors []interface{}
+		for _, fieldError := range validationErrors {
+			error := "error " + fieldError.Field() + " on tag " + fieldError.Tag()
+			errors = append(errors, error)
+		}
+		return nil, response.BadRequestErrorWithAdditionalInfo(errors)
+	}
+	if req.Stock == "" {
+		req.Stock = "5160"
+	}
+	stock := label.Stocks[req.Stock]
+	if req.Skip >= stock.Columns*stock.Rows {
+		return nil, response.BadRequestErrorWithAdditionalInfo(fmt.Sprintf("skip must be less than %d", stock.Columns*stock.Rows))
+	}
+
+	var ids []int
+	for _, id := range req.BookIDs {
+		ids = append(ids, int(id))
+	}
+	books, err := service.BookRepository.FindBooksByIds(ctx, service.DB, ids)
+	if err != nil {
+		return nil, response.RepositoryError()
+	}
+	booksById := make(map[uint]*models.Book)
+	for _, book := range books {
+		booksById[book.ID] = book
+	}
+
+	// labels are printed in the order they were requested
+	var labels []label.SpineLabel
+	for _, id := range req.BookIDs {
+		book, ok := booksById[id]
+		if !ok {
+			return nil, response.NotFoundErrorWithAdditionalInfo(fmt.Sprintf("book %d not found", id))
+		}
+		labels = append(labels, label.SpineLabel{
+			Title:  book.Title,
+			Author: book.Author.Name,
+			Code:   bookLabelCode(book),
+		})
+	}
+
+	body, err := label.SpineLabelSheet(stock, labels, req.Skip)
+	if err != nil {
+		return nil, response.GeneralErrorWithAdditionalInfo(err.Error())
+	}
+
+	return &params.FileResponse{
+		Filename:    fmt.Sprintf("spine-labels-%s.pdf", req.Stock),
+		ContentType: "application/pdf",
+		Body:        body,
+	}, nil
+}
+
+func validateBarcodeRequest(req *params.BarcodeRequest) *response.CustomError {
+	val := validator.New()
+	err := val.Struct(req)
+	if err != nil {
+		validationErrors := err.(validator.ValidationErrors)
+		var errors []interface{}
+		for _, fieldError := range validationErrors {
+			error := "error " + fieldError.Field() + " on tag " + fieldError.Tag()
+			errors = append(errors, error)
+		}
+		return response.BadRequestErrorWithAdditionalInfo(errors)
+	}
+	if req.Format == "" {
+		req.Format = label.FormatPNG
+	}
+	if req.Scale == 0 {
+		req.Scale = 4
+	}
+	return nil
+}
+
+// bookLabelCode is the Code 128 content identifying a book: its ISBN when it
+// has one, otherwise its catalog ID.
+func bookLabelCode(book *models.Book) string {
+	if ean, err := label.EAN13FromISBN(book.ISBN); err == nil {
+		return ean
+	}
+	return fmt.Sprintf("BOOK%08d", book.ID)
+}
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"golang-backend-test/app/models"
+	"golang-backend-test/app/params"
+	"golang-backend-test/app/repositories"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestBookBarcode_EAN13Success(t *testing.T) {
+	bookRepo := new(repositories.MockBookRepository)
+	db := new(gorm.DB)
+	service := NewLabelService(bookRepo, db)
+
+	bookRepo.On("FindBookById", mock.Anything, db, 1).Return(&models.Book{ID: 1, Title: "Test Book", ISBN: "0-261-10235-4"}, nil)
+
+	result, err := service.BookBarcode(context.Background(), 1, &params.BarcodeRequest{})
+
+	assert.Nil(t, err)
+	assert.NotNil(t, result)
+	assert.Equal(t, "image/png", result.ContentType)
+	assert.Equal(t, "book-1-ean13.png", result.Filename)
+	assert.True(t, bytes.HasPrefix(result.Body, []byte("\x89PNG")))
+	bookRepo.AssertExpectations(t)
+}
+
+func TestBookBarcode_SVG(t *testing.T) {
+	bookRepo := new(repositories.MockBookRepository)
+	db := new(gorm.DB)
+	service := NewLabelService(bookRepo, db)
+
+	bookRepo.On("FindBookById", mock.Anything, db, 1).Return(&models.Book{ID: 1, Title: "Test Book", ISBN: "9780261102354"}, nil)
+
+	result, err := service.BookBarcode(context.Background(), 1, &params.BarcodeRequest{Format: "svg", Scale: 1})
+
+	assert.Nil(t, err)
+	assert.NotNil(t, result)
+	assert.Equal(t, "image/svg+xml", result.ContentType)
+	assert.True(t, bytes.HasPrefix(result.Body, []byte(`<svg xmlns="http://www.w3.org/2000/svg" width="95" height="40"`)))
+	bookRepo.AssertExpectations(t)
+}
+
+func TestBookBarcode_InvalidISBN(t *testing.T) {
+	bookRepo := new(repositories.MockBookRepository)
+	db := new(gorm.DB)
+	service := NewLabelService(bookRepo, db)
+
+	bookRepo.On("FindBookById", mock.Anything, db, 1).Return(&models.Book{ID: 1, Title: "Test Book", ISBN: "123456789"}, nil)
+
+	result, err := service.BookBarcode(context.Background(), 1, &params.BarcodeRequest{})
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "BAD REQUEST ERROR", err.Message)
+	bookRepo.AssertExpectations(t)
+}
+
+func TestBookBarcode_Code128WithoutISBN(t *testing.T) {
+	bookRepo := new(repositories.MockBookRepository)
+	db := new(gorm.DB)
+	service := NewLabelService(bookRepo, db)
+
+	bookRepo.On("FindBookById", mock.Anything, db, 1).Return(&models.Book{ID: 1, Title: "Test Book"}, nil)
+
+	result, err := service.BookBarcode(context.Background(), 1, &params.BarcodeRequest{Symbology: "code128"})
+
+	assert.Nil(t, err)
+	assert.NotNil(t, result)
+	assert.Equal(t, "book-1-code128.png", result.Filename)
+	bookRepo.AssertExpectations(t)
+}
+
+func TestBookBarcode_BookNotFound(t *testing.T) {
+	bookRepo := new(repositories.MockBookRepository)
+	db := new(gorm.DB)
+	service := NewLabelService(bookRepo, db)
+
+	bookRepo.On("FindBookById", mock.Anything, db, 1).Return(nil, errors.New("book not found"))
+
+	result, err := service.BookBarcode(context.Background(), 1, &params.BarcodeRequest{})
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "NOT FOUND ERROR", err.Message)
+	bookRepo.AssertExpectations(t)
+}
+
+func TestBookQRCode_Success(t *testing.T) {
+	bookRepo := new(repositories.MockBookRepository)
+	db := new(gorm.DB)
+	service := NewLabelService(bookRepo, db)
+
+	bookRepo.On("FindBookById", mock.Anything, db, 1).Return(&models.Book{ID: 1, Title: "Test Book"}, nil)
+
+	result, err := service.BookQRCode(context.Background(), 1, "http://localhost:8080/books/1", &params.BarcodeRequest{Format: "svg"})
+
+	assert.Nil(t, err)
+	assert.NotNil(t, result)
+	assert.Equal(t, "image/svg+xml", result.ContentType)
+	assert.Equal(t, "book-1-qr.svg", result.Filename)
+	bookRepo.AssertExpectations(t)
+}
+
+func TestSpineLabels_Success(t *testing.T) {
+	bookRepo := new(repositories.MockBookRepository)
+	db := new(gorm.DB)
+	service := NewLabelService(bookRepo, db)
+
+	bookRepo.On("FindBooksByIds", mock.Anything, db, []int{2, 1}).Return([]*models.Book{
+		{ID: 1, Title: "Test Book", ISBN: "9780261102354", Author: models.Author{Name: "Test Author"}},
+		{ID: 2, Title: "Other Book", Author: models.Author{Name: "Test Author"}},
+	}, nil)
+
+	result, err := service.SpineLabels(context.Background(), &params.SpineLabelRequest{BookIDs: []uint{2, 1}, Stock: "L7160"})
+
+	assert.Nil(t, err)
+	assert.NotNil(t, result)
+	assert.Equal(t, "application/pdf", result.ContentType)
+	assert.Equal(t, "spine-labels-L7160.pdf", result.Filename)
+	assert.True(t, bytes.HasPrefix(result.Body, []byte("%PDF")))
+	bookRepo.AssertExpectations(t)
+}
+
+func TestSpineLabels_BookNotFound(t *testing.T) {
+	bookRepo := new(repositories.MockBookRepository)
+	db := new(gorm.DB)
+	service := NewLabelService(bookRepo, db)
+
+	bookRepo.On("FindBooksByIds", mock.Anything, db, []int{1, 9}).Return([]*models.Book{
+		{ID: 1, Title: "Test Book"},
+	}, nil)
+
+	result, err := service.SpineLabels(context.Background(), &params.SpineLabelRequest{BookIDs: []uint{1, 9}})
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "NOT FOUND ERROR", err.Message)
+	bookRepo.AssertExpectations(t)
+}
+
+func TestSpineLabels_ValidationError(t *testing.T) {
+	bookRepo := new(repositories.MockBookRepository)
+	db := new(gorm.DB)
+	service := NewLabelService(bookRepo, db)
+
+	result, err := service.SpineLabels(context.Background(), &params.SpineLabelRequest{BookIDs: []uint{1}, Stock: "9999"})
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "BAD REQUEST ERROR", err.Message)
+}
+
+func TestSpineLabels_SkipBeyondSheet(t *testing.T) {
+	bookRepo := new(repositories.MockBookRepository)
+	db := new(gorm.DB)
+	service := NewLabelService(bookRepo, db)
+
+	result, err := service.SpineLabels(context.Background(), &params.SpineLabelRequest{BookIDs: []uint{1}, Stock: "5160", Skip: 30})
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "BAD REQUEST ERROR", err.Message)
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"golang-backend-test/app/commons/response"
+	"golang-backend-test/app/models"
+	"golang-backend-test/app/params"
+	"golang-backend-test/app/repositories"
+	"time"
+
+	"github.com/go-playground/validator"
+	"gorm.io/gorm"
+)
+
+type LoanService interface {
+	FindDetailLoan(ctx context.Context, id int) (*params.LoanResponse, *response.CustomError)
+	CreateLoan(ctx context.Context, userId int, req *params.LoanRequest) (*params.LoanResponse, *response.CustomError)
+	ReturnLoan(ctx context.Context, id int) (*params.LoanResponse, *response.CustomError)
+}
+
+type LoanServiceImpl struct {
+	LoanRepository     repositories.LoanRepository
+	BookCopyRepository repositories.BookCopyRepository
+	DB                 *gorm.DB
+}
+
+// defaultLoanDays is how long a copy is lent when the request does not say.
+const defaultLoanDays = 14
+
+func NewLoanService(loanRepository repositories.LoanRepository, bookCopyRepository repositories.BookCopyRepository, db *gorm.DB) LoanService {
+	return &LoanServiceImpl{
+		LoanRepository:     loanRepository,
+		BookCopyRepository: bookCopyRepository,
+		DB:                 db,
+	}
+}
+
+func (service *LoanServiceImpl) FindDetailLoan(ctx context.Context, id int) (*params.LoanResponse, *response.CustomError) {
+	loan, err := service.LoanRepository.FindLoanById(ctx, service.DB, id)
+	if err != nil {
+		return nil, response.NotFoundError()
+	}
+	return loanResponse(loan), nil
+}
+
+// CreateLoan lends an available copy that is not out on another loan. Both
+// are checked in the transaction that adds the loan.
+func (service *LoanServiceImpl) CreateLoan(ctx context.Context, userId int, req *params.LoanRequest) (*params.LoanResponse, *response.CustomError) {
+	val := validator.New()
+	err := val.Struct(req)
+	if err != nil {
+		validationErrors := err.(validator.ValidationErrors)
+		var errors []interface{}
+		for _, fieldError := range validationErrors {
+			error := "error " + fieldError.Field() + " on tag " + fieldError.Tag()
+			errors = append(errors, error)
+		}
+		return nil, response.BadRequestErrorWithAdditionalInfo(errors)
+	}
+	days := req.Days
+	if days == 0 {
+		days = defaultLoanDays
+	}
+
+	now := time.Now()
+	var loan = new(models.Loan)
+	loan.BookCopyID = req.BookCopyID
+	loan.UserID = uint(userId)
+	loan.BorrowedAt = now
+	loan.DueAt = now.AddDate(0, 0, days)
+	var custErr *response.CustomError
+	err = service.DB.Transaction(func(tx *gorm.DB) error {
+		bookCopy, err := service.BookCopyRepository.FindBookCopyById(ctx, tx, int(req.BookCopyID))
+		if err != nil {
+			custErr = response.BadRequestErrorWithAdditionalInfo(err.Error())
+			return err
+		}
+		if bookCopy.Status != models.CopyAvailable {
+			custErr = response.BadRequestErrorWithAdditionalInfo("book copy is not available")
+			return errors.New("book copy is not available")
+		}
+		onLoan, err := service.LoanRepository.HasOpenLoan(ctx, tx, int(bookCopy.ID))
+		if err != nil {
+			return err
+		}
+		if onLoan {
+			custErr = response.ConflictErrorWithAdditionalInfo("book copy is already on loan")
+			return errors.New("book copy is already on loan")
+		}
+		return service.LoanRepository.CreateLoan(ctx, tx, loan)
+	})
+	if custErr != nil {
+		return nil, custErr
+	}
+	if err != nil {
+		return nil, response.RepositoryError()
+	}
+	return loanResponse(loan), nil
+}
+
+func (service *LoanServiceImpl) ReturnLoan(ctx context.Context, id int) (*params.LoanResponse, *response.CustomError) {
+	loan, err := service.LoanRepository.FindLoanById(ctx, service.DB, id)
+	if err != nil {
+		return nil, response.NotFoundError()
+	}
+	if loan.ReturnedAt != nil {
+		return nil, response.BadRequestErrorWithAdditionalInfo("loan is already returned")
+	}
+
+	now := time.Now()
+	loan.ReturnedAt = &now
+	if err := service.LoanRepository.ReturnLoan(ctx, service.DB, loan); err != nil {
+		return nil, response.ConflictErrorWithAdditionalInfo("the loan changed meanwhile")
+	}
+	return loanResponse(loan), nil
+}
+
+func loanResponse(loan *models.Loan) *params.LoanResponse {
+	result := &params.LoanResponse{
+		ID:         loan.ID,
+		BookCopyID: loan.BookCopyID,
+		UserID:     loan.UserID,
+		BorrowedAt: loan.BorrowedAt.UTC().Format(time.RFC3339),
+		DueAt:      loan.DueAt.UTC().Format(time.RFC3339),
+	}
+	if loan.ReturnedAt != nil {
+		result.ReturnedAt = loan.ReturnedAt.UTC().Format(time.RFC3339)
+	}
+	return result
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"golang-backend-test/app/models"
+	"golang-backend-test/app/params"
+	"golang-backend-test/app/repositories"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func newTestLoanService(t *testing.T) (LoanService, *repositories.MockLoanRepository, *repositories.MockBookCopyRepository, *gorm.DB) {
+	loanRepo := new(repositories.MockLoanRepository)
+	bookCopyRepo := new(repositories.MockBookCopyRepository)
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	assert.Nil(t, err)
+	return NewLoanService(loanRepo, bookCopyRepo, db), loanRepo, bookCopyRepo, db
+}
+
+func TestCreateLoan_Success(t *testing.T) {
+	service, loanRepo, bookCopyRepo, _ := newTestLoanService(t)
+
+	bookCopyRepo.On("FindBookCopyById", mock.Anything, mock.Anything, 1).Return(&models.BookCopy{ID: 1, Status: models.CopyAvailable}, nil)
+	loanRepo.On("HasOpenLoan", mock.Anything, mock.Anything, 1).Return(false, nil)
+	loanRepo.On("CreateLoan", mock.Anything, mock.Anything, mock.MatchedBy(func(loan *models.Loan) bool {
+		return loan.BookCopyID == 1 && loan.UserID == 7 && loan.DueAt.Sub(loan.BorrowedAt) == 21*24*time.Hour
+	})).Run(func(args mock.Arguments) {
+		args.Get(2).(*models.Loan).ID = 3
+	}).Return(nil)
+
+	result, err := service.CreateLoan(context.Background(), 7, &params.LoanRequest{BookCopyID: 1, Days: 21})
+
+	assert.Nil(t, err)
+	assert.Equal(t, uint(3), result.ID)
+	assert.Equal(t, uint(7), result.UserID)
+	assert.Empty(t, result.ReturnedAt)
+	loanRepo.AssertExpectations(t)
+	bookCopyRepo.AssertExpectations(t)
+}
+
+func TestCreateLoan_DefaultPeriod(t *testing.T) {
+	service, loanRepo, bookCopyRepo, _ := newTestLoanService(t)
+
+	bookCopyRepo.On("FindBookCopyById", mock.Anything, mock.Anything, 1).Return(&models.BookCopy{ID: 1, Status: models.CopyAvailable}, nil)
+	loanRepo.On("HasOpenLoan", mock.Anything, mock.Anything, 1).Return(false, nil)
+	loanRepo.On("CreateLoan", mock.Anything, mock.Anything, mock.MatchedBy(func(loan *models.Loan) bool {
+		return loan.DueAt.Sub(loan.BorrowedAt) == defaultLoanDays*24*time.Hour
+	})).Return(nil)
+
+	_, err := service.CreateLoan(context.Background(), 7, &params.LoanRequest{BookCopyID: 1})
+
+	assert.Nil(t, err)
+	loanRepo.AssertExpectations(t)
+}
+
+func TestCreateLoan_ValidationError(t *testing.T) {
+	service, _, _, _ := newTestLoanService(t)
+
+	result, err := service.CreateLoan(context.Background(), 7, &params.LoanRequest{Days: 365})
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, []interface{}{"error BookCopyID on tag required", "error Days on tag max"}, err.AdditionalInfo)
+}
+
+func TestCreateLoan_CopyNotAvailable(t *testing.T) {
+	service, loanRepo, bookCopyRepo, _ := newTestLoanService(t)
+
+	bookCopyRepo.On("FindBookCopyById", mock.Anything, mock.Anything, 1).Return(&models.BookCopy{ID: 1, Status: models.CopyLost}, nil)
+
+	result, err := service.CreateLoan(context.Background(), 7, &params.LoanRequest{BookCopyID: 1})
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "book copy is not available", err.AdditionalInfo)
+	loanRepo.AssertNotCalled(t, "CreateLoan", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCreateLoan_AlreadyOnLoan(t *testing.T) {
+	service, loanRepo, bookCopyRepo, _ := newTestLoanService(t)
+
+	bookCopyRepo.On("FindBookCopyById", mock.Anything, mock.Anything, 1).Return(&models.BookCopy{ID: 1, Status: models.CopyAvailable}, nil)
+	loanRepo.On("HasOpenLoan", mock.Anything, mock.Anything, 1).Return(true, nil)
+
+	result, err := service.CreateLoan(context.Background(), 7, &params.LoanRequest{BookCopyID: 1})
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, 409, err.StatusCode)
+	loanRepo.AssertNotCalled(t, "CreateLoan", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCreateLoan_CopyNotFound(t *testing.T) {
+	service, _, bookCopyRepo, _ := newTestLoanService(t)
+
+	bookCopyRepo.On("FindBookCopyById", mock.Anything, mock.Anything, 9).Return(nil, errors.New("book copy not found"))
+
+	result, err := service.CreateLoan(context.Background(), 7, &params.LoanRequest{BookCopyID: 9})
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "book copy not found", err.AdditionalInfo)
+}
+
+func TestReturnLoan_Success(t *testing.T) {
+	service, loanRepo, _, db := newTestLoanService(t)
+
+	loanRepo.On("FindLoanById", mock.Anything, db, 3).Return(&models.Loan{ID: 3, BookCopyID: 1, UserID: 7}, nil)
+	loanRepo.On("ReturnLoan", mock.Anything, db, mock.MatchedBy(func(loan *models.Loan) bool {
+		return loan.ReturnedAt != nil
+	})).Return(nil)
+
+	result, err := service.ReturnLoan(context.Background(), 3)
+
+	assert.Nil(t, err)
+	assert.NotEmpty(t, result.ReturnedAt)
+	loanRepo.AssertExpectations(t)
+}
+
+func TestReturnLoan_AlreadyReturned(t *testing.T) {
+	service, loanRepo, _, db := newTestLoanService(t)
+
+	returnedAt := time.Now()
+	loanRepo.On("FindLoanById", mock.Anything, db, 3).Return(&models.Loan{ID: 3, ReturnedAt: &returnedAt}, nil)
+
+	result, err := service.ReturnLoan(context.Background(), 3)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "loan is already returned", err.AdditionalInfo)
+	loanRepo.AssertNotCalled(t, "ReturnLoan", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestReturnLoan_ReturnedMeanwhile(t *testing.T) {
+	service, loanRepo, _, db := newTestLoanService(t)
+
+	loanRepo.On("FindLoanById", mock.Anything, db, 3).Return(&models.Loan{ID: 3}, nil)
+	loanRepo.On("ReturnLoan", mock.Anything, db, mock.AnythingOfType("*models.Loan")).Return(errors.New("loan not found"))
+
+	result, err := service.ReturnLoan(context.Background(), 3)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, 409, err.StatusCode)
+}
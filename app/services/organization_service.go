@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"golang-backend-test/app/commons/response"
+	"golang-backend-test/app/models"
+	"golang-backend-test/app/params"
+	"golang-backend-test/app/repositories"
+	"golang-backend-test/pkg/tenant"
+	"regexp"
+	"time"
+
+	"github.com/go-playground/validator"
+	"gorm.io/gorm"
+)
+
+type OrganizationService interface {
+	CreateOrganization(ctx context.Context, req *params.OrganizationRequest) (*params.OrganizationResponse, *response.CustomError)
+	FindCurrentOrganization(ctx context.Context) (*params.OrganizationResponse, *response.CustomError)
+	CreateInvite(ctx context.Context) (*params.InviteResponse, *response.CustomError)
+}
+
+type OrganizationServiceImpl struct {
+	OrganizationRepository repositories.OrganizationRepository
+	DB                     *gorm.DB
+}
+
+func NewOrganizationService(organizationRepository repositories.OrganizationRepository, db *gorm.DB) OrganizationService {
+	return &OrganizationServiceImpl{
+		OrganizationRepository: organizationRepository,
+		DB:                     db,
+	}
+}
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+
+// inviteLifetime is how long an invite can be used.
+const inviteLifetime = 7 * 24 * time.Hour
+
+func (service *OrganizationServiceImpl) CreateOrganization(ctx context.Context, req *params.OrganizationRequest) (*params.OrganizationResponse, *response.CustomError) {
+	val := validator.New()
+	val.RegisterValidation("slug", func(fl validator.FieldLevel) bool {
+		return slugPattern.MatchString(fl.Field().String())
+	})
+	err := val.Struct(req)
+	if err != nil {
+		validationErrors := err.(validator.ValidationErrors)
+		var errors []interface{}
+		for _, fieldError := range validationErrors {
+			error := "error " + fieldError.Field() + " on tag " + fieldError.Tag()
+			errors = append(errors, error)
+		}
+		return nil, response.BadRequestErrorWithAdditionalInfo(errors)
+	}
+
+	_, errCheck := service.OrganizationRepository.FindOrganizationBySlug(ctx, service.DB, req.Slug)
+	if errCheck == nil {
+		return nil, response.BadRequestErrorWithAdditionalInfo("slug already exists")
+	}
+
+	var organization = new(models.Organization)
+	organization.Name = req.Name
+	organization.Slug = req.Slug
+	var invite *models.Invite
+	err = service.DB.Transaction(func(tx *gorm.DB) error {
+		if err := service.OrganizationRepository.CreateOrganization(ctx, tx, organization); err != nil {
+			return err
+		}
+		// the invite of the first admin, who registers with it
+		var err error
+		invite, err = service.createInvite(tenant.WithID(ctx, organization.ID), tx)
+		return err
+	})
+	if err != nil {
+		return nil, response.BadRequestError()
+	}
+
+	result := organizationResponse(organization)
+	result.Invite = inviteResponse(invite)
+	return result, nil
+}
+
+func (service *OrganizationServiceImpl) FindCurrentOrganization(ctx context.Context) (*params.OrganizationResponse, *response.CustomError) {
+	tenantId, ok := tenant.FromContext(ctx)
+	if !ok {
+		return nil, response.UnauthorizedError()
+	}
+	organization, err := service.OrganizationRepository.FindOrganizationById(ctx, service.DB, int(tenantId))
+	if err != nil {
+		return nil, response.NotFoundError()
+	}
+
+	return organizationResponse(organization), nil
+}
+
+func (service *OrganizationServiceImpl) CreateInvite(ctx context.Context) (*params.InviteResponse, *response.CustomError) {
+	invite, err := service.createInvite(ctx, service.DB)
+	if err != nil {
+		return nil, response.RepositoryErrorWithAdditionalInfo(err.Error())
+	}
+
+	return inviteResponse(invite), nil
+}
+
+func (service *OrganizationServiceImpl) createInvite(ctx context.Context, db *gorm.DB) (*models.Invite, error) {
+	buffer := make([]byte, 24)
+	if _, err := rand.Read(buffer); err != nil {
+		return nil, err
+	}
+	invite := &models.Invite{
+		Token:     "inv_" + hex.EncodeToString(buffer),
+		ExpiresAt: time.Now().Add(inviteLifetime),
+	}
+	if err := service.OrganizationRepository.CreateInvite(ctx, db, invite); err != nil {
+		return nil, err
+	}
+	return invite, nil
+}
+
+func inviteResponse(invite *models.Invite) *params.InviteResponse {
+	return &params.InviteResponse{
+		Token:     invite.Token,
+		ExpiresAt: invite.ExpiresAt.UTC().Format(time.RFC3339),
+	}
+}
+
+func organizationResponse(organization *models.Organization) *params.OrganizationResponse {
+	return &params.OrganizationResponse{
+		ID:   organization.ID,
+		Name: organization.Name,
+		Slug: organization.Slug,
+	}
+}
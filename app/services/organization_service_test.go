@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"golang-backend-test/app/models"
+	"golang-backend-test/app/params"
+	"golang-backend-test/app/repositories"
+	"golang-backend-test/pkg/tenant"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestCreateOrganization_Success(t *testing.T) {
+	organizationRepo := new(repositories.MockOrganizationRepository)
+	db, errDB := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	assert.Nil(t, errDB)
+	service := NewOrganizationService(organizationRepo, db)
+
+	organizationRepo.On("FindOrganizationBySlug", mock.Anything, db, "city-library").Return(nil, errors.New("organization not found"))
+	organizationRepo.On("CreateOrganization", mock.Anything, mock.Anything, mock.AnythingOfType("*models.Organization")).Run(func(args mock.Arguments) {
+		args.Get(2).(*models.Organization).ID = 4
+	}).Return(nil)
+	// the invite of the first admin belongs to the new organization
+	organizationRepo.On("CreateInvite", mock.MatchedBy(func(ctx context.Context) bool {
+		id, _ := tenant.FromContext(ctx)
+		return id == 4
+	}), mock.Anything, mock.AnythingOfType("*models.Invite")).Return(nil)
+
+	result, err := service.CreateOrganization(context.Background(), &params.OrganizationRequest{Name: "City Library", Slug: "city-library"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "city-library", result.Slug)
+	assert.NotEmpty(t, result.Invite.Token)
+	organizationRepo.AssertExpectations(t)
+}
+
+func TestCreateOrganization_InviteFails(t *testing.T) {
+	organizationRepo := new(repositories.MockOrganizationRepository)
+	db, errDB := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	assert.Nil(t, errDB)
+	service := NewOrganizationService(organizationRepo, db)
+
+	organizationRepo.On("FindOrganizationBySlug", mock.Anything, db, "city-library").Return(nil, errors.New("organization not found"))
+	organizationRepo.On("CreateOrganization", mock.Anything, mock.Anything, mock.AnythingOfType("*models.Organization")).Return(nil)
+	organizationRepo.On("CreateInvite", mock.Anything, mock.Anything, mock.AnythingOfType("*models.Invite")).Return(errors.New("db error"))
+
+	result, err := service.CreateOrganization(context.Background(), &params.OrganizationRequest{Name: "City Library", Slug: "city-library"})
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, 400, err.StatusCode)
+}
+
+func TestCreateOrganization_InvalidSlug(t *testing.T) {
+	organizationRepo := new(repositories.MockOrganizationRepository)
+	db := new(gorm.DB)
+	service := NewOrganizationService(organizationRepo, db)
+
+	result, err := service.CreateOrganization(context.Background(), &params.OrganizationRequest{Name: "City Library", Slug: "City Library"})
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, []interface{}{"error Slug on tag slug"}, err.AdditionalInfo)
+}
+
+func TestCreateOrganization_SlugExists(t *testing.T) {
+	organizationRepo := new(repositories.MockOrganizationRepository)
+	db := new(gorm.DB)
+	service := NewOrganizationService(organizationRepo, db)
+
+	organizationRepo.On("FindOrganizationBySlug", mock.Anything, db, "city-library").Return(&models.Organization{ID: 1, Slug: "city-library"}, nil)
+
+	result, err := service.CreateOrganization(context.Background(), &params.OrganizationRequest{Name: "City Library", Slug: "city-library"})
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "slug already exists", err.AdditionalInfo)
+	organizationRepo.AssertNotCalled(t, "CreateOrganization", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestFindCurrentOrganization_Success(t *testing.T) {
+	organizationRepo := new(repositories.MockOrganizationRepository)
+	db := new(gorm.DB)
+	service := NewOrganizationService(organizationRepo, db)
+	ctx := tenant.WithID(context.Background(), 3)
+
+	organizationRepo.On("FindOrganizationById", ctx, db, 3).Return(&models.Organization{ID: 3, Name: "City Library", Slug: "city-library"}, nil)
+
+	result, err := service.FindCurrentOrganization(ctx)
+
+	assert.Nil(t, err)
+	assert.Equal(t, uint(3), result.ID)
+	organizationRepo.AssertExpectations(t)
+}
+
+func TestCreateInvite_Success(t *testing.T) {
+	organizationRepo := new(repositories.MockOrganizationRepository)
+	db := new(gorm.DB)
+	service := NewOrganizationService(organizationRepo, db)
+	ctx := tenant.WithID(context.Background(), 3)
+
+	organizationRepo.On("CreateInvite", ctx, db, mock.MatchedBy(func(invite *models.Invite) bool {
+		return invite.Token != "" && invite.ExpiresAt.After(time.Now())
+	})).Return(nil)
+
+	result, err := service.CreateInvite(ctx)
+
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(result.Token, "inv_"))
+	organizationRepo.AssertExpectations(t)
+}
@@ -0,0 +1,206 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"golang-backend-test/app/commons/response"
+	"golang-backend-test/app/models"
+	"golang-backend-test/app/params"
+	"golang-backend-test/app/repositories"
+	"golang-backend-test/pkg/tenant"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// EventRecorder records the events of a change in the transaction of the
+// change, so that they are kept exactly when the change is.
+type EventRecorder interface {
+	Record(ctx context.Context, tx *gorm.DB, event string, aggregateId uint, data interface{}) error
+}
+
+// inTransaction runs change in a transaction when there is a recorder to
+// keep its events, and on db as it is otherwise. Inside a transaction that
+// is already open, such as a batch, the change gets a savepoint.
+func inTransaction(db *gorm.DB, events EventRecorder, change func(tx *gorm.DB) error) error {
+	if events == nil {
+		return change(db)
+	}
+	return db.Transaction(change)
+}
+
+// record records an event when there is a recorder.
+func record(ctx context.Context, tx *gorm.DB, events EventRecorder, event string, aggregateId uint, data interface{}) error {
+	if events == nil {
+		return nil
+	}
+	return events.Record(ctx, tx, event, aggregateId, data)
+}
+
+// OutboxService keeps the events services record in the outbox table and
+// relays them to the sinks. Relaying is at least once: an event stays in
+// the outbox until every sink took it, and is relayed again after a
+// failure, so sinks see the same event id more than once. The events of an
+// aggregate are relayed in the order they were recorded; one that fails
+// holds up the later ones until it goes through.
+type OutboxService interface {
+	EventRecorder
+	Relay(ctx context.Context) *response.CustomError
+	Run(ctx context.Context, interval time.Duration)
+}
+
+type OutboxServiceImpl struct {
+	OutboxRepository       repositories.OutboxRepository
+	OrganizationRepository repositories.OrganizationRepository
+	Sinks                  []EventPublisher
+	// Backoff is how long a failed event waits, twice as long after each
+	// further failure up to maxOutboxBackoff
+	Backoff time.Duration
+	// Retention is how long published events are kept
+	Retention time.Duration
+	DB        *gorm.DB
+}
+
+const (
+	defaultOutboxBackoff   = time.Second
+	maxOutboxBackoff       = time.Hour
+	defaultOutboxRetention = 7 * 24 * time.Hour
+	outboxBatchSize        = 100
+)
+
+func NewOutboxService(outboxRepository repositories.OutboxRepository, organizationRepository repositories.OrganizationRepository, sinks []EventPublisher, db *gorm.DB) OutboxService {
+	return &OutboxServiceImpl{
+		OutboxRepository:       outboxRepository,
+		OrganizationRepository: organizationRepository,
+		Sinks:                  sinks,
+		Backoff:                defaultOutboxBackoff,
+		Retention:              defaultOutboxRetention,
+		DB:                     db,
+	}
+}
+
+// Record writes the event to the outbox with tx. The aggregate is the
+// entity the event is named after, book for book.created.
+func (service *OutboxServiceImpl) Record(ctx context.Context, tx *gorm.DB, event string, aggregateId uint, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	return service.OutboxRepository.CreateEvent(ctx, tx, &models.OutboxEvent{
+		Aggregate:     strings.SplitN(event, ".", 2)[0],
+		AggregateID:   aggregateId,
+		Event:         event,
+		Payload:       payload,
+		CreatedAt:     now,
+		NextAttemptAt: now,
+	})
+}
+
+// Relay hands the waiting events of every organization to the sinks.
+func (service *OutboxServiceImpl) Relay(ctx context.Context) *response.CustomError {
+	organizations, err := service.OrganizationRepository.GetListOrganizations(ctx, service.DB)
+	if err != nil {
+		return response.RepositoryError()
+	}
+	for _, organization := range organizations {
+		tenantCtx := tenant.WithID(ctx, organization.ID)
+		if custErr := service.relayTenant(tenantCtx); custErr != nil {
+			return custErr
+		}
+		if err := service.OutboxRepository.DeletePublishedEvents(tenantCtx, service.DB, time.Now().Add(-service.Retention)); err != nil {
+			return response.RepositoryError()
+		}
+	}
+	return nil
+}
+
+func (service *OutboxServiceImpl) relayTenant(ctx context.Context) *response.CustomError {
+	events, err := service.OutboxRepository.GetDueEvents(ctx, service.DB, time.Now(), outboxBatchSize)
+	if err != nil {
+		return response.RepositoryError()
+	}
+	// aggregates with an event that has to wait; their later events wait
+	// behind it
+	held := map[string]bool{}
+	for _, event := range events {
+		aggregate := event.Aggregate + "/" + strconv.Itoa(int(event.AggregateID))
+		if held[aggregate] {
+			continue
+		}
+		now := time.Now()
+		if event.NextAttemptAt.After(now) {
+			held[aggregate] = true
+			continue
+		}
+
+		event.Attempts++
+		if custErr := service.publish(ctx, event); custErr != nil {
+			held[aggregate] = true
+			lastError := custErr.Message
+			if custErr.AdditionalInfo != nil {
+				lastError += fmt.Sprintf(" %v", custErr.AdditionalInfo)
+			}
+			event.LastError = truncate(lastError, 1024)
+			event.NextAttemptAt = now.Add(service.backoff(event.Attempts))
+		} else {
+			event.PublishedAt = &now
+			event.LastError = ""
+		}
+		if err := service.OutboxRepository.UpdateEvent(ctx, service.DB, event); err != nil {
+			return response.RepositoryError()
+		}
+	}
+	return nil
+}
+
+// publish hands event to every sink, even after one fails, so that a sink
+// that is down does not keep the others waiting longer than it has to.
+func (service *OutboxServiceImpl) publish(ctx context.Context, event *models.OutboxEvent) *response.CustomError {
+	message := &params.EventMessage{
+		ID:          event.ID,
+		Event:       event.Event,
+		Aggregate:   event.Aggregate,
+		AggregateID: event.AggregateID,
+		CreatedAt:   event.CreatedAt,
+		Data:        event.Payload,
+	}
+	var failure *response.CustomError
+	for _, sink := range service.Sinks {
+		if custErr := sink.Publish(ctx, message); custErr != nil && failure == nil {
+			failure = custErr
+		}
+	}
+	return failure
+}
+
+func (service *OutboxServiceImpl) backoff(attempts int) time.Duration {
+	delay := service.Backoff
+	for i := 1; i < attempts && delay < maxOutboxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxOutboxBackoff {
+		delay = maxOutboxBackoff
+	}
+	return delay
+}
+
+// Run relays the outbox every interval until ctx is done.
+func (service *OutboxServiceImpl) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if custErr := service.Relay(ctx); custErr != nil {
+			log.Printf("relaying the outbox: %s %v", custErr.Message, custErr.AdditionalInfo)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"golang-backend-test/app/commons/response"
+	"golang-backend-test/app/models"
+	"golang-backend-test/app/params"
+	"golang-backend-test/app/repositories"
+	"golang-backend-test/pkg/tenant"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func newTestOutboxService() (*OutboxServiceImpl, *repositories.MockOutboxRepository, *MockEventPublisher, *gorm.DB) {
+	outboxRepo := new(repositories.MockOutboxRepository)
+	organizationRepo := new(repositories.MockOrganizationRepository)
+	sink := new(MockEventPublisher)
+	db := new(gorm.DB)
+	organizationRepo.On("GetListOrganizations", mock.Anything, db).Return([]*models.Organization{{ID: 1}}, nil)
+	outboxRepo.On("DeletePublishedEvents", mock.Anything, db, mock.AnythingOfType("time.Time")).Return(nil)
+	service := NewOutboxService(outboxRepo, organizationRepo, []EventPublisher{sink}, db).(*OutboxServiceImpl)
+	return service, outboxRepo, sink, db
+}
+
+func TestRecord_WritesEvent(t *testing.T) {
+	service, outboxRepo, _, db := newTestOutboxService()
+
+	var recorded *models.OutboxEvent
+	outboxRepo.On("CreateEvent", mock.Anything, db, mock.AnythingOfType("*models.OutboxEvent")).Run(func(args mock.Arguments) {
+		recorded = args.Get(2).(*models.OutboxEvent)
+	}).Return(nil)
+
+	err := service.Record(context.Background(), db, EventBookCreated, 4, &params.DeletedResponse{ID: 4})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "book", recorded.Aggregate)
+	assert.Equal(t, uint(4), recorded.AggregateID)
+	assert.Equal(t, EventBookCreated, recorded.Event)
+	assert.Equal(t, `{"id":4}`, string(recorded.Payload))
+	assert.Nil(t, recorded.PublishedAt)
+}
+
+func TestRelay_PublishesEvents(t *testing.T) {
+	service, outboxRepo, sink, db := newTestOutboxService()
+	event := &models.OutboxEvent{ID: 7, Aggregate: "book", AggregateID: 4, Event: EventBookCreated, Payload: []byte(`{"id":4}`)}
+
+	outboxRepo.On("GetDueEvents", mock.Anything, db, mock.AnythingOfType("time.Time"), outboxBatchSize).Return([]*models.OutboxEvent{event}, nil)
+	sink.On("Publish", mock.Anything, mock.MatchedBy(func(message *params.EventMessage) bool {
+		return message.ID == 7 && message.Aggregate == "book" && string(message.Data) == `{"id":4}`
+	})).Return(nil)
+	outboxRepo.On("UpdateEvent", mock.Anything, db, event).Return(nil)
+
+	err := service.Relay(context.Background())
+
+	assert.Nil(t, err)
+	assert.NotNil(t, event.PublishedAt)
+	assert.Equal(t, 1, event.Attempts)
+	sink.AssertExpectations(t)
+	outboxRepo.AssertExpectations(t)
+}
+
+func TestRelay_FailureHoldsAggregate(t *testing.T) {
+	service, outboxRepo, sink, db := newTestOutboxService()
+	failing := &models.OutboxEvent{ID: 1, Aggregate: "book", AggregateID: 4, Event: EventBookCreated}
+	later := &models.OutboxEvent{ID: 2, Aggregate: "book", AggregateID: 4, Event: EventBookUpdated}
+	other := &models.OutboxEvent{ID: 3, Aggregate: "book", AggregateID: 5, Event: EventBookCreated}
+
+	outboxRepo.On("GetDueEvents", mock.Anything, db, mock.AnythingOfType("time.Time"), outboxBatchSize).Return([]*models.OutboxEvent{failing, later, other}, nil)
+	sink.On("Publish", mock.Anything, mock.MatchedBy(func(message *params.EventMessage) bool {
+		return message.ID == 1
+	})).Return(response.RepositoryError())
+	sink.On("Publish", mock.Anything, mock.MatchedBy(func(message *params.EventMessage) bool {
+		return message.ID == 3
+	})).Return(nil)
+	outboxRepo.On("UpdateEvent", mock.Anything, db, mock.AnythingOfType("*models.OutboxEvent")).Return(nil)
+
+	before := time.Now()
+	err := service.Relay(context.Background())
+
+	assert.Nil(t, err)
+	assert.Nil(t, failing.PublishedAt)
+	assert.Equal(t, 1, failing.Attempts)
+	assert.Equal(t, "REPOSITORY ERROR", failing.LastError)
+	assert.False(t, failing.NextAttemptAt.Before(before.Add(service.Backoff)))
+	assert.Nil(t, later.PublishedAt)
+	assert.Equal(t, 0, later.Attempts)
+	assert.NotNil(t, other.PublishedAt)
+	sink.AssertNumberOfCalls(t, "Publish", 2)
+}
+
+func TestRelay_WaitsForNextAttempt(t *testing.T) {
+	service, outboxRepo, sink, db := newTestOutboxService()
+	waiting := &models.OutboxEvent{ID: 1, Aggregate: "author", AggregateID: 2, Attempts: 1, NextAttemptAt: time.Now().Add(time.Minute)}
+	later := &models.OutboxEvent{ID: 2, Aggregate: "author", AggregateID: 2}
+
+	outboxRepo.On("GetDueEvents", mock.Anything, db, mock.AnythingOfType("time.Time"), outboxBatchSize).Return([]*models.OutboxEvent{waiting, later}, nil)
+
+	err := service.Relay(context.Background())
+
+	assert.Nil(t, err)
+	sink.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+	outboxRepo.AssertNotCalled(t, "UpdateEvent", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRelay_SkipsAggregatesWaitingForRetry(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	assert.Nil(t, err)
+	assert.Nil(t, db.AutoMigrate(&models.Organization{}, &models.OutboxEvent{}))
+	assert.Nil(t, tenant.Register(db, &models.OutboxEvent{}))
+	assert.Nil(t, db.Create(&models.Organization{ID: 1, Name: "Acme", Slug: "acme"}).Error)
+	ctx := tenant.WithID(context.Background(), 1)
+
+	// a whole batch of events waits behind the first event of author 2
+	now := time.Now()
+	for i := 0; i <= outboxBatchSize; i++ {
+		event := &models.OutboxEvent{Aggregate: "author", AggregateID: 2, Event: EventAuthorUpdated, CreatedAt: now, NextAttemptAt: now}
+		if i == 0 {
+			event.Attempts = 1
+			event.NextAttemptAt = now.Add(time.Minute)
+		}
+		assert.Nil(t, db.WithContext(ctx).Create(event).Error)
+	}
+	other := &models.OutboxEvent{Aggregate: "book", AggregateID: 4, Event: EventBookCreated, CreatedAt: now, NextAttemptAt: now}
+	assert.Nil(t, db.WithContext(ctx).Create(other).Error)
+
+	sink := new(MockEventPublisher)
+	sink.On("Publish", mock.Anything, mock.MatchedBy(func(message *params.EventMessage) bool {
+		return message.ID == other.ID
+	})).Return(nil)
+	service := NewOutboxService(repositories.NewOutboxRepository(), repositories.NewOrganizationRepository(), []EventPublisher{sink}, db)
+
+	custErr := service.Relay(context.Background())
+
+	assert.Nil(t, custErr)
+	sink.AssertNumberOfCalls(t, "Publish", 1)
+	var published models.OutboxEvent
+	assert.Nil(t, db.WithContext(ctx).First(&published, other.ID).Error)
+	assert.NotNil(t, published.PublishedAt)
+}
+
+func TestOutboxBackoff_Capped(t *testing.T) {
+	service, _, _, _ := newTestOutboxService()
+
+	assert.Equal(t, time.Second, service.backoff(1))
+	assert.Equal(t, 4*time.Second, service.backoff(3))
+	assert.Equal(t, maxOutboxBackoff, service.backoff(40))
+}
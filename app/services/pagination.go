@@ -0,0 +1,112 @@
+package services
+
+import (
+	"fmt"
+	"golang-backend-test/app/commons/response"
+	"golang-backend-test/app/params"
+	"golang-backend-test/app/repositories"
+	"golang-backend-test/pkg/cursor"
+	"sort"
+	"strings"
+	"time"
+)
+
+// keysetSort is a sort order a list accepts. Cursors carry time sort keys
+// as text, so time tells to parse them back.
+type keysetSort struct {
+	order repositories.KeysetOrder
+	time  bool
+}
+
+type keysetSorts map[string]keysetSort
+
+// keysetRow is the position of a row in its list.
+type keysetRow struct {
+	value interface{}
+	id    uint
+}
+
+func (sorts keysetSorts) find(name string) (keysetSort, *response.CustomError) {
+	found, ok := sorts[name]
+	if !ok {
+		var names []string
+		for name := range sorts {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return keysetSort{}, response.BadRequestErrorWithAdditionalInfo("sort must be one of " + strings.Join(names, ", "))
+	}
+	return found, nil
+}
+
+// cursorOnly rejects a page number past the first on a list that pages by
+// cursor alone, rather than answer with the first page.
+func cursorOnly(req *params.PaginationRequest) *response.CustomError {
+	if req.Page > 1 && req.Cursor == "" {
+		return response.BadRequestErrorWithAdditionalInfo("page numbers are not supported, follow next_cursor instead")
+	}
+	return nil
+}
+
+// keysetPage reads the page the cursor of req points at. Without a cursor it
+// is the first page.
+func keysetPage(req *params.PaginationRequest, sort keysetSort) (repositories.KeysetPage, *response.CustomError) {
+	page := repositories.KeysetPage{Order: sort.order, Limit: req.Limit}
+	if req.Cursor == "" {
+		return page, nil
+	}
+
+	position, err := cursor.Decode(req.Cursor)
+	if err != nil {
+		return page, response.BadRequestErrorWithAdditionalInfo(err.Error())
+	}
+	if position.Sort != req.Sort {
+		return page, response.BadRequestErrorWithAdditionalInfo(fmt.Sprintf("cursor is for sort %s", position.Sort))
+	}
+	page.Value = position.Value
+	page.ID = position.ID
+	page.Before = position.Before
+	if value, ok := position.Value.(string); ok && sort.time {
+		parsed, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return page, response.BadRequestErrorWithAdditionalInfo(cursor.ErrInvalidCursor.Error())
+		}
+		page.Value = parsed
+	}
+	return page, nil
+}
+
+// keysetNeighbours tells whether pages follow and precede a page read for
+// page. more tells whether rows lie beyond the page in the direction it was
+// read.
+func keysetNeighbours(page repositories.KeysetPage, more bool) (bool, bool) {
+	if page.Before {
+		return true, more
+	}
+	return more, page.ID != 0
+}
+
+// pageCursors returns the cursors to the pages after and before rows.
+func pageCursors(sortName string, rows []keysetRow, hasNext, hasPrev bool) (string, string) {
+	if len(rows) == 0 {
+		return "", ""
+	}
+	var next, prev string
+	if hasNext {
+		last := rows[len(rows)-1]
+		next = cursor.Encode(cursor.Position{Sort: sortName, Value: last.value, ID: last.id})
+	}
+	if hasPrev {
+		first := rows[0]
+		prev = cursor.Encode(cursor.Position{Sort: sortName, Value: first.value, ID: first.id, Before: true})
+	}
+	return next, prev
+}
+
+// optionalTime keeps a missing time a nil sort key rather than a typed nil.
+func optionalTime(value *time.Time) interface{} {
+	if value == nil {
+		return nil
+	}
+	return *value
+}
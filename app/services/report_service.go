@@ -0,0 +1,191 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"golang-backend-test/app/commons/response"
+	"golang-backend-test/app/params"
+	"golang-backend-test/app/repositories"
+	"time"
+
+	"github.com/go-playground/validator"
+	"gorm.io/gorm"
+)
+
+type ReportService interface {
+	BooksPerAuthor(ctx context.Context, req *params.ReportRequest) ([]*params.BooksPerAuthorReport, *response.CustomError)
+	BooksWithoutISBN(ctx context.Context, req *params.ReportRequest) ([]*params.BookWithoutISBNReport, *response.CustomError)
+	AuthorsWithoutBooks(ctx context.Context, req *params.ReportRequest) ([]*params.AuthorWithoutBooksReport, *response.CustomError)
+	MostBorrowedBooks(ctx context.Context, req *params.ReportRequest) ([]*params.BorrowedBookReport, *response.CustomError)
+	LeastBorrowedBooks(ctx context.Context, req *params.ReportRequest) ([]*params.BorrowedBookReport, *response.CustomError)
+	AcquisitionsPerMonth(ctx context.Context, req *params.ReportRequest) ([]*params.AcquisitionReport, *response.CustomError)
+}
+
+type ReportServiceImpl struct {
+	ReportRepository repositories.ReportRepository
+	DB               *gorm.DB
+}
+
+func NewReportService(reportRepository repositories.ReportRepository, db *gorm.DB) ReportService {
+	return &ReportServiceImpl{
+		ReportRepository: reportRepository,
+		DB:               db,
+	}
+}
+
+// reportRangeEnd stands in for an open-ended "to" parameter.
+var reportRangeEnd = time.Date(9999, time.December, 31, 0, 0, 0, 0, time.UTC)
+
+func (service *ReportServiceImpl) BooksPerAuthor(ctx context.Context, req *params.ReportRequest) ([]*params.BooksPerAuthorReport, *response.CustomError) {
+	from, to, custErr := parseReportRange(req)
+	if custErr != nil {
+		return nil, custErr
+	}
+
+	rows, err := service.ReportRepository.BooksPerAuthor(ctx, service.DB, from, to)
+	if err != nil {
+		return nil, response.RepositoryError()
+	}
+	reports := []*params.BooksPerAuthorReport{}
+	for _, row := range rows {
+		reports = append(reports, &params.BooksPerAuthorReport{
+			AuthorID:   row.AuthorID,
+			AuthorName: row.AuthorName,
+			TotalBooks: row.TotalBooks,
+		})
+	}
+	return reports, nil
+}
+
+func (service *ReportServiceImpl) BooksWithoutISBN(ctx context.Context, req *params.ReportRequest) ([]*params.BookWithoutISBNReport, *response.CustomError) {
+	from, to, custErr := parseReportRange(req)
+	if custErr != nil {
+		return nil, custErr
+	}
+
+	books, err := service.ReportRepository.BooksWithoutISBN(ctx, service.DB, from, to)
+	if err != nil {
+		return nil, response.RepositoryError()
+	}
+	reports := []*params.BookWithoutISBNReport{}
+	for _, book := range books {
+		reports = append(reports, &params.BookWithoutISBNReport{
+			BookID:     book.ID,
+			Title:      book.Title,
+			AuthorID:   book.AuthorID,
+			AuthorName: book.Author.Name,
+			CreatedAt:  book.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	return reports, nil
+}
+
+func (service *ReportServiceImpl) AuthorsWithoutBooks(ctx context.Context, req *params.ReportRequest) ([]*params.AuthorWithoutBooksReport, *response.CustomError) {
+	from, to, custErr := parseReportRange(req)
+	if custErr != nil {
+		return nil, custErr
+	}
+
+	authors, err := service.ReportRepository.AuthorsWithoutBooks(ctx, service.DB, from, to)
+	if err != nil {
+		return nil, response.RepositoryError()
+	}
+	reports := []*params.AuthorWithoutBooksReport{}
+	for _, author := range authors {
+		reports = append(reports, &params.AuthorWithoutBooksReport{
+			AuthorID: author.ID,
+			Name:     author.Name,
+		})
+	}
+	return reports, nil
+}
+
+func (service *ReportServiceImpl) MostBorrowedBooks(ctx context.Context, req *params.ReportRequest) ([]*params.BorrowedBookReport, *response.CustomError) {
+	return service.borrowedBooks(ctx, req, false)
+}
+
+func (service *ReportServiceImpl) LeastBorrowedBooks(ctx context.Context, req *params.ReportRequest) ([]*params.BorrowedBookReport, *response.CustomError) {
+	return service.borrowedBooks(ctx, req, true)
+}
+
+func (service *ReportServiceImpl) borrowedBooks(ctx context.Context, req *params.ReportRequest, ascending bool) ([]*params.BorrowedBookReport, *response.CustomError) {
+	from, to, custErr := parseReportRange(req)
+	if custErr != nil {
+		return nil, custErr
+	}
+	if req.Limit == 0 {
+		req.Limit = 10
+	}
+
+	rows, err := service.ReportRepository.BorrowedBooks(ctx, service.DB, from, to, ascending, req.Limit)
+	if err != nil {
+		return nil, response.RepositoryError()
+	}
+	reports := []*params.BorrowedBookReport{}
+	for _, row := range rows {
+		reports = append(reports, &params.BorrowedBookReport{
+			BookID:     row.BookID,
+			Title:      row.Title,
+			ISBN:       row.ISBN,
+			TotalLoans: row.TotalLoans,
+		})
+	}
+	return reports, nil
+}
+
+func (service *ReportServiceImpl) AcquisitionsPerMonth(ctx context.Context, req *params.ReportRequest) ([]*params.AcquisitionReport, *response.CustomError) {
+	from, to, custErr := parseReportRange(req)
+	if custErr != nil {
+		return nil, custErr
+	}
+
+	rows, err := service.ReportRepository.AcquisitionsPerMonth(ctx, service.DB, from, to)
+	if err != nil {
+		return nil, response.RepositoryError()
+	}
+	reports := []*params.AcquisitionReport{}
+	for _, row := range rows {
+		reports = append(reports, &params.AcquisitionReport{
+			Month:       row.Month,
+			TotalCopies: row.TotalCopies,
+			TotalTitles: row.TotalTitles,
+		})
+	}
+	return reports, nil
+}
+
+// parseReportRange validates req and turns its inclusive from/to dates into a
+// half-open [from, to) range. Missing bounds leave the range open.
+func parseReportRange(req *params.ReportRequest) (time.Time, time.Time, *response.CustomError) {
+	val := validator.New()
+	err := val.Struct(req)
+	if err != nil {
+		validationErrors := err.(validator.ValidationErrors)
+		var errors []interface{}
+		for _, fieldError := range validationErrors {
+			error := "error " + fieldError.Field() + " on tag " + fieldError.Tag()
+			errors = append(errors, error)
+		}
+		return time.Time{}, time.Time{}, response.BadRequestErrorWithAdditionalInfo(errors)
+	}
+
+	from := time.Time{}
+	to := reportRangeEnd
+	if req.From != "" {
+		from, err = time.Parse("2006-01-02", req.From)
+		if err != nil {
+			return time.Time{}, time.Time{}, response.BadRequestErrorWithAdditionalInfo(fmt.Sprintf("Invalid date format: %s", err.Error()))
+		}
+	}
+	if req.To != "" {
+		to, err = time.Parse("2006-01-02", req.To)
+		if err != nil {
+			return time.Time{}, time.Time{}, response.BadRequestErrorWithAdditionalInfo(fmt.Sprintf("Invalid date format: %s", err.Error()))
+		}
+		to = to.AddDate(0, 0, 1)
+	}
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, response.BadRequestErrorWithAdditionalInfo("from must not be after to")
+	}
+	return from, to, nil
+}
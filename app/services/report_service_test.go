@@ -0,0 +1,147 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"golang-backend-test/app/models"
+	"golang-backend-test/app/params"
+	"golang-backend-test/app/repositories"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestBooksPerAuthor_Success(t *testing.T) {
+	reportRepo := new(repositories.MockReportRepository)
+	db := new(gorm.DB)
+	service := NewReportService(reportRepo, db)
+
+	from := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	reportRepo.On("BooksPerAuthor", mock.Anything, db, from, to).Return([]*repositories.BooksPerAuthorRow{
+		{AuthorID: 1, AuthorName: "Test Author", TotalBooks: 3},
+	}, nil)
+
+	result, err := service.BooksPerAuthor(context.Background(), &params.ReportRequest{From: "2024-01-01", To: "2024-12-31"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(result))
+	assert.Equal(t, "Test Author", result[0].AuthorName)
+	assert.Equal(t, int64(3), result[0].TotalBooks)
+	reportRepo.AssertExpectations(t)
+}
+
+func TestBooksPerAuthor_OpenRange(t *testing.T) {
+	reportRepo := new(repositories.MockReportRepository)
+	db := new(gorm.DB)
+	service := NewReportService(reportRepo, db)
+
+	reportRepo.On("BooksPerAuthor", mock.Anything, db, time.Time{}, reportRangeEnd).Return([]*repositories.BooksPerAuthorRow{}, nil)
+
+	result, err := service.BooksPerAuthor(context.Background(), &params.ReportRequest{})
+
+	assert.Nil(t, err)
+	assert.NotNil(t, result)
+	assert.Equal(t, 0, len(result))
+	reportRepo.AssertExpectations(t)
+}
+
+func TestBooksPerAuthor_InvalidRange(t *testing.T) {
+	reportRepo := new(repositories.MockReportRepository)
+	db := new(gorm.DB)
+	service := NewReportService(reportRepo, db)
+
+	result, err := service.BooksPerAuthor(context.Background(), &params.ReportRequest{From: "2025-01-01", To: "2024-01-01"})
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "BAD REQUEST ERROR", err.Message)
+}
+
+func TestBooksWithoutISBN_Success(t *testing.T) {
+	reportRepo := new(repositories.MockReportRepository)
+	db := new(gorm.DB)
+	service := NewReportService(reportRepo, db)
+
+	reportRepo.On("BooksWithoutISBN", mock.Anything, db, time.Time{}, reportRangeEnd).Return([]*models.Book{
+		{ID: 2, Title: "Untitled Draft", AuthorID: 1, Author: models.Author{ID: 1, Name: "Test Author"}},
+	}, nil)
+
+	result, err := service.BooksWithoutISBN(context.Background(), &params.ReportRequest{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(result))
+	assert.Equal(t, "Untitled Draft", result[0].Title)
+	assert.Equal(t, "Test Author", result[0].AuthorName)
+	reportRepo.AssertExpectations(t)
+}
+
+func TestAuthorsWithoutBooks_RepositoryError(t *testing.T) {
+	reportRepo := new(repositories.MockReportRepository)
+	db := new(gorm.DB)
+	service := NewReportService(reportRepo, db)
+
+	reportRepo.On("AuthorsWithoutBooks", mock.Anything, db, time.Time{}, reportRangeEnd).Return(nil, errors.New("db error"))
+
+	result, err := service.AuthorsWithoutBooks(context.Background(), &params.ReportRequest{})
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "REPOSITORY ERROR", err.Message)
+	reportRepo.AssertExpectations(t)
+}
+
+func TestMostBorrowedBooks_DefaultLimit(t *testing.T) {
+	reportRepo := new(repositories.MockReportRepository)
+	db := new(gorm.DB)
+	service := NewReportService(reportRepo, db)
+
+	reportRepo.On("BorrowedBooks", mock.Anything, db, time.Time{}, reportRangeEnd, false, 10).Return([]*repositories.BorrowedBookRow{
+		{BookID: 1, Title: "Popular", ISBN: "123456789", TotalLoans: 42},
+	}, nil)
+
+	result, err := service.MostBorrowedBooks(context.Background(), &params.ReportRequest{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(result))
+	assert.Equal(t, int64(42), result[0].TotalLoans)
+	reportRepo.AssertExpectations(t)
+}
+
+func TestLeastBorrowedBooks_Ascending(t *testing.T) {
+	reportRepo := new(repositories.MockReportRepository)
+	db := new(gorm.DB)
+	service := NewReportService(reportRepo, db)
+
+	reportRepo.On("BorrowedBooks", mock.Anything, db, time.Time{}, reportRangeEnd, true, 5).Return([]*repositories.BorrowedBookRow{
+		{BookID: 2, Title: "Forgotten", TotalLoans: 0},
+	}, nil)
+
+	result, err := service.LeastBorrowedBooks(context.Background(), &params.ReportRequest{Limit: 5})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(result))
+	assert.Equal(t, "Forgotten", result[0].Title)
+	reportRepo.AssertExpectations(t)
+}
+
+func TestAcquisitionsPerMonth_Success(t *testing.T) {
+	reportRepo := new(repositories.MockReportRepository)
+	db := new(gorm.DB)
+	service := NewReportService(reportRepo, db)
+
+	reportRepo.On("AcquisitionsPerMonth", mock.Anything, db, time.Time{}, reportRangeEnd).Return([]*repositories.AcquisitionRow{
+		{Month: "2024-05", TotalCopies: 12, TotalTitles: 4},
+	}, nil)
+
+	result, err := service.AcquisitionsPerMonth(context.Background(), &params.ReportRequest{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(result))
+	assert.Equal(t, "2024-05", result[0].Month)
+	assert.Equal(t, int64(12), result[0].TotalCopies)
+	reportRepo.AssertExpectations(t)
+}
@@ -0,0 +1,458 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"golang-backend-test/app/commons/response"
+	"golang-backend-test/app/models"
+	"golang-backend-test/app/params"
+	"golang-backend-test/app/repositories"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator"
+	"gorm.io/gorm"
+)
+
+type StocktakeService interface {
+	FindDetailStocktake(ctx context.Context, id int) (*params.StocktakeResponse, *response.CustomError)
+	OpenStocktake(ctx context.Context, userId int, req *params.StocktakeRequest) (*params.StocktakeResponse, *response.CustomError)
+	ScanStocktake(ctx context.Context, userId, id int, req *params.StocktakeScanRequest) (*params.StocktakeScanResponse, *response.CustomError)
+	CloseStocktake(ctx context.Context, userId, id int) (*params.StocktakeReportResponse, *response.CustomError)
+	FindStocktakeReport(ctx context.Context, id int) (*params.StocktakeReportResponse, *response.CustomError)
+	MarkMissingLost(ctx context.Context, userId, id int, req *params.StocktakeMarkLostRequest) (*params.StocktakeReportResponse, *response.CustomError)
+}
+
+type StocktakeServiceImpl struct {
+	StocktakeRepository repositories.StocktakeRepository
+	BookCopyRepository  repositories.BookCopyRepository
+	BranchRepository    repositories.BranchRepository
+	DB                  *gorm.DB
+}
+
+func NewStocktakeService(stocktakeRepository repositories.StocktakeRepository, bookCopyRepository repositories.BookCopyRepository, branchRepository repositories.BranchRepository, db *gorm.DB) StocktakeService {
+	return &StocktakeServiceImpl{
+		StocktakeRepository: stocktakeRepository,
+		BookCopyRepository:  bookCopyRepository,
+		BranchRepository:    branchRepository,
+		DB:                  db,
+	}
+}
+
+func (service *StocktakeServiceImpl) FindDetailStocktake(ctx context.Context, id int) (*params.StocktakeResponse, *response.CustomError) {
+	stocktake, err := service.StocktakeRepository.FindStocktakeById(ctx, service.DB, id)
+	if err != nil {
+		return nil, response.NotFoundError()
+	}
+	scanCount, err := service.StocktakeRepository.CountStocktakeScans(ctx, service.DB, id)
+	if err != nil {
+		return nil, response.RepositoryError()
+	}
+
+	return stocktakeResponse(stocktake, scanCount), nil
+}
+
+func (service *StocktakeServiceImpl) OpenStocktake(ctx context.Context, userId int, req *params.StocktakeRequest) (*params.StocktakeResponse, *response.CustomError) {
+	val := validator.New()
+	err := val.Struct(req)
+	if err != nil {
+		validationErrors := err.(validator.ValidationErrors)
+		var errors []interface{}
+		for _, fieldError := range validationErrors {
+			error := "error " + fieldError.Field() + " on tag " + fieldError.Tag()
+			errors = append(errors, error)
+		}
+		return nil, response.BadRequestErrorWithAdditionalInfo(errors)
+	}
+
+	branch, err := service.BranchRepository.FindBranchById(ctx, service.DB, int(req.BranchID))
+	if err != nil {
+		return nil, response.BadRequestErrorWithAdditionalInfo(err.Error())
+	}
+	var location *models.ShelfLocation
+	if req.ShelfLocationID != nil {
+		location, err = service.BranchRepository.FindShelfLocationById(ctx, service.DB, int(*req.ShelfLocationID))
+		if err != nil {
+			return nil, response.BadRequestErrorWithAdditionalInfo(err.Error())
+		}
+		if location.BranchID != branch.ID {
+			return nil, response.BadRequestErrorWithAdditionalInfo("shelf location is not at the branch")
+		}
+	}
+
+	// a shelf can only be counted by one session at a time
+	open, err := service.StocktakeRepository.GetOpenStocktakes(ctx, service.DB, int(branch.ID))
+	if err != nil {
+		return nil, response.RepositoryError()
+	}
+	for _, other := range open {
+		if other.ShelfLocationID == nil || req.ShelfLocationID == nil || *other.ShelfLocationID == *req.ShelfLocationID {
+			return nil, response.BadRequestErrorWithAdditionalInfo(fmt.Sprintf("stocktake %d is already open for this shelf", other.ID))
+		}
+	}
+
+	var stocktake = new(models.Stocktake)
+	stocktake.BranchID = branch.ID
+	stocktake.ShelfLocationID = req.ShelfLocationID
+	stocktake.Status = models.StocktakeOpen
+	stocktake.OpenedAt = time.Now()
+	stocktake.OpenedBy = uint(userId)
+	if err := service.StocktakeRepository.CreateStocktake(ctx, service.DB, stocktake); err != nil {
+		return nil, response.RepositoryError()
+	}
+	stocktake.Branch = *branch
+	stocktake.ShelfLocation = location
+
+	return stocktakeResponse(stocktake, 0), nil
+}
+
+func (service *StocktakeServiceImpl) ScanStocktake(ctx context.Context, userId, id int, req *params.StocktakeScanRequest) (*params.StocktakeScanResponse, *response.CustomError) {
+	val := validator.New()
+	err := val.Struct(req)
+	if err != nil {
+		validationErrors := err.(validator.ValidationErrors)
+		var errors []interface{}
+		for _, fieldError := range validationErrors {
+			error := "error " + fieldError.Field() + " on tag " + fieldError.Tag()
+			errors = append(errors, error)
+		}
+		return nil, response.BadRequestErrorWithAdditionalInfo(errors)
+	}
+
+	stocktake, err := service.StocktakeRepository.FindStocktakeById(ctx, service.DB, id)
+	if err != nil {
+		return nil, response.NotFoundError()
+	}
+	if stocktake.Status != models.StocktakeOpen {
+		return nil, response.BadRequestErrorWithAdditionalInfo("stocktake is closed")
+	}
+
+	// scans of a shelf stocktake are taken at that shelf; in a branch
+	// stocktake staff may say which shelf they are scanning
+	locationId := stocktake.ShelfLocationID
+	if req.ShelfLocationID != nil {
+		if stocktake.ShelfLocationID != nil && *req.ShelfLocationID != *stocktake.ShelfLocationID {
+			return nil, response.BadRequestErrorWithAdditionalInfo("stocktake is limited to another shelf")
+		}
+		location, err := service.BranchRepository.FindShelfLocationById(ctx, service.DB, int(*req.ShelfLocationID))
+		if err != nil {
+			return nil, response.BadRequestErrorWithAdditionalInfo(err.Error())
+		}
+		if location.BranchID != stocktake.BranchID {
+			return nil, response.BadRequestErrorWithAdditionalInfo("shelf location is not at the branch")
+		}
+		locationId = &location.ID
+	}
+
+	barcodes := req.Barcodes
+	if req.Barcode != "" {
+		barcodes = append(barcodes, req.Barcode)
+	}
+	now := time.Now()
+	var scans []*models.StocktakeScan
+	for _, barcode := range barcodes {
+		barcode = strings.TrimSpace(barcode)
+		if barcode == "" {
+			continue
+		}
+		scans = append(scans, &models.StocktakeScan{
+			StocktakeID:     stocktake.ID,
+			Barcode:         barcode,
+			ShelfLocationID: locationId,
+			ScannedAt:       now,
+			ScannedBy:       uint(userId),
+		})
+	}
+	if len(scans) == 0 {
+		return nil, response.BadRequestErrorWithAdditionalInfo("no barcodes scanned")
+	}
+	if err := service.StocktakeRepository.CreateStocktakeScans(ctx, service.DB, scans); err != nil {
+		return nil, response.RepositoryError()
+	}
+	scanCount, err := service.StocktakeRepository.CountStocktakeScans(ctx, service.DB, id)
+	if err != nil {
+		return nil, response.RepositoryError()
+	}
+
+	return &params.StocktakeScanResponse{Accepted: len(scans), ScanCount: scanCount}, nil
+}
+
+func (service *StocktakeServiceImpl) CloseStocktake(ctx context.Context, userId, id int) (*params.StocktakeReportResponse, *response.CustomError) {
+	stocktake, err := service.StocktakeRepository.FindStocktakeById(ctx, service.DB, id)
+	if err != nil {
+		return nil, response.NotFoundError()
+	}
+	if stocktake.Status != models.StocktakeOpen {
+		return nil, response.BadRequestErrorWithAdditionalInfo("stocktake is closed")
+	}
+
+	shelfLocationId := 0
+	if stocktake.ShelfLocationID != nil {
+		shelfLocationId = int(*stocktake.ShelfLocationID)
+	}
+	expected, err := service.BookCopyRepository.GetShelvedCopies(ctx, service.DB, int(stocktake.BranchID), shelfLocationId)
+	if err != nil {
+		return nil, response.RepositoryError()
+	}
+	scans, err := service.StocktakeRepository.GetStocktakeScans(ctx, service.DB, id)
+	if err != nil {
+		return nil, response.RepositoryError()
+	}
+	var barcodes []string
+	for _, scan := range scans {
+		barcodes = append(barcodes, scan.Barcode)
+	}
+	scanned := make(map[string]*models.BookCopy)
+	if len(barcodes) > 0 {
+		copies, err := service.BookCopyRepository.FindBookCopiesByBarcodes(ctx, service.DB, barcodes)
+		if err != nil {
+			return nil, response.RepositoryError()
+		}
+		for _, bookCopy := range copies {
+			scanned[bookCopy.Barcode] = bookCopy
+		}
+	}
+
+	discrepancies, found := stocktakeDiscrepancies(stocktake, expected, scans, scanned)
+	now := time.Now()
+	by := uint(userId)
+	stocktake.Status = models.StocktakeClosed
+	stocktake.ExpectedCount = len(expected)
+	stocktake.FoundCount = found
+	stocktake.ClosedAt = &now
+	stocktake.ClosedBy = &by
+	var custErr *response.CustomError
+	err = service.DB.Transaction(func(tx *gorm.DB) error {
+		// closing only an open stocktake makes a concurrent close fail here
+		// rather than record a second set of discrepancies
+		if err := service.StocktakeRepository.UpdateStocktake(ctx, tx, stocktake, models.StocktakeOpen); err != nil {
+			custErr = response.BadRequestErrorWithAdditionalInfo("stocktake is closed")
+			return err
+		}
+		return service.StocktakeRepository.CreateStocktakeDiscrepancies(ctx, tx, discrepancies)
+	})
+	if custErr != nil {
+		return nil, custErr
+	}
+	if err != nil {
+		return nil, response.RepositoryErrorWithAdditionalInfo(err.Error())
+	}
+
+	return service.stocktakeReport(ctx, stocktake, int64(len(scans)))
+}
+
+func (service *StocktakeServiceImpl) FindStocktakeReport(ctx context.Context, id int) (*params.StocktakeReportResponse, *response.CustomError) {
+	stocktake, err := service.StocktakeRepository.FindStocktakeById(ctx, service.DB, id)
+	if err != nil {
+		return nil, response.NotFoundError()
+	}
+	if stocktake.Status != models.StocktakeClosed {
+		return nil, response.BadRequestErrorWithAdditionalInfo("stocktake is still open")
+	}
+	scanCount, err := service.StocktakeRepository.CountStocktakeScans(ctx, service.DB, id)
+	if err != nil {
+		return nil, response.RepositoryError()
+	}
+
+	return service.stocktakeReport(ctx, stocktake, scanCount)
+}
+
+func (service *StocktakeServiceImpl) MarkMissingLost(ctx context.Context, userId, id int, req *params.StocktakeMarkLostRequest) (*params.StocktakeReportResponse, *response.CustomError) {
+	stocktake, err := service.StocktakeRepository.FindStocktakeById(ctx, service.DB, id)
+	if err != nil {
+		return nil, response.NotFoundError()
+	}
+	if stocktake.Status != models.StocktakeClosed {
+		return nil, response.BadRequestErrorWithAdditionalInfo("stocktake is still open")
+	}
+	discrepancies, err := service.StocktakeRepository.GetStocktakeDiscrepancies(ctx, service.DB, id)
+	if err != nil {
+		return nil, response.RepositoryError()
+	}
+
+	missing := make(map[uint]*models.StocktakeDiscrepancy)
+	for _, discrepancy := range discrepancies {
+		if discrepancy.Kind == models.DiscrepancyMissing && discrepancy.BookCopy != nil {
+			missing[discrepancy.BookCopy.ID] = discrepancy
+		}
+	}
+	// without a selection every missing copy is marked
+	selected := missing
+	if len(req.BookCopyIDs) > 0 {
+		selected = make(map[uint]*models.StocktakeDiscrepancy)
+		for _, bookCopyId := range req.BookCopyIDs {
+			discrepancy, ok := missing[bookCopyId]
+			if !ok {
+				return nil, response.BadRequestErrorWithAdditionalInfo(fmt.Sprintf("book copy %d is not missing in this stocktake", bookCopyId))
+			}
+			selected[bookCopyId] = discrepancy
+		}
+	}
+
+	now := time.Now()
+	by := uint(userId)
+	err = service.DB.Transaction(func(tx *gorm.DB) error {
+		for _, discrepancy := range selected {
+			if discrepancy.MarkedLostAt != nil {
+				continue
+			}
+			// a copy that turned up or went out on loan since the count is
+			// left alone, which only the row as it is now can tell
+			marked, err := service.BookCopyRepository.MarkCopyLost(ctx, tx, int(discrepancy.BookCopy.ID))
+			if err != nil {
+				return err
+			}
+			bookCopy, err := service.BookCopyRepository.FindBookCopyById(ctx, tx, int(discrepancy.BookCopy.ID))
+			if err != nil {
+				return err
+			}
+			discrepancy.BookCopy = bookCopy
+			if !marked {
+				continue
+			}
+			discrepancy.MarkedLostAt = &now
+			discrepancy.MarkedLostBy = &by
+			if err := service.StocktakeRepository.UpdateStocktakeDiscrepancy(ctx, tx, discrepancy); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, response.RepositoryErrorWithAdditionalInfo(err.Error())
+	}
+	scanCount, err := service.StocktakeRepository.CountStocktakeScans(ctx, service.DB, id)
+	if err != nil {
+		return nil, response.RepositoryError()
+	}
+
+	return stocktakeReportResponse(stocktake, scanCount, discrepancies), nil
+}
+
+func (service *StocktakeServiceImpl) stocktakeReport(ctx context.Context, stocktake *models.Stocktake, scanCount int64) (*params.StocktakeReportResponse, *response.CustomError) {
+	discrepancies, err := service.StocktakeRepository.GetStocktakeDiscrepancies(ctx, service.DB, int(stocktake.ID))
+	if err != nil {
+		return nil, response.RepositoryError()
+	}
+	return stocktakeReportResponse(stocktake, scanCount, discrepancies), nil
+}
+
+// stocktakeDiscrepancies compares the scans of a stocktake with the copies
+// expected on its shelves. It returns the findings and the number of
+// expected copies that were scanned. A barcode scanned twice counts once,
+// at the shelf where it was first scanned.
+func stocktakeDiscrepancies(stocktake *models.Stocktake, expected []*models.BookCopy, scans []*models.StocktakeScan, scanned map[string]*models.BookCopy) ([]*models.StocktakeDiscrepancy, int) {
+	expectedById := make(map[uint]*models.BookCopy)
+	for _, bookCopy := range expected {
+		expectedById[bookCopy.ID] = bookCopy
+	}
+
+	var discrepancies []*models.StocktakeDiscrepancy
+	seen := make(map[string]bool)
+	found := make(map[uint]bool)
+	for _, scan := range scans {
+		if seen[scan.Barcode] {
+			continue
+		}
+		seen[scan.Barcode] = true
+
+		discrepancy := &models.StocktakeDiscrepancy{
+			StocktakeID:     stocktake.ID,
+			Kind:            models.DiscrepancyUnexpected,
+			Barcode:         scan.Barcode,
+			FoundLocationID: scan.ShelfLocationID,
+		}
+		bookCopy, ok := scanned[scan.Barcode]
+		switch {
+		case !ok:
+			discrepancy.Reason = "unknown barcode"
+		case bookCopy.ShelfLocation == nil:
+			discrepancy.Reason = "copy has no shelf location"
+		case bookCopy.ShelfLocation.BranchID != stocktake.BranchID:
+			discrepancy.Reason = "copy belongs to another branch"
+		case expectedById[bookCopy.ID] != nil:
+			found[bookCopy.ID] = true
+			if scan.ShelfLocationID == nil || *scan.ShelfLocationID == *bookCopy.ShelfLocationID {
+				continue
+			}
+			discrepancy.Kind = models.DiscrepancyWrongShelf
+		case stocktake.ShelfLocationID != nil && *bookCopy.ShelfLocationID != *stocktake.ShelfLocationID:
+			discrepancy.Kind = models.DiscrepancyWrongShelf
+		case bookCopy.Status != models.CopyAvailable:
+			discrepancy.Reason = "copy is " + strings.ReplaceAll(bookCopy.Status, "_", " ")
+		default:
+			discrepancy.Reason = "copy is on loan"
+		}
+		if ok {
+			discrepancy.BookCopyID = &bookCopy.ID
+			discrepancy.ExpectedLocationID = bookCopy.ShelfLocationID
+		}
+		discrepancies = append(discrepancies, discrepancy)
+	}
+
+	for _, bookCopy := range expected {
+		if found[bookCopy.ID] {
+			continue
+		}
+		discrepancies = append(discrepancies, &models.StocktakeDiscrepancy{
+			StocktakeID:        stocktake.ID,
+			Kind:               models.DiscrepancyMissing,
+			Barcode:            bookCopy.Barcode,
+			BookCopyID:         &bookCopy.ID,
+			ExpectedLocationID: bookCopy.ShelfLocationID,
+		})
+	}
+	return discrepancies, len(found)
+}
+
+func stocktakeResponse(stocktake *models.Stocktake, scanCount int64) *params.StocktakeResponse {
+	result := &params.StocktakeResponse{
+		ID:        stocktake.ID,
+		Status:    stocktake.Status,
+		Branch:    branchResponse(&stocktake.Branch),
+		ScanCount: scanCount,
+		Opened:    &params.StepResponse{At: stocktake.OpenedAt.Format(time.RFC3339), By: stocktake.OpenedBy},
+		Closed:    stepResponse(stocktake.ClosedAt, stocktake.ClosedBy),
+	}
+	if stocktake.ShelfLocation != nil {
+		result.ShelfLocation = shelfLocationResponse(stocktake.ShelfLocation)
+	}
+	return result
+}
+
+func stocktakeReportResponse(stocktake *models.Stocktake, scanCount int64, discrepancies []*models.StocktakeDiscrepancy) *params.StocktakeReportResponse {
+	result := &params.StocktakeReportResponse{
+		Stocktake:     stocktakeResponse(stocktake, scanCount),
+		ExpectedCount: stocktake.ExpectedCount,
+		FoundCount:    stocktake.FoundCount,
+		Missing:       []*params.StocktakeDiscrepancyResponse{},
+		Unexpected:    []*params.StocktakeDiscrepancyResponse{},
+		WrongShelf:    []*params.StocktakeDiscrepancyResponse{},
+	}
+	for _, discrepancy := range discrepancies {
+		item := &params.StocktakeDiscrepancyResponse{
+			ID:         discrepancy.ID,
+			Barcode:    discrepancy.Barcode,
+			Reason:     discrepancy.Reason,
+			MarkedLost: stepResponse(discrepancy.MarkedLostAt, discrepancy.MarkedLostBy),
+		}
+		if discrepancy.BookCopy != nil {
+			item.BookCopy = bookCopyResponse(discrepancy.BookCopy)
+		}
+		if discrepancy.ExpectedLocation != nil {
+			item.ExpectedLocation = shelfLocationResponse(discrepancy.ExpectedLocation)
+		}
+		if discrepancy.FoundLocation != nil {
+			item.FoundLocation = shelfLocationResponse(discrepancy.FoundLocation)
+		}
+		switch discrepancy.Kind {
+		case models.DiscrepancyMissing:
+			result.Missing = append(result.Missing, item)
+		case models.DiscrepancyUnexpected:
+			result.Unexpected = append(result.Unexpected, item)
+		case models.DiscrepancyWrongShelf:
+			result.WrongShelf = append(result.WrongShelf, item)
+		}
+	}
+	return result
+}
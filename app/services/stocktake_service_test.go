@@ -0,0 +1,270 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"golang-backend-test/app/models"
+	"golang-backend-test/app/params"
+	"golang-backend-test/app/repositories"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func newTestStocktakeCopy(id uint, barcode string, branchID, locationID uint) *models.BookCopy {
+	return &models.BookCopy{
+		ID:              id,
+		Barcode:         barcode,
+		ShelfLocationID: &locationID,
+		ShelfLocation:   &models.ShelfLocation{ID: locationID, BranchID: branchID},
+		Status:          models.CopyAvailable,
+	}
+}
+
+func newTestStocktakeScan(barcode string, locationID uint) *models.StocktakeScan {
+	return &models.StocktakeScan{StocktakeID: 1, Barcode: barcode, ShelfLocationID: &locationID}
+}
+
+func TestStocktakeDiscrepancies(t *testing.T) {
+	shelfID := uint(1)
+	stocktake := &models.Stocktake{ID: 1, BranchID: 1, ShelfLocationID: &shelfID, Status: models.StocktakeOpen}
+	onShelf := newTestStocktakeCopy(1, "C1", 1, 1)
+	missing := newTestStocktakeCopy(2, "C2", 1, 1)
+	otherShelf := newTestStocktakeCopy(3, "C3", 1, 2)
+	otherBranch := newTestStocktakeCopy(4, "C4", 2, 3)
+	lost := newTestStocktakeCopy(5, "C5", 1, 1)
+	lost.Status = models.CopyLost
+	scanned := map[string]*models.BookCopy{"C1": onShelf, "C3": otherShelf, "C4": otherBranch, "C5": lost}
+	scans := []*models.StocktakeScan{
+		newTestStocktakeScan("C1", 1),
+		newTestStocktakeScan("C3", 1),
+		newTestStocktakeScan("C4", 1),
+		newTestStocktakeScan("C5", 1),
+		newTestStocktakeScan("UNKNOWN", 1),
+		newTestStocktakeScan("C1", 1),
+	}
+
+	discrepancies, found := stocktakeDiscrepancies(stocktake, []*models.BookCopy{onShelf, missing}, scans, scanned)
+
+	assert.Equal(t, 1, found)
+	var kinds, reasons []string
+	for _, discrepancy := range discrepancies {
+		kinds = append(kinds, discrepancy.Barcode+":"+discrepancy.Kind)
+		reasons = append(reasons, discrepancy.Reason)
+	}
+	assert.Equal(t, []string{"C3:wrong_shelf", "C4:unexpected", "C5:unexpected", "UNKNOWN:unexpected", "C2:missing"}, kinds)
+	assert.Equal(t, []string{"", "copy belongs to another branch", "copy is lost", "unknown barcode", ""}, reasons)
+	assert.Equal(t, uint(2), *discrepancies[0].ExpectedLocationID)
+	assert.Equal(t, uint(1), *discrepancies[0].FoundLocationID)
+}
+
+func TestStocktakeDiscrepancies_BranchWrongShelf(t *testing.T) {
+	stocktake := &models.Stocktake{ID: 1, BranchID: 1, Status: models.StocktakeOpen}
+	bookCopy := newTestStocktakeCopy(1, "C1", 1, 1)
+	scans := []*models.StocktakeScan{newTestStocktakeScan("C1", 2)}
+
+	discrepancies, found := stocktakeDiscrepancies(stocktake, []*models.BookCopy{bookCopy}, scans, map[string]*models.BookCopy{"C1": bookCopy})
+
+	assert.Equal(t, 1, found)
+	assert.Len(t, discrepancies, 1)
+	assert.Equal(t, models.DiscrepancyWrongShelf, discrepancies[0].Kind)
+}
+
+func TestOpenStocktake_ShelfAlreadyOpen(t *testing.T) {
+	stocktakeRepo := new(repositories.MockStocktakeRepository)
+	bookCopyRepo := new(repositories.MockBookCopyRepository)
+	branchRepo := new(repositories.MockBranchRepository)
+	db := new(gorm.DB)
+	shelfID := uint(1)
+
+	branchRepo.On("FindBranchById", mock.Anything, db, 1).Return(&models.Branch{ID: 1, Code: "MAIN"}, nil)
+	branchRepo.On("FindShelfLocationById", mock.Anything, db, 1).Return(&models.ShelfLocation{ID: 1, BranchID: 1}, nil)
+	stocktakeRepo.On("GetOpenStocktakes", mock.Anything, db, 1).Return([]*models.Stocktake{{ID: 4, BranchID: 1}}, nil)
+	service := NewStocktakeService(stocktakeRepo, bookCopyRepo, branchRepo, db)
+
+	result, err := service.OpenStocktake(context.Background(), 7, &params.StocktakeRequest{BranchID: 1, ShelfLocationID: &shelfID})
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "stocktake 4 is already open for this shelf", err.AdditionalInfo)
+	stocktakeRepo.AssertNotCalled(t, "CreateStocktake", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestScanStocktake_Batch(t *testing.T) {
+	stocktakeRepo := new(repositories.MockStocktakeRepository)
+	bookCopyRepo := new(repositories.MockBookCopyRepository)
+	branchRepo := new(repositories.MockBranchRepository)
+	db := new(gorm.DB)
+	shelfID := uint(1)
+
+	stocktakeRepo.On("FindStocktakeById", mock.Anything, db, 1).Return(&models.Stocktake{ID: 1, BranchID: 1, ShelfLocationID: &shelfID, Status: models.StocktakeOpen}, nil)
+	stocktakeRepo.On("CreateStocktakeScans", mock.Anything, db, mock.MatchedBy(func(scans []*models.StocktakeScan) bool {
+		return len(scans) == 2 && scans[0].Barcode == "C1" && *scans[1].ShelfLocationID == shelfID
+	})).Return(nil)
+	stocktakeRepo.On("CountStocktakeScans", mock.Anything, db, 1).Return(int64(5), nil)
+	service := NewStocktakeService(stocktakeRepo, bookCopyRepo, branchRepo, db)
+
+	result, err := service.ScanStocktake(context.Background(), 7, 1, &params.StocktakeScanRequest{Barcodes: []string{" C1 ", "C2"}})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, result.Accepted)
+	assert.Equal(t, int64(5), result.ScanCount)
+	stocktakeRepo.AssertExpectations(t)
+}
+
+func TestScanStocktake_Closed(t *testing.T) {
+	stocktakeRepo := new(repositories.MockStocktakeRepository)
+	bookCopyRepo := new(repositories.MockBookCopyRepository)
+	branchRepo := new(repositories.MockBranchRepository)
+	db := new(gorm.DB)
+
+	stocktakeRepo.On("FindStocktakeById", mock.Anything, db, 1).Return(&models.Stocktake{ID: 1, BranchID: 1, Status: models.StocktakeClosed}, nil)
+	service := NewStocktakeService(stocktakeRepo, bookCopyRepo, branchRepo, db)
+
+	result, err := service.ScanStocktake(context.Background(), 7, 1, &params.StocktakeScanRequest{Barcode: "C1"})
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "stocktake is closed", err.AdditionalInfo)
+}
+
+func TestCloseStocktake_Success(t *testing.T) {
+	stocktakeRepo := new(repositories.MockStocktakeRepository)
+	bookCopyRepo := new(repositories.MockBookCopyRepository)
+	branchRepo := new(repositories.MockBranchRepository)
+	db, errDB := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	assert.Nil(t, errDB)
+	stocktake := &models.Stocktake{ID: 1, BranchID: 1, Status: models.StocktakeOpen, OpenedAt: time.Now(), OpenedBy: 7}
+	found := newTestStocktakeCopy(1, "C1", 1, 1)
+	missing := newTestStocktakeCopy(2, "C2", 1, 1)
+
+	stocktakeRepo.On("FindStocktakeById", mock.Anything, db, 1).Return(stocktake, nil)
+	bookCopyRepo.On("GetShelvedCopies", mock.Anything, db, 1, 0).Return([]*models.BookCopy{found, missing}, nil)
+	stocktakeRepo.On("GetStocktakeScans", mock.Anything, db, 1).Return([]*models.StocktakeScan{{StocktakeID: 1, Barcode: "C1"}}, nil)
+	bookCopyRepo.On("FindBookCopiesByBarcodes", mock.Anything, db, []string{"C1"}).Return([]*models.BookCopy{found}, nil)
+	stocktakeRepo.On("CreateStocktakeDiscrepancies", mock.Anything, mock.Anything, mock.MatchedBy(func(discrepancies []*models.StocktakeDiscrepancy) bool {
+		return len(discrepancies) == 1 && discrepancies[0].Kind == models.DiscrepancyMissing && *discrepancies[0].BookCopyID == 2
+	})).Return(nil)
+	stocktakeRepo.On("UpdateStocktake", mock.Anything, mock.Anything, stocktake, models.StocktakeOpen).Return(nil)
+	stocktakeRepo.On("GetStocktakeDiscrepancies", mock.Anything, db, 1).Return([]*models.StocktakeDiscrepancy{
+		{ID: 1, StocktakeID: 1, Kind: models.DiscrepancyMissing, Barcode: "C2", BookCopy: missing},
+	}, nil)
+	service := NewStocktakeService(stocktakeRepo, bookCopyRepo, branchRepo, db)
+
+	result, err := service.CloseStocktake(context.Background(), 8, 1)
+
+	assert.Nil(t, err)
+	assert.Equal(t, models.StocktakeClosed, result.Stocktake.Status)
+	assert.Equal(t, uint(8), result.Stocktake.Closed.By)
+	assert.Equal(t, 2, result.ExpectedCount)
+	assert.Equal(t, 1, result.FoundCount)
+	assert.Len(t, result.Missing, 1)
+	assert.Empty(t, result.Unexpected)
+	stocktakeRepo.AssertExpectations(t)
+	bookCopyRepo.AssertExpectations(t)
+}
+
+func TestCloseStocktake_ClosedMeanwhile(t *testing.T) {
+	stocktakeRepo := new(repositories.MockStocktakeRepository)
+	bookCopyRepo := new(repositories.MockBookCopyRepository)
+	branchRepo := new(repositories.MockBranchRepository)
+	db, errDB := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	assert.Nil(t, errDB)
+	stocktake := &models.Stocktake{ID: 1, BranchID: 1, Status: models.StocktakeOpen, OpenedAt: time.Now(), OpenedBy: 7}
+
+	// another request closed the stocktake after it was read
+	stocktakeRepo.On("FindStocktakeById", mock.Anything, db, 1).Return(stocktake, nil)
+	bookCopyRepo.On("GetShelvedCopies", mock.Anything, db, 1, 0).Return([]*models.BookCopy{}, nil)
+	stocktakeRepo.On("GetStocktakeScans", mock.Anything, db, 1).Return([]*models.StocktakeScan{}, nil)
+	stocktakeRepo.On("UpdateStocktake", mock.Anything, mock.Anything, stocktake, models.StocktakeOpen).Return(errors.New("stocktake not found"))
+	service := NewStocktakeService(stocktakeRepo, bookCopyRepo, branchRepo, db)
+
+	result, err := service.CloseStocktake(context.Background(), 8, 1)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "stocktake is closed", err.AdditionalInfo)
+	stocktakeRepo.AssertNotCalled(t, "CreateStocktakeDiscrepancies", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMarkMissingLost_NotMissing(t *testing.T) {
+	stocktakeRepo := new(repositories.MockStocktakeRepository)
+	bookCopyRepo := new(repositories.MockBookCopyRepository)
+	branchRepo := new(repositories.MockBranchRepository)
+	db := new(gorm.DB)
+
+	stocktakeRepo.On("FindStocktakeById", mock.Anything, db, 1).Return(&models.Stocktake{ID: 1, BranchID: 1, Status: models.StocktakeClosed}, nil)
+	stocktakeRepo.On("GetStocktakeDiscrepancies", mock.Anything, db, 1).Return([]*models.StocktakeDiscrepancy{
+		{ID: 1, StocktakeID: 1, Kind: models.DiscrepancyMissing, Barcode: "C2", BookCopy: newTestStocktakeCopy(2, "C2", 1, 1)},
+	}, nil)
+	service := NewStocktakeService(stocktakeRepo, bookCopyRepo, branchRepo, db)
+
+	result, err := service.MarkMissingLost(context.Background(), 7, 1, &params.StocktakeMarkLostRequest{BookCopyIDs: []uint{1}})
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "book copy 1 is not missing in this stocktake", err.AdditionalInfo)
+	bookCopyRepo.AssertNotCalled(t, "MarkCopyLost", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMarkMissingLost_All(t *testing.T) {
+	stocktakeRepo := new(repositories.MockStocktakeRepository)
+	bookCopyRepo := new(repositories.MockBookCopyRepository)
+	branchRepo := new(repositories.MockBranchRepository)
+	db, errDB := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	assert.Nil(t, errDB)
+	missing := newTestStocktakeCopy(2, "C2", 1, 1)
+	discrepancy := &models.StocktakeDiscrepancy{ID: 1, StocktakeID: 1, Kind: models.DiscrepancyMissing, Barcode: "C2", BookCopy: missing}
+
+	stocktakeRepo.On("FindStocktakeById", mock.Anything, db, 1).Return(&models.Stocktake{ID: 1, BranchID: 1, Status: models.StocktakeClosed}, nil)
+	stocktakeRepo.On("GetStocktakeDiscrepancies", mock.Anything, db, 1).Return([]*models.StocktakeDiscrepancy{discrepancy}, nil)
+	lost := newTestStocktakeCopy(2, "C2", 1, 1)
+	lost.Status = models.CopyLost
+	bookCopyRepo.On("MarkCopyLost", mock.Anything, mock.Anything, 2).Return(true, nil)
+	bookCopyRepo.On("FindBookCopyById", mock.Anything, mock.Anything, 2).Return(lost, nil)
+	stocktakeRepo.On("UpdateStocktakeDiscrepancy", mock.Anything, mock.Anything, discrepancy).Return(nil)
+	stocktakeRepo.On("CountStocktakeScans", mock.Anything, db, 1).Return(int64(3), nil)
+	service := NewStocktakeService(stocktakeRepo, bookCopyRepo, branchRepo, db)
+
+	result, err := service.MarkMissingLost(context.Background(), 7, 1, &params.StocktakeMarkLostRequest{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, models.CopyLost, discrepancy.BookCopy.Status)
+	assert.Equal(t, uint(7), result.Missing[0].MarkedLost.By)
+	assert.False(t, result.Missing[0].BookCopy.Available)
+	bookCopyRepo.AssertExpectations(t)
+	stocktakeRepo.AssertExpectations(t)
+}
+
+func TestMarkMissingLost_SkipsCopiesOnLoan(t *testing.T) {
+	db, acme, _ := newTenantTestDB(t)
+	onLoan := &models.BookCopy{BookID: 1, Barcode: "C1", Status: models.CopyAvailable}
+	missing := &models.BookCopy{BookID: 1, Barcode: "C2", Status: models.CopyAvailable}
+	assert.Nil(t, db.WithContext(acme).Create(onLoan).Error)
+	assert.Nil(t, db.WithContext(acme).Create(missing).Error)
+	stocktake := &models.Stocktake{BranchID: 1, Status: models.StocktakeClosed}
+	assert.Nil(t, db.WithContext(acme).Create(stocktake).Error)
+	for _, bookCopy := range []*models.BookCopy{onLoan, missing} {
+		bookCopyId := bookCopy.ID
+		assert.Nil(t, db.WithContext(acme).Create(&models.StocktakeDiscrepancy{StocktakeID: stocktake.ID, Kind: models.DiscrepancyMissing, Barcode: bookCopy.Barcode, BookCopyID: &bookCopyId}).Error)
+	}
+	// the copy went out on loan after the count
+	assert.Nil(t, db.WithContext(acme).Create(&models.Loan{BookCopyID: onLoan.ID, UserID: 1, BorrowedAt: time.Now(), DueAt: time.Now().AddDate(0, 0, 14)}).Error)
+	service := NewStocktakeService(repositories.NewStocktakeRepository(), repositories.NewBookCopyRepository(), repositories.NewBranchRepository(), db)
+
+	result, err := service.MarkMissingLost(acme, 7, int(stocktake.ID), &params.StocktakeMarkLostRequest{})
+
+	assert.Nil(t, err)
+	var statuses []string
+	for _, discrepancy := range result.Missing {
+		statuses = append(statuses, discrepancy.BookCopy.Barcode+":"+discrepancy.BookCopy.Status)
+	}
+	assert.ElementsMatch(t, []string{"C1:available", "C2:lost"}, statuses)
+	var stored models.BookCopy
+	assert.Nil(t, db.WithContext(acme).First(&stored, onLoan.ID).Error)
+	assert.Equal(t, models.CopyAvailable, stored.Status)
+}
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"context"
+	"golang-backend-test/app/commons/response"
+	"golang-backend-test/app/models"
+	"golang-backend-test/app/params"
+	"golang-backend-test/app/repositories"
+	"golang-backend-test/pkg/pubsub"
+	"golang-backend-test/pkg/tenant"
+	"strconv"
+	"sync"
+
+	"github.com/go-playground/validator"
+	"gorm.io/gorm"
+)
+
+// StreamService sends the changes to books and authors to the clients of
+// the event stream as they happen. It keeps the newest changes of every
+// organization so that clients can catch up after losing the connection.
+type StreamService interface {
+	EventPublisher
+	Subscribe(ctx context.Context, req *params.EventStreamRequest) (*EventStream, *response.CustomError)
+}
+
+// EventStream is a subscription to the event stream. Events holds the
+// changes after the last one the client saw, then the new ones; it is
+// closed when the client falls too far behind, and should reconnect.
+type EventStream struct {
+	// Reset is set when changes after the last one the client saw are no
+	// longer kept, so that it has to load the catalog again.
+	Reset  bool
+	Events <-chan *params.StreamEvent
+	close  func()
+}
+
+// Close ends the subscription.
+func (stream *EventStream) Close() {
+	stream.close()
+}
+
+type StreamServiceImpl struct {
+	CatalogEventRepository repositories.CatalogEventRepository
+	Bus                    *pubsub.Bus
+	// BufferSize is how many changes of an organization are kept
+	BufferSize int
+	DB         *gorm.DB
+}
+
+// streamEntities are the entities whose events the stream carries.
+var streamEntities = map[string]bool{"book": true, "author": true}
+
+// liveBuffer is how many new events a client can fall behind by.
+const liveBuffer = 64
+
+func NewStreamService(catalogEventRepository repositories.CatalogEventRepository, bus *pubsub.Bus, bufferSize int, db *gorm.DB) StreamService {
+	return &StreamServiceImpl{
+		CatalogEventRepository: catalogEventRepository,
+		Bus:                    bus,
+		BufferSize:             bufferSize,
+		DB:                     db,
+	}
+}
+
+// Publish keeps the event and sends it to the subscribers of the caller's
+// organization. Events that are not about books or authors are left out,
+// and so are events it has already kept: the event keeps the id the outbox
+// gave it.
+func (service *StreamServiceImpl) Publish(ctx context.Context, message *params.EventMessage) *response.CustomError {
+	if !streamEntities[message.Aggregate] {
+		return nil
+	}
+	tenantId, ok := tenant.FromContext(ctx)
+	if !ok {
+		return response.GeneralErrorWithAdditionalInfo(tenant.ErrMissingTenant.Error())
+	}
+	if _, err := service.CatalogEventRepository.FindEventById(ctx, service.DB, message.ID); err == nil {
+		return nil
+	}
+
+	record := &models.CatalogEvent{
+		ID:        message.ID,
+		Event:     message.Event,
+		Entity:    message.Aggregate,
+		Data:      message.Data,
+		CreatedAt: message.CreatedAt,
+	}
+	if err := service.CatalogEventRepository.CreateEvent(ctx, service.DB, record); err != nil {
+		return response.RepositoryError()
+	}
+	if err := service.CatalogEventRepository.PruneEvents(ctx, service.DB, service.BufferSize); err != nil {
+		return response.RepositoryError()
+	}
+	service.Bus.Publish(streamTopic(tenantId), streamEvent(record))
+	return nil
+}
+
+func (service *StreamServiceImpl) Subscribe(ctx context.Context, req *params.EventStreamRequest) (*EventStream, *response.CustomError) {
+	val := validator.New()
+	if err := val.Struct(req); err != nil {
+		return nil, response.BadRequestErrorWithAdditionalInfo(err.Error())
+	}
+	tenantId, ok := tenant.FromContext(ctx)
+	if !ok {
+		return nil, response.GeneralErrorWithAdditionalInfo(tenant.ErrMissingTenant.Error())
+	}
+
+	// subscribing before reading the kept events loses nothing in between;
+	// what is both kept and new is skipped by its id
+	live, cancel := service.Bus.Subscribe(streamTopic(tenantId), liveBuffer)
+	stream := &EventStream{}
+	var replay []*models.CatalogEvent
+	if req.LastEventID != 0 {
+		// the last event the client saw is gone, and maybe later ones too
+		oldest, err := service.CatalogEventRepository.FindOldestEvent(ctx, service.DB)
+		stream.Reset = err == nil && oldest.ID > req.LastEventID
+		replay, err = service.CatalogEventRepository.GetEventsAfter(ctx, service.DB, req.LastEventID, req.Entity, service.BufferSize)
+		if err != nil {
+			cancel()
+			return nil, response.RepositoryError()
+		}
+	}
+
+	events := make(chan *params.StreamEvent)
+	done := make(chan struct{})
+	var once sync.Once
+	stream.Events = events
+	stream.close = func() {
+		once.Do(func() {
+			close(done)
+			cancel()
+		})
+	}
+	go func() {
+		defer close(events)
+		last := req.LastEventID
+		send := func(event *params.StreamEvent) bool {
+			select {
+			case events <- event:
+				last = event.ID
+				return true
+			case <-done:
+				return false
+			}
+		}
+		for _, record := range replay {
+			if !send(streamEvent(record)) {
+				return
+			}
+		}
+		for message := range live {
+			event := message.(*params.StreamEvent)
+			if event.ID <= last || (req.Entity != "" && event.Entity != req.Entity) {
+				continue
+			}
+			if !send(event) {
+				return
+			}
+		}
+	}()
+	return stream, nil
+}
+
+func streamTopic(tenantId uint) string {
+	return "catalog-events/" + strconv.Itoa(int(tenantId))
+}
+
+func streamEvent(record *models.CatalogEvent) *params.StreamEvent {
+	return &params.StreamEvent{
+		ID:     record.ID,
+		Event:  record.Event,
+		Entity: record.Entity,
+		Data:   record.Data,
+	}
+}
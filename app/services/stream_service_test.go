@@ -0,0 +1,178 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"golang-backend-test/app/models"
+	"golang-backend-test/app/params"
+	"golang-backend-test/app/repositories"
+	"golang-backend-test/pkg/pubsub"
+	"golang-backend-test/pkg/tenant"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func newTestStreamService() (StreamService, *repositories.MockCatalogEventRepository, *gorm.DB) {
+	eventRepo := new(repositories.MockCatalogEventRepository)
+	db := new(gorm.DB)
+	return NewStreamService(eventRepo, pubsub.NewBus(), 100, db), eventRepo, db
+}
+
+// nextEvent waits for the next event of stream, nil when it is closed.
+func nextEvent(t *testing.T, stream *EventStream) *params.StreamEvent {
+	select {
+	case event := <-stream.Events:
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("no event")
+		return nil
+	}
+}
+
+// acceptEvents lets the repository keep every event it is given.
+func acceptEvents(eventRepo *repositories.MockCatalogEventRepository, db *gorm.DB) {
+	eventRepo.On("FindEventById", mock.Anything, db, mock.Anything).Return(nil, errors.New("event not found"))
+	eventRepo.On("CreateEvent", mock.Anything, db, mock.AnythingOfType("*models.CatalogEvent")).Return(nil)
+	eventRepo.On("PruneEvents", mock.Anything, db, 100).Return(nil)
+}
+
+func newEventMessage(id uint, event, aggregate, data string) *params.EventMessage {
+	return &params.EventMessage{ID: id, Event: event, Aggregate: aggregate, Data: []byte(data)}
+}
+
+func TestStreamPublish_SendsToSubscribers(t *testing.T) {
+	service, eventRepo, db := newTestStreamService()
+	acceptEvents(eventRepo, db)
+	ctx := tenant.WithID(context.Background(), 1)
+
+	stream, err := service.Subscribe(ctx, &params.EventStreamRequest{})
+	assert.Nil(t, err)
+	defer stream.Close()
+	other, err := service.Subscribe(tenant.WithID(context.Background(), 2), &params.EventStreamRequest{})
+	assert.Nil(t, err)
+	defer other.Close()
+
+	assert.Nil(t, service.Publish(ctx, newEventMessage(1, EventBookCreated, "book", `{"id":4,"title":"Dune"}`)))
+
+	event := nextEvent(t, stream)
+	assert.Equal(t, uint(1), event.ID)
+	assert.Equal(t, EventBookCreated, event.Event)
+	assert.Equal(t, "book", event.Entity)
+	assert.Contains(t, string(event.Data), `"title":"Dune"`)
+	select {
+	case event := <-other.Events:
+		t.Fatalf("another organization got %v", event)
+	default:
+	}
+}
+
+func TestStreamPublish_IgnoresOtherEvents(t *testing.T) {
+	service, eventRepo, _ := newTestStreamService()
+
+	err := service.Publish(tenant.WithID(context.Background(), 1), newEventMessage(1, EventLoanOverdue, "loan", `{"id":1}`))
+
+	assert.Nil(t, err)
+	eventRepo.AssertNotCalled(t, "CreateEvent", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSubscribe_FiltersEntity(t *testing.T) {
+	service, eventRepo, db := newTestStreamService()
+	acceptEvents(eventRepo, db)
+	ctx := tenant.WithID(context.Background(), 1)
+
+	stream, err := service.Subscribe(ctx, &params.EventStreamRequest{Entity: "author"})
+	assert.Nil(t, err)
+	defer stream.Close()
+
+	assert.Nil(t, service.Publish(ctx, newEventMessage(1, EventBookCreated, "book", `{"id":4}`)))
+	assert.Nil(t, service.Publish(ctx, newEventMessage(2, EventAuthorDeleted, "author", `{"id":2}`)))
+
+	event := nextEvent(t, stream)
+	assert.Equal(t, uint(2), event.ID)
+	assert.Equal(t, EventAuthorDeleted, event.Event)
+}
+
+func TestSubscribe_ReplaysAfterLastEventID(t *testing.T) {
+	service, eventRepo, db := newTestStreamService()
+	acceptEvents(eventRepo, db)
+	ctx := tenant.WithID(context.Background(), 1)
+
+	eventRepo.On("FindOldestEvent", mock.Anything, db).Return(&models.CatalogEvent{ID: 3}, nil)
+	eventRepo.On("GetEventsAfter", mock.Anything, db, uint(9), "", 100).Return([]*models.CatalogEvent{
+		{ID: 10, Event: EventBookUpdated, Entity: "book", Data: []byte(`{"id":1}`)},
+		{ID: 11, Event: EventBookDeleted, Entity: "book", Data: []byte(`{"id":2}`)},
+	}, nil)
+
+	stream, err := service.Subscribe(ctx, &params.EventStreamRequest{LastEventID: 9})
+	assert.Nil(t, err)
+	defer stream.Close()
+	assert.False(t, stream.Reset)
+
+	assert.Nil(t, service.Publish(ctx, newEventMessage(12, EventAuthorCreated, "author", `{"id":5}`)))
+
+	assert.Equal(t, uint(10), nextEvent(t, stream).ID)
+	assert.Equal(t, uint(11), nextEvent(t, stream).ID)
+	assert.Equal(t, uint(12), nextEvent(t, stream).ID)
+}
+
+func TestSubscribe_ResetWhenEventsArePruned(t *testing.T) {
+	service, eventRepo, db := newTestStreamService()
+	ctx := tenant.WithID(context.Background(), 1)
+
+	eventRepo.On("FindOldestEvent", mock.Anything, db).Return(&models.CatalogEvent{ID: 50}, nil)
+	eventRepo.On("GetEventsAfter", mock.Anything, db, uint(9), "", 100).Return([]*models.CatalogEvent{
+		{ID: 50, Event: EventBookUpdated, Entity: "book", Data: []byte(`{"id":1}`)},
+	}, nil)
+
+	stream, err := service.Subscribe(ctx, &params.EventStreamRequest{LastEventID: 9})
+	assert.Nil(t, err)
+	defer stream.Close()
+
+	assert.True(t, stream.Reset)
+	assert.Equal(t, uint(50), nextEvent(t, stream).ID)
+}
+
+func TestSubscribe_ValidationError(t *testing.T) {
+	service, _, _ := newTestStreamService()
+
+	_, err := service.Subscribe(tenant.WithID(context.Background(), 1), &params.EventStreamRequest{Entity: "loan"})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, 400, err.StatusCode)
+}
+
+func TestSubscribe_SlowClientIsDropped(t *testing.T) {
+	service, eventRepo, db := newTestStreamService()
+	acceptEvents(eventRepo, db)
+	ctx := tenant.WithID(context.Background(), 1)
+
+	stream, err := service.Subscribe(ctx, &params.EventStreamRequest{})
+	assert.Nil(t, err)
+	defer stream.Close()
+
+	for i := 1; i <= liveBuffer+2; i++ {
+		assert.Nil(t, service.Publish(ctx, newEventMessage(uint(i), EventBookUpdated, "book", `{"id":1}`)))
+	}
+
+	count := 0
+	for range stream.Events {
+		count++
+	}
+	assert.Less(t, count, liveBuffer+2)
+}
+
+func TestStreamPublish_SkipsRelayedEvent(t *testing.T) {
+	service, eventRepo, db := newTestStreamService()
+	ctx := tenant.WithID(context.Background(), 1)
+
+	eventRepo.On("FindEventById", mock.Anything, db, uint(3)).Return(&models.CatalogEvent{ID: 3}, nil)
+
+	err := service.Publish(ctx, newEventMessage(3, EventBookCreated, "book", `{"id":4}`))
+
+	assert.Nil(t, err)
+	eventRepo.AssertNotCalled(t, "CreateEvent", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"golang-backend-test/app/models"
+	"golang-backend-test/app/params"
+	"golang-backend-test/app/repositories"
+	"golang-backend-test/pkg/tenant"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// newTenantTestDB opens an in-memory database with the tenant callbacks
+// installed and one author and book in each of two organizations. Both books
+// share an ISBN, which is only unique within an organization.
+func newTenantTestDB(t *testing.T) (*gorm.DB, context.Context, context.Context) {
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	assert.Nil(t, err)
+	tenantModels := []interface{}{
+		&models.Author{}, &models.AuthorAlias{}, &models.AuthorPseudonym{}, &models.AuthorRedirect{},
+		&models.Book{}, &models.Branch{}, &models.ShelfLocation{}, &models.BookCopy{}, &models.User{},
+		&models.Loan{}, &models.Stocktake{}, &models.StocktakeScan{}, &models.StocktakeDiscrepancy{},
+	}
+	assert.Nil(t, db.AutoMigrate(append([]interface{}{&models.Organization{}}, tenantModels...)...))
+	assert.Nil(t, tenant.Register(db, tenantModels...))
+
+	acme := tenant.WithID(context.Background(), 1)
+	globex := tenant.WithID(context.Background(), 2)
+	for _, ctx := range []context.Context{acme, globex} {
+		id, _ := tenant.FromContext(ctx)
+		author := &models.Author{Name: fmt.Sprintf("Author %d", id), Birthdate: time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC)}
+		assert.Nil(t, db.WithContext(ctx).Create(author).Error)
+		assert.Nil(t, db.WithContext(ctx).Create(&models.Book{Title: fmt.Sprintf("Book %d", id), ISBN: "9780000000001", AuthorID: author.ID}).Error)
+	}
+	return db, acme, globex
+}
+
+func TestTenantIsolation_Reads(t *testing.T) {
+	db, acme, globex := newTenantTestDB(t)
+	service := NewBookService(repositories.NewBookRepository(), repositories.NewAuthorRepository(), nil, nil, db)
+
+	books, err := service.FindAllBooks(acme, nil)
+	assert.Nil(t, err)
+	assert.Len(t, books, 1)
+	assert.Equal(t, "Book 1", books[0].Title)
+
+	_, err = service.FindDetailBook(globex, 1, nil)
+	assert.NotNil(t, err)
+	assert.Equal(t, "NOT FOUND ERROR", err.Message)
+
+	result, err := service.FindDetailBook(globex, 2, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "Book 2", result.Title)
+}
+
+func TestTenantIsolation_SearchDoesNotEscapeWithOr(t *testing.T) {
+	db, acme, _ := newTenantTestDB(t)
+	service := NewAuthorService(repositories.NewAuthorRepository(), nil, db)
+
+	// the search ORs the name with alias and pseudonym matches, which must
+	// stay inside the tenant condition
+	authors, err := service.SearchAuthors(acme, "Author")
+	assert.Nil(t, err)
+	assert.Len(t, authors, 1)
+	assert.Equal(t, uint(1), authors[0].ID)
+}
+
+func TestTenantIsolation_Writes(t *testing.T) {
+	db, acme, globex := newTenantTestDB(t)
+	service := NewBookService(repositories.NewBookRepository(), repositories.NewAuthorRepository(), nil, nil, db)
+
+	// acme cannot create a book for globex's author
+	_, err := service.CrateBook(acme, &params.BookRequest{Title: "Stolen", ISBN: "9780000000002", AuthorID: 2})
+	assert.NotNil(t, err)
+
+	// nor overwrite or delete globex's book
+	_, err = service.UpdateBook(acme, 2, &params.BookRequest{Title: "Overwritten", ISBN: "9780000000001", AuthorID: 1})
+	assert.NotNil(t, err)
+	assert.NotNil(t, service.DeleteBook(acme, 2))
+
+	book, errFind := service.FindDetailBook(globex, 2, nil)
+	assert.Nil(t, errFind)
+	assert.Equal(t, "Book 2", book.Title)
+
+	var count int64
+	assert.Nil(t, db.WithContext(acme).Model(&models.Book{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestTenantIsolation_CreateStampsTenant(t *testing.T) {
+	db, _, globex := newTenantTestDB(t)
+	service := NewBookService(repositories.NewBookRepository(), repositories.NewAuthorRepository(), nil, nil, db)
+
+	_, err := service.CrateBook(globex, &params.BookRequest{Title: "Another", ISBN: "9780000000003", AuthorID: 2})
+	assert.Nil(t, err)
+
+	var book models.Book
+	assert.Nil(t, db.WithContext(globex).Where("title = ?", "Another").First(&book).Error)
+	assert.Equal(t, uint(2), book.TenantID)
+}
+
+func TestTenantIsolation_MissingTenant(t *testing.T) {
+	db, _, _ := newTenantTestDB(t)
+	service := NewBookService(repositories.NewBookRepository(), repositories.NewAuthorRepository(), nil, nil, db)
+
+	_, err := service.FindAllBooks(context.Background(), nil)
+	assert.NotNil(t, err)
+
+	errCreate := db.Create(&models.Author{Name: "Nobody"}).Error
+	assert.ErrorIs(t, errCreate, tenant.ErrMissingTenant)
+}
@@ -0,0 +1,283 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"golang-backend-test/app/commons/response"
+	"golang-backend-test/app/models"
+	"golang-backend-test/app/params"
+	"golang-backend-test/app/repositories"
+	"time"
+
+	"github.com/go-playground/validator"
+	"gorm.io/gorm"
+)
+
+type TransferService interface {
+	FindDetailTransfer(ctx context.Context, id int) (*params.TransferResponse, *response.CustomError)
+	FindBranchTransfers(ctx context.Context, branchId int) (*params.BranchTransfersResponse, *response.CustomError)
+	RequestTransfer(ctx context.Context, userId int, req *params.TransferRequest) (*params.TransferResponse, *response.CustomError)
+	ShipTransfer(ctx context.Context, userId, id int) (*params.TransferResponse, *response.CustomError)
+	ReceiveTransfer(ctx context.Context, userId, id int, req *params.TransferReceiveRequest) (*params.TransferResponse, *response.CustomError)
+	CancelTransfer(ctx context.Context, userId, id int) (*params.TransferResponse, *response.CustomError)
+}
+
+type TransferServiceImpl struct {
+	TransferRepository repositories.TransferRepository
+	BookCopyRepository repositories.BookCopyRepository
+	BranchRepository   repositories.BranchRepository
+	DB                 *gorm.DB
+}
+
+func NewTransferService(transferRepository repositories.TransferRepository, bookCopyRepository repositories.BookCopyRepository, branchRepository repositories.BranchRepository, db *gorm.DB) TransferService {
+	return &TransferServiceImpl{
+		TransferRepository: transferRepository,
+		BookCopyRepository: bookCopyRepository,
+		BranchRepository:   branchRepository,
+		DB:                 db,
+	}
+}
+
+// transferTransitions lists, for each target status, the statuses a
+// transfer may move from.
+var transferTransitions = map[string][]string{
+	models.TransferInTransit: {models.TransferRequested},
+	models.TransferReceived:  {models.TransferInTransit},
+	models.TransferCancelled: {models.TransferRequested, models.TransferInTransit},
+}
+
+func (service *TransferServiceImpl) FindDetailTransfer(ctx context.Context, id int) (*params.TransferResponse, *response.CustomError) {
+	transfer, err := service.TransferRepository.FindTransferById(ctx, service.DB, id)
+	if err != nil {
+		return nil, response.NotFoundError()
+	}
+
+	return transferResponse(transfer), nil
+}
+
+func (service *TransferServiceImpl) FindBranchTransfers(ctx context.Context, branchId int) (*params.BranchTransfersResponse, *response.CustomError) {
+	if _, err := service.BranchRepository.FindBranchById(ctx, service.DB, branchId); err != nil {
+		return nil, response.NotFoundError()
+	}
+
+	transfers, err := service.TransferRepository.GetPendingTransfers(ctx, service.DB, branchId)
+	if err != nil {
+		return nil, response.RepositoryError()
+	}
+	result := &params.BranchTransfersResponse{
+		Inbound:  []*params.TransferResponse{},
+		Outbound: []*params.TransferResponse{},
+	}
+	for _, transfer := range transfers {
+		if int(transfer.ToBranchID) == branchId {
+			result.Inbound = append(result.Inbound, transferResponse(transfer))
+		} else {
+			result.Outbound = append(result.Outbound, transferResponse(transfer))
+		}
+	}
+	return result, nil
+}
+
+func (service *TransferServiceImpl) RequestTransfer(ctx context.Context, userId int, req *params.TransferRequest) (*params.TransferResponse, *response.CustomError) {
+	val := validator.New()
+	err := val.Struct(req)
+	if err != nil {
+		validationErrors := err.(validator.ValidationErrors)
+		var errors []interface{}
+		for _, fieldError := range validationErrors {
+			error := "error " + fieldError.Field() + " on tag " + fieldError.Tag()
+			errors = append(errors, error)
+		}
+		return nil, response.BadRequestErrorWithAdditionalInfo(errors)
+	}
+
+	var transfer = new(models.Transfer)
+	var bookCopy *models.BookCopy
+	var toBranch *models.Branch
+	var custErr *response.CustomError
+	// the open transfer is looked up in the transaction that opens the new
+	// one, so two requests for the same copy cannot both get through
+	err = service.DB.Transaction(func(tx *gorm.DB) error {
+		bookCopy, err = service.BookCopyRepository.FindBookCopyById(ctx, tx, int(req.BookCopyID))
+		if err != nil {
+			custErr = response.BadRequestErrorWithAdditionalInfo(err.Error())
+			return err
+		}
+		if bookCopy.ShelfLocation == nil {
+			custErr = response.BadRequestErrorWithAdditionalInfo("book copy has no shelf location")
+			return errors.New("book copy has no shelf location")
+		}
+		if bookCopy.Status != models.CopyAvailable {
+			custErr = response.BadRequestErrorWithAdditionalInfo("book copy is not available")
+			return errors.New("book copy is not available")
+		}
+		if _, err := service.TransferRepository.FindOpenTransferByCopy(ctx, tx, int(bookCopy.ID)); err == nil {
+			custErr = response.ConflictErrorWithAdditionalInfo("book copy already has an open transfer")
+			return errors.New("book copy already has an open transfer")
+		}
+		toBranch, err = service.BranchRepository.FindBranchById(ctx, tx, int(req.ToBranchID))
+		if err != nil {
+			custErr = response.BadRequestErrorWithAdditionalInfo(err.Error())
+			return err
+		}
+		if toBranch.ID == bookCopy.ShelfLocation.BranchID {
+			custErr = response.BadRequestErrorWithAdditionalInfo("book copy is already at the destination branch")
+			return errors.New("book copy is already at the destination branch")
+		}
+
+		transfer.BookCopyID = bookCopy.ID
+		transfer.FromBranchID = bookCopy.ShelfLocation.BranchID
+		transfer.ToBranchID = toBranch.ID
+		transfer.Status = models.TransferRequested
+		transfer.RequestedAt = time.Now()
+		transfer.RequestedBy = uint(userId)
+		return service.TransferRepository.CreateTransfer(ctx, tx, transfer)
+	})
+	if custErr != nil {
+		return nil, custErr
+	}
+	if err != nil {
+		return nil, response.RepositoryError()
+	}
+	transfer.BookCopy = *bookCopy
+	transfer.FromBranch = bookCopy.ShelfLocation.Branch
+	transfer.ToBranch = *toBranch
+
+	return transferResponse(transfer), nil
+}
+
+func (service *TransferServiceImpl) ShipTransfer(ctx context.Context, userId, id int) (*params.TransferResponse, *response.CustomError) {
+	transfer, custErr := service.findTransferForTransition(ctx, id, models.TransferInTransit)
+	if custErr != nil {
+		return nil, custErr
+	}
+
+	from := transfer.Status
+	now := time.Now()
+	by := uint(userId)
+	transfer.Status = models.TransferInTransit
+	transfer.ShippedAt = &now
+	transfer.ShippedBy = &by
+	transfer.BookCopy.Status = models.CopyInTransit
+	if custErr := service.saveTransfer(ctx, transfer, from); custErr != nil {
+		return nil, custErr
+	}
+
+	return transferResponse(transfer), nil
+}
+
+func (service *TransferServiceImpl) ReceiveTransfer(ctx context.Context, userId, id int, req *params.TransferReceiveRequest) (*params.TransferResponse, *response.CustomError) {
+	val := validator.New()
+	err := val.Struct(req)
+	if err != nil {
+		validationErrors := err.(validator.ValidationErrors)
+		var errors []interface{}
+		for _, fieldError := range validationErrors {
+			error := "error " + fieldError.Field() + " on tag " + fieldError.Tag()
+			errors = append(errors, error)
+		}
+		return nil, response.BadRequestErrorWithAdditionalInfo(errors)
+	}
+
+	transfer, custErr := service.findTransferForTransition(ctx, id, models.TransferReceived)
+	if custErr != nil {
+		return nil, custErr
+	}
+	location, err := service.BranchRepository.FindShelfLocationById(ctx, service.DB, int(req.ShelfLocationID))
+	if err != nil {
+		return nil, response.BadRequestErrorWithAdditionalInfo(err.Error())
+	}
+	if location.BranchID != transfer.ToBranchID {
+		return nil, response.BadRequestErrorWithAdditionalInfo("shelf location is not at the destination branch")
+	}
+
+	from := transfer.Status
+	now := time.Now()
+	by := uint(userId)
+	transfer.Status = models.TransferReceived
+	transfer.ReceivedAt = &now
+	transfer.ReceivedBy = &by
+	transfer.BookCopy.Status = models.CopyAvailable
+	transfer.BookCopy.ShelfLocationID = &location.ID
+	transfer.BookCopy.ShelfLocation = location
+	if custErr := service.saveTransfer(ctx, transfer, from); custErr != nil {
+		return nil, custErr
+	}
+
+	return transferResponse(transfer), nil
+}
+
+func (service *TransferServiceImpl) CancelTransfer(ctx context.Context, userId, id int) (*params.TransferResponse, *response.CustomError) {
+	transfer, custErr := service.findTransferForTransition(ctx, id, models.TransferCancelled)
+	if custErr != nil {
+		return nil, custErr
+	}
+
+	from := transfer.Status
+	now := time.Now()
+	by := uint(userId)
+	transfer.Status = models.TransferCancelled
+	transfer.CancelledAt = &now
+	transfer.CancelledBy = &by
+	transfer.BookCopy.Status = models.CopyAvailable
+	if custErr := service.saveTransfer(ctx, transfer, from); custErr != nil {
+		return nil, custErr
+	}
+
+	return transferResponse(transfer), nil
+}
+
+func (service *TransferServiceImpl) findTransferForTransition(ctx context.Context, id int, status string) (*models.Transfer, *response.CustomError) {
+	transfer, err := service.TransferRepository.FindTransferById(ctx, service.DB, id)
+	if err != nil {
+		return nil, response.NotFoundError()
+	}
+	for _, from := range transferTransitions[status] {
+		if transfer.Status == from {
+			return transfer, nil
+		}
+	}
+	return nil, response.BadRequestErrorWithAdditionalInfo(fmt.Sprintf("cannot move a %s transfer to %s", transfer.Status, status))
+}
+
+// saveTransfer stores the transfer together with the status and location of
+// the copy it moves, provided the transfer is still in status from.
+func (service *TransferServiceImpl) saveTransfer(ctx context.Context, transfer *models.Transfer, from string) *response.CustomError {
+	var custErr *response.CustomError
+	err := service.DB.Transaction(func(tx *gorm.DB) error {
+		if err := service.TransferRepository.UpdateTransfer(ctx, tx, transfer, from); err != nil {
+			custErr = response.ConflictErrorWithAdditionalInfo("the transfer changed meanwhile")
+			return err
+		}
+		return service.BookCopyRepository.UpdateBookCopy(ctx, tx, &transfer.BookCopy)
+	})
+	if custErr != nil {
+		return custErr
+	}
+	if err != nil {
+		return response.RepositoryErrorWithAdditionalInfo(err.Error())
+	}
+	return nil
+}
+
+func transferResponse(transfer *models.Transfer) *params.TransferResponse {
+	return &params.TransferResponse{
+		ID:         transfer.ID,
+		Status:     transfer.Status,
+		BookCopy:   bookCopyResponse(&transfer.BookCopy),
+		FromBranch: branchResponse(&transfer.FromBranch),
+		ToBranch:   branchResponse(&transfer.ToBranch),
+		Requested:  &params.StepResponse{At: transfer.RequestedAt.Format(time.RFC3339), By: transfer.RequestedBy},
+		Shipped:    stepResponse(transfer.ShippedAt, transfer.ShippedBy),
+		Received:   stepResponse(transfer.ReceivedAt, transfer.ReceivedBy),
+		Cancelled:  stepResponse(transfer.CancelledAt, transfer.CancelledBy),
+	}
+}
+
+func stepResponse(at *time.Time, by *uint) *params.StepResponse {
+	if at == nil || by == nil {
+		return nil
+	}
+	return &params.StepResponse{At: at.Format(time.RFC3339), By: *by}
+}
@@ -0,0 +1,335 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"golang-backend-test/app/models"
+	"golang-backend-test/app/params"
+	"golang-backend-test/app/repositories"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func newTestTransfer(status string) *models.Transfer {
+	locationID := uint(1)
+	return &models.Transfer{
+		ID:           1,
+		BookCopyID:   1,
+		FromBranchID: 1,
+		FromBranch:   models.Branch{ID: 1, Code: "MAIN", Name: "Main Library"},
+		ToBranchID:   2,
+		ToBranch:     models.Branch{ID: 2, Code: "EAST", Name: "East Branch"},
+		Status:       status,
+		RequestedAt:  time.Date(2024, time.March, 1, 9, 0, 0, 0, time.UTC),
+		RequestedBy:  7,
+		BookCopy: models.BookCopy{
+			ID:              1,
+			BookID:          1,
+			Barcode:         "C0001",
+			ShelfLocationID: &locationID,
+			Status:          models.CopyAvailable,
+		},
+	}
+}
+
+func TestRequestTransfer_Success(t *testing.T) {
+	transferRepo := new(repositories.MockTransferRepository)
+	bookCopyRepo := new(repositories.MockBookCopyRepository)
+	branchRepo := new(repositories.MockBranchRepository)
+	db, errDB := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	assert.Nil(t, errDB)
+	locationID := uint(1)
+
+	bookCopy := &models.BookCopy{
+		ID:              1,
+		Barcode:         "C0001",
+		Status:          models.CopyAvailable,
+		ShelfLocationID: &locationID,
+		ShelfLocation: &models.ShelfLocation{
+			ID:       locationID,
+			BranchID: 1,
+			Branch:   models.Branch{ID: 1, Code: "MAIN", Name: "Main Library"},
+		},
+	}
+
+	bookCopyRepo.On("FindBookCopyById", mock.Anything, mock.Anything, 1).Return(bookCopy, nil)
+	// the open transfer is looked up inside the transaction, not on db
+	transferRepo.On("FindOpenTransferByCopy", mock.Anything, mock.MatchedBy(func(tx *gorm.DB) bool { return tx != db }), 1).Return(nil, errors.New("transfer not found"))
+	branchRepo.On("FindBranchById", mock.Anything, mock.Anything, 2).Return(&models.Branch{ID: 2, Code: "EAST", Name: "East Branch"}, nil)
+	transferRepo.On("CreateTransfer", mock.Anything, mock.Anything, mock.MatchedBy(func(transfer *models.Transfer) bool {
+		return transfer.FromBranchID == 1 && transfer.ToBranchID == 2 &&
+			transfer.Status == models.TransferRequested && transfer.RequestedBy == 7
+	})).Return(nil)
+	service := NewTransferService(transferRepo, bookCopyRepo, branchRepo, db)
+
+	result, err := service.RequestTransfer(context.Background(), 7, &params.TransferRequest{BookCopyID: 1, ToBranchID: 2})
+
+	assert.Nil(t, err)
+	assert.NotNil(t, result)
+	assert.Equal(t, models.TransferRequested, result.Status)
+	assert.Equal(t, "MAIN", result.FromBranch.Code)
+	assert.Equal(t, "EAST", result.ToBranch.Code)
+	assert.Equal(t, uint(7), result.Requested.By)
+	assert.Nil(t, result.Shipped)
+
+	transferRepo.AssertExpectations(t)
+	bookCopyRepo.AssertExpectations(t)
+	branchRepo.AssertExpectations(t)
+}
+
+func TestRequestTransfer_CopyInTransit(t *testing.T) {
+	transferRepo := new(repositories.MockTransferRepository)
+	bookCopyRepo := new(repositories.MockBookCopyRepository)
+	branchRepo := new(repositories.MockBranchRepository)
+	db, errDB := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	assert.Nil(t, errDB)
+
+	bookCopyRepo.On("FindBookCopyById", mock.Anything, mock.Anything, 1).Return(&models.BookCopy{
+		ID:            1,
+		Status:        models.CopyInTransit,
+		ShelfLocation: &models.ShelfLocation{ID: 1, BranchID: 1},
+	}, nil)
+	service := NewTransferService(transferRepo, bookCopyRepo, branchRepo, db)
+
+	result, err := service.RequestTransfer(context.Background(), 7, &params.TransferRequest{BookCopyID: 1, ToBranchID: 2})
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "book copy is not available", err.AdditionalInfo)
+
+	bookCopyRepo.AssertExpectations(t)
+}
+
+func TestRequestTransfer_OpenTransferExists(t *testing.T) {
+	transferRepo := new(repositories.MockTransferRepository)
+	bookCopyRepo := new(repositories.MockBookCopyRepository)
+	branchRepo := new(repositories.MockBranchRepository)
+	db, errDB := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	assert.Nil(t, errDB)
+
+	bookCopyRepo.On("FindBookCopyById", mock.Anything, mock.Anything, 1).Return(&models.BookCopy{
+		ID:            1,
+		Status:        models.CopyAvailable,
+		ShelfLocation: &models.ShelfLocation{ID: 1, BranchID: 1},
+	}, nil)
+	transferRepo.On("FindOpenTransferByCopy", mock.Anything, mock.Anything, 1).Return(newTestTransfer(models.TransferRequested), nil)
+	service := NewTransferService(transferRepo, bookCopyRepo, branchRepo, db)
+
+	result, err := service.RequestTransfer(context.Background(), 7, &params.TransferRequest{BookCopyID: 1, ToBranchID: 2})
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, 409, err.StatusCode)
+	assert.Equal(t, "book copy already has an open transfer", err.AdditionalInfo)
+	transferRepo.AssertNotCalled(t, "CreateTransfer", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRequestTransfer_SameBranch(t *testing.T) {
+	transferRepo := new(repositories.MockTransferRepository)
+	bookCopyRepo := new(repositories.MockBookCopyRepository)
+	branchRepo := new(repositories.MockBranchRepository)
+	db, errDB := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	assert.Nil(t, errDB)
+
+	bookCopyRepo.On("FindBookCopyById", mock.Anything, mock.Anything, 1).Return(&models.BookCopy{
+		ID:            1,
+		Status:        models.CopyAvailable,
+		ShelfLocation: &models.ShelfLocation{ID: 1, BranchID: 1},
+	}, nil)
+	transferRepo.On("FindOpenTransferByCopy", mock.Anything, mock.Anything, 1).Return(nil, errors.New("transfer not found"))
+	branchRepo.On("FindBranchById", mock.Anything, mock.Anything, 1).Return(&models.Branch{ID: 1, Code: "MAIN"}, nil)
+	service := NewTransferService(transferRepo, bookCopyRepo, branchRepo, db)
+
+	result, err := service.RequestTransfer(context.Background(), 7, &params.TransferRequest{BookCopyID: 1, ToBranchID: 1})
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "BAD REQUEST ERROR", err.Message)
+}
+
+func TestShipTransfer_Success(t *testing.T) {
+	transferRepo := new(repositories.MockTransferRepository)
+	bookCopyRepo := new(repositories.MockBookCopyRepository)
+	branchRepo := new(repositories.MockBranchRepository)
+	db, errDB := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	assert.Nil(t, errDB)
+	transfer := newTestTransfer(models.TransferRequested)
+
+	transferRepo.On("FindTransferById", mock.Anything, db, 1).Return(transfer, nil)
+	transferRepo.On("UpdateTransfer", mock.Anything, mock.Anything, transfer, models.TransferRequested).Return(nil)
+	bookCopyRepo.On("UpdateBookCopy", mock.Anything, mock.Anything, &transfer.BookCopy).Return(nil)
+	service := NewTransferService(transferRepo, bookCopyRepo, branchRepo, db)
+
+	result, err := service.ShipTransfer(context.Background(), 8, 1)
+
+	assert.Nil(t, err)
+	assert.Equal(t, models.TransferInTransit, result.Status)
+	assert.Equal(t, uint(8), result.Shipped.By)
+	assert.False(t, result.BookCopy.Available)
+	assert.Equal(t, models.CopyInTransit, transfer.BookCopy.Status)
+
+	transferRepo.AssertExpectations(t)
+	bookCopyRepo.AssertExpectations(t)
+}
+
+func TestShipTransfer_InvalidState(t *testing.T) {
+	transferRepo := new(repositories.MockTransferRepository)
+	bookCopyRepo := new(repositories.MockBookCopyRepository)
+	branchRepo := new(repositories.MockBranchRepository)
+	db := new(gorm.DB)
+
+	transferRepo.On("FindTransferById", mock.Anything, db, 1).Return(newTestTransfer(models.TransferCancelled), nil)
+	service := NewTransferService(transferRepo, bookCopyRepo, branchRepo, db)
+
+	result, err := service.ShipTransfer(context.Background(), 8, 1)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "cannot move a cancelled transfer to in_transit", err.AdditionalInfo)
+	transferRepo.AssertNotCalled(t, "UpdateTransfer", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestReceiveTransfer_Success(t *testing.T) {
+	transferRepo := new(repositories.MockTransferRepository)
+	bookCopyRepo := new(repositories.MockBookCopyRepository)
+	branchRepo := new(repositories.MockBranchRepository)
+	db, errDB := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	assert.Nil(t, errDB)
+	transfer := newTestTransfer(models.TransferInTransit)
+	transfer.BookCopy.Status = models.CopyInTransit
+
+	transferRepo.On("FindTransferById", mock.Anything, db, 1).Return(transfer, nil)
+	branchRepo.On("FindShelfLocationById", mock.Anything, db, 5).Return(&models.ShelfLocation{
+		ID:       5,
+		BranchID: 2,
+		Name:     "Stacks",
+		Branch:   models.Branch{ID: 2, Code: "EAST", Name: "East Branch"},
+	}, nil)
+	transferRepo.On("UpdateTransfer", mock.Anything, mock.Anything, transfer, models.TransferInTransit).Return(nil)
+	bookCopyRepo.On("UpdateBookCopy", mock.Anything, mock.Anything, &transfer.BookCopy).Return(nil)
+	service := NewTransferService(transferRepo, bookCopyRepo, branchRepo, db)
+
+	result, err := service.ReceiveTransfer(context.Background(), 9, 1, &params.TransferReceiveRequest{ShelfLocationID: 5})
+
+	assert.Nil(t, err)
+	assert.Equal(t, models.TransferReceived, result.Status)
+	assert.Equal(t, uint(9), result.Received.By)
+	assert.True(t, result.BookCopy.Available)
+	assert.Equal(t, "EAST", result.BookCopy.Location.Branch.Code)
+	assert.Equal(t, uint(5), *transfer.BookCopy.ShelfLocationID)
+
+	transferRepo.AssertExpectations(t)
+	branchRepo.AssertExpectations(t)
+	bookCopyRepo.AssertExpectations(t)
+}
+
+func TestReceiveTransfer_WrongBranch(t *testing.T) {
+	transferRepo := new(repositories.MockTransferRepository)
+	bookCopyRepo := new(repositories.MockBookCopyRepository)
+	branchRepo := new(repositories.MockBranchRepository)
+	db := new(gorm.DB)
+
+	transferRepo.On("FindTransferById", mock.Anything, db, 1).Return(newTestTransfer(models.TransferInTransit), nil)
+	branchRepo.On("FindShelfLocationById", mock.Anything, db, 4).Return(&models.ShelfLocation{ID: 4, BranchID: 1}, nil)
+	service := NewTransferService(transferRepo, bookCopyRepo, branchRepo, db)
+
+	result, err := service.ReceiveTransfer(context.Background(), 9, 1, &params.TransferReceiveRequest{ShelfLocationID: 4})
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "BAD REQUEST ERROR", err.Message)
+	transferRepo.AssertNotCalled(t, "UpdateTransfer", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCancelTransfer_RepositoryError(t *testing.T) {
+	transferRepo := new(repositories.MockTransferRepository)
+	bookCopyRepo := new(repositories.MockBookCopyRepository)
+	branchRepo := new(repositories.MockBranchRepository)
+	db, errDB := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	assert.Nil(t, errDB)
+	transfer := newTestTransfer(models.TransferInTransit)
+
+	transferRepo.On("FindTransferById", mock.Anything, db, 1).Return(transfer, nil)
+	transferRepo.On("UpdateTransfer", mock.Anything, mock.Anything, transfer, models.TransferInTransit).Return(nil)
+	bookCopyRepo.On("UpdateBookCopy", mock.Anything, mock.Anything, &transfer.BookCopy).Return(errors.New("db error"))
+	service := NewTransferService(transferRepo, bookCopyRepo, branchRepo, db)
+
+	result, err := service.CancelTransfer(context.Background(), 9, 1)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "REPOSITORY ERROR", err.Message)
+
+	transferRepo.AssertExpectations(t)
+	bookCopyRepo.AssertExpectations(t)
+}
+
+func TestCancelTransfer_ChangedMeanwhile(t *testing.T) {
+	transferRepo := new(repositories.MockTransferRepository)
+	bookCopyRepo := new(repositories.MockBookCopyRepository)
+	branchRepo := new(repositories.MockBranchRepository)
+	db, errDB := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	assert.Nil(t, errDB)
+	transfer := newTestTransfer(models.TransferInTransit)
+
+	// another request received the transfer after it was read
+	transferRepo.On("FindTransferById", mock.Anything, db, 1).Return(transfer, nil)
+	transferRepo.On("UpdateTransfer", mock.Anything, mock.Anything, transfer, models.TransferInTransit).Return(errors.New("transfer not found"))
+	service := NewTransferService(transferRepo, bookCopyRepo, branchRepo, db)
+
+	result, err := service.CancelTransfer(context.Background(), 9, 1)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, 409, err.StatusCode)
+	bookCopyRepo.AssertNotCalled(t, "UpdateBookCopy", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestFindBranchTransfers_Success(t *testing.T) {
+	transferRepo := new(repositories.MockTransferRepository)
+	bookCopyRepo := new(repositories.MockBookCopyRepository)
+	branchRepo := new(repositories.MockBranchRepository)
+	db := new(gorm.DB)
+
+	outbound := newTestTransfer(models.TransferRequested)
+	inbound := newTestTransfer(models.TransferInTransit)
+	inbound.ID = 2
+	inbound.FromBranchID, inbound.ToBranchID = 2, 1
+	inbound.FromBranch, inbound.ToBranch = inbound.ToBranch, inbound.FromBranch
+
+	branchRepo.On("FindBranchById", mock.Anything, db, 1).Return(&models.Branch{ID: 1, Code: "MAIN"}, nil)
+	transferRepo.On("GetPendingTransfers", mock.Anything, db, 1).Return([]*models.Transfer{outbound, inbound}, nil)
+	service := NewTransferService(transferRepo, bookCopyRepo, branchRepo, db)
+
+	result, err := service.FindBranchTransfers(context.Background(), 1)
+
+	assert.Nil(t, err)
+	assert.Len(t, result.Outbound, 1)
+	assert.Len(t, result.Inbound, 1)
+	assert.Equal(t, uint(1), result.Outbound[0].ID)
+	assert.Equal(t, uint(2), result.Inbound[0].ID)
+
+	branchRepo.AssertExpectations(t)
+	transferRepo.AssertExpectations(t)
+}
+
+func TestFindBranchTransfers_BranchNotFound(t *testing.T) {
+	transferRepo := new(repositories.MockTransferRepository)
+	bookCopyRepo := new(repositories.MockBookCopyRepository)
+	branchRepo := new(repositories.MockBranchRepository)
+	db := new(gorm.DB)
+
+	branchRepo.On("FindBranchById", mock.Anything, db, 1).Return(nil, errors.New("branch not found"))
+	service := NewTransferService(transferRepo, bookCopyRepo, branchRepo, db)
+
+	result, err := service.FindBranchTransfers(context.Background(), 1)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "NOT FOUND ERROR", err.Message)
+}
@@ -7,6 +7,7 @@ import (
 	"golang-backend-test/app/params"
 	"golang-backend-test/app/repositories"
 	"golang-backend-test/pkg/encryption"
+	"golang-backend-test/pkg/tenant"
 	"golang-backend-test/pkg/token"
 
 	"github.com/go-playground/validator"
@@ -16,17 +17,24 @@ import (
 type UserService interface {
 	Register(ctx context.Context, req *params.UserRequest) *response.CustomError
 	Login(ctx context.Context, req *params.UserRequest) (*params.UserResponse, *response.CustomError)
+	FindDetailUser(ctx context.Context, id int) (*params.UserDetailResponse, *response.CustomError)
 }
 
 type UserServiceImpl struct {
-	UserRepository repositories.UserRepository
-	DB             *gorm.DB
+	UserRepository         repositories.UserRepository
+	OrganizationRepository repositories.OrganizationRepository
+	Events                 EventRecorder
+	DB                     *gorm.DB
 }
 
-func NewUserService(userRepository repositories.UserRepository, db *gorm.DB) UserService {
+// NewUserService builds the user service. events may be nil, in which case
+// no events are recorded.
+func NewUserService(userRepository repositories.UserRepository, organizationRepository repositories.OrganizationRepository, events EventRecorder, db *gorm.DB) UserService {
 	return &UserServiceImpl{
-		UserRepository: userRepository,
-		DB:             db,
+		UserRepository:         userRepository,
+		OrganizationRepository: organizationRepository,
+		Events:                 events,
+		DB:                     db,
 	}
 }
 
@@ -43,6 +51,18 @@ func (service *UserServiceImpl) Register(ctx context.Context, req *params.UserRe
 		return response.BadRequestErrorWithAdditionalInfo(errors)
 	}
 
+	// users belong to the organization they register with, and join it with
+	// one of its invites
+	organization, err := service.OrganizationRepository.FindOrganizationBySlug(ctx, service.DB, req.Organization)
+	if err != nil {
+		return response.BadRequestErrorWithAdditionalInfo("organization not found")
+	}
+	ctx = tenant.WithID(ctx, organization.ID)
+	invite, err := service.OrganizationRepository.FindInviteByToken(ctx, service.DB, req.Invite)
+	if err != nil {
+		return response.BadRequestErrorWithAdditionalInfo("invite not found")
+	}
+
 	_, errCheck := service.UserRepository.FindUserByUsername(ctx, service.DB, req.Username)
 	if errCheck == nil {
 		return response.BadRequestErrorWithAdditionalInfo("username already exists")
@@ -53,10 +73,31 @@ func (service *UserServiceImpl) Register(ctx context.Context, req *params.UserRe
 		return response.GeneralError()
 	}
 
+	// the first user of an organization administers it
+	count, err := service.UserRepository.CountUsers(ctx, service.DB)
+	if err != nil {
+		return response.RepositoryError()
+	}
+
 	var user = new(models.User)
 	user.Username = req.Username
 	user.Password = hashPaswword
-	if err := service.UserRepository.CreateUser(ctx, service.DB, user); err != nil {
+	user.Admin = count == 0
+	var custErr *response.CustomError
+	err = service.DB.Transaction(func(tx *gorm.DB) error {
+		if err := service.OrganizationRepository.UseInvite(ctx, tx, invite); err != nil {
+			custErr = response.BadRequestErrorWithAdditionalInfo("invite not found")
+			return err
+		}
+		if err := service.UserRepository.CreateUser(ctx, tx, user); err != nil {
+			return err
+		}
+		return record(ctx, tx, service.Events, EventUserCreated, user.ID, &params.UserDetailResponse{ID: user.ID, Username: user.Username, Admin: user.Admin})
+	})
+	if custErr != nil {
+		return custErr
+	}
+	if err != nil {
 		return response.BadRequestError()
 	}
 
@@ -76,6 +117,12 @@ func (service *UserServiceImpl) Login(ctx context.Context, req *params.UserReque
 		return nil, response.BadRequestErrorWithAdditionalInfo(errors)
 	}
 
+	organization, err := service.OrganizationRepository.FindOrganizationBySlug(ctx, service.DB, req.Organization)
+	if err != nil {
+		return nil, response.NotFoundError()
+	}
+	ctx = tenant.WithID(ctx, organization.ID)
+
 	user, err := service.UserRepository.FindUserByUsername(ctx, service.DB, req.Username)
 	if err != nil {
 		return nil, response.NotFoundError()
@@ -86,7 +133,7 @@ func (service *UserServiceImpl) Login(ctx context.Context, req *params.UserReque
 
 		return nil, response.GeneralError()
 	}
-	token, err := token.GenerateToken(int(user.ID))
+	token, err := token.GenerateToken(int(user.ID), int(organization.ID))
 	if err != nil {
 		return nil, response.GeneralErrorWithAdditionalInfo(err.Error())
 	}
@@ -95,3 +142,16 @@ func (service *UserServiceImpl) Login(ctx context.Context, req *params.UserReque
 		Token: token,
 	}, nil
 }
+
+func (service *UserServiceImpl) FindDetailUser(ctx context.Context, id int) (*params.UserDetailResponse, *response.CustomError) {
+	user, err := service.UserRepository.FindUserById(ctx, service.DB, id)
+	if err != nil {
+		return nil, response.NotFoundError()
+	}
+
+	return &params.UserDetailResponse{
+		ID:       user.ID,
+		Username: user.Username,
+		Admin:    user.Admin,
+	}, nil
+}
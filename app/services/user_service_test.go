@@ -8,43 +8,166 @@ import (
 	"golang-backend-test/app/params"
 	"golang-backend-test/app/repositories"
 	"golang-backend-test/pkg/encryption"
+	"golang-backend-test/pkg/token"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
 
 func TestRegister_Success(t *testing.T) {
 	// Arrange
 	mockRepo := new(repositories.MockUserRepository)
-	db := new(gorm.DB)
-	service := NewUserService(mockRepo, db)
+	mockOrganizationRepo := new(repositories.MockOrganizationRepository)
+	db, errDB := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	assert.Nil(t, errDB)
+	service := NewUserService(mockRepo, mockOrganizationRepo, nil, db)
 
 	validRequest := &params.UserRequest{
-		Username: "naufalhakm",
-		Password: "password123",
+		Organization: "acme",
+		Username:     "naufalhakm",
+		Password:     "password123",
+		Invite:       "inv_123",
 	}
 
+	mockOrganizationRepo.On("FindOrganizationBySlug", mock.Anything, db, "acme").Return(&models.Organization{ID: 1, Slug: "acme"}, nil)
+	mockOrganizationRepo.On("FindInviteByToken", mock.Anything, db, "inv_123").Return(&models.Invite{ID: 7, TenantID: 1}, nil)
+	mockOrganizationRepo.On("UseInvite", mock.Anything, mock.Anything, &models.Invite{ID: 7, TenantID: 1}).Return(nil)
 	mockRepo.On("FindUserByUsername", mock.Anything, db, "naufalhakm").Return(nil, errors.New("users not found"))
+	mockRepo.On("CountUsers", mock.Anything, db).Return(int64(3), nil)
 
-	mockRepo.On("CreateUser", mock.Anything, db, mock.AnythingOfType("*models.User")).Return(nil)
+	mockRepo.On("CreateUser", mock.Anything, mock.Anything, mock.MatchedBy(func(user *models.User) bool {
+		return !user.Admin
+	})).Return(nil)
 
 	err := service.Register(context.Background(), validRequest)
 
 	assert.Nil(t, err)
 	mockRepo.AssertExpectations(t)
+	mockOrganizationRepo.AssertExpectations(t)
+}
+
+func TestRegister_FirstUserIsAdmin(t *testing.T) {
+	// Arrange
+	mockRepo := new(repositories.MockUserRepository)
+	mockOrganizationRepo := new(repositories.MockOrganizationRepository)
+	db, errDB := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	assert.Nil(t, errDB)
+	service := NewUserService(mockRepo, mockOrganizationRepo, nil, db)
+
+	validRequest := &params.UserRequest{
+		Organization: "acme",
+		Username:     "naufalhakm",
+		Password:     "password123",
+		Invite:       "inv_123",
+	}
+
+	mockOrganizationRepo.On("FindOrganizationBySlug", mock.Anything, db, "acme").Return(&models.Organization{ID: 1, Slug: "acme"}, nil)
+	mockOrganizationRepo.On("FindInviteByToken", mock.Anything, db, "inv_123").Return(&models.Invite{ID: 7, TenantID: 1}, nil)
+	mockOrganizationRepo.On("UseInvite", mock.Anything, mock.Anything, &models.Invite{ID: 7, TenantID: 1}).Return(nil)
+	mockRepo.On("FindUserByUsername", mock.Anything, db, "naufalhakm").Return(nil, errors.New("users not found"))
+	mockRepo.On("CountUsers", mock.Anything, db).Return(int64(0), nil)
+
+	mockRepo.On("CreateUser", mock.Anything, mock.Anything, mock.MatchedBy(func(user *models.User) bool {
+		return user.Admin
+	})).Return(nil)
+
+	err := service.Register(context.Background(), validRequest)
+
+	assert.Nil(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRegister_OrganizationNotFound(t *testing.T) {
+	// Arrange
+	mockRepo := new(repositories.MockUserRepository)
+	mockOrganizationRepo := new(repositories.MockOrganizationRepository)
+	db := new(gorm.DB)
+	service := NewUserService(mockRepo, mockOrganizationRepo, nil, db)
+
+	validRequest := &params.UserRequest{
+		Organization: "unknown",
+		Username:     "naufalhakm",
+		Password:     "password123",
+	}
+
+	mockOrganizationRepo.On("FindOrganizationBySlug", mock.Anything, db, "unknown").Return(nil, errors.New("organization not found"))
+
+	err := service.Register(context.Background(), validRequest)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, 400, err.StatusCode)
+	assert.Equal(t, "organization not found", err.AdditionalInfo)
+	mockRepo.AssertNotCalled(t, "FindUserByUsername", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRegister_InviteNotFound(t *testing.T) {
+	// Arrange
+	mockRepo := new(repositories.MockUserRepository)
+	mockOrganizationRepo := new(repositories.MockOrganizationRepository)
+	db := new(gorm.DB)
+	service := NewUserService(mockRepo, mockOrganizationRepo, nil, db)
+
+	validRequest := &params.UserRequest{
+		Organization: "acme",
+		Username:     "naufalhakm",
+		Password:     "password123",
+		Invite:       "inv_of_globex",
+	}
+
+	mockOrganizationRepo.On("FindOrganizationBySlug", mock.Anything, db, "acme").Return(&models.Organization{ID: 1, Slug: "acme"}, nil)
+	mockOrganizationRepo.On("FindInviteByToken", mock.Anything, db, "inv_of_globex").Return(nil, errors.New("invite not found"))
+
+	err := service.Register(context.Background(), validRequest)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, 400, err.StatusCode)
+	assert.Equal(t, "invite not found", err.AdditionalInfo)
+	mockRepo.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRegister_InviteUsedMeanwhile(t *testing.T) {
+	// Arrange
+	mockRepo := new(repositories.MockUserRepository)
+	mockOrganizationRepo := new(repositories.MockOrganizationRepository)
+	db, errDB := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	assert.Nil(t, errDB)
+	service := NewUserService(mockRepo, mockOrganizationRepo, nil, db)
+
+	validRequest := &params.UserRequest{
+		Organization: "acme",
+		Username:     "naufalhakm",
+		Password:     "password123",
+		Invite:       "inv_123",
+	}
+
+	mockOrganizationRepo.On("FindOrganizationBySlug", mock.Anything, db, "acme").Return(&models.Organization{ID: 1, Slug: "acme"}, nil)
+	mockOrganizationRepo.On("FindInviteByToken", mock.Anything, db, "inv_123").Return(&models.Invite{ID: 7, TenantID: 1}, nil)
+	mockOrganizationRepo.On("UseInvite", mock.Anything, mock.Anything, &models.Invite{ID: 7, TenantID: 1}).Return(errors.New("invite not found"))
+	mockRepo.On("FindUserByUsername", mock.Anything, db, "naufalhakm").Return(nil, errors.New("users not found"))
+	mockRepo.On("CountUsers", mock.Anything, db).Return(int64(3), nil)
+
+	err := service.Register(context.Background(), validRequest)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, 400, err.StatusCode)
+	assert.Equal(t, "invite not found", err.AdditionalInfo)
+	mockRepo.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything, mock.Anything)
 }
 
 func TestRegister_ValidationErrorRequired(t *testing.T) {
 	// Arrange
 	mockRepo := new(repositories.MockUserRepository)
+	mockOrganizationRepo := new(repositories.MockOrganizationRepository)
 	db := new(gorm.DB)
-	service := NewUserService(mockRepo, db)
+	service := NewUserService(mockRepo, mockOrganizationRepo, nil, db)
 
 	invalidRequest := &params.UserRequest{
-		Username: "",
-		Password: "password",
+		Organization: "acme",
+		Username:     "",
+		Password:     "password",
 	}
 
 	err := service.Register(context.Background(), invalidRequest)
@@ -57,12 +180,14 @@ func TestRegister_ValidationErrorRequired(t *testing.T) {
 func TestRegister_ValidationErrorMaximal(t *testing.T) {
 	// Arrange
 	mockRepo := new(repositories.MockUserRepository)
+	mockOrganizationRepo := new(repositories.MockOrganizationRepository)
 	db := new(gorm.DB)
-	service := NewUserService(mockRepo, db)
+	service := NewUserService(mockRepo, mockOrganizationRepo, nil, db)
 
 	invalidRequest := &params.UserRequest{
-		Username: "naufalhakm",
-		Password: "qwerty",
+		Organization: "acme",
+		Username:     "naufalhakm",
+		Password:     "qwerty",
 	}
 
 	err := service.Register(context.Background(), invalidRequest)
@@ -75,20 +200,24 @@ func TestRegister_ValidationErrorMaximal(t *testing.T) {
 func TestRegister_UserAlreadyExists(t *testing.T) {
 	// Arrange
 	mockRepo := new(repositories.MockUserRepository)
+	mockOrganizationRepo := new(repositories.MockOrganizationRepository)
 	db := new(gorm.DB)
-	service := NewUserService(mockRepo, db)
+	service := NewUserService(mockRepo, mockOrganizationRepo, nil, db)
 
 	invalidRequest := &params.UserRequest{
-		Username: "naufalhakm",
-		Password: "qwerty",
+		Organization: "acme",
+		Username:     "naufalhakm",
+		Password:     "qwerty",
 	}
 
 	user := &models.User{
 		ID:       1,
+		TenantID: 1,
 		Username: "naufalhakm",
 		Password: "hashedpassword",
 	}
 
+	mockOrganizationRepo.On("FindOrganizationBySlug", mock.Anything, db, "acme").Return(&models.Organization{ID: 1, Slug: "acme"}, nil)
 	mockRepo.On("FindUserByUsername", mock.Anything, db, "naufalhakm").Return(user, nil)
 
 	err := service.Register(context.Background(), invalidRequest)
@@ -101,22 +230,26 @@ func TestRegister_UserAlreadyExists(t *testing.T) {
 
 func TestLogin_Success(t *testing.T) {
 	mockRepo := new(repositories.MockUserRepository)
+	mockOrganizationRepo := new(repositories.MockOrganizationRepository)
 	db := new(gorm.DB)
-	service := NewUserService(mockRepo, db)
+	service := NewUserService(mockRepo, mockOrganizationRepo, nil, db)
 
 	validRequest := &params.UserRequest{
-		Username: "naufalhakm",
-		Password: "password123",
+		Organization: "acme",
+		Username:     "naufalhakm",
+		Password:     "password123",
 	}
 
 	hashPaswword, _ := encryption.HashPassword(validRequest.Password)
 
 	user := &models.User{
 		ID:       1,
+		TenantID: 1,
 		Username: "naufalhakm",
 		Password: hashPaswword,
 	}
 
+	mockOrganizationRepo.On("FindOrganizationBySlug", mock.Anything, db, "acme").Return(&models.Organization{ID: 1, Slug: "acme"}, nil)
 	mockRepo.On("FindUserByUsername", mock.Anything, db, "naufalhakm").Return(user, nil)
 
 	result, err := service.Login(context.Background(), validRequest)
@@ -127,20 +260,27 @@ func TestLogin_Success(t *testing.T) {
 	assert.NotNil(t, result)
 	assert.NotNil(t, result.Token)
 
+	payload, errToken := token.ValidateToken(result.Token)
+	assert.Nil(t, errToken)
+	assert.Equal(t, 1, payload.TenantId)
+
 	mockRepo.AssertExpectations(t)
 }
 
 func TestLogin_UserNotFound(t *testing.T) {
 	// Arrange
 	mockRepo := new(repositories.MockUserRepository)
+	mockOrganizationRepo := new(repositories.MockOrganizationRepository)
 	db := new(gorm.DB)
-	service := NewUserService(mockRepo, db)
+	service := NewUserService(mockRepo, mockOrganizationRepo, nil, db)
 
 	invalidRequest := &params.UserRequest{
-		Username: "invaliduser",
-		Password: "password",
+		Organization: "acme",
+		Username:     "invaliduser",
+		Password:     "password",
 	}
 
+	mockOrganizationRepo.On("FindOrganizationBySlug", mock.Anything, db, "acme").Return(&models.Organization{ID: 1, Slug: "acme"}, nil)
 	mockRepo.On("FindUserByUsername", mock.Anything, db, "invaliduser").Return(nil, errors.New("user not found"))
 
 	_, err := service.Login(context.Background(), invalidRequest)
@@ -154,12 +294,14 @@ func TestLogin_UserNotFound(t *testing.T) {
 func TestLogin_ValidationErrorRequired(t *testing.T) {
 	// Arrange
 	mockRepo := new(repositories.MockUserRepository)
+	mockOrganizationRepo := new(repositories.MockOrganizationRepository)
 	db := new(gorm.DB)
-	service := NewUserService(mockRepo, db)
+	service := NewUserService(mockRepo, mockOrganizationRepo, nil, db)
 
 	invalidRequest := &params.UserRequest{
-		Username: "",
-		Password: "password",
+		Organization: "acme",
+		Username:     "",
+		Password:     "password",
 	}
 
 	_, err := service.Login(context.Background(), invalidRequest)
@@ -172,12 +314,14 @@ func TestLogin_ValidationErrorRequired(t *testing.T) {
 func TestLogin_ValidationErrorMinimun(t *testing.T) {
 	// Arrange
 	mockRepo := new(repositories.MockUserRepository)
+	mockOrganizationRepo := new(repositories.MockOrganizationRepository)
 	db := new(gorm.DB)
-	service := NewUserService(mockRepo, db)
+	service := NewUserService(mockRepo, mockOrganizationRepo, nil, db)
 
 	invalidRequest := &params.UserRequest{
-		Username: "naufalhakm",
-		Password: "naufal",
+		Organization: "acme",
+		Username:     "naufalhakm",
+		Password:     "naufal",
 	}
 
 	_, err := service.Login(context.Background(), invalidRequest)
@@ -186,3 +330,33 @@ func TestLogin_ValidationErrorMinimun(t *testing.T) {
 	assert.Equal(t, "BAD REQUEST ERROR", err.Message)
 	assert.Equal(t, 400, err.StatusCode)
 }
+
+func TestFindDetailUser_Success(t *testing.T) {
+	mockRepo := new(repositories.MockUserRepository)
+	mockOrganizationRepo := new(repositories.MockOrganizationRepository)
+	db := new(gorm.DB)
+	service := NewUserService(mockRepo, mockOrganizationRepo, nil, db)
+
+	mockRepo.On("FindUserById", mock.Anything, db, 7).Return(&models.User{ID: 7, Username: "naufalhakm", Password: "hash"}, nil)
+
+	result, err := service.FindDetailUser(context.Background(), 7)
+
+	assert.Nil(t, err)
+	assert.Equal(t, &params.UserDetailResponse{ID: 7, Username: "naufalhakm"}, result)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestFindDetailUser_NotFound(t *testing.T) {
+	mockRepo := new(repositories.MockUserRepository)
+	mockOrganizationRepo := new(repositories.MockOrganizationRepository)
+	db := new(gorm.DB)
+	service := NewUserService(mockRepo, mockOrganizationRepo, nil, db)
+
+	mockRepo.On("FindUserById", mock.Anything, db, 7).Return(nil, errors.New("user not found"))
+
+	result, err := service.FindDetailUser(context.Background(), 7)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, "NOT FOUND ERROR", err.Message)
+}
@@ -0,0 +1,447 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"golang-backend-test/app/commons/response"
+	"golang-backend-test/app/models"
+	"golang-backend-test/app/params"
+	"golang-backend-test/app/repositories"
+	"golang-backend-test/pkg/tenant"
+	"golang-backend-test/pkg/webhook"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator"
+	"gorm.io/gorm"
+)
+
+type WebhookService interface {
+	EventPublisher
+	FindAllSubscriptions(ctx context.Context) ([]*params.WebhookSubscriptionResponse, *response.CustomError)
+	FindDetailSubscription(ctx context.Context, id int) (*params.WebhookSubscriptionResponse, *response.CustomError)
+	CreateSubscription(ctx context.Context, req *params.WebhookSubscriptionRequest) (*params.WebhookSubscriptionResponse, *response.CustomError)
+	UpdateSubscription(ctx context.Context, id int, req *params.WebhookSubscriptionRequest) (*params.WebhookSubscriptionResponse, *response.CustomError)
+	DeleteSubscription(ctx context.Context, id int) *response.CustomError
+	FindDeliveries(ctx context.Context, subscriptionId int, req *params.WebhookDeliveryRequest) ([]*params.WebhookDeliveryResponse, *response.CustomError)
+	Redeliver(ctx context.Context, subscriptionId, deliveryId int) (*params.WebhookDeliveryResponse, *response.CustomError)
+	DeliverDue(ctx context.Context) *response.CustomError
+	PublishOverdueLoans(ctx context.Context) *response.CustomError
+	Run(ctx context.Context, interval time.Duration)
+}
+
+type WebhookServiceImpl struct {
+	WebhookRepository      repositories.WebhookRepository
+	OrganizationRepository repositories.OrganizationRepository
+	LoanRepository         repositories.LoanRepository
+	Client                 *http.Client
+	// a failed delivery is retried Backoff after its first attempt, twice
+	// as long after each further one up to maxWebhookBackoff, and is dead
+	// after MaxAttempts
+	MaxAttempts int
+	Backoff     time.Duration
+	DB          *gorm.DB
+}
+
+const (
+	defaultWebhookAttempts = 10
+	defaultWebhookBackoff  = 30 * time.Second
+	maxWebhookBackoff      = 6 * time.Hour
+	webhookBatchSize       = 100
+	webhookTimeout         = 10 * time.Second
+)
+
+// NewWebhookService builds the webhook service. client sends the
+// deliveries, a client with a 10 second timeout when nil.
+func NewWebhookService(webhookRepository repositories.WebhookRepository, organizationRepository repositories.OrganizationRepository, loanRepository repositories.LoanRepository, client *http.Client, db *gorm.DB) WebhookService {
+	if client == nil {
+		client = &http.Client{Timeout: webhookTimeout}
+	}
+	return &WebhookServiceImpl{
+		WebhookRepository:      webhookRepository,
+		OrganizationRepository: organizationRepository,
+		LoanRepository:         loanRepository,
+		Client:                 client,
+		MaxAttempts:            defaultWebhookAttempts,
+		Backoff:                defaultWebhookBackoff,
+		DB:                     db,
+	}
+}
+
+func (service *WebhookServiceImpl) FindAllSubscriptions(ctx context.Context) ([]*params.WebhookSubscriptionResponse, *response.CustomError) {
+	subscriptions, err := service.WebhookRepository.GetListSubscriptions(ctx, service.DB)
+	if err != nil {
+		return nil, response.RepositoryError()
+	}
+
+	result := []*params.WebhookSubscriptionResponse{}
+	for _, subscription := range subscriptions {
+		result = append(result, webhookSubscriptionResponse(subscription))
+	}
+	return result, nil
+}
+
+func (service *WebhookServiceImpl) FindDetailSubscription(ctx context.Context, id int) (*params.WebhookSubscriptionResponse, *response.CustomError) {
+	subscription, err := service.WebhookRepository.FindSubscriptionById(ctx, service.DB, id)
+	if err != nil {
+		return nil, response.NotFoundError()
+	}
+	return webhookSubscriptionResponse(subscription), nil
+}
+
+func (service *WebhookServiceImpl) CreateSubscription(ctx context.Context, req *params.WebhookSubscriptionRequest) (*params.WebhookSubscriptionResponse, *response.CustomError) {
+	if custErr := validateWebhookSubscription(req); custErr != nil {
+		return nil, custErr
+	}
+
+	var subscription = new(models.WebhookSubscription)
+	subscription.URL = req.URL
+	subscription.Events = strings.Join(req.Events, ",")
+	subscription.Active = req.Active == nil || *req.Active
+	subscription.Secret = req.Secret
+	if subscription.Secret == "" {
+		secret, err := webhook.NewSecret()
+		if err != nil {
+			return nil, response.GeneralErrorWithAdditionalInfo(err.Error())
+		}
+		subscription.Secret = secret
+	}
+	if err := service.WebhookRepository.CreateSubscription(ctx, service.DB, subscription); err != nil {
+		return nil, response.RepositoryError()
+	}
+
+	// the secret is only shown once
+	result := webhookSubscriptionResponse(subscription)
+	result.Secret = subscription.Secret
+	return result, nil
+}
+
+// UpdateSubscription replaces the URL, events and state of a subscription.
+// Its secret is kept unless the request gives a new one.
+func (service *WebhookServiceImpl) UpdateSubscription(ctx context.Context, id int, req *params.WebhookSubscriptionRequest) (*params.WebhookSubscriptionResponse, *response.CustomError) {
+	if custErr := validateWebhookSubscription(req); custErr != nil {
+		return nil, custErr
+	}
+	subscription, err := service.WebhookRepository.FindSubscriptionById(ctx, service.DB, id)
+	if err != nil {
+		return nil, response.NotFoundError()
+	}
+
+	subscription.URL = req.URL
+	subscription.Events = strings.Join(req.Events, ",")
+	subscription.Active = req.Active == nil || *req.Active
+	if req.Secret != "" {
+		subscription.Secret = req.Secret
+	}
+	if err := service.WebhookRepository.UpdateSubscription(ctx, service.DB, subscription); err != nil {
+		return nil, response.RepositoryError()
+	}
+	return webhookSubscriptionResponse(subscription), nil
+}
+
+func (service *WebhookServiceImpl) DeleteSubscription(ctx context.Context, id int) *response.CustomError {
+	if err := service.WebhookRepository.DeleteSubscription(ctx, service.DB, id); err != nil {
+		return response.NotFoundError()
+	}
+	return nil
+}
+
+func (service *WebhookServiceImpl) FindDeliveries(ctx context.Context, subscriptionId int, req *params.WebhookDeliveryRequest) ([]*params.WebhookDeliveryResponse, *response.CustomError) {
+	val := validator.New()
+	if err := val.Struct(req); err != nil {
+		return nil, response.BadRequestErrorWithAdditionalInfo(err.Error())
+	}
+	if _, err := service.WebhookRepository.FindSubscriptionById(ctx, service.DB, subscriptionId); err != nil {
+		return nil, response.NotFoundError()
+	}
+
+	deliveries, err := service.WebhookRepository.GetListDeliveries(ctx, service.DB, subscriptionId, req.Status)
+	if err != nil {
+		return nil, response.RepositoryError()
+	}
+	result := []*params.WebhookDeliveryResponse{}
+	for _, delivery := range deliveries {
+		result = append(result, webhookDeliveryResponse(delivery))
+	}
+	return result, nil
+}
+
+// Redeliver queues the event of a delivery again as a new delivery, which
+// keeps the event id so that receivers can tell it is the same event.
+func (service *WebhookServiceImpl) Redeliver(ctx context.Context, subscriptionId, deliveryId int) (*params.WebhookDeliveryResponse, *response.CustomError) {
+	previous, err := service.WebhookRepository.FindDeliveryById(ctx, service.DB, subscriptionId, deliveryId)
+	if err != nil {
+		return nil, response.NotFoundError()
+	}
+
+	now := time.Now()
+	delivery := &models.WebhookDelivery{
+		SubscriptionID: previous.SubscriptionID,
+		EventID:        previous.EventID,
+		Event:          previous.Event,
+		Payload:        previous.Payload,
+		Status:         models.WebhookDeliveryPending,
+		NextAttemptAt:  now,
+		CreatedAt:      now,
+	}
+	if err := service.WebhookRepository.CreateDelivery(ctx, service.DB, delivery); err != nil {
+		return nil, response.RepositoryError()
+	}
+	return webhookDeliveryResponse(delivery), nil
+}
+
+// Publish queues a delivery of the event to every active subscription of
+// the caller's organization that listens to it. The event id is the one
+// the outbox gave it, so that receivers can tell an event they had.
+func (service *WebhookServiceImpl) Publish(ctx context.Context, message *params.EventMessage) *response.CustomError {
+	return service.queue(ctx, "evt_"+strconv.Itoa(int(message.ID)), message.Event, message.CreatedAt, message.Data)
+}
+
+// queue queues a delivery of an event to the subscriptions that listen to
+// it, once per subscription however often the event comes.
+func (service *WebhookServiceImpl) queue(ctx context.Context, eventId string, event string, createdAt time.Time, data interface{}) *response.CustomError {
+	subscriptions, err := service.WebhookRepository.GetListSubscriptions(ctx, service.DB)
+	if err != nil {
+		return response.RepositoryError()
+	}
+	var subscribed []*models.WebhookSubscription
+	for _, subscription := range subscriptions {
+		if subscription.Active && listensTo(subscription, event) {
+			subscribed = append(subscribed, subscription)
+		}
+	}
+	if len(subscribed) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(&params.WebhookEvent{
+		ID:        eventId,
+		Event:     event,
+		CreatedAt: createdAt.UTC().Format(time.RFC3339),
+		Data:      data,
+	})
+	if err != nil {
+		return response.GeneralErrorWithAdditionalInfo(err.Error())
+	}
+	now := time.Now()
+	for _, subscription := range subscribed {
+		if _, err := service.WebhookRepository.FindDeliveryByEventId(ctx, service.DB, int(subscription.ID), eventId); err == nil {
+			continue
+		}
+		delivery := &models.WebhookDelivery{
+			SubscriptionID: subscription.ID,
+			EventID:        eventId,
+			Event:          event,
+			Payload:        payload,
+			Status:         models.WebhookDeliveryPending,
+			NextAttemptAt:  now,
+			CreatedAt:      now,
+		}
+		if err := service.WebhookRepository.CreateDelivery(ctx, service.DB, delivery); err != nil {
+			return response.RepositoryError()
+		}
+	}
+	return nil
+}
+
+// DeliverDue attempts the deliveries of every organization that are due.
+func (service *WebhookServiceImpl) DeliverDue(ctx context.Context) *response.CustomError {
+	organizations, err := service.OrganizationRepository.GetListOrganizations(ctx, service.DB)
+	if err != nil {
+		return response.RepositoryError()
+	}
+	for _, organization := range organizations {
+		tenantCtx := tenant.WithID(ctx, organization.ID)
+		deliveries, err := service.WebhookRepository.GetDueDeliveries(tenantCtx, service.DB, time.Now(), webhookBatchSize)
+		if err != nil {
+			return response.RepositoryError()
+		}
+		for _, delivery := range deliveries {
+			service.attempt(tenantCtx, delivery)
+			if err := service.WebhookRepository.UpdateDelivery(tenantCtx, service.DB, delivery); err != nil {
+				return response.RepositoryError()
+			}
+		}
+	}
+	return nil
+}
+
+// PublishOverdueLoans publishes loan.overdue once for every loan that went
+// past its due date.
+func (service *WebhookServiceImpl) PublishOverdueLoans(ctx context.Context) *response.CustomError {
+	organizations, err := service.OrganizationRepository.GetListOrganizations(ctx, service.DB)
+	if err != nil {
+		return response.RepositoryError()
+	}
+	for _, organization := range organizations {
+		tenantCtx := tenant.WithID(ctx, organization.ID)
+		now := time.Now()
+		loans, err := service.LoanRepository.GetOverdueLoans(tenantCtx, service.DB, now)
+		if err != nil {
+			return response.RepositoryError()
+		}
+		for _, loan := range loans {
+			eventId := "evt_loan_" + strconv.Itoa(int(loan.ID)) + "_overdue"
+			if custErr := service.queue(tenantCtx, eventId, EventLoanOverdue, now, loanResponse(loan)); custErr != nil {
+				return custErr
+			}
+			loan.OverdueNotifiedAt = &now
+			if err := service.LoanRepository.UpdateLoan(tenantCtx, service.DB, loan); err != nil {
+				return response.RepositoryError()
+			}
+		}
+	}
+	return nil
+}
+
+// Run publishes overdue loans and attempts due deliveries every interval
+// until ctx is done.
+func (service *WebhookServiceImpl) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if custErr := service.PublishOverdueLoans(ctx); custErr != nil {
+			log.Printf("publishing overdue loans: %s %v", custErr.Message, custErr.AdditionalInfo)
+		}
+		if custErr := service.DeliverDue(ctx); custErr != nil {
+			log.Printf("delivering webhooks: %s %v", custErr.Message, custErr.AdditionalInfo)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// attempt sends a delivery once and records the outcome on it: delivered,
+// pending with its next attempt scheduled, or dead.
+func (service *WebhookServiceImpl) attempt(ctx context.Context, delivery *models.WebhookDelivery) {
+	now := time.Now()
+	statusCode, err := service.send(ctx, delivery, now)
+	delivery.Attempts++
+	delivery.LastStatusCode = statusCode
+	delivery.LastError = ""
+	switch {
+	case err == nil:
+		delivery.Status = models.WebhookDeliveryDelivered
+		delivery.DeliveredAt = &now
+	case delivery.Attempts >= service.MaxAttempts || !delivery.Subscription.Active:
+		delivery.Status = models.WebhookDeliveryDead
+		delivery.LastError = truncate(err.Error(), 1024)
+	default:
+		delivery.LastError = truncate(err.Error(), 1024)
+		delivery.NextAttemptAt = now.Add(service.backoff(delivery.Attempts))
+	}
+}
+
+func (service *WebhookServiceImpl) send(ctx context.Context, delivery *models.WebhookDelivery, now time.Time) (int, error) {
+	subscription := delivery.Subscription
+	if !subscription.Active {
+		return 0, fmt.Errorf("subscription %d is not active", subscription.ID)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "golang-backend-test-webhooks")
+	req.Header.Set(webhook.EventHeader, delivery.Event)
+	req.Header.Set(webhook.DeliveryHeader, strconv.Itoa(int(delivery.ID)))
+	req.Header.Set(webhook.TimestampHeader, strconv.FormatInt(now.Unix(), 10))
+	req.Header.Set(webhook.SignatureHeader, webhook.Sign(subscription.Secret, now, delivery.Payload))
+
+	resp, err := service.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("receiver answered %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// backoff is the delay before the attempt after the given number of failed
+// ones.
+func (service *WebhookServiceImpl) backoff(attempts int) time.Duration {
+	delay := service.Backoff
+	for i := 1; i < attempts && delay < maxWebhookBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxWebhookBackoff {
+		delay = maxWebhookBackoff
+	}
+	return delay
+}
+
+func validateWebhookSubscription(req *params.WebhookSubscriptionRequest) *response.CustomError {
+	val := validator.New()
+	err := val.Struct(req)
+	if err != nil {
+		validationErrors := err.(validator.ValidationErrors)
+		var errors []interface{}
+		for _, fieldError := range validationErrors {
+			error := "error " + fieldError.Field() + " on tag " + fieldError.Tag()
+			errors = append(errors, error)
+		}
+		return response.BadRequestErrorWithAdditionalInfo(errors)
+	}
+	if !strings.HasPrefix(req.URL, "http://") && !strings.HasPrefix(req.URL, "https://") {
+		return response.BadRequestErrorWithAdditionalInfo("url must be http or https")
+	}
+	return nil
+}
+
+func listensTo(subscription *models.WebhookSubscription, event string) bool {
+	for _, name := range strings.Split(subscription.Events, ",") {
+		if name == event {
+			return true
+		}
+	}
+	return false
+}
+
+func truncate(value string, length int) string {
+	if len(value) > length {
+		return value[:length]
+	}
+	return value
+}
+
+func webhookSubscriptionResponse(subscription *models.WebhookSubscription) *params.WebhookSubscriptionResponse {
+	return &params.WebhookSubscriptionResponse{
+		ID:        subscription.ID,
+		URL:       subscription.URL,
+		Events:    strings.Split(subscription.Events, ","),
+		Active:    subscription.Active,
+		CreatedAt: subscription.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+func webhookDeliveryResponse(delivery *models.WebhookDelivery) *params.WebhookDeliveryResponse {
+	result := &params.WebhookDeliveryResponse{
+		ID:             delivery.ID,
+		EventID:        delivery.EventID,
+		Event:          delivery.Event,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		LastStatusCode: delivery.LastStatusCode,
+		LastError:      delivery.LastError,
+		CreatedAt:      delivery.CreatedAt.UTC().Format(time.RFC3339),
+	}
+	if delivery.Status == models.WebhookDeliveryPending {
+		result.NextAttemptAt = delivery.NextAttemptAt.UTC().Format(time.RFC3339)
+	}
+	if delivery.DeliveredAt != nil {
+		result.DeliveredAt = delivery.DeliveredAt.UTC().Format(time.RFC3339)
+	}
+	return result
+}
@@ -0,0 +1,295 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"golang-backend-test/app/models"
+	"golang-backend-test/app/params"
+	"golang-backend-test/app/repositories"
+	"golang-backend-test/pkg/webhook"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+const testWebhookSecret = "whsec_0123456789abcdef"
+
+// receivedDelivery is what the test receiver saw of a delivery.
+type receivedDelivery struct {
+	header http.Header
+	body   []byte
+	err    error
+}
+
+// newWebhookReceiver starts a receiver that checks signatures and answers
+// with status.
+func newWebhookReceiver(t *testing.T, status int) (*httptest.Server, chan receivedDelivery) {
+	received := make(chan receivedDelivery, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		err := webhook.Verify(testWebhookSecret, r.Header.Get(webhook.SignatureHeader), r.Header.Get(webhook.TimestampHeader), body, 5*time.Minute, time.Now())
+		received <- receivedDelivery{header: r.Header, body: body, err: err}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server, received
+}
+
+func newTestWebhookService(client *http.Client) (*WebhookServiceImpl, *repositories.MockWebhookRepository, *repositories.MockOrganizationRepository, *repositories.MockLoanRepository, *gorm.DB) {
+	webhookRepo := new(repositories.MockWebhookRepository)
+	organizationRepo := new(repositories.MockOrganizationRepository)
+	loanRepo := new(repositories.MockLoanRepository)
+	db := new(gorm.DB)
+	service := NewWebhookService(webhookRepo, organizationRepo, loanRepo, client, db).(*WebhookServiceImpl)
+	return service, webhookRepo, organizationRepo, loanRepo, db
+}
+
+func dueDelivery(url string, attempts int) *models.WebhookDelivery {
+	return &models.WebhookDelivery{
+		ID:             7,
+		SubscriptionID: 1,
+		Subscription:   models.WebhookSubscription{ID: 1, URL: url, Secret: testWebhookSecret, Events: EventBookCreated, Active: true},
+		EventID:        "evt_1",
+		Event:          EventBookCreated,
+		Payload:        []byte(`{"id":"evt_1","event":"book.created","data":{"id":1}}`),
+		Status:         models.WebhookDeliveryPending,
+		Attempts:       attempts,
+	}
+}
+
+func TestCreateSubscription_GeneratesSecret(t *testing.T) {
+	service, webhookRepo, _, _, db := newTestWebhookService(nil)
+
+	webhookRepo.On("CreateSubscription", mock.Anything, db, mock.MatchedBy(func(subscription *models.WebhookSubscription) bool {
+		return subscription.Events == "book.created,author.deleted" && subscription.Active
+	})).Return(nil)
+
+	result, err := service.CreateSubscription(context.Background(), &params.WebhookSubscriptionRequest{
+		URL:    "https://example.com/hooks",
+		Events: []string{EventBookCreated, EventAuthorDeleted},
+	})
+
+	assert.Nil(t, err)
+	assert.Contains(t, result.Secret, "whsec_")
+	assert.Equal(t, []string{EventBookCreated, EventAuthorDeleted}, result.Events)
+	webhookRepo.AssertExpectations(t)
+}
+
+func TestCreateSubscription_ValidationError(t *testing.T) {
+	service, webhookRepo, _, _, _ := newTestWebhookService(nil)
+
+	_, err := service.CreateSubscription(context.Background(), &params.WebhookSubscriptionRequest{
+		URL:    "ftp://example.com/hooks",
+		Events: []string{EventBookCreated},
+	})
+	assert.NotNil(t, err)
+	assert.Equal(t, 400, err.StatusCode)
+
+	_, err = service.CreateSubscription(context.Background(), &params.WebhookSubscriptionRequest{
+		URL:    "https://example.com/hooks",
+		Events: []string{"book.read"},
+	})
+	assert.NotNil(t, err)
+	assert.Equal(t, 400, err.StatusCode)
+	webhookRepo.AssertNotCalled(t, "CreateSubscription", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUpdateSubscription_KeepsSecret(t *testing.T) {
+	service, webhookRepo, _, _, db := newTestWebhookService(nil)
+	active := false
+
+	webhookRepo.On("FindSubscriptionById", mock.Anything, db, 1).Return(&models.WebhookSubscription{ID: 1, Secret: testWebhookSecret, Active: true}, nil)
+	webhookRepo.On("UpdateSubscription", mock.Anything, db, mock.MatchedBy(func(subscription *models.WebhookSubscription) bool {
+		return subscription.Secret == testWebhookSecret && !subscription.Active
+	})).Return(nil)
+
+	result, err := service.UpdateSubscription(context.Background(), 1, &params.WebhookSubscriptionRequest{
+		URL:    "https://example.com/hooks",
+		Events: []string{EventLoanOverdue},
+		Active: &active,
+	})
+
+	assert.Nil(t, err)
+	assert.Empty(t, result.Secret)
+	webhookRepo.AssertExpectations(t)
+}
+
+func TestPublish_QueuesSubscribedDeliveries(t *testing.T) {
+	service, webhookRepo, _, _, db := newTestWebhookService(nil)
+
+	webhookRepo.On("GetListSubscriptions", mock.Anything, db).Return([]*models.WebhookSubscription{
+		{ID: 1, Events: "book.created,book.updated", Active: true},
+		{ID: 2, Events: "author.deleted", Active: true},
+		{ID: 3, Events: "book.created", Active: false},
+	}, nil)
+	webhookRepo.On("FindDeliveryByEventId", mock.Anything, db, 1, "evt_7").Return(nil, errors.New("delivery not found"))
+	var queued []*models.WebhookDelivery
+	webhookRepo.On("CreateDelivery", mock.Anything, db, mock.AnythingOfType("*models.WebhookDelivery")).Run(func(args mock.Arguments) {
+		queued = append(queued, args.Get(2).(*models.WebhookDelivery))
+	}).Return(nil)
+
+	err := service.Publish(context.Background(), &params.EventMessage{ID: 7, Event: EventBookCreated, Aggregate: "book", Data: []byte(`{"id":4}`)})
+
+	assert.Nil(t, err)
+	assert.Len(t, queued, 1)
+	assert.Equal(t, "evt_7", queued[0].EventID)
+	assert.Equal(t, uint(1), queued[0].SubscriptionID)
+	assert.Equal(t, models.WebhookDeliveryPending, queued[0].Status)
+
+	var event params.WebhookEvent
+	assert.NoError(t, json.Unmarshal(queued[0].Payload, &event))
+	assert.Equal(t, queued[0].EventID, event.ID)
+	assert.Equal(t, EventBookCreated, event.Event)
+	assert.Equal(t, map[string]interface{}{"id": float64(4)}, event.Data)
+}
+
+func TestPublish_NoSubscribers(t *testing.T) {
+	service, webhookRepo, _, _, db := newTestWebhookService(nil)
+
+	webhookRepo.On("GetListSubscriptions", mock.Anything, db).Return([]*models.WebhookSubscription{}, nil)
+
+	err := service.Publish(context.Background(), &params.EventMessage{ID: 7, Event: EventBookDeleted, Aggregate: "book", Data: []byte(`{"id":4}`)})
+
+	assert.Nil(t, err)
+	webhookRepo.AssertNotCalled(t, "CreateDelivery", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPublish_SkipsRelayedEvent(t *testing.T) {
+	service, webhookRepo, _, _, db := newTestWebhookService(nil)
+
+	webhookRepo.On("GetListSubscriptions", mock.Anything, db).Return([]*models.WebhookSubscription{{ID: 1, Events: EventBookCreated, Active: true}}, nil)
+	webhookRepo.On("FindDeliveryByEventId", mock.Anything, db, 1, "evt_7").Return(&models.WebhookDelivery{ID: 3, EventID: "evt_7"}, nil)
+
+	err := service.Publish(context.Background(), &params.EventMessage{ID: 7, Event: EventBookCreated, Aggregate: "book", Data: []byte(`{"id":4}`)})
+
+	assert.Nil(t, err)
+	webhookRepo.AssertNotCalled(t, "CreateDelivery", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestDeliverDue_SignedDelivery(t *testing.T) {
+	server, received := newWebhookReceiver(t, http.StatusNoContent)
+	service, webhookRepo, organizationRepo, _, db := newTestWebhookService(server.Client())
+	delivery := dueDelivery(server.URL, 0)
+
+	organizationRepo.On("GetListOrganizations", mock.Anything, db).Return([]*models.Organization{{ID: 1}}, nil)
+	webhookRepo.On("GetDueDeliveries", mock.Anything, db, mock.AnythingOfType("time.Time"), webhookBatchSize).Return([]*models.WebhookDelivery{delivery}, nil)
+	webhookRepo.On("UpdateDelivery", mock.Anything, db, delivery).Return(nil)
+
+	err := service.DeliverDue(context.Background())
+
+	assert.Nil(t, err)
+	got := <-received
+	assert.NoError(t, got.err)
+	assert.Equal(t, delivery.Payload, got.body)
+	assert.Equal(t, EventBookCreated, got.header.Get(webhook.EventHeader))
+	assert.Equal(t, "7", got.header.Get(webhook.DeliveryHeader))
+	assert.Equal(t, models.WebhookDeliveryDelivered, delivery.Status)
+	assert.Equal(t, 1, delivery.Attempts)
+	assert.Equal(t, http.StatusNoContent, delivery.LastStatusCode)
+	assert.NotNil(t, delivery.DeliveredAt)
+	webhookRepo.AssertExpectations(t)
+}
+
+func TestDeliverDue_RetriesWithBackoff(t *testing.T) {
+	server, received := newWebhookReceiver(t, http.StatusInternalServerError)
+	service, webhookRepo, organizationRepo, _, db := newTestWebhookService(server.Client())
+	delivery := dueDelivery(server.URL, 2)
+
+	organizationRepo.On("GetListOrganizations", mock.Anything, db).Return([]*models.Organization{{ID: 1}}, nil)
+	webhookRepo.On("GetDueDeliveries", mock.Anything, db, mock.AnythingOfType("time.Time"), webhookBatchSize).Return([]*models.WebhookDelivery{delivery}, nil)
+	webhookRepo.On("UpdateDelivery", mock.Anything, db, delivery).Return(nil)
+
+	before := time.Now()
+	err := service.DeliverDue(context.Background())
+
+	assert.Nil(t, err)
+	assert.NoError(t, (<-received).err)
+	assert.Equal(t, models.WebhookDeliveryPending, delivery.Status)
+	assert.Equal(t, 3, delivery.Attempts)
+	assert.Equal(t, http.StatusInternalServerError, delivery.LastStatusCode)
+	assert.Contains(t, delivery.LastError, "500")
+	// the third failure waits four times the first delay
+	assert.WithinDuration(t, before.Add(4*defaultWebhookBackoff), delivery.NextAttemptAt, 5*time.Second)
+}
+
+func TestDeliverDue_DeadAfterLastAttempt(t *testing.T) {
+	server, received := newWebhookReceiver(t, http.StatusBadGateway)
+	service, webhookRepo, organizationRepo, _, db := newTestWebhookService(server.Client())
+	delivery := dueDelivery(server.URL, defaultWebhookAttempts-1)
+
+	organizationRepo.On("GetListOrganizations", mock.Anything, db).Return([]*models.Organization{{ID: 1}}, nil)
+	webhookRepo.On("GetDueDeliveries", mock.Anything, db, mock.AnythingOfType("time.Time"), webhookBatchSize).Return([]*models.WebhookDelivery{delivery}, nil)
+	webhookRepo.On("UpdateDelivery", mock.Anything, db, delivery).Return(nil)
+
+	err := service.DeliverDue(context.Background())
+
+	assert.Nil(t, err)
+	<-received
+	assert.Equal(t, models.WebhookDeliveryDead, delivery.Status)
+	assert.Equal(t, defaultWebhookAttempts, delivery.Attempts)
+}
+
+func TestBackoff_Capped(t *testing.T) {
+	service, _, _, _, _ := newTestWebhookService(nil)
+
+	assert.Equal(t, defaultWebhookBackoff, service.backoff(1))
+	assert.Equal(t, 2*defaultWebhookBackoff, service.backoff(2))
+	assert.Equal(t, maxWebhookBackoff, service.backoff(40))
+}
+
+func TestRedeliver_Success(t *testing.T) {
+	service, webhookRepo, _, _, db := newTestWebhookService(nil)
+	previous := dueDelivery("https://example.com/hooks", defaultWebhookAttempts)
+	previous.Status = models.WebhookDeliveryDead
+
+	webhookRepo.On("FindDeliveryById", mock.Anything, db, 1, 7).Return(previous, nil)
+	webhookRepo.On("CreateDelivery", mock.Anything, db, mock.MatchedBy(func(delivery *models.WebhookDelivery) bool {
+		return delivery.EventID == "evt_1" && delivery.Attempts == 0 && delivery.Status == models.WebhookDeliveryPending
+	})).Return(nil)
+
+	result, err := service.Redeliver(context.Background(), 1, 7)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "evt_1", result.EventID)
+	assert.Equal(t, models.WebhookDeliveryPending, result.Status)
+	webhookRepo.AssertExpectations(t)
+}
+
+func TestRedeliver_NotFound(t *testing.T) {
+	service, webhookRepo, _, _, db := newTestWebhookService(nil)
+
+	webhookRepo.On("FindDeliveryById", mock.Anything, db, 1, 7).Return(nil, errors.New("record not found"))
+
+	_, err := service.Redeliver(context.Background(), 1, 7)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "NOT FOUND ERROR", err.Message)
+}
+
+func TestPublishOverdueLoans_NotifiesOnce(t *testing.T) {
+	service, webhookRepo, organizationRepo, loanRepo, db := newTestWebhookService(nil)
+	loan := &models.Loan{ID: 3, BookCopyID: 5, UserID: 2, DueAt: time.Now().Add(-time.Hour)}
+
+	organizationRepo.On("GetListOrganizations", mock.Anything, db).Return([]*models.Organization{{ID: 1}}, nil)
+	loanRepo.On("GetOverdueLoans", mock.Anything, db, mock.AnythingOfType("time.Time")).Return([]*models.Loan{loan}, nil)
+	loanRepo.On("UpdateLoan", mock.Anything, db, loan).Return(nil)
+	webhookRepo.On("GetListSubscriptions", mock.Anything, db).Return([]*models.WebhookSubscription{{ID: 1, Events: EventLoanOverdue, Active: true}}, nil)
+	webhookRepo.On("FindDeliveryByEventId", mock.Anything, db, 1, "evt_loan_3_overdue").Return(nil, errors.New("delivery not found"))
+	webhookRepo.On("CreateDelivery", mock.Anything, db, mock.MatchedBy(func(delivery *models.WebhookDelivery) bool {
+		return delivery.Event == EventLoanOverdue && delivery.EventID == "evt_loan_3_overdue"
+	})).Return(nil)
+
+	err := service.PublishOverdueLoans(context.Background())
+
+	assert.Nil(t, err)
+	assert.NotNil(t, loan.OverdueNotifiedAt)
+	loanRepo.AssertExpectations(t)
+	webhookRepo.AssertExpectations(t)
+}
@@ -2,17 +2,103 @@ package database
 
 import (
 	"golang-backend-test/app/models"
+	"golang-backend-test/pkg/tenant"
+	"time"
 
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
 
+// tenantModels are the models whose rows belong to an organization.
+var tenantModels = []interface{}{
+	&models.Author{}, &models.AuthorAlias{}, &models.AuthorPseudonym{}, &models.AuthorRedirect{},
+	&models.Book{}, &models.Branch{}, &models.ShelfLocation{}, &models.BookCopy{},
+	&models.BookRating{}, &models.Loan{}, &models.Transfer{}, &models.Stocktake{}, &models.StocktakeScan{},
+	&models.StocktakeDiscrepancy{}, &models.Vendor{}, &models.Fund{}, &models.PurchaseOrder{}, &models.PurchaseOrderLine{},
+	&models.IdempotencyKey{}, &models.User{}, &models.WebhookSubscription{}, &models.WebhookDelivery{},
+	&models.CatalogEvent{}, &models.OutboxEvent{}, &models.Job{}, &models.Invite{},
+}
+
 func NewSQLiteConnection() (*gorm.DB, error) {
 	db, err := gorm.Open(sqlite.Open("./database/bayarind.db"), &gorm.Config{})
 	if err != nil {
 		return nil, err
 	}
+	return migrate(db)
+}
+
+// NewSQLiteMemoryConnection opens an empty database that lives as long as
+// the connection, for tests.
+func NewSQLiteMemoryConnection() (*gorm.DB, error) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		return nil, err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	// every connection would get a database of its own
+	sqlDB.SetMaxOpenConns(1)
+	return migrate(db)
+}
 
-	db.AutoMigrate(&models.Author{}, &models.Book{}, &models.User{})
+func migrate(db *gorm.DB) (*gorm.DB, error) {
+	db.AutoMigrate(append([]interface{}{&models.Organization{}}, tenantModels...)...)
+	if err := backfillDefaultOrganization(db); err != nil {
+		return nil, err
+	}
+	if err := backfillAdmins(db); err != nil {
+		return nil, err
+	}
+	if err := backfillBookCreatedAt(db); err != nil {
+		return nil, err
+	}
+	if err := tenant.Register(db, tenantModels...); err != nil {
+		return nil, err
+	}
 	return db, nil
 }
+
+// backfillDefaultOrganization moves the data of a database created before
+// organizations existed into a "default" organization.
+func backfillDefaultOrganization(db *gorm.DB) error {
+	var count int64
+	if err := db.Model(&models.Organization{}).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		organization := &models.Organization{Name: "Default", Slug: "default"}
+		if err := tx.Create(organization).Error; err != nil {
+			return err
+		}
+		for _, model := range tenantModels {
+			stmt := &gorm.Statement{DB: tx}
+			if err := stmt.Parse(model); err != nil {
+				return err
+			}
+			err := tx.Exec("UPDATE "+stmt.Table+" SET "+tenant.Column+" = ? WHERE "+tenant.Column+" IS NULL OR "+tenant.Column+" = 0", organization.ID).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// backfillAdmins makes the first user of every organization without an
+// admin one, as registering does for new organizations.
+func backfillAdmins(db *gorm.DB) error {
+	return db.Exec("UPDATE users SET admin = true WHERE id IN (SELECT MIN(id) FROM users GROUP BY tenant_id HAVING MAX(COALESCE(admin, false)) = false)").Error
+}
+
+// backfillBookCreatedAt dates the books of a database created before books
+// had a creation time to the migration, so that reports over a period count
+// them instead of leaving them out of every period.
+func backfillBookCreatedAt(db *gorm.DB) error {
+	return db.Exec("UPDATE books SET created_at = ? WHERE created_at IS NULL", time.Now()).Error
+}
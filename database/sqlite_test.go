@@ -0,0 +1,35 @@
+package database
+
+import (
+	"context"
+	"golang-backend-test/app/models"
+	"golang-backend-test/pkg/tenant"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestMigrate_BackfillsBookCreatedAt(t *testing.T) {
+	db, errDB := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	assert.Nil(t, errDB)
+	sqlDB, errDB := db.DB()
+	assert.Nil(t, errDB)
+	sqlDB.SetMaxOpenConns(1)
+	// a book stored before books had a creation time
+	assert.Nil(t, db.AutoMigrate(&models.Author{}, &models.Book{}))
+	assert.Nil(t, db.Exec("INSERT INTO authors (name) VALUES ('Legacy Author')").Error)
+	assert.Nil(t, db.Exec("INSERT INTO books (title, author_id, created_at) VALUES ('Legacy Book', 1, NULL)").Error)
+	before := time.Now()
+
+	_, err := migrate(db)
+
+	assert.Nil(t, err)
+	var createdAt []time.Time
+	assert.Nil(t, db.WithContext(tenant.WithID(context.Background(), 1)).Model(&models.Book{}).Pluck("created_at", &createdAt).Error)
+	if assert.Len(t, createdAt, 1) {
+		assert.False(t, createdAt[0].Before(before.Truncate(time.Second)))
+	}
+}
@@ -1,36 +1,239 @@
 package factory
 
 import (
+	"fmt"
 	"golang-backend-test/app/controllers"
 	"golang-backend-test/app/repositories"
+	"golang-backend-test/app/rpc"
 	"golang-backend-test/app/services"
+	"golang-backend-test/pkg/metadata"
+	"golang-backend-test/pkg/pubsub"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
 )
 
 type Provider struct {
-	UserProvider   controllers.UserController
-	BookProvider   controllers.BookController
-	AuthorProvider controllers.AuthorController
+	OrganizationProvider controllers.OrganizationController
+	UserProvider         controllers.UserController
+	BookProvider         controllers.BookController
+	AuthorProvider       controllers.AuthorController
+	ReportProvider       controllers.ReportController
+	LabelProvider        controllers.LabelController
+	LoanProvider         controllers.LoanController
+	BranchProvider       controllers.BranchController
+	BookCopyProvider     controllers.BookCopyController
+	TransferProvider     controllers.TransferController
+	StocktakeProvider    controllers.StocktakeController
+	AcquisitionProvider  controllers.AcquisitionController
+	BatchProvider        controllers.BatchController
+	GraphQLProvider      controllers.GraphQLController
+	BookV2Provider       controllers.BookV2Controller
+	AuthorV2Provider     controllers.AuthorV2Controller
+	WebhookProvider      controllers.WebhookController
+	EventProvider        controllers.EventController
+	JobProvider          controllers.JobController
+	IdempotencyProvider  services.IdempotencyService
+	UserServiceProvider  services.UserService
+	// WebhookServiceProvider runs the deliveries in the background
+	WebhookServiceProvider services.WebhookService
+	// OutboxServiceProvider relays the recorded events in the background
+	OutboxServiceProvider services.OutboxService
+	// JobServiceProvider runs the queued jobs in the background; services
+	// give it their job handlers
+	JobServiceProvider services.JobService
+	BookRPCProvider    *rpc.BookServer
+	AuthorRPCProvider  *rpc.AuthorServer
+	AuthRPCProvider    *rpc.AuthServer
+	ValidateRequests   bool
+	// OperatorKey lets operators create organizations; without it nobody
+	// can
+	OperatorKey string
 }
 
-func InitFactory(db *gorm.DB) *Provider {
+func InitFactory(db *gorm.DB) (*Provider, error) {
+
+	organizationRepo := repositories.NewOrganizationRepository()
+	organizationService := services.NewOrganizationService(organizationRepo, db)
+	organizationController := controllers.NewOrganizationController(organizationService)
+
+	// HTTP clients follow redirects, which a webhook receiver has no use for
+	webhookClient := &http.Client{
+		Timeout: 10 * time.Second,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	webhookRepo := repositories.NewWebhookRepository()
+	loanRepo := repositories.NewLoanRepository()
+	webhookService := services.NewWebhookService(webhookRepo, organizationRepo, loanRepo, webhookClient, db)
+	webhookController := controllers.NewWebhookController(webhookService)
+
+	// the event stream keeps the last 1000 changes of every organization
+	catalogEventRepo := repositories.NewCatalogEventRepository()
+	streamService := services.NewStreamService(catalogEventRepo, pubsub.NewBus(), 1000, db)
+	eventController := controllers.NewEventController(streamService)
+
+	// OUTBOX_SINKS lists, separated by commas, where recorded events are
+	// relayed: "log", "webhook" and "stream"
+	sinkNames := os.Getenv("OUTBOX_SINKS")
+	if sinkNames == "" {
+		sinkNames = "webhook,stream"
+	}
+	var sinks []services.EventPublisher
+	for _, name := range strings.Split(sinkNames, ",") {
+		switch strings.TrimSpace(name) {
+		case "log":
+			sinks = append(sinks, services.LogPublisher{})
+		case "webhook":
+			sinks = append(sinks, webhookService)
+		case "stream":
+			sinks = append(sinks, streamService)
+		default:
+			return nil, fmt.Errorf("unknown outbox sink %q", name)
+		}
+	}
+	outboxRepo := repositories.NewOutboxRepository()
+	outboxService := services.NewOutboxService(outboxRepo, organizationRepo, sinks, db)
 
 	userRepo := repositories.NewUserRepository()
-	userService := services.NewUserService(userRepo, db)
+	userService := services.NewUserService(userRepo, organizationRepo, outboxService, db)
 	userController := controllers.NewUserController(userService)
 
+	// JOB_QUEUES sets how many jobs of a queue run at once, as queue=count
+	// pairs separated by commas such as "imports=1,emails=4"; other queues
+	// run one job at a time
+	jobConcurrency := map[string]int{}
+	if value := os.Getenv("JOB_QUEUES"); value != "" {
+		for _, pair := range strings.Split(value, ",") {
+			queue, count, _ := strings.Cut(strings.TrimSpace(pair), "=")
+			concurrency, err := strconv.Atoi(count)
+			if err != nil || concurrency < 1 {
+				return nil, fmt.Errorf("invalid job queue concurrency %q", pair)
+			}
+			jobConcurrency[queue] = concurrency
+		}
+	}
+	jobRepo := repositories.NewJobRepository()
+	jobService := services.NewJobService(jobRepo, organizationRepo, jobConcurrency, db)
+
 	bookRepo := repositories.NewBookRepository()
 	authorRepo := repositories.NewAuthorRepository()
-	bookService := services.NewBookService(bookRepo, authorRepo, db)
-	bookController := controllers.NewBookController(bookService)
+	// METADATA_PROVIDER is "openlibrary", "file" (reading METADATA_FILE) or
+	// empty to turn book enrichment off
+	metadataProvider, err := metadata.NewProvider(os.Getenv("METADATA_PROVIDER"), os.Getenv("METADATA_FILE"))
+	if err != nil {
+		return nil, err
+	}
+	bookService := services.NewBookService(bookRepo, authorRepo, metadataProvider, outboxService, db)
+	jobService.Handle(services.JobImportBooks, "imports", services.TypedJobHandler(bookService.ImportBooks))
+	bookController := controllers.NewBookController(bookService, jobService)
+	bookV2Controller := controllers.NewBookV2Controller(bookService)
+
+	labelService := services.NewLabelService(bookRepo, db)
+	// PUBLIC_BASE_URL is where clients reach the API, used for the links in
+	// QR codes
+	publicBaseURL := os.Getenv("PUBLIC_BASE_URL")
+	if publicBaseURL == "" {
+		publicBaseURL = "http://localhost:8080"
+	}
+	labelController := controllers.NewLabelController(labelService, publicBaseURL)
+
+	branchRepo := repositories.NewBranchRepository()
+	branchService := services.NewBranchService(branchRepo, db)
+	branchController := controllers.NewBranchController(branchService)
+
+	bookCopyRepo := repositories.NewBookCopyRepository()
+	bookCopyService := services.NewBookCopyService(bookCopyRepo, bookRepo, branchRepo, db)
+	bookCopyController := controllers.NewBookCopyController(bookCopyService)
 
-	authorService := services.NewAuthorService(authorRepo, db)
+	loanService := services.NewLoanService(loanRepo, bookCopyRepo, db)
+	loanController := controllers.NewLoanController(loanService)
+
+	transferRepo := repositories.NewTransferRepository()
+	transferService := services.NewTransferService(transferRepo, bookCopyRepo, branchRepo, db)
+	transferController := controllers.NewTransferController(transferService)
+
+	stocktakeRepo := repositories.NewStocktakeRepository()
+	stocktakeService := services.NewStocktakeService(stocktakeRepo, bookCopyRepo, branchRepo, db)
+	stocktakeController := controllers.NewStocktakeController(stocktakeService)
+
+	acquisitionRepo := repositories.NewAcquisitionRepository()
+	acquisitionService := services.NewAcquisitionService(acquisitionRepo, bookRepo, authorRepo, bookCopyRepo, branchRepo, outboxService, db)
+	acquisitionController := controllers.NewAcquisitionController(acquisitionService)
+
+	authorService := services.NewAuthorService(authorRepo, outboxService, db)
 	authorController := controllers.NewAuthorController(authorService)
+	authorV2Controller := controllers.NewAuthorV2Controller(authorService)
 
-	return &Provider{
-		UserProvider:   userController,
-		BookProvider:   bookController,
-		AuthorProvider: authorController,
+	batchService := services.NewBatchService(bookRepo, authorRepo, metadataProvider, outboxService, db)
+	batchController := controllers.NewBatchController(batchService)
+
+	graphqlController := controllers.NewGraphQLController(bookService, authorService, userService)
+
+	bookServer := rpc.NewBookServer(bookService)
+	authorServer := rpc.NewAuthorServer(authorService)
+	authServer := rpc.NewAuthServer(userService)
+
+	reportRepo := repositories.NewReportRepository()
+	reportService := services.NewReportService(reportRepo, db)
+	reportController := controllers.NewReportController(reportService)
+
+	// IDEMPOTENCY_TTL is how long idempotency keys are kept, as a duration
+	// such as "24h"
+	idempotencyTTL := 24 * time.Hour
+	if value := os.Getenv("IDEMPOTENCY_TTL"); value != "" {
+		idempotencyTTL, err = time.ParseDuration(value)
+		if err != nil {
+			return nil, err
+		}
 	}
+	idempotencyRepo := repositories.NewIdempotencyRepository()
+	idempotencyService := services.NewIdempotencyService(idempotencyRepo, idempotencyTTL, db)
+
+	jobController := controllers.NewJobController(jobService)
+
+	// OPENAPI_VALIDATION set to "true" checks requests against the OpenAPI
+	// document before they reach the handlers
+	validateRequests := os.Getenv("OPENAPI_VALIDATION") == "true"
+
+	// OPERATOR_KEY is what operators send in X-Operator-Key to create
+	// organizations; left empty, organizations cannot be created
+	operatorKey := os.Getenv("OPERATOR_KEY")
+
+	return &Provider{
+		OrganizationProvider:   organizationController,
+		UserProvider:           userController,
+		BookProvider:           bookController,
+		AuthorProvider:         authorController,
+		ReportProvider:         reportController,
+		LabelProvider:          labelController,
+		LoanProvider:           loanController,
+		BranchProvider:         branchController,
+		BookCopyProvider:       bookCopyController,
+		TransferProvider:       transferController,
+		StocktakeProvider:      stocktakeController,
+		AcquisitionProvider:    acquisitionController,
+		BatchProvider:          batchController,
+		GraphQLProvider:        graphqlController,
+		BookV2Provider:         bookV2Controller,
+		AuthorV2Provider:       authorV2Controller,
+		WebhookProvider:        webhookController,
+		EventProvider:          eventController,
+		JobProvider:            jobController,
+		IdempotencyProvider:    idempotencyService,
+		UserServiceProvider:    userService,
+		WebhookServiceProvider: webhookService,
+		OutboxServiceProvider:  outboxService,
+		JobServiceProvider:     jobService,
+		BookRPCProvider:        bookServer,
+		AuthorRPCProvider:      authorServer,
+		AuthRPCProvider:        authServer,
+		ValidateRequests:       validateRequests,
+		OperatorKey:            operatorKey,
+	}, nil
 }
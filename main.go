@@ -1,9 +1,18 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"golang-backend-test/database"
 	"golang-backend-test/factory"
 	"golang-backend-test/routes"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -14,7 +23,83 @@ func main() {
 		panic(err)
 	}
 	router := gin.New()
-	factory := factory.InitFactory(db)
+	factory, err := factory.InitFactory(db)
+	if err != nil {
+		panic(err)
+	}
 	routes.NewRoutes(router, factory)
-	router.Run(":8080")
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// GRPC_PORT is the port the gRPC services listen on next to the HTTP API
+	grpcPort := os.Getenv("GRPC_PORT")
+	if grpcPort == "" {
+		grpcPort = "9090"
+	}
+	listener, err := net.Listen("tcp", ":"+grpcPort)
+	if err != nil {
+		panic(err)
+	}
+	go routes.NewGRPCServer(factory).Serve(listener)
+
+	// WEBHOOK_INTERVAL is how often due webhook deliveries are sent, as a
+	// duration such as "10s"
+	webhookInterval := 10 * time.Second
+	if value := os.Getenv("WEBHOOK_INTERVAL"); value != "" {
+		webhookInterval, err = time.ParseDuration(value)
+		if err != nil {
+			panic(err)
+		}
+	}
+	go factory.WebhookServiceProvider.Run(ctx, webhookInterval)
+
+	// OUTBOX_INTERVAL is how often recorded events are relayed to the
+	// OUTBOX_SINKS, as a duration such as "1s"
+	outboxInterval := time.Second
+	if value := os.Getenv("OUTBOX_INTERVAL"); value != "" {
+		outboxInterval, err = time.ParseDuration(value)
+		if err != nil {
+			panic(err)
+		}
+	}
+	go factory.OutboxServiceProvider.Run(ctx, outboxInterval)
+
+	// JOB_DRAIN_TIMEOUT is how long the running jobs get to finish on
+	// shutdown, as a duration such as "30s"; the jobs still running after
+	// it are run again on the next start
+	drainTimeout := 30 * time.Second
+	if value := os.Getenv("JOB_DRAIN_TIMEOUT"); value != "" {
+		drainTimeout, err = time.ParseDuration(value)
+		if err != nil {
+			panic(err)
+		}
+	}
+	drained := make(chan struct{})
+	go func() {
+		factory.JobServiceProvider.Run(ctx)
+		close(drained)
+	}()
+
+	server := &http.Server{Addr: ":8080", Handler: router}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			panic(err)
+		}
+	}()
+
+	// on SIGINT or SIGTERM, stop taking requests and new jobs, and let the
+	// running ones finish
+	<-ctx.Done()
+	stop()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), drainTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("shutting down: %v", err)
+	}
+	select {
+	case <-drained:
+	case <-shutdownCtx.Done():
+		log.Printf("jobs still running after %s", drainTimeout)
+	}
 }
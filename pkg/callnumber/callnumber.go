@@ -0,0 +1,89 @@
+package callnumber
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+const (
+	Dewey = "dewey"
+	LCC   = "lcc"
+)
+
+var (
+	ErrUnsupportedScheme = errors.New("unsupported call number scheme")
+	ErrInvalidCallNumber = errors.New("invalid call number")
+)
+
+var (
+	deweyPattern  = regexp.MustCompile(`^(\d{1,3})(?:\.(\d+))?(?:\s+(.*))?$`)
+	lccPattern    = regexp.MustCompile(`^([A-Z]{1,3})\s*(\d{1,4})(?:\.(\d+))?(.*)$`)
+	cutterPattern = regexp.MustCompile(`^[A-Z]+\d+[A-Z]*$`)
+	digitsPattern = regexp.MustCompile(`\d+`)
+)
+
+// SortKey returns a string that orders call numbers of the given scheme in
+// shelf order when compared byte by byte. Class numbers are compared as
+// numbers, their fractional part and Cutter numbers as decimals, and any
+// other number (years, volumes, copies) as an integer.
+func SortKey(scheme, value string) (string, error) {
+	value = strings.ToUpper(strings.Join(strings.Fields(value), " "))
+	switch scheme {
+	case Dewey:
+		return deweySortKey(value)
+	case LCC:
+		return lccSortKey(value)
+	}
+	return "", ErrUnsupportedScheme
+}
+
+func deweySortKey(value string) (string, error) {
+	match := deweyPattern.FindStringSubmatch(value)
+	if match == nil {
+		return "", ErrInvalidCallNumber
+	}
+	key := strings.Repeat("0", 3-len(match[1])) + match[1]
+	if match[2] != "" {
+		key += "." + match[2]
+	}
+	return join(key, strings.Fields(match[3])), nil
+}
+
+func lccSortKey(value string) (string, error) {
+	match := lccPattern.FindStringSubmatch(value)
+	if match == nil {
+		return "", ErrInvalidCallNumber
+	}
+	key := match[1] + " " + strings.Repeat("0", 4-len(match[2])) + match[2]
+	if match[3] != "" {
+		key += "." + match[3]
+	}
+	// a Cutter may follow the class number with only a period, as in
+	// QA76.73.G63, so periods in front of letters separate tokens too
+	rest := strings.NewReplacer(".", " .").Replace(match[4])
+	var tokens []string
+	for _, token := range strings.Fields(rest) {
+		if token = strings.TrimPrefix(token, "."); token != "" {
+			tokens = append(tokens, token)
+		}
+	}
+	return join(key, tokens), nil
+}
+
+func join(key string, tokens []string) string {
+	for _, token := range tokens {
+		if !cutterPattern.MatchString(token) {
+			token = digitsPattern.ReplaceAllStringFunc(token, padNumber)
+		}
+		key += " " + token
+	}
+	return key
+}
+
+func padNumber(digits string) string {
+	if len(digits) >= 6 {
+		return digits
+	}
+	return strings.Repeat("0", 6-len(digits)) + digits
+}
@@ -0,0 +1,81 @@
+package callnumber
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortKey(t *testing.T) {
+	tests := []struct {
+		scheme string
+		value  string
+		key    string
+	}{
+		{Dewey, "5", "005"},
+		{Dewey, "823.914 ROW 1997", "823.914 ROW 001997"},
+		{Dewey, "  823.914   row  ", "823.914 ROW"},
+		{Dewey, "005.133 KNU v.2", "005.133 KNU V.000002"},
+		{LCC, "QA76.73.G63", "QA 0076.73 G63"},
+		{LCC, "QA76.73 .G63 2015", "QA 0076.73 G63 002015"},
+		{LCC, "pr6068.o93", "PR 6068 O93"},
+		{LCC, "PS3566 .Y55 v.3", "PS 3566 Y55 V 000003"},
+	}
+	for _, test := range tests {
+		t.Run(test.scheme+" "+test.value, func(t *testing.T) {
+			key, err := SortKey(test.scheme, test.value)
+
+			assert.Nil(t, err)
+			assert.Equal(t, test.key, key)
+		})
+	}
+}
+
+func TestSortKey_ShelfOrder(t *testing.T) {
+	tests := []struct {
+		scheme string
+		shelf  []string
+	}{
+		// class numbers compare as numbers and their fractions as decimals,
+		// years and volumes as integers
+		{Dewey, []string{"5.1 ABC", "23 ABC", "100.15 ABC", "100.2 ABC", "100.2 ABC 9", "100.2 ABC 10"}},
+		// Cutters compare as decimals
+		{LCC, []string{"P9", "P100", "PA1", "QA76.73.G6", "QA76.73.G63", "QA76.73.G7", "QA76.73.G7 1999", "QA76.73.G7 2015", "QA761"}},
+	}
+	for _, test := range tests {
+		t.Run(test.scheme, func(t *testing.T) {
+			keys := make([]string, len(test.shelf))
+			for i, value := range test.shelf {
+				key, err := SortKey(test.scheme, value)
+				assert.Nil(t, err)
+				keys[i] = key
+			}
+
+			assert.True(t, sort.StringsAreSorted(keys), "%v", keys)
+		})
+	}
+}
+
+func TestSortKey_Errors(t *testing.T) {
+	tests := []struct {
+		scheme string
+		value  string
+		err    error
+	}{
+		{Dewey, "", ErrInvalidCallNumber},
+		{Dewey, "1234", ErrInvalidCallNumber},
+		{Dewey, "ABC 823", ErrInvalidCallNumber},
+		{LCC, "76.73 G63", ErrInvalidCallNumber},
+		{LCC, "QABC76", ErrInvalidCallNumber},
+		{"udc", "821.111", ErrUnsupportedScheme},
+	}
+	for _, test := range tests {
+		t.Run(test.scheme+" "+test.value, func(t *testing.T) {
+			key, err := SortKey(test.scheme, test.value)
+
+			assert.Equal(t, test.err, err)
+			assert.Empty(t, key)
+		})
+	}
+}
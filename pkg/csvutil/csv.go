@@ -0,0 +1,109 @@
+package csvutil
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var ErrUnsupportedType = errors.New("csv: value must be a slice of structs")
+
+// Marshal encodes a slice of structs (or struct pointers) as CSV. The header
+// row is taken from each field's `csv` tag, falling back to its `json` tag
+// and then to the field name. Fields tagged "-" are skipped.
+func Marshal(items interface{}) ([]byte, error) {
+	value := reflect.ValueOf(items)
+	if value.Kind() != reflect.Slice {
+		return nil, ErrUnsupportedType
+	}
+	elemType := value.Type().Elem()
+	if elemType.Kind() == reflect.Ptr {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() != reflect.Struct {
+		return nil, ErrUnsupportedType
+	}
+
+	var header []string
+	var fields []int
+	for i := 0; i < elemType.NumField(); i++ {
+		field := elemType.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		name := columnName(field)
+		if name == "-" {
+			continue
+		}
+		header = append(header, name)
+		fields = append(fields, i)
+	}
+
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+	if err := writer.Write(header); err != nil {
+		return nil, err
+	}
+	for i := 0; i < value.Len(); i++ {
+		item := value.Index(i)
+		if item.Kind() == reflect.Ptr {
+			if item.IsNil() {
+				continue
+			}
+			item = item.Elem()
+		}
+		record := make([]string, len(fields))
+		for j, index := range fields {
+			record[j] = format(item.Field(index))
+		}
+		if err := writer.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func columnName(field reflect.StructField) string {
+	for _, key := range []string{"csv", "json"} {
+		if tag, ok := field.Tag.Lookup(key); ok {
+			name, _, _ := strings.Cut(tag, ",")
+			if name != "" {
+				return name
+			}
+		}
+	}
+	return field.Name
+}
+
+func format(value reflect.Value) string {
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return ""
+		}
+		value = value.Elem()
+	}
+	if stringer, ok := value.Interface().(fmt.Stringer); ok {
+		return Escape(stringer.String())
+	}
+	if value.Kind() == reflect.String {
+		return Escape(value.String())
+	}
+	return fmt.Sprint(value.Interface())
+}
+
+// Escape prefixes a cell that a spreadsheet would read as a formula with a
+// quote, so that exported text cannot run in the program that opens it.
+// Marshal escapes text fields only, numbers keep their sign.
+func Escape(cell string) string {
+	if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+		return "'" + cell
+	}
+	return cell
+}
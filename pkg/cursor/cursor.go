@@ -0,0 +1,54 @@
+package cursor
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+const CURSOR_Key = "q6Jb2Xw0cS9RkT1mVf4LzPn8YhD3uGe7"
+
+// Position marks a row of a list sorted by Sort and then by id. Value is the
+// row's sort key, nil for NULL or when sorting by id alone. Before asks for
+// the rows ahead of the position instead of after it.
+type Position struct {
+	Sort   string      `json:"s"`
+	Value  interface{} `json:"v"`
+	ID     uint        `json:"i"`
+	Before bool        `json:"b,omitempty"`
+}
+
+// Encode turns a position into an opaque token, signed so that clients can
+// not forge positions. Value must be a string, number, time or nil.
+func Encode(position Position) string {
+	payload, _ := json.Marshal(position)
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + sign(encoded)
+}
+
+func Decode(token string) (*Position, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(sign(encoded))) {
+		return nil, ErrInvalidCursor
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var position Position
+	if err := json.Unmarshal(payload, &position); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &position, nil
+}
+
+func sign(encoded string) string {
+	mac := hmac.New(sha256.New, []byte(CURSOR_Key))
+	mac.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
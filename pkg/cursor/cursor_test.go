@@ -0,0 +1,75 @@
+package cursor
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	tests := []struct {
+		name     string
+		position Position
+	}{
+		{"id only", Position{Sort: "id", ID: 42}},
+		{"string value", Position{Sort: "title", Value: "Dune", ID: 7}},
+		{"number value", Position{Sort: "page_count", Value: float64(412), ID: 7}},
+		{"null value before", Position{Sort: "-publication_date", Value: nil, ID: 3, Before: true}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			position, err := Decode(Encode(test.position))
+
+			assert.Nil(t, err)
+			assert.Equal(t, &test.position, position)
+		})
+	}
+}
+
+func TestDecode_Tampered(t *testing.T) {
+	token := Encode(Position{Sort: "id", ID: 42})
+	encoded, signature, _ := strings.Cut(token, ".")
+	forged := base64.RawURLEncoding.EncodeToString([]byte(`{"s":"id","v":null,"i":1}`))
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"empty", ""},
+		{"no signature", encoded},
+		{"empty signature", encoded + "."},
+		{"forged position with the signature of another", forged + "." + signature},
+		{"position changed by a character", flip(encoded) + "." + signature},
+		{"signature changed by a character", encoded + "." + flip(signature)},
+		{"signature of another key", encoded + "." + signWith("another-key", encoded)},
+		{"signed garbage", "not-base64!" + "." + sign("not-base64!")},
+		{"signed payload that is not a position", base64.RawURLEncoding.EncodeToString([]byte("[]")) + "." + sign(base64.RawURLEncoding.EncodeToString([]byte("[]")))},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			position, err := Decode(test.token)
+
+			assert.Nil(t, position)
+			assert.Equal(t, ErrInvalidCursor, err)
+		})
+	}
+}
+
+// flip changes the last character of s.
+func flip(s string) string {
+	last := s[len(s)-1]
+	if last == 'A' {
+		return s[:len(s)-1] + "B"
+	}
+	return s[:len(s)-1] + "A"
+}
+
+func signWith(key, encoded string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
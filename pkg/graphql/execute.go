@@ -0,0 +1,474 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// DefaultListSize is the number of items a list field counts for in the
+// complexity of a query when the field has no Multiplier.
+const DefaultListSize = 10
+
+// Request is a GraphQL request as sent over HTTP.
+type Request struct {
+	Query         string                 `json:"query" form:"query"`
+	OperationName string                 `json:"operationName" form:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Result is the response to a request. Data is left out when the request
+// failed before it ran.
+type Result struct {
+	Data   interface{} `json:"data,omitempty"`
+	Errors []*Error    `json:"errors,omitempty"`
+}
+
+// Limits bound the queries a schema runs. Zero leaves a limit off.
+type Limits struct {
+	MaxDepth      int
+	MaxComplexity int
+}
+
+// Execute runs a request against schema. root is the source of the fields
+// of the query and mutation objects.
+func Execute(ctx context.Context, schema *Schema, limits Limits, req *Request, root interface{}) *Result {
+	doc, err := parse(req.Query)
+	if err != nil {
+		return &Result{Errors: []*Error{toError(err, nil)}}
+	}
+	op, err := doc.operation(req.OperationName)
+	if err != nil {
+		return &Result{Errors: []*Error{toError(err, nil)}}
+	}
+
+	var object *Object
+	switch op.kind {
+	case "query":
+		object = schema.Query
+	case "mutation":
+		object = schema.Mutation
+	}
+	if object == nil {
+		return &Result{Errors: []*Error{{Message: fmt.Sprintf("%s operations are not supported", op.kind)}}}
+	}
+
+	variables, err := op.coerceVariables(req.Variables)
+	if err != nil {
+		return &Result{Errors: []*Error{toError(err, nil)}}
+	}
+	check := &validator{limits: limits, fragments: doc.fragments, variables: variables, spreading: map[string]bool{}}
+	check.validate(object, op.selections)
+	if check.err != nil {
+		return &Result{Errors: []*Error{check.err}}
+	}
+
+	run := &executor{ctx: ctx, fragments: doc.fragments, variables: variables}
+	data := run.objects(object, []interface{}{root}, op.selections, nil)
+	return &Result{Data: data[0], Errors: run.errors}
+}
+
+func (doc *document) operation(name string) (*operation, error) {
+	if name == "" {
+		if len(doc.operations) > 1 {
+			return nil, &Error{Message: "operationName is required for a document with several operations"}
+		}
+		return doc.operations[0], nil
+	}
+	for _, op := range doc.operations {
+		if op.name == name {
+			return op, nil
+		}
+	}
+	return nil, &Error{Message: fmt.Sprintf("unknown operation %s", name)}
+}
+
+func (op *operation) coerceVariables(given map[string]interface{}) (map[string]interface{}, error) {
+	variables := map[string]interface{}{}
+	for _, definition := range op.variables {
+		value, ok := given[definition.name]
+		if !ok && definition.value != nil {
+			value, ok = literal(definition.value, nil), true
+		}
+		if definition.required && value == nil {
+			return nil, &Error{Message: fmt.Sprintf("variable $%s is required", definition.name)}
+		}
+		if ok {
+			variables[definition.name] = value
+		}
+	}
+	return variables, nil
+}
+
+// literal turns a document value into a Go value, reading variables from
+// variables.
+func literal(v value, variables map[string]interface{}) interface{} {
+	switch v := v.(type) {
+	case variable:
+		return variables[string(v)]
+	case []value:
+		list := make([]interface{}, len(v))
+		for i, item := range v {
+			list[i] = literal(item, variables)
+		}
+		return list
+	case map[string]value:
+		object := make(map[string]interface{}, len(v))
+		for name, item := range v {
+			object[name] = literal(item, variables)
+		}
+		return object
+	}
+	return v
+}
+
+func arguments(given map[string]value, variables map[string]interface{}) map[string]interface{} {
+	args := make(map[string]interface{}, len(given))
+	for name, v := range given {
+		args[name] = literal(v, variables)
+	}
+	return args
+}
+
+// included applies the @skip and @include directives.
+func included(directives []*directive, variables map[string]interface{}) bool {
+	for _, d := range directives {
+		condition, _ := literal(d.arguments["if"], variables).(bool)
+		if (d.name == "skip" && condition) || (d.name == "include" && !condition) {
+			return false
+		}
+	}
+	return true
+}
+
+// validator checks a query against the schema and the limits before it
+// runs, stopping at the first error.
+type validator struct {
+	limits     Limits
+	fragments  map[string]*fragment
+	variables  map[string]interface{}
+	spreading  map[string]bool
+	complexity int
+	err        *Error
+}
+
+func (check *validator) validate(object *Object, selections []selection) {
+	check.selections(object, selections, 1)
+}
+
+func (check *validator) fail(format string, args ...interface{}) {
+	if check.err == nil {
+		check.err = &Error{Message: fmt.Sprintf(format, args...)}
+	}
+}
+
+// selections checks selections on object at depth and returns what they
+// cost.
+func (check *validator) selections(object *Object, selections []selection, depth int) int {
+	cost := 0
+	for _, sel := range selections {
+		if check.err != nil {
+			return cost
+		}
+		switch sel := sel.(type) {
+		case *field:
+			if included(sel.directives, check.variables) {
+				cost += check.field(object, sel, depth)
+			}
+		case *fragmentSpread:
+			frag, ok := check.fragments[sel.name]
+			if !ok {
+				check.fail("unknown fragment %s", sel.name)
+				return cost
+			}
+			if check.spreading[sel.name] {
+				check.fail("fragment %s spreads itself", sel.name)
+				return cost
+			}
+			if frag.typeCondition != object.Name {
+				check.fail("fragment %s on %s cannot be spread on %s", sel.name, frag.typeCondition, object.Name)
+				return cost
+			}
+			if included(sel.directives, check.variables) {
+				check.spreading[sel.name] = true
+				cost += check.selections(object, frag.selections, depth)
+				delete(check.spreading, sel.name)
+			}
+		case *inlineFragment:
+			if sel.typeCondition != "" && sel.typeCondition != object.Name {
+				check.fail("fragment on %s cannot be spread on %s", sel.typeCondition, object.Name)
+				return cost
+			}
+			if included(sel.directives, check.variables) {
+				cost += check.selections(object, sel.selections, depth)
+			}
+		}
+	}
+	return cost
+}
+
+func (check *validator) field(object *Object, f *field, depth int) int {
+	if check.limits.MaxDepth > 0 && depth > check.limits.MaxDepth {
+		check.fail("query is deeper than %d", check.limits.MaxDepth)
+		return 0
+	}
+	if f.name == "__typename" {
+		if f.selections != nil {
+			check.fail("field __typename of %s cannot have a selection", object.Name)
+		}
+		return 0
+	}
+
+	definition, ok := object.Fields[f.name]
+	if !ok {
+		check.fail("cannot query field %s on type %s", f.name, object.Name)
+		return 0
+	}
+	for name := range f.arguments {
+		known := false
+		for _, arg := range definition.Args {
+			known = known || arg == name
+		}
+		if !known {
+			check.fail("unknown argument %s on field %s.%s", name, object.Name, f.name)
+			return 0
+		}
+	}
+	if definition.Type == nil {
+		if f.selections != nil {
+			check.fail("field %s of %s is a scalar and cannot have a selection", f.name, object.Name)
+		}
+		return check.add(1)
+	}
+	if f.selections == nil {
+		check.fail("field %s of %s needs a selection of the fields of %s", f.name, object.Name, definition.Type.Name)
+		return 0
+	}
+
+	// the cost of the selection is counted once for each time it runs
+	multiplier := 1
+	if definition.Multiplier != nil {
+		multiplier = definition.Multiplier(arguments(f.arguments, check.variables))
+	} else if definition.List {
+		multiplier = DefaultListSize
+	}
+	before := check.complexity
+	check.complexity = 0
+	selection := check.selections(definition.Type, f.selections, depth+1)
+	check.complexity = before
+	if limit := check.limits.MaxComplexity; limit > 0 && multiplier > limit {
+		multiplier = limit + 1
+	}
+	return check.add(1 + multiplier*selection)
+}
+
+// add counts cost towards the complexity of the query.
+func (check *validator) add(cost int) int {
+	check.complexity += cost
+	if check.limits.MaxComplexity > 0 && check.complexity > check.limits.MaxComplexity {
+		check.fail("query is more complex than %d", check.limits.MaxComplexity)
+	}
+	return cost
+}
+
+// executor resolves a query breadth first: each field is resolved once for
+// all the objects it is selected on, however many lists they are spread
+// over.
+type executor struct {
+	ctx       context.Context
+	fragments map[string]*fragment
+	variables map[string]interface{}
+	errors    []*Error
+}
+
+type collectedField struct {
+	key    string
+	fields []*field
+}
+
+func (run *executor) collect(object *Object, selections []selection, collected []*collectedField) []*collectedField {
+	for _, sel := range selections {
+		switch sel := sel.(type) {
+		case *field:
+			if !included(sel.directives, run.variables) {
+				continue
+			}
+			found := false
+			for _, c := range collected {
+				if c.key == sel.responseKey() {
+					c.fields = append(c.fields, sel)
+					found = true
+				}
+			}
+			if !found {
+				collected = append(collected, &collectedField{key: sel.responseKey(), fields: []*field{sel}})
+			}
+		case *fragmentSpread:
+			if included(sel.directives, run.variables) {
+				collected = run.collect(object, run.fragments[sel.name].selections, collected)
+			}
+		case *inlineFragment:
+			if included(sel.directives, run.variables) {
+				collected = run.collect(object, sel.selections, collected)
+			}
+		}
+	}
+	return collected
+}
+
+func (run *executor) objects(object *Object, sources []interface{}, selections []selection, path []string) []*orderedObject {
+	results := make([]*orderedObject, len(sources))
+	for i := range results {
+		results[i] = &orderedObject{values: map[string]interface{}{}}
+	}
+
+	for _, c := range run.collect(object, selections, nil) {
+		f := c.fields[0]
+		fieldPath := append(append([]string{}, path...), c.key)
+		if f.name == "__typename" {
+			for _, result := range results {
+				result.set(c.key, object.Name)
+			}
+			continue
+		}
+
+		definition := object.Fields[f.name]
+		values, err := definition.Resolve(run.ctx, sources, arguments(f.arguments, run.variables))
+		if err == nil && len(values) != len(sources) {
+			err = fmt.Errorf("field %s.%s resolved %d values for %d objects", object.Name, f.name, len(values), len(sources))
+		}
+		if err != nil {
+			run.errors = append(run.errors, toError(err, fieldPath))
+			for _, result := range results {
+				result.set(c.key, nil)
+			}
+			continue
+		}
+		if definition.Type == nil {
+			for i, result := range results {
+				result.set(c.key, values[i])
+			}
+			continue
+		}
+
+		var selections []selection
+		for _, f := range c.fields {
+			selections = append(selections, f.selections...)
+		}
+		for i, value := range run.children(definition, values, selections, fieldPath) {
+			results[i].set(c.key, value)
+		}
+	}
+	return results
+}
+
+// children resolves the selection of an object field on the values it
+// resolved to, all at once.
+func (run *executor) children(definition *Field, values []interface{}, selections []selection, path []string) []interface{} {
+	var items []interface{}
+	counts := make([]int, len(values))
+	for i, value := range values {
+		counts[i] = -1
+		if isNil(value) {
+			continue
+		}
+		if !definition.List {
+			counts[i] = 1
+			items = append(items, value)
+			continue
+		}
+		list := reflect.ValueOf(value)
+		if list.Kind() != reflect.Slice {
+			run.errors = append(run.errors, &Error{Message: fmt.Sprintf("list field resolved to %T", value), Path: path})
+			continue
+		}
+		counts[i] = list.Len()
+		for j := 0; j < list.Len(); j++ {
+			items = append(items, list.Index(j).Interface())
+		}
+	}
+
+	objects := run.objects(definition.Type, items, selections, path)
+	results := make([]interface{}, len(values))
+	for i, count := range counts {
+		switch {
+		case count < 0:
+			results[i] = nil
+		case !definition.List:
+			results[i] = objects[0]
+			objects = objects[1:]
+		default:
+			list := make([]interface{}, count)
+			for j := range list {
+				list[j] = objects[j]
+			}
+			results[i] = list
+			objects = objects[count:]
+		}
+	}
+	return results
+}
+
+func isNil(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	switch v := reflect.ValueOf(value); v.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Interface:
+		return v.IsNil()
+	}
+	return false
+}
+
+// toError turns err into a response error. Errors with an Extensions
+// method have them copied into the response.
+func toError(err error, path []string) *Error {
+	if graphqlErr, ok := err.(*Error); ok {
+		copied := *graphqlErr
+		if copied.Path == nil {
+			copied.Path = path
+		}
+		return &copied
+	}
+	result := &Error{Message: err.Error(), Path: path}
+	if extended, ok := err.(interface{ Extensions() map[string]interface{} }); ok {
+		result.Extensions = extended.Extensions()
+	}
+	return result
+}
+
+// orderedObject keeps the fields of a response object in the order they
+// were selected.
+type orderedObject struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func (object *orderedObject) set(key string, value interface{}) {
+	if _, ok := object.values[key]; !ok {
+		object.keys = append(object.keys, key)
+	}
+	object.values[key] = value
+}
+
+func (object *orderedObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range object.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, _ := json.Marshal(key)
+		buf.Write(name)
+		buf.WriteByte(':')
+		value, err := json.Marshal(object.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
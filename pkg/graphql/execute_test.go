@@ -0,0 +1,247 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testAuthor struct {
+	ID   int
+	Name string
+}
+
+type testBook struct {
+	Title    string
+	AuthorID int
+}
+
+var testAuthors = []*testAuthor{{ID: 1, Name: "Ursula K. Le Guin"}, {ID: 2, Name: "Octavia E. Butler"}}
+
+var testBooks = []*testBook{
+	{Title: "A Wizard of Earthsea", AuthorID: 1},
+	{Title: "Kindred", AuthorID: 2},
+	{Title: "The Dispossessed", AuthorID: 1},
+	{Title: "Dawn", AuthorID: 2},
+}
+
+// authorLoads records the sources of every call of the Book.author resolver.
+type authorLoads [][]interface{}
+
+// newTestSchema builds
+//
+//	type Query { books(limit: Int): [Book] authors: [Author] }
+//	type Book { title author: Author }
+//	type Author { name books: [Book] }
+func newTestSchema(loads *authorLoads) *Schema {
+	query := NewObject("Query")
+	book := NewObject("Book")
+	author := NewObject("Author")
+
+	book.Fields = map[string]*Field{
+		"title": {Resolve: Each(func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+			return source.(*testBook).Title, nil
+		})},
+		"author": {
+			Type: author,
+			Resolve: func(ctx context.Context, sources []interface{}, args map[string]interface{}) ([]interface{}, error) {
+				*loads = append(*loads, sources)
+				values := make([]interface{}, len(sources))
+				for i, source := range sources {
+					for _, a := range testAuthors {
+						if a.ID == source.(*testBook).AuthorID {
+							values[i] = a
+						}
+					}
+				}
+				return values, nil
+			},
+		},
+	}
+	author.Fields = map[string]*Field{
+		"name": {Resolve: Each(func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+			return source.(*testAuthor).Name, nil
+		})},
+		"books": {Type: book, List: true, Resolve: Each(func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+			var books []*testBook
+			for _, b := range testBooks {
+				if b.AuthorID == source.(*testAuthor).ID {
+					books = append(books, b)
+				}
+			}
+			return books, nil
+		})},
+	}
+	query.Fields = map[string]*Field{
+		"books": {
+			Type: book,
+			List: true,
+			Args: []string{"limit"},
+			Multiplier: func(args map[string]interface{}) int {
+				if limit, ok, _ := IntArg(args, "limit"); ok {
+					return limit
+				}
+				return len(testBooks)
+			},
+			Resolve: Each(func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+				limit, ok, err := IntArg(args, "limit")
+				if err != nil {
+					return nil, err
+				}
+				if !ok || limit > len(testBooks) {
+					limit = len(testBooks)
+				}
+				return testBooks[:limit], nil
+			}),
+		},
+		"authors": {Type: author, List: true, Resolve: Each(func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+			return testAuthors, nil
+		})},
+	}
+	return &Schema{Query: query}
+}
+
+func TestExecute_ParseErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		message string
+	}{
+		{"empty document", "", "document has no operation"},
+		{"unclosed selection", "{", "syntax error at 1:2: unexpected end of document"},
+		{"unclosed nested selection", "{ books { title }", "syntax error at 1:18: unexpected end of document"},
+		{"extra brace", "{ books { title } } }", `syntax error at 1:21: unexpected "}"`},
+		{"position on a later line", "query {\n  books(limit: ) { title }\n}", `syntax error at 2:16: unexpected ")"`},
+		{"unterminated string", `{ books(limit: "5) { title } }`, "syntax error at 1:16: unterminated string"},
+		{"unknown operation kind", "select { books { title } }", `syntax error at 1:1: unexpected "select"`},
+		{"duplicate fragment", "{ books { ...F } } fragment F on Book { title } fragment F on Book { title }", "there can be only one fragment named F"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var loads authorLoads
+
+			result := Execute(context.Background(), newTestSchema(&loads), Limits{}, &Request{Query: test.query}, nil)
+
+			assert.Nil(t, result.Data)
+			if assert.Len(t, result.Errors, 1) {
+				assert.Equal(t, test.message, result.Errors[0].Message)
+			}
+		})
+	}
+}
+
+func TestExecute_DepthLimit(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		message string
+	}{
+		{"within the limit", "{ books { author { name } } }", ""},
+		{"deeper than the limit", "{ books { author { books { title } } } }", "query is deeper than 3"},
+		{"deeper through a fragment", "{ ...Deep } fragment Deep on Query { authors { books { author { name } } } }", "query is deeper than 3"},
+		{"deeper through an inline fragment", "{ authors { ... on Author { books { author { name } } } } }", "query is deeper than 3"},
+		{"skipped selection does not count", "{ books { author { name books @skip(if: true) { title } } } }", ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var loads authorLoads
+
+			result := Execute(context.Background(), newTestSchema(&loads), Limits{MaxDepth: 3}, &Request{Query: test.query}, nil)
+
+			if test.message == "" {
+				assert.Empty(t, result.Errors)
+				assert.NotNil(t, result.Data)
+				return
+			}
+			assert.Nil(t, result.Data)
+			if assert.Len(t, result.Errors, 1) {
+				assert.Equal(t, test.message, result.Errors[0].Message)
+			}
+			assert.Empty(t, loads)
+		})
+	}
+}
+
+func TestExecute_ComplexityLimit(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		message string
+	}{
+		// 1 + 5 * 1
+		{"list counted by its limit", "{ books(limit: 5) { title } }", ""},
+		// 1 + 5 * (1 + (1 + 1))
+		{"object field counted once per item", "{ books(limit: 5) { title author { name } } }", ""},
+		// 1 + 20 * (1 + (1 + 1))
+		{"limit raises the cost", "{ books(limit: 20) { title author { name } } }", "query is more complex than 50"},
+		// 1 + 4 * (1 + 1 + (1 + DefaultListSize * 1))
+		{"list without a multiplier counts DefaultListSize", "{ books { title author { books { title } } } }", "query is more complex than 50"},
+		{"huge limit does not overflow", "{ books(limit: 9223372036854775807) { author { books { title } } } }", "query is more complex than 50"},
+		{"limit from a variable", "query ($limit: Int) { books(limit: $limit) { title author { name } } }", "query is more complex than 50"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var loads authorLoads
+			req := &Request{Query: test.query, Variables: map[string]interface{}{"limit": float64(40)}}
+
+			result := Execute(context.Background(), newTestSchema(&loads), Limits{MaxComplexity: 50}, req, nil)
+
+			if test.message == "" {
+				assert.Empty(t, result.Errors)
+				assert.NotNil(t, result.Data)
+				return
+			}
+			assert.Nil(t, result.Data)
+			if assert.Len(t, result.Errors, 1) {
+				assert.Equal(t, test.message, result.Errors[0].Message)
+			}
+			assert.Empty(t, loads)
+		})
+	}
+}
+
+func TestExecute_BatchesBookAuthor(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		sources []int
+	}{
+		{"books of a list", "{ books { title author { name } } }", []int{4}},
+		{"books spread over several lists", "{ authors { books { author { name } } } }", []int{4}},
+		{"same field selected twice", "{ books { author { name } ... on Book { author { name } } } }", []int{4}},
+		{"aliased field is loaded apart", "{ books { author { name } writer: author { name } } }", []int{4, 4}},
+		// the four books have two authors of two books each
+		{"nested books load their authors once per level", "{ books { author { books { author { name } } } } }", []int{4, 8}},
+		{"no author selected", "{ books { title } }", nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var loads authorLoads
+
+			result := Execute(context.Background(), newTestSchema(&loads), Limits{}, &Request{Query: test.query}, nil)
+
+			assert.Empty(t, result.Errors)
+			var sources []int
+			for _, load := range loads {
+				sources = append(sources, len(load))
+			}
+			assert.Equal(t, test.sources, sources)
+		})
+	}
+}
+
+func TestExecute_WritesAuthorOfEveryBook(t *testing.T) {
+	var loads authorLoads
+
+	result := Execute(context.Background(), newTestSchema(&loads), Limits{}, &Request{Query: "{ books(limit: 2) { title author { name } } }"}, nil)
+
+	assert.Empty(t, result.Errors)
+	encoded, err := json.Marshal(result)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"data":{"books":[
+		{"title":"A Wizard of Earthsea","author":{"name":"Ursula K. Le Guin"}},
+		{"title":"Kindred","author":{"name":"Octavia E. Butler"}}
+	]}}`, string(encoded))
+}
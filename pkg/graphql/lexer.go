@@ -0,0 +1,196 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenPunct
+	tokenName
+	tokenInt
+	tokenFloat
+	tokenString
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+// lexer splits a GraphQL document into tokens. Commas, white space and
+// comments are insignificant and skipped.
+type lexer struct {
+	source string
+	pos    int
+}
+
+func (lex *lexer) next() (token, error) {
+	lex.skipIgnored()
+	if lex.pos >= len(lex.source) {
+		return token{kind: tokenEOF, pos: lex.pos}, nil
+	}
+
+	start := lex.pos
+	c := lex.source[lex.pos]
+	switch {
+	case strings.IndexByte("!$&():=@[]{}|", c) >= 0:
+		lex.pos++
+		return token{kind: tokenPunct, value: string(c), pos: start}, nil
+	case c == '.':
+		if strings.HasPrefix(lex.source[lex.pos:], "...") {
+			lex.pos += 3
+			return token{kind: tokenPunct, value: "...", pos: start}, nil
+		}
+	case c == '_' || isLetter(c):
+		for lex.pos < len(lex.source) && (lex.source[lex.pos] == '_' || isLetter(lex.source[lex.pos]) || isDigit(lex.source[lex.pos])) {
+			lex.pos++
+		}
+		return token{kind: tokenName, value: lex.source[start:lex.pos], pos: start}, nil
+	case c == '-' || isDigit(c):
+		return lex.number()
+	case c == '"':
+		return lex.string()
+	}
+	return token{}, lex.errorf(start, "unexpected character %q", c)
+}
+
+func (lex *lexer) skipIgnored() {
+	for lex.pos < len(lex.source) {
+		switch c := lex.source[lex.pos]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',':
+			lex.pos++
+		case c == '#':
+			for lex.pos < len(lex.source) && lex.source[lex.pos] != '\n' {
+				lex.pos++
+			}
+		case strings.HasPrefix(lex.source[lex.pos:], "\uFEFF"):
+			lex.pos += len("\uFEFF")
+		default:
+			return
+		}
+	}
+}
+
+func (lex *lexer) number() (token, error) {
+	start := lex.pos
+	kind := tokenInt
+	if lex.source[lex.pos] == '-' {
+		lex.pos++
+	}
+	digits := lex.digits()
+	if digits == 0 {
+		return token{}, lex.errorf(start, "invalid number")
+	}
+	if lex.pos < len(lex.source) && lex.source[lex.pos] == '.' {
+		kind = tokenFloat
+		lex.pos++
+		if lex.digits() == 0 {
+			return token{}, lex.errorf(start, "invalid number")
+		}
+	}
+	if lex.pos < len(lex.source) && (lex.source[lex.pos] == 'e' || lex.source[lex.pos] == 'E') {
+		kind = tokenFloat
+		lex.pos++
+		if lex.pos < len(lex.source) && (lex.source[lex.pos] == '+' || lex.source[lex.pos] == '-') {
+			lex.pos++
+		}
+		if lex.digits() == 0 {
+			return token{}, lex.errorf(start, "invalid number")
+		}
+	}
+	return token{kind: kind, value: lex.source[start:lex.pos], pos: start}, nil
+}
+
+func (lex *lexer) digits() int {
+	start := lex.pos
+	for lex.pos < len(lex.source) && isDigit(lex.source[lex.pos]) {
+		lex.pos++
+	}
+	return lex.pos - start
+}
+
+// string reads a quoted string. Block strings are not supported.
+func (lex *lexer) string() (token, error) {
+	start := lex.pos
+	if strings.HasPrefix(lex.source[lex.pos:], `"""`) {
+		return token{}, lex.errorf(start, "block strings are not supported")
+	}
+	lex.pos++
+
+	var value strings.Builder
+	for lex.pos < len(lex.source) {
+		c := lex.source[lex.pos]
+		switch {
+		case c == '"':
+			lex.pos++
+			return token{kind: tokenString, value: value.String(), pos: start}, nil
+		case c == '\n' || c == '\r':
+			return token{}, lex.errorf(start, "unterminated string")
+		case c == '\\':
+			if lex.pos+1 >= len(lex.source) {
+				return token{}, lex.errorf(start, "unterminated string")
+			}
+			escape := lex.source[lex.pos+1]
+			lex.pos += 2
+			switch escape {
+			case '"', '\\', '/':
+				value.WriteByte(escape)
+			case 'b':
+				value.WriteByte('\b')
+			case 'f':
+				value.WriteByte('\f')
+			case 'n':
+				value.WriteByte('\n')
+			case 'r':
+				value.WriteByte('\r')
+			case 't':
+				value.WriteByte('\t')
+			case 'u':
+				if lex.pos+4 > len(lex.source) {
+					return token{}, lex.errorf(start, "invalid unicode escape")
+				}
+				code, err := strconv.ParseUint(lex.source[lex.pos:lex.pos+4], 16, 32)
+				if err != nil {
+					return token{}, lex.errorf(start, "invalid unicode escape")
+				}
+				value.WriteRune(rune(code))
+				lex.pos += 4
+			default:
+				return token{}, lex.errorf(start, "invalid escape \\%c", escape)
+			}
+		default:
+			r, size := utf8.DecodeRuneInString(lex.source[lex.pos:])
+			value.WriteRune(r)
+			lex.pos += size
+		}
+	}
+	return token{}, lex.errorf(start, "unterminated string")
+}
+
+func (lex *lexer) errorf(pos int, format string, args ...interface{}) error {
+	line, column := 1, 1
+	for _, c := range lex.source[:pos] {
+		if c == '\n' {
+			line++
+			column = 1
+		} else {
+			column++
+		}
+	}
+	return &Error{Message: fmt.Sprintf("syntax error at %d:%d: %s", line, column, fmt.Sprintf(format, args...))}
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,128 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// Schema is the root of a GraphQL API. Mutation may be nil.
+type Schema struct {
+	Query    *Object
+	Mutation *Object
+}
+
+// Object is an object type. Fields are added after the objects they refer
+// to exist, so that types can refer to each other.
+type Object struct {
+	Name   string
+	Fields map[string]*Field
+}
+
+func NewObject(name string) *Object {
+	return &Object{Name: name, Fields: map[string]*Field{}}
+}
+
+// Field describes a field of an object. Type is nil for scalars, which are
+// written as their JSON form. List tells that the field resolves to a slice.
+// Args lists the arguments the field takes. Multiplier tells how many times
+// the selection of the field is resolved, for the complexity of a query;
+// nil counts lists as DefaultListSize and others as one.
+type Field struct {
+	Type       *Object
+	List       bool
+	Args       []string
+	Multiplier func(args map[string]interface{}) int
+	Resolve    Resolver
+}
+
+// Resolver resolves a field for every source at once, returning a value
+// for each of them in the same order. Resolving the sources together lets
+// a resolver load what they refer to in one go instead of one by one.
+type Resolver func(ctx context.Context, sources []interface{}, args map[string]interface{}) ([]interface{}, error)
+
+// Each makes a resolver from a function resolving one source at a time.
+func Each(resolve func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error)) Resolver {
+	return func(ctx context.Context, sources []interface{}, args map[string]interface{}) ([]interface{}, error) {
+		values := make([]interface{}, len(sources))
+		for i, source := range sources {
+			value, err := resolve(ctx, source, args)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = value
+		}
+		return values, nil
+	}
+}
+
+// Error is an error of a GraphQL response. Path is the response keys
+// leading to the field that failed.
+type Error struct {
+	Message    string                 `json:"message"`
+	Path       []string               `json:"path,omitempty"`
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+}
+
+func (err *Error) Error() string {
+	return err.Message
+}
+
+// IntArg reads an Int argument, telling whether it was given.
+func IntArg(args map[string]interface{}, name string) (int, bool, error) {
+	switch value := args[name].(type) {
+	case nil:
+		return 0, false, nil
+	case int:
+		return value, true, nil
+	case float64:
+		if value == float64(int(value)) {
+			return int(value), true, nil
+		}
+	}
+	return 0, false, &Error{Message: fmt.Sprintf("argument %s must be an Int", name)}
+}
+
+// StringArg reads a String argument, telling whether it was given.
+func StringArg(args map[string]interface{}, name string) (string, bool, error) {
+	switch value := args[name].(type) {
+	case nil:
+		return "", false, nil
+	case string:
+		return value, true, nil
+	}
+	return "", false, &Error{Message: fmt.Sprintf("argument %s must be a String", name)}
+}
+
+// IDArg reads a numeric ID argument, given as a string or an int.
+func IDArg(args map[string]interface{}, name string) (int, error) {
+	if text, ok := args[name].(string); ok {
+		id, err := strconv.Atoi(text)
+		if err != nil {
+			return 0, &Error{Message: fmt.Sprintf("argument %s must be an ID", name)}
+		}
+		return id, nil
+	}
+	id, ok, err := IntArg(args, name)
+	if err != nil || !ok {
+		return 0, &Error{Message: fmt.Sprintf("argument %s must be an ID", name)}
+	}
+	return id, nil
+}
+
+// DecodeArg decodes an input object argument into target through its JSON
+// form, so that inputs bind like JSON request bodies.
+func DecodeArg(args map[string]interface{}, name string, target interface{}) error {
+	if _, ok := args[name].(map[string]interface{}); !ok {
+		return &Error{Message: fmt.Sprintf("argument %s must be an input object", name)}
+	}
+	encoded, err := json.Marshal(args[name])
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(encoded, target); err != nil {
+		return &Error{Message: fmt.Sprintf("argument %s: %s", name, err.Error())}
+	}
+	return nil
+}
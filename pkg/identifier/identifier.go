@@ -0,0 +1,39 @@
+package identifier
+
+import (
+	"regexp"
+)
+
+var (
+	viafPattern     = regexp.MustCompile(`^[1-9][0-9]{0,21}$`)
+	orcidPattern    = regexp.MustCompile(`^[0-9]{4}-[0-9]{4}-[0-9]{4}-[0-9]{3}[0-9X]$`)
+	wikidataPattern = regexp.MustCompile(`^Q[1-9][0-9]*$`)
+)
+
+func ValidVIAF(value string) bool {
+	return viafPattern.MatchString(value)
+}
+
+// ValidORCID checks the layout and the ISO 7064 MOD 11-2 check character.
+func ValidORCID(value string) bool {
+	if !orcidPattern.MatchString(value) {
+		return false
+	}
+	total := 0
+	for _, r := range value[:len(value)-1] {
+		if r == '-' {
+			continue
+		}
+		total = (total + int(r-'0')) * 2
+	}
+	check := (12 - total%11) % 11
+	expected := byte('0' + check)
+	if check == 10 {
+		expected = 'X'
+	}
+	return value[len(value)-1] == expected
+}
+
+func ValidWikidata(value string) bool {
+	return wikidataPattern.MatchString(value)
+}
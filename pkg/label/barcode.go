@@ -0,0 +1,97 @@
+package label
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image/color"
+	"image/png"
+
+	"github.com/boombuler/barcode"
+	"github.com/boombuler/barcode/code128"
+	"github.com/boombuler/barcode/ean"
+	"github.com/boombuler/barcode/qr"
+)
+
+const (
+	SymbologyEAN13   = "ean13"
+	SymbologyCode128 = "code128"
+	SymbologyQR      = "qr"
+
+	FormatPNG = "png"
+	FormatSVG = "svg"
+)
+
+var ErrUnsupportedFormat = errors.New("format must be png or svg")
+
+func Encode(symbology, content string) (barcode.Barcode, error) {
+	switch symbology {
+	case SymbologyEAN13:
+		return ean.Encode(content)
+	case SymbologyCode128:
+		return code128.Encode(content)
+	case SymbologyQR:
+		return qr.Encode(content, qr.M, qr.Auto)
+	}
+	return nil, fmt.Errorf("unsupported symbology %q", symbology)
+}
+
+// Render draws code as a PNG or SVG image. Each module is scaled to a whole
+// number of pixels, so the result is at least as large as the code itself.
+func Render(code barcode.Barcode, format string, moduleSize, height int) ([]byte, string, error) {
+	bounds := code.Bounds()
+	width := bounds.Dx() * moduleSize
+	if code.Metadata().Dimensions == 2 {
+		height = bounds.Dy() * moduleSize
+	}
+
+	switch format {
+	case FormatPNG:
+		scaled, err := barcode.Scale(code, width, height)
+		if err != nil {
+			return nil, "", err
+		}
+		var buffer bytes.Buffer
+		if err := png.Encode(&buffer, scaled); err != nil {
+			return nil, "", err
+		}
+		return buffer.Bytes(), "image/png", nil
+	case FormatSVG:
+		return renderSVG(code, moduleSize, width, height), "image/svg+xml", nil
+	}
+	return nil, "", ErrUnsupportedFormat
+}
+
+func renderSVG(code barcode.Barcode, moduleSize, width, height int) []byte {
+	bounds := code.Bounds()
+	rowHeight := height
+	if code.Metadata().Dimensions == 2 {
+		rowHeight = moduleSize
+	}
+
+	var buffer bytes.Buffer
+	fmt.Fprintf(&buffer, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`, width, height, width, height)
+	fmt.Fprintf(&buffer, `<rect width="%d" height="%d" fill="#fff"/>`, width, height)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		// merge horizontal runs of dark modules into a single rect
+		for x := bounds.Min.X; x < bounds.Max.X; {
+			if !isDark(code.At(x, y)) {
+				x++
+				continue
+			}
+			start := x
+			for x < bounds.Max.X && isDark(code.At(x, y)) {
+				x++
+			}
+			fmt.Fprintf(&buffer, `<rect x="%d" y="%d" width="%d" height="%d"/>`,
+				(start-bounds.Min.X)*moduleSize, (y-bounds.Min.Y)*rowHeight, (x-start)*moduleSize, rowHeight)
+		}
+	}
+	buffer.WriteString(`</svg>`)
+	return buffer.Bytes()
+}
+
+func isDark(c color.Color) bool {
+	gray := color.GrayModel.Convert(c).(color.Gray)
+	return gray.Y < 128
+}
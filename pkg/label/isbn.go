@@ -0,0 +1,68 @@
+package label
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrInvalidISBN = errors.New("isbn must have 10 or 13 digits with a valid check digit")
+
+// EAN13FromISBN returns the 13 digit EAN for an ISBN-10 or ISBN-13, ignoring
+// hyphens and spaces.
+func EAN13FromISBN(isbn string) (string, error) {
+	digits := strings.NewReplacer("-", "", " ", "").Replace(isbn)
+	switch len(digits) {
+	case 10:
+		if !validISBN10(digits) {
+			return "", ErrInvalidISBN
+		}
+		code := "978" + digits[:9]
+		return code + string(eanCheckDigit(code)), nil
+	case 13:
+		if !isDigits(digits) || eanCheckDigit(digits[:12]) != digits[12] {
+			return "", ErrInvalidISBN
+		}
+		return digits, nil
+	}
+	return "", ErrInvalidISBN
+}
+
+func validISBN10(digits string) bool {
+	if !isDigits(digits[:9]) {
+		return false
+	}
+	sum := 0
+	for i := 0; i < 9; i++ {
+		sum += int(digits[i]-'0') * (10 - i)
+	}
+	switch last := digits[9]; {
+	case last == 'X' || last == 'x':
+		sum += 10
+	case last >= '0' && last <= '9':
+		sum += int(last - '0')
+	default:
+		return false
+	}
+	return sum%11 == 0
+}
+
+func eanCheckDigit(code string) byte {
+	sum := 0
+	for i := 0; i < 12; i++ {
+		digit := int(code[i] - '0')
+		if i%2 == 1 {
+			digit *= 3
+		}
+		sum += digit
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+func isDigits(value string) bool {
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return value != ""
+}
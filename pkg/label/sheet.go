@@ -0,0 +1,117 @@
+package label
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// Stock describes a sheet of labels. All lengths are in millimetres.
+type Stock struct {
+	PageSize        string
+	Columns         int
+	Rows            int
+	Width           float64
+	Height          float64
+	TopMargin       float64
+	LeftMargin      float64
+	HorizontalPitch float64
+	VerticalPitch   float64
+}
+
+// Stocks holds the supported Avery label products, keyed by product code.
+var Stocks = map[string]Stock{
+	"5160":  {PageSize: "Letter", Columns: 3, Rows: 10, Width: 66.675, Height: 25.4, TopMargin: 12.7, LeftMargin: 4.7625, HorizontalPitch: 69.85, VerticalPitch: 25.4},
+	"5167":  {PageSize: "Letter", Columns: 4, Rows: 20, Width: 44.45, Height: 12.7, TopMargin: 12.7, LeftMargin: 7.62, HorizontalPitch: 52.07, VerticalPitch: 12.7},
+	"L7160": {PageSize: "A4", Columns: 3, Rows: 7, Width: 63.5, Height: 38.1, TopMargin: 15.15, LeftMargin: 7.25, HorizontalPitch: 66.04, VerticalPitch: 38.1},
+}
+
+type SpineLabel struct {
+	Title  string
+	Author string
+	Code   string
+}
+
+const labelPadding = 1.5
+
+// SpineLabelSheet lays labels out on stock and returns the PDF. The first
+// skip positions are left blank so partly used sheets can be fed again.
+func SpineLabelSheet(stock Stock, labels []SpineLabel, skip int) ([]byte, error) {
+	pdf := gofpdf.New("P", "mm", stock.PageSize, "")
+	pdf.SetMargins(0, 0, 0)
+	pdf.SetAutoPageBreak(false, 0)
+	translate := pdf.UnicodeTranslatorFromDescriptor("")
+
+	fontSize := math.Max(5, math.Min(9, stock.Height*0.35))
+	lineHeight := fontSize * 0.3528 * 1.2
+	perPage := stock.Columns * stock.Rows
+
+	for i, spine := range labels {
+		position := i + skip
+		if position%perPage == 0 || i == 0 {
+			pdf.AddPage()
+		}
+		slot := position % perPage
+		x := stock.LeftMargin + float64(slot%stock.Columns)*stock.HorizontalPitch + labelPadding
+		y := stock.TopMargin + float64(slot/stock.Columns)*stock.VerticalPitch + labelPadding
+		width := stock.Width - 2*labelPadding
+
+		pdf.SetFont("Helvetica", "B", fontSize)
+		pdf.SetXY(x, y)
+		pdf.CellFormat(width, lineHeight, fit(pdf, translate(spine.Title), width), "", 0, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", fontSize)
+		pdf.SetXY(x, y+lineHeight)
+		pdf.CellFormat(width, lineHeight, fit(pdf, translate(spine.Author), width), "", 0, "L", false, 0, "")
+
+		barHeight := stock.Height - 2*labelPadding - 2*lineHeight
+		if spine.Code == "" || barHeight < 3 {
+			continue
+		}
+		code, err := Encode(SymbologyCode128, spine.Code)
+		if err != nil {
+			return nil, fmt.Errorf("label %d: %w", i, err)
+		}
+		drawBars(pdf, code.Bounds().Dx(), func(column int) bool {
+			return isDark(code.At(code.Bounds().Min.X+column, 0))
+		}, x, y+2*lineHeight, width, barHeight)
+	}
+
+	if pdf.PageCount() == 0 {
+		pdf.AddPage()
+	}
+	var buffer bytes.Buffer
+	if err := pdf.Output(&buffer); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func drawBars(pdf *gofpdf.Fpdf, modules int, dark func(column int) bool, x, y, width, height float64) {
+	moduleWidth := width / float64(modules)
+	pdf.SetFillColor(0, 0, 0)
+	for column := 0; column < modules; {
+		if !dark(column) {
+			column++
+			continue
+		}
+		start := column
+		for column < modules && dark(column) {
+			column++
+		}
+		pdf.Rect(x+float64(start)*moduleWidth, y, float64(column-start)*moduleWidth, height, "F")
+	}
+}
+
+// fit shortens text with an ellipsis until it fits in width. text is already
+// translated to the single-byte font encoding, so trimming bytes is safe.
+func fit(pdf *gofpdf.Fpdf, text string, width float64) string {
+	if pdf.GetStringWidth(text) <= width {
+		return text
+	}
+	for len(text) > 0 && pdf.GetStringWidth(text+"...") > width {
+		text = text[:len(text)-1]
+	}
+	return text + "..."
+}
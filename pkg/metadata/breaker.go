@@ -0,0 +1,54 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+type circuitBreaker struct {
+	provider  Provider
+	threshold int
+	cooldown  time.Duration
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+// WithCircuitBreaker stops calling provider for cooldown once threshold
+// lookups in a row have failed, returning ErrCircuitOpen instead. After the
+// cooldown a single lookup is let through; it closes the circuit again when
+// it succeeds. ErrNotFound counts as a success.
+func WithCircuitBreaker(provider Provider, threshold int, cooldown time.Duration) Provider {
+	return &circuitBreaker{provider: provider, threshold: threshold, cooldown: cooldown}
+}
+
+func (p *circuitBreaker) Lookup(ctx context.Context, isbn string) (*Record, error) {
+	p.mu.Lock()
+	if p.failures >= p.threshold {
+		if p.probing || time.Now().Before(p.openUntil) {
+			p.mu.Unlock()
+			return nil, ErrCircuitOpen
+		}
+		p.probing = true
+	}
+	p.mu.Unlock()
+
+	record, err := p.provider.Lookup(ctx, isbn)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.probing = false
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		p.failures++
+		if p.failures >= p.threshold {
+			p.openUntil = time.Now().Add(p.cooldown)
+		}
+		return nil, err
+	}
+	p.failures = 0
+	return record, err
+}
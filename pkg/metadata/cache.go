@@ -0,0 +1,79 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+type cacheEntry struct {
+	record  *Record
+	expires time.Time
+}
+
+type cachedProvider struct {
+	provider Provider
+	ttl      time.Duration
+	size     int
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+// WithCache keeps the lookups of provider for ttl, holding at most size
+// ISBNs. ISBNs the provider has no record for are cached too, so repeated
+// misses do not reach the source; other errors are not cached.
+func WithCache(provider Provider, ttl time.Duration, size int) Provider {
+	return &cachedProvider{provider: provider, ttl: ttl, size: size, entries: make(map[string]cacheEntry)}
+}
+
+func (p *cachedProvider) Lookup(ctx context.Context, isbn string) (*Record, error) {
+	key := NormalizeISBN(isbn)
+	now := time.Now()
+
+	p.mu.Lock()
+	entry, ok := p.entries[key]
+	p.mu.Unlock()
+	if ok && now.Before(entry.expires) {
+		if entry.record == nil {
+			return nil, ErrNotFound
+		}
+		copied := *entry.record
+		return &copied, nil
+	}
+
+	record, err := p.provider.Lookup(ctx, isbn)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	p.evict(now)
+	p.entries[key] = cacheEntry{record: record, expires: now.Add(p.ttl)}
+	p.mu.Unlock()
+	if record == nil {
+		return nil, err
+	}
+	copied := *record
+	return &copied, nil
+}
+
+// evict makes room for one more entry, dropping expired entries first and
+// then arbitrary ones. The caller holds mu.
+func (p *cachedProvider) evict(now time.Time) {
+	if len(p.entries) < p.size {
+		return
+	}
+	for key, entry := range p.entries {
+		if !now.Before(entry.expires) {
+			delete(p.entries, key)
+		}
+	}
+	for key := range p.entries {
+		if len(p.entries) < p.size {
+			break
+		}
+		delete(p.entries, key)
+	}
+}
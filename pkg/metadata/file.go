@@ -0,0 +1,39 @@
+package metadata
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+)
+
+// FileProvider serves records from a JSON file holding an array of records.
+// It stands in for a real source in tests and offline setups.
+type FileProvider struct {
+	records map[string]*Record
+}
+
+func NewFileProvider(path string) (*FileProvider, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var records []*Record
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, err
+	}
+
+	provider := &FileProvider{records: make(map[string]*Record)}
+	for _, record := range records {
+		provider.records[NormalizeISBN(record.ISBN)] = record
+	}
+	return provider, nil
+}
+
+func (p *FileProvider) Lookup(ctx context.Context, isbn string) (*Record, error) {
+	record, ok := p.records[NormalizeISBN(isbn)]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	copied := *record
+	return &copied, nil
+}
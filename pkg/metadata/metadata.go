@@ -0,0 +1,85 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNotFound    = errors.New("no metadata found for isbn")
+	ErrCircuitOpen = errors.New("metadata provider is unavailable")
+)
+
+// Record is the bibliographic metadata a provider knows about an ISBN.
+type Record struct {
+	ISBN            string     `json:"isbn"`
+	Title           string     `json:"title"`
+	Authors         []string   `json:"authors"`
+	Publisher       string     `json:"publisher"`
+	PageCount       int        `json:"page_count"`
+	CoverURL        string     `json:"cover_url"`
+	PublicationDate *time.Time `json:"publication_date"`
+}
+
+// Provider looks up an ISBN in a metadata source. It returns ErrNotFound
+// when the source has no record for the ISBN.
+type Provider interface {
+	Lookup(ctx context.Context, isbn string) (*Record, error)
+}
+
+const (
+	DefaultTimeout          = 5 * time.Second
+	DefaultCacheTTL         = 24 * time.Hour
+	DefaultCacheSize        = 1000
+	DefaultFailureThreshold = 5
+	DefaultCooldown         = 30 * time.Second
+)
+
+// NewProvider builds the provider named by kind with the default timeout,
+// circuit breaker and cache around it. An empty kind disables enrichment and
+// returns a nil provider.
+func NewProvider(kind, path string) (Provider, error) {
+	var provider Provider
+	switch kind {
+	case "":
+		return nil, nil
+	case "openlibrary":
+		provider = NewOpenLibrary(OpenLibraryURL)
+	case "file":
+		file, err := NewFileProvider(path)
+		if err != nil {
+			return nil, err
+		}
+		provider = file
+	default:
+		return nil, fmt.Errorf("unknown metadata provider %q", kind)
+	}
+	provider = WithTimeout(provider, DefaultTimeout)
+	provider = WithCircuitBreaker(provider, DefaultFailureThreshold, DefaultCooldown)
+	return WithCache(provider, DefaultCacheTTL, DefaultCacheSize), nil
+}
+
+// NormalizeISBN drops the hyphens and spaces of an ISBN so that differently
+// written forms share one lookup key.
+func NormalizeISBN(isbn string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(isbn))
+}
+
+type timeoutProvider struct {
+	provider Provider
+	timeout  time.Duration
+}
+
+// WithTimeout bounds every lookup of provider to timeout.
+func WithTimeout(provider Provider, timeout time.Duration) Provider {
+	return &timeoutProvider{provider: provider, timeout: timeout}
+}
+
+func (p *timeoutProvider) Lookup(ctx context.Context, isbn string) (*Record, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	return p.provider.Lookup(ctx, isbn)
+}
@@ -0,0 +1,97 @@
+package metadata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const OpenLibraryURL = "https://openlibrary.org"
+
+// OpenLibrary looks ISBNs up with the Open Library books API.
+type OpenLibrary struct {
+	BaseURL string
+	Client  *http.Client
+}
+
+func NewOpenLibrary(baseURL string) *OpenLibrary {
+	return &OpenLibrary{BaseURL: baseURL, Client: http.DefaultClient}
+}
+
+type openLibraryBook struct {
+	Title   string `json:"title"`
+	Authors []struct {
+		Name string `json:"name"`
+	} `json:"authors"`
+	Publishers []struct {
+		Name string `json:"name"`
+	} `json:"publishers"`
+	NumberOfPages int    `json:"number_of_pages"`
+	PublishDate   string `json:"publish_date"`
+	Cover         struct {
+		Small  string `json:"small"`
+		Medium string `json:"medium"`
+		Large  string `json:"large"`
+	} `json:"cover"`
+}
+
+// openLibraryDateLayouts are the publish_date forms that name a full day.
+// Dates with only a year or month are left out rather than guessed.
+var openLibraryDateLayouts = []string{"2006-01-02", "January 2, 2006", "Jan 2, 2006", "2 January 2006"}
+
+func (p *OpenLibrary) Lookup(ctx context.Context, isbn string) (*Record, error) {
+	isbn = NormalizeISBN(isbn)
+	query := url.Values{}
+	query.Set("bibkeys", "ISBN:"+isbn)
+	query.Set("format", "json")
+	query.Set("jscmd", "data")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.BaseURL+"/api/books?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	res, err := p.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("open library returned status %d", res.StatusCode)
+	}
+
+	var books map[string]openLibraryBook
+	if err := json.NewDecoder(res.Body).Decode(&books); err != nil {
+		return nil, err
+	}
+	book, ok := books["ISBN:"+isbn]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	record := &Record{
+		ISBN:      isbn,
+		Title:     book.Title,
+		PageCount: book.NumberOfPages,
+		CoverURL:  book.Cover.Large,
+	}
+	if record.CoverURL == "" {
+		record.CoverURL = book.Cover.Medium
+	}
+	for _, author := range book.Authors {
+		record.Authors = append(record.Authors, author.Name)
+	}
+	if len(book.Publishers) > 0 {
+		record.Publisher = book.Publishers[0].Name
+	}
+	for _, layout := range openLibraryDateLayouts {
+		if date, err := time.Parse(layout, book.PublishDate); err == nil {
+			record.PublicationDate = &date
+			break
+		}
+	}
+	return record, nil
+}
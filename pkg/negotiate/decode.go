@@ -0,0 +1,111 @@
+package negotiate
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/ugorji/go/codec"
+)
+
+var decodeHandle = func() *codec.MsgpackHandle {
+	handle := &codec.MsgpackHandle{}
+	handle.RawToString = true
+	handle.MapType = reflect.TypeOf(map[string]interface{}(nil))
+	return handle
+}()
+
+// Decode reads a body in format as the values encoding/json decodes into an
+// interface{}. XML has no types: every value is a string, an empty element
+// is "", and repeated elements become a list, as do the <item> elements
+// Encode writes. openapi.Document.Coerce sorts them out by a schema.
+func Decode(format string, body []byte) (interface{}, error) {
+	switch format {
+	case XML:
+		return decodeXML(body)
+	case MsgPack:
+		var value interface{}
+		if err := codec.NewDecoderBytes(body, decodeHandle).Decode(&value); err != nil {
+			return nil, err
+		}
+		return value, nil
+	}
+	return nil, ErrUnsupportedFormat
+}
+
+func decodeXML(body []byte) (interface{}, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil, errors.New("negotiate: XML document has no root element")
+		}
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := token.(xml.StartElement); ok {
+			return readElement(decoder)
+		}
+	}
+}
+
+// readElement reads the content of the element just opened, up to its end.
+func readElement(decoder *xml.Decoder) (interface{}, error) {
+	var text strings.Builder
+	var children map[string]interface{}
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch token := token.(type) {
+		case xml.CharData:
+			text.Write(token)
+		case xml.StartElement:
+			key := token.Name.Local
+			for _, attr := range token.Attr {
+				if key == "entry" && attr.Name.Local == "key" {
+					key = attr.Value
+				}
+			}
+			value, err := readElement(decoder)
+			if err != nil {
+				return nil, err
+			}
+			if children == nil {
+				children = map[string]interface{}{}
+			}
+			switch existing := children[key].(type) {
+			case nil:
+				children[key] = value
+			case repeated:
+				children[key] = append(existing, value)
+			default:
+				children[key] = repeated{existing, value}
+			}
+		case xml.EndElement:
+			if children == nil {
+				return text.String(), nil
+			}
+			for key, value := range children {
+				if list, ok := value.(repeated); ok {
+					children[key] = []interface{}(list)
+				}
+			}
+			if list, ok := children["item"]; ok && len(children) == 1 {
+				if _, ok := list.([]interface{}); !ok {
+					list = []interface{}{list}
+				}
+				return list, nil
+			}
+			return children, nil
+		}
+	}
+}
+
+// repeated collects the elements sharing a name while they are read, so
+// that an element holding a list is not taken for one that repeats.
+type repeated []interface{}
@@ -0,0 +1,318 @@
+package negotiate
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"golang-backend-test/pkg/csvutil"
+	"strings"
+	"unicode"
+
+	"github.com/ugorji/go/codec"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("negotiate: unsupported format")
+	ErrNotList           = errors.New("negotiate: only lists can be written as CSV")
+)
+
+var msgpackHandle = &codec.MsgpackHandle{WriteExt: true}
+
+// object is a JSON object with its keys in order, which codec writes as a
+// map.
+type object []interface{}
+
+func (object) MapBySlice() {}
+
+func (o object) get(key string) (interface{}, bool) {
+	for i := 0; i < len(o); i += 2 {
+		if o[i] == key {
+			return o[i+1], true
+		}
+	}
+	return nil, false
+}
+
+// MarshalJSON writes the object back as JSON, for nested lists in CSV.
+func (o object) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteByte('{')
+	for i := 0; i < len(o); i += 2 {
+		if i > 0 {
+			buffer.WriteByte(',')
+		}
+		key, _ := json.Marshal(o[i])
+		value, err := json.Marshal(o[i+1])
+		if err != nil {
+			return nil, err
+		}
+		buffer.Write(key)
+		buffer.WriteByte(':')
+		buffer.Write(value)
+	}
+	buffer.WriteByte('}')
+	return buffer.Bytes(), nil
+}
+
+// Encode converts a JSON document to format.
+//
+// XML documents have a <response> root, an element per object key and an
+// <item> element per array entry; keys that are not XML names become
+// <entry key="..."> elements. CSV documents have a row per item of the
+// list the document holds: itself, its data, or the items of either. Nested
+// objects become dotted columns and lists of values are joined with "; ".
+func Encode(format string, document []byte) ([]byte, error) {
+	switch format {
+	case JSON:
+		return document, nil
+	case XML:
+		return encodeXML(document)
+	}
+
+	value, err := read(document)
+	if err != nil {
+		return nil, err
+	}
+	var buffer bytes.Buffer
+	switch format {
+	case MsgPack:
+		err = codec.NewEncoder(&buffer, msgpackHandle).Encode(value)
+	case CSV:
+		err = encodeCSV(&buffer, value)
+	default:
+		err = ErrUnsupportedFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// read decodes a JSON document keeping the order of object keys, and
+// integers as int64.
+func read(document []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(document))
+	decoder.UseNumber()
+	return readValue(decoder)
+}
+
+func readValue(decoder *json.Decoder) (interface{}, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch token := token.(type) {
+	case json.Delim:
+		if token == '[' {
+			list := []interface{}{}
+			for decoder.More() {
+				item, err := readValue(decoder)
+				if err != nil {
+					return nil, err
+				}
+				list = append(list, item)
+			}
+			_, err := decoder.Token()
+			return list, err
+		}
+		fields := object{}
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := readValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, key, value)
+		}
+		_, err := decoder.Token()
+		return fields, err
+	case json.Number:
+		if integer, err := token.Int64(); err == nil {
+			return integer, nil
+		}
+		return token.Float64()
+	}
+	return token, nil
+}
+
+func encodeXML(document []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(document))
+	decoder.UseNumber()
+
+	var buffer bytes.Buffer
+	buffer.WriteString(xml.Header)
+	encoder := xml.NewEncoder(&buffer)
+	if err := writeXML(encoder, decoder, xml.StartElement{Name: xml.Name{Local: "response"}}); err != nil {
+		return nil, err
+	}
+	if err := encoder.Flush(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// writeXML writes the next value of decoder as the element start opens.
+func writeXML(encoder *xml.Encoder, decoder *json.Decoder, start xml.StartElement) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	delim, ok := token.(json.Delim)
+	if !ok {
+		if token == nil {
+			return encoder.EncodeElement("", start)
+		}
+		return encoder.EncodeElement(fmt.Sprint(token), start)
+	}
+
+	if err := encoder.EncodeToken(start); err != nil {
+		return err
+	}
+	for decoder.More() {
+		child := xml.StartElement{Name: xml.Name{Local: "item"}}
+		if delim == '{' {
+			key, err := decoder.Token()
+			if err != nil {
+				return err
+			}
+			child = element(key.(string))
+		}
+		if err := writeXML(encoder, decoder, child); err != nil {
+			return err
+		}
+	}
+	if _, err := decoder.Token(); err != nil {
+		return err
+	}
+	return encoder.EncodeToken(start.End())
+}
+
+// element opens the element of an object key.
+func element(key string) xml.StartElement {
+	if isXMLName(key) {
+		return xml.StartElement{Name: xml.Name{Local: key}}
+	}
+	return xml.StartElement{
+		Name: xml.Name{Local: "entry"},
+		Attr: []xml.Attr{{Name: xml.Name{Local: "key"}, Value: key}},
+	}
+}
+
+func isXMLName(name string) bool {
+	if name == "" || strings.HasPrefix(strings.ToLower(name), "xml") {
+		return false
+	}
+	for i, r := range name {
+		switch {
+		case unicode.IsLetter(r), r == '_':
+		case i > 0 && (unicode.IsDigit(r) || r == '-' || r == '.'):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func encodeCSV(buffer *bytes.Buffer, value interface{}) error {
+	items, ok := listOf(value)
+	if !ok {
+		return ErrNotList
+	}
+
+	var header []string
+	columns := map[string]int{}
+	var rows []map[string]string
+	for _, item := range items {
+		row := map[string]string{}
+		flatten(row, "", item, func(column string) {
+			if _, ok := columns[column]; !ok {
+				columns[column] = len(header)
+				header = append(header, column)
+			}
+		})
+		rows = append(rows, row)
+	}
+
+	writer := csv.NewWriter(buffer)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		record := make([]string, len(header))
+		for column, value := range row {
+			record[columns[column]] = value
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// listOf finds the list a document holds: the document itself, its data,
+// or the items of either.
+func listOf(value interface{}) ([]interface{}, bool) {
+	switch value := value.(type) {
+	case []interface{}:
+		return value, true
+	case object:
+		for _, key := range []string{"data", "items"} {
+			if field, ok := value.get(key); ok {
+				return listOf(field)
+			}
+		}
+	}
+	return nil, false
+}
+
+// flatten sets the columns of value in row, telling column about each one
+// in order.
+func flatten(row map[string]string, prefix string, value interface{}, column func(string)) {
+	name := prefix
+	if name == "" {
+		name = "value"
+	}
+	switch value := value.(type) {
+	case object:
+		for i := 0; i < len(value); i += 2 {
+			key := value[i].(string)
+			if prefix != "" {
+				key = prefix + "." + key
+			}
+			flatten(row, key, value[i+1], column)
+		}
+		return
+	case []interface{}:
+		var values []string
+		for _, item := range value {
+			if _, ok := item.(object); ok {
+				document, _ := json.Marshal(item)
+				values = append(values, string(document))
+				continue
+			}
+			values = append(values, cell(item))
+		}
+		column(name)
+		row[name] = strings.Join(values, "; ")
+		return
+	}
+	column(name)
+	row[name] = cell(value)
+}
+
+func cell(value interface{}) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return csvutil.Escape(value)
+	}
+	return fmt.Sprint(value)
+}
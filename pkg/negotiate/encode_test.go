@@ -0,0 +1,85 @@
+package negotiate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncode_XML(t *testing.T) {
+	document := `{"data":{"id":1,"title":"Dune","tags":["sf","classic"],"publisher":null,"1st edition":true}}`
+
+	encoded, err := Encode(XML, []byte(document))
+
+	assert.Nil(t, err)
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>`+"\n"+
+		`<response><data><id>1</id><title>Dune</title><tags><item>sf</item><item>classic</item></tags>`+
+		`<publisher></publisher><entry key="1st edition">true</entry></data></response>`, string(encoded))
+}
+
+func TestEncode_CSV(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		want     string
+	}{
+		{"list", `[{"id":1,"title":"Dune"},{"id":2,"title":"Emma"}]`, "id,title\n1,Dune\n2,Emma\n"},
+		{"data of a response", `{"data":[{"id":1}]}`, "id\n1\n"},
+		{"items of a page", `{"data":{"items":[{"id":1}],"total":1}}`, "id\n1\n"},
+		{"nested object as dotted columns", `[{"id":1,"author":{"id":2,"name":"Frank Herbert"}}]`, "id,author.id,author.name\n1,2,Frank Herbert\n"},
+		{"list of values joined", `[{"tags":["sf","classic"]}]`, "tags\nsf; classic\n"},
+		{"columns of later rows are added", `[{"id":1},{"id":2,"isbn":"9780441013593"}]`, "id,isbn\n1,\n2,9780441013593\n"},
+		{"formula is escaped", `[{"name":"=HYPERLINK(\"http://example.com\")"}]`, "name\n\"'=HYPERLINK(\"\"http://example.com\"\")\"\n"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			encoded, err := Encode(CSV, []byte(test.document))
+
+			assert.Nil(t, err)
+			assert.Equal(t, test.want, string(encoded))
+		})
+	}
+
+	_, err := Encode(CSV, []byte(`{"data":{"id":1}}`))
+	assert.Equal(t, ErrNotList, err)
+}
+
+func TestEncodeDecode(t *testing.T) {
+	document := `{"data":{"id":1,"title":"Dune","rating":4.5,"tags":["sf"],"author":{"name":"Frank Herbert"},"publisher":null}}`
+
+	t.Run("msgpack keeps the types", func(t *testing.T) {
+		encoded, err := Encode(MsgPack, []byte(document))
+		assert.Nil(t, err)
+
+		decoded, err := Decode(MsgPack, encoded)
+
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]interface{}{"data": map[string]interface{}{
+			"id": int64(1), "title": "Dune", "rating": 4.5, "tags": []interface{}{"sf"},
+			"author": map[string]interface{}{"name": "Frank Herbert"}, "publisher": nil,
+		}}, decoded)
+	})
+	t.Run("XML reads every value as a string", func(t *testing.T) {
+		encoded, err := Encode(XML, []byte(document))
+		assert.Nil(t, err)
+
+		decoded, err := Decode(XML, encoded)
+
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]interface{}{"data": map[string]interface{}{
+			"id": "1", "title": "Dune", "rating": "4.5", "tags": []interface{}{"sf"},
+			"author": map[string]interface{}{"name": "Frank Herbert"}, "publisher": "",
+		}}, decoded)
+	})
+}
+
+func TestDecode_Errors(t *testing.T) {
+	_, err := Decode(XML, []byte(""))
+	assert.NotNil(t, err)
+	_, err = Decode(XML, []byte("<book><title>Dune</book>"))
+	assert.NotNil(t, err)
+	_, err = Decode(MsgPack, []byte{0xc1})
+	assert.NotNil(t, err)
+	_, err = Decode(CSV, []byte("id\n1\n"))
+	assert.Equal(t, ErrUnsupportedFormat, err)
+}
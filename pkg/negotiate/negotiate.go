@@ -0,0 +1,106 @@
+// Package negotiate picks the representation of a response from an Accept
+// header, and converts JSON documents to and from the other representations
+// the API speaks: XML, MessagePack and, for lists, CSV.
+package negotiate
+
+import (
+	"mime"
+	"strconv"
+	"strings"
+)
+
+const (
+	JSON    = "application/json"
+	XML     = "application/xml"
+	MsgPack = "application/msgpack"
+	CSV     = "text/csv"
+)
+
+// aliases are the other names clients use for the types above.
+var aliases = map[string]string{
+	"text/xml":                XML,
+	"application/x-msgpack":   MsgPack,
+	"application/vnd.msgpack": MsgPack,
+}
+
+// Canonical returns the media type of a Content-Type or Accept value without
+// its parameters, under the name this package uses for it.
+func Canonical(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+	if alias, ok := aliases[mediaType]; ok {
+		return alias
+	}
+	return mediaType
+}
+
+// ContentType is the Content-Type header of a response in format.
+func ContentType(format string) string {
+	switch format {
+	case JSON, XML, CSV:
+		return format + "; charset=utf-8"
+	}
+	return format
+}
+
+// Accept picks the type of offered that an Accept header prefers, taking
+// ties in the order of offered. An empty header accepts anything. ok is false
+// when the header accepts none of the offered types.
+func Accept(header string, offered []string) (string, bool) {
+	if strings.TrimSpace(header) == "" {
+		if len(offered) == 0 {
+			return "", false
+		}
+		return offered[0], true
+	}
+
+	type mediaRange struct {
+		mediaType string
+		quality   float64
+	}
+	var ranges []mediaRange
+	for _, part := range strings.Split(header, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		if alias, ok := aliases[mediaType]; ok {
+			mediaType = alias
+		}
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if quality, err = strconv.ParseFloat(q, 64); err != nil {
+				continue
+			}
+		}
+		ranges = append(ranges, mediaRange{mediaType, quality})
+	}
+
+	best, bestQuality := "", 0.0
+	for _, candidate := range offered {
+		// the most specific range matching the candidate sets its quality
+		quality, specificity := 0.0, 0
+		for _, r := range ranges {
+			var s int
+			switch {
+			case r.mediaType == candidate:
+				s = 3
+			case strings.HasSuffix(r.mediaType, "/*") && strings.HasPrefix(candidate, strings.TrimSuffix(r.mediaType, "*")):
+				s = 2
+			case r.mediaType == "*/*":
+				s = 1
+			default:
+				continue
+			}
+			if s > specificity {
+				quality, specificity = r.quality, s
+			}
+		}
+		if quality > bestQuality {
+			best, bestQuality = candidate, quality
+		}
+	}
+	return best, best != ""
+}
@@ -0,0 +1,56 @@
+package negotiate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccept(t *testing.T) {
+	offered := []string{JSON, XML, MsgPack, CSV}
+
+	tests := []struct {
+		name   string
+		header string
+		want   string
+		ok     bool
+	}{
+		{"no header takes the first offer", "", JSON, true},
+		{"exact type", "application/xml", XML, true},
+		{"alias", "application/x-msgpack", MsgPack, true},
+		{"text alias of XML", "text/xml", XML, true},
+		{"highest quality wins", "application/json;q=0.5, text/csv", CSV, true},
+		{"ties go to the order of offered", "text/csv, application/xml", XML, true},
+		{"subtype wildcard", "text/*", CSV, true},
+		{"any type", "*/*", JSON, true},
+		{"specific range overrides a wildcard", "application/*;q=0.9, application/json;q=0.1", XML, true},
+		{"quality zero refuses a type", "application/json;q=0, */*;q=0.1", XML, true},
+		{"malformed ranges are skipped", "???, application/xml", XML, true},
+		{"malformed quality is skipped", "application/json;q=high, text/csv", CSV, true},
+		{"nothing acceptable", "image/png", "", false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, ok := Accept(test.header, offered)
+
+			assert.Equal(t, test.ok, ok)
+			assert.Equal(t, test.want, got)
+		})
+	}
+
+	_, ok := Accept("", nil)
+	assert.False(t, ok)
+}
+
+func TestCanonical(t *testing.T) {
+	assert.Equal(t, JSON, Canonical("application/json; charset=utf-8"))
+	assert.Equal(t, XML, Canonical("text/xml"))
+	assert.Equal(t, MsgPack, Canonical("application/vnd.msgpack"))
+	assert.Equal(t, "", Canonical("not a media type"))
+}
+
+func TestContentType(t *testing.T) {
+	assert.Equal(t, "application/json; charset=utf-8", ContentType(JSON))
+	assert.Equal(t, "text/csv; charset=utf-8", ContentType(CSV))
+	assert.Equal(t, MsgPack, ContentType(MsgPack))
+}
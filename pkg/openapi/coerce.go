@@ -0,0 +1,65 @@
+package openapi
+
+// Coerce converts a value read from a format without types, such as XML, to
+// the JSON types schema asks for: strings become numbers and booleans, empty
+// strings become null where a string is not allowed, and single values become
+// arrays. What does not convert is left as it is, for Validate to report.
+func (doc *Document) Coerce(schema *Schema, value interface{}) interface{} {
+	schema = doc.Resolve(schema)
+	if schema == nil {
+		return value
+	}
+	for _, part := range schema.AllOf {
+		value = doc.Coerce(part, value)
+	}
+	for _, option := range schema.AnyOf {
+		if !contains(typeNames(option), "null") {
+			return doc.Coerce(option, value)
+		}
+	}
+
+	types := typeNames(schema)
+	switch {
+	case contains(types, "array"):
+		list, ok := value.([]interface{})
+		if !ok {
+			list = []interface{}{value}
+			if value == "" || value == nil {
+				list = []interface{}{}
+			}
+		}
+		if schema.Items != nil {
+			for i, item := range list {
+				list[i] = doc.Coerce(schema.Items, item)
+			}
+		}
+		return list
+	case contains(types, "object"):
+		fields, ok := value.(map[string]interface{})
+		if !ok {
+			if value == "" {
+				return map[string]interface{}{}
+			}
+			return value
+		}
+		for name, field := range fields {
+			property := schema.Properties[name]
+			if property == nil {
+				property = schema.AdditionalProperties
+			}
+			if property != nil {
+				fields[name] = doc.Coerce(property, field)
+			}
+		}
+		return fields
+	}
+
+	raw, ok := value.(string)
+	if !ok {
+		return value
+	}
+	if raw == "" && len(types) > 0 && !contains(types, "string") {
+		return nil
+	}
+	return parseParameter(schema, raw)
+}
@@ -0,0 +1,330 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schema is a JSON Schema, as OpenAPI 3.1 uses them. Type is a string or,
+// for nullable values, a list of them.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 interface{}        `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+}
+
+func Ref(name string) *Schema {
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// Nullable lets schema be null as well.
+func Nullable(schema *Schema) *Schema {
+	if schema.Ref != "" || schema.Type == nil {
+		return &Schema{AnyOf: []*Schema{schema, {Type: "null"}}}
+	}
+	nullable := *schema
+	switch t := schema.Type.(type) {
+	case string:
+		nullable.Type = []string{t, "null"}
+	case []string:
+		nullable.Type = append(append([]string{}, t...), "null")
+	}
+	return &nullable
+}
+
+// Mode tells how a Go type is read. Requests are read by the validate tags
+// of github.com/go-playground/validator: a field is required when it is
+// tagged so. Responses are written with encoding/json: a field is always
+// present unless it is tagged omitempty.
+type Mode int
+
+const (
+	RequestMode Mode = iota
+	ResponseMode
+)
+
+func (mode Mode) String() string {
+	if mode == ResponseMode {
+		return "Response"
+	}
+	return "Request"
+}
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	rawType       = reflect.TypeOf(json.RawMessage{})
+	marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// SchemaOf returns the schema of the JSON form of value, adding the structs
+// it holds to the components under their type names. Structs implementing
+// json.Marshaler are taken to write any subset of their fields.
+func (doc *Document) SchemaOf(value interface{}, mode Mode) *Schema {
+	return doc.schemaOf(reflect.TypeOf(value), mode)
+}
+
+func (doc *Document) schemaOf(t reflect.Type, mode Mode) *Schema {
+	if t == nil {
+		return &Schema{}
+	}
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case rawType:
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return Nullable(doc.schemaOf(t.Elem(), mode))
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &Schema{Type: "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer", Minimum: float(0)}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return Nullable(&Schema{Type: "array", Items: doc.schemaOf(t.Elem(), mode)})
+	case reflect.Map:
+		return Nullable(&Schema{Type: "object", AdditionalProperties: doc.schemaOf(t.Elem(), mode)})
+	case reflect.Struct:
+		return doc.structRef(t, mode)
+	}
+	return &Schema{}
+}
+
+// structRef adds a named struct to the components. A struct read both as a
+// request and as a response gets a second component, named after the mode
+// it was read in second.
+func (doc *Document) structRef(t reflect.Type, mode Mode) *Schema {
+	if t.Name() == "" {
+		return doc.structSchema(t, mode)
+	}
+	name := t.Name()
+	if existing, ok := doc.schemaModes[name]; ok && existing != mode {
+		name += mode.String()
+	}
+	if _, ok := doc.Components.Schemas[name]; !ok {
+		if doc.schemaModes == nil {
+			doc.schemaModes = map[string]Mode{}
+		}
+		doc.schemaModes[name] = mode
+		// added first so that recursive types refer to it
+		doc.Components.Schemas[name] = &Schema{}
+		*doc.Components.Schemas[name] = *doc.structSchema(t, mode)
+	}
+	return Ref(name)
+}
+
+func (doc *Document) structSchema(t reflect.Type, mode Mode) *Schema {
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	partial := mode == ResponseMode && t.Implements(marshalerType)
+	doc.addFields(schema, t, mode, partial)
+	return schema
+}
+
+func (doc *Document) addFields(schema *Schema, t reflect.Type, mode Mode, partial bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Tag.Get("json") == "" && field.Type.Kind() == reflect.Struct {
+			doc.addFields(schema, field.Type, mode, partial)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		rules := strings.Split(field.Tag.Get("validate"), ",")
+		fieldRules := rules
+		for i, rule := range rules {
+			if rule == "dive" {
+				fieldRules = rules[:i]
+				break
+			}
+		}
+		property := doc.schemaOf(field.Type, mode)
+		if mode == RequestMode {
+			property = constrain(property, rules)
+		}
+		schema.Properties[name] = property
+
+		switch {
+		case partial:
+		case mode == RequestMode && contains(fieldRules, "required"):
+			schema.Required = append(schema.Required, name)
+		case mode == ResponseMode && !contains(strings.Split(options, ","), "omitempty"):
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
+
+// Parameters returns the query parameters a struct binds through its form
+// tags.
+func (doc *Document) Parameters(value interface{}) []*Parameter {
+	var parameters []*Parameter
+	t := reflect.TypeOf(value)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("form"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		rules := strings.Split(field.Tag.Get("validate"), ",")
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		parameters = append(parameters, &Parameter{
+			Name:     name,
+			In:       "query",
+			Required: contains(rules, "required"),
+			Schema:   constrain(doc.schemaOf(fieldType, RequestMode), rules),
+		})
+	}
+	return parameters
+}
+
+// constrain adds the rules of a validate tag that JSON Schema can say.
+// Rules after dive apply to the items of a list.
+func constrain(schema *Schema, rules []string) *Schema {
+	for i, rule := range rules {
+		if rule == "dive" {
+			if target := nonNull(schema); target.Items != nil {
+				target.Items = constrain(target.Items, rules[i+1:])
+			}
+			rules = rules[:i]
+			break
+		}
+	}
+	if len(rules) == 0 {
+		return schema
+	}
+
+	target := nonNull(schema)
+	types := typeNames(target)
+	omitEmpty := contains(rules, "omitempty")
+	for _, rule := range rules {
+		name, arg, _ := strings.Cut(rule, "=")
+		switch name {
+		case "required":
+			if contains(types, "string") {
+				target.MinLength = length(1)
+			}
+		case "min", "max", "gte", "lte":
+			number, err := strconv.ParseFloat(arg, 64)
+			if err != nil {
+				continue
+			}
+			atLeast := name == "min" || name == "gte"
+			switch {
+			case contains(types, "string"):
+				if atLeast {
+					if !omitEmpty {
+						target.MinLength = length(int(number))
+					}
+				} else {
+					target.MaxLength = length(int(number))
+				}
+			case contains(types, "array"):
+				if atLeast {
+					target.MinItems = length(int(number))
+				} else {
+					target.MaxItems = length(int(number))
+				}
+			case contains(types, "integer"), contains(types, "number"):
+				if atLeast {
+					target.Minimum = float(number)
+				} else {
+					target.Maximum = float(number)
+				}
+			}
+		case "oneof":
+			for _, option := range strings.Fields(arg) {
+				if contains(types, "integer") {
+					if number, err := strconv.Atoi(option); err == nil {
+						target.Enum = append(target.Enum, number)
+					}
+					continue
+				}
+				target.Enum = append(target.Enum, option)
+			}
+			if omitEmpty && contains(types, "string") {
+				target.Enum = append(target.Enum, "")
+			}
+		case "email":
+			target.Format = "email"
+		case "url":
+			target.Format = "uri"
+		}
+	}
+	return schema
+}
+
+// nonNull returns the part of a nullable schema that is not null.
+func nonNull(schema *Schema) *Schema {
+	if len(schema.AnyOf) == 2 {
+		return schema.AnyOf[0]
+	}
+	return schema
+}
+
+func typeNames(schema *Schema) []string {
+	switch t := schema.Type.(type) {
+	case string:
+		return []string{t}
+	case []string:
+		return t
+	}
+	return nil
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+func float(value float64) *float64 {
+	return &value
+}
+
+func length(value int) *int {
+	return &value
+}
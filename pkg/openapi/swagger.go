@@ -0,0 +1,16 @@
+package openapi
+
+import (
+	_ "embed"
+	"strings"
+)
+
+//go:embed swagger.html
+var swaggerPage string
+
+// SwaggerUI returns a page showing the document served at specURL. The page
+// loads Swagger UI itself from a CDN.
+func SwaggerUI(title, specURL string) []byte {
+	page := strings.ReplaceAll(swaggerPage, "{{title}}", title)
+	return []byte(strings.ReplaceAll(page, "{{spec}}", specURL))
+}
@@ -0,0 +1,279 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"mime"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"unicode/utf8"
+)
+
+// Validate checks a decoded JSON value against schema, returning a message
+// for every problem found. Formats are annotations only, as JSON Schema
+// 2020-12 has them by default.
+func (doc *Document) Validate(schema *Schema, value interface{}) []string {
+	var problems []string
+	doc.validate(schema, value, "", &problems)
+	return problems
+}
+
+func (doc *Document) validate(schema *Schema, value interface{}, path string, problems *[]string) {
+	fail := func(format string, args ...interface{}) {
+		at := path
+		if at == "" {
+			at = "body"
+		}
+		*problems = append(*problems, at+": "+fmt.Sprintf(format, args...))
+	}
+
+	if schema.Ref != "" {
+		resolved := doc.Resolve(schema)
+		if resolved == nil {
+			fail("unknown schema %s", schema.Ref)
+			return
+		}
+		schema = resolved
+	}
+
+	for _, part := range schema.AllOf {
+		doc.validate(part, value, path, problems)
+	}
+	if len(schema.AnyOf) > 0 {
+		matched := false
+		for _, option := range schema.AnyOf {
+			if len(doc.Validate(option, value)) == 0 {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			fail("does not match any of the allowed schemas")
+			return
+		}
+	}
+
+	if types := typeNames(schema); len(types) > 0 {
+		actual := jsonType(value)
+		if !contains(types, actual) && !(actual == "integer" && contains(types, "number")) {
+			fail("must be %s, not %s", joinTypes(types), actual)
+			return
+		}
+	}
+
+	if len(schema.Enum) > 0 {
+		found := false
+		for _, option := range schema.Enum {
+			if equalJSON(option, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			fail("must be one of %v", schema.Enum)
+		}
+	}
+
+	switch value := value.(type) {
+	case string:
+		length := utf8.RuneCountInString(value)
+		if schema.MinLength != nil && length < *schema.MinLength {
+			fail("must be at least %d characters long", *schema.MinLength)
+		}
+		if schema.MaxLength != nil && length > *schema.MaxLength {
+			fail("must be at most %d characters long", *schema.MaxLength)
+		}
+	case float64:
+		if schema.Minimum != nil && value < *schema.Minimum {
+			fail("must be at least %v", *schema.Minimum)
+		}
+		if schema.Maximum != nil && value > *schema.Maximum {
+			fail("must be at most %v", *schema.Maximum)
+		}
+	case []interface{}:
+		if schema.MinItems != nil && len(value) < *schema.MinItems {
+			fail("must have at least %d items", *schema.MinItems)
+		}
+		if schema.MaxItems != nil && len(value) > *schema.MaxItems {
+			fail("must have at most %d items", *schema.MaxItems)
+		}
+		if schema.Items != nil {
+			for i, item := range value {
+				doc.validate(schema.Items, item, path+"["+strconv.Itoa(i)+"]", problems)
+			}
+		}
+	case map[string]interface{}:
+		for _, name := range schema.Required {
+			if _, ok := value[name]; !ok {
+				fail("missing property %s", name)
+			}
+		}
+		names := make([]string, 0, len(value))
+		for name := range value {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			property := schema.Properties[name]
+			if property == nil {
+				property = schema.AdditionalProperties
+			}
+			if property == nil {
+				continue
+			}
+			at := name
+			if path != "" {
+				at = path + "." + name
+			}
+			doc.validate(property, value[name], at, problems)
+		}
+	}
+}
+
+func jsonType(value interface{}) string {
+	switch value := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if value == math.Trunc(value) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return "unknown"
+}
+
+func joinTypes(types []string) string {
+	if len(types) == 1 {
+		return types[0]
+	}
+	joined := types[0]
+	for _, t := range types[1:] {
+		joined += " or " + t
+	}
+	return joined
+}
+
+func equalJSON(a, b interface{}) bool {
+	if number, ok := a.(int); ok {
+		a = float64(number)
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// ValidateRequest checks the parameters and body of a request against op.
+// Bodies are only checked when they are JSON.
+func (doc *Document) ValidateRequest(op *Operation, pathParams map[string]string, query url.Values, contentType string, body []byte) []string {
+	var problems []string
+	for _, parameter := range op.Parameters {
+		var raw string
+		var ok bool
+		switch parameter.In {
+		case "path":
+			raw, ok = pathParams[parameter.Name]
+		case "query":
+			ok = query.Has(parameter.Name)
+			raw = query.Get(parameter.Name)
+		default:
+			continue
+		}
+		if !ok {
+			if parameter.Required {
+				problems = append(problems, parameter.In+" parameter "+parameter.Name+": is required")
+			}
+			continue
+		}
+		for _, problem := range doc.Validate(parameter.Schema, parseParameter(doc.Resolve(parameter.Schema), raw)) {
+			problems = append(problems, parameter.In+" parameter "+parameter.Name+problem[len("body"):])
+		}
+	}
+
+	if op.RequestBody == nil {
+		return problems
+	}
+	if len(body) == 0 {
+		if op.RequestBody.Required {
+			problems = append(problems, "body: is required")
+		}
+		return problems
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	content, ok := op.RequestBody.Content[mediaType]
+	if !ok {
+		return append(problems, "body: content type "+contentType+" is not accepted")
+	}
+	if content.Schema == nil || !isJSON(mediaType) {
+		return problems
+	}
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return append(problems, "body: "+err.Error())
+	}
+	return append(problems, doc.Validate(content.Schema, value)...)
+}
+
+// ValidateResponse checks a response written for op against the response
+// declared for its status, or the default one.
+func (doc *Document) ValidateResponse(op *Operation, status int, contentType string, body []byte) error {
+	response, ok := op.Responses[strconv.Itoa(status)]
+	if !ok {
+		response, ok = op.Responses["default"]
+	}
+	if !ok {
+		return fmt.Errorf("status %d is not declared", status)
+	}
+	if len(response.Content) == 0 {
+		return nil
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	content, ok := response.Content[mediaType]
+	if !ok {
+		return fmt.Errorf("status %d: content type %q is not declared", status, contentType)
+	}
+	if content.Schema == nil || !isJSON(mediaType) {
+		return nil
+	}
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return fmt.Errorf("status %d: %s", status, err.Error())
+	}
+	if problems := doc.Validate(content.Schema, value); len(problems) > 0 {
+		return fmt.Errorf("status %d: %v", status, problems)
+	}
+	return nil
+}
+
+// parseParameter reads a path or query parameter as the type its schema
+// asks for, leaving it a string when it does not parse.
+func parseParameter(schema *Schema, raw string) interface{} {
+	if schema == nil {
+		return raw
+	}
+	types := typeNames(nonNull(schema))
+	switch {
+	case contains(types, "integer"), contains(types, "number"):
+		if number, err := strconv.ParseFloat(raw, 64); err == nil {
+			return number
+		}
+	case contains(types, "boolean"):
+		if value, err := strconv.ParseBool(raw); err == nil {
+			return value
+		}
+	}
+	return raw
+}
+
+func isJSON(mediaType string) bool {
+	return mediaType == "application/json" || len(mediaType) > 5 && mediaType[len(mediaType)-5:] == "+json"
+}
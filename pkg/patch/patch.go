@@ -0,0 +1,47 @@
+package patch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+
+	jsonpatch "github.com/evanphx/json-patch"
+)
+
+const (
+	MergePatch = "application/merge-patch+json"
+	JSONPatch  = "application/json-patch+json"
+)
+
+var ErrUnsupportedMediaType = errors.New("unsupported patch media type")
+
+// Apply patches the JSON form of original with a RFC 7386 merge patch or a
+// RFC 6902 JSON patch, depending on mediaType, and decodes the result into
+// target. Fields unknown to target are rejected.
+func Apply(mediaType string, original interface{}, patchDoc []byte, target interface{}) error {
+	doc, err := json.Marshal(original)
+	if err != nil {
+		return err
+	}
+
+	var patched []byte
+	switch mediaType {
+	case MergePatch:
+		patched, err = jsonpatch.MergePatch(doc, patchDoc)
+	case JSONPatch:
+		var ops jsonpatch.Patch
+		ops, err = jsonpatch.DecodePatch(patchDoc)
+		if err == nil {
+			patched, err = ops.Apply(doc)
+		}
+	default:
+		return ErrUnsupportedMediaType
+	}
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(patched))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(target)
+}
@@ -0,0 +1,73 @@
+package patch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testBook struct {
+	Title     string   `json:"title"`
+	Publisher *string  `json:"publisher"`
+	PageCount int      `json:"page_count"`
+	Tags      []string `json:"tags"`
+}
+
+func TestApply(t *testing.T) {
+	publisher := "Ace"
+	original := testBook{Title: "Dune", Publisher: &publisher, PageCount: 412, Tags: []string{"sf"}}
+
+	tests := []struct {
+		name      string
+		mediaType string
+		patch     string
+		want      testBook
+	}{
+		{"merge patch changes a field", MergePatch, `{"title":"Dune Messiah"}`, testBook{Title: "Dune Messiah", Publisher: &publisher, PageCount: 412, Tags: []string{"sf"}}},
+		{"merge patch clears a field with null", MergePatch, `{"publisher":null}`, testBook{Title: "Dune", PageCount: 412, Tags: []string{"sf"}}},
+		{"merge patch replaces a list", MergePatch, `{"tags":["classic"]}`, testBook{Title: "Dune", Publisher: &publisher, PageCount: 412, Tags: []string{"classic"}}},
+		{"JSON patch replaces a field", JSONPatch, `[{"op":"replace","path":"/page_count","value":896}]`, testBook{Title: "Dune", Publisher: &publisher, PageCount: 896, Tags: []string{"sf"}}},
+		{"JSON patch adds to a list", JSONPatch, `[{"op":"add","path":"/tags/-","value":"classic"}]`, testBook{Title: "Dune", Publisher: &publisher, PageCount: 412, Tags: []string{"sf", "classic"}}},
+		{"JSON patch test passes", JSONPatch, `[{"op":"test","path":"/title","value":"Dune"},{"op":"remove","path":"/tags"}]`, testBook{Title: "Dune", Publisher: &publisher, PageCount: 412}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var patched testBook
+
+			err := Apply(test.mediaType, original, []byte(test.patch), &patched)
+
+			assert.Nil(t, err)
+			assert.Equal(t, test.want, patched)
+		})
+	}
+	assert.Equal(t, "Dune", original.Title)
+}
+
+func TestApply_Errors(t *testing.T) {
+	tests := []struct {
+		name      string
+		mediaType string
+		patch     string
+		err       error
+	}{
+		{"unsupported media type", "application/json", `{"title":"Dune Messiah"}`, ErrUnsupportedMediaType},
+		{"merge patch that is not JSON", MergePatch, `{"title":`, nil},
+		{"merge patch of an unknown field", MergePatch, `{"subtitle":"Book One"}`, nil},
+		{"merge patch of a wrong type", MergePatch, `{"page_count":"many"}`, nil},
+		{"JSON patch that is not a list of operations", JSONPatch, `{"op":"replace"}`, nil},
+		{"JSON patch of a missing path", JSONPatch, `[{"op":"replace","path":"/subtitle/0","value":"Book One"}]`, nil},
+		{"JSON patch test fails", JSONPatch, `[{"op":"test","path":"/title","value":"Dune Messiah"},{"op":"replace","path":"/title","value":"Children of Dune"}]`, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var patched testBook
+
+			err := Apply(test.mediaType, testBook{Title: "Dune"}, []byte(test.patch), &patched)
+
+			assert.NotNil(t, err)
+			if test.err != nil {
+				assert.Equal(t, test.err, err)
+			}
+		})
+	}
+}
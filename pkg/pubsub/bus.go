@@ -0,0 +1,71 @@
+// Package pubsub passes messages between the goroutines of one process.
+//
+// Publishing never waits for a subscriber: one that lets its buffer fill up
+// is dropped, its channel closed, so that a slow reader cannot hold up the
+// writers. Subscribers that need every message catch up from storage of
+// their own after a drop.
+package pubsub
+
+import "sync"
+
+type Bus struct {
+	mu          sync.Mutex
+	subscribers map[string]map[*subscriber]bool
+}
+
+type subscriber struct {
+	messages chan interface{}
+	once     sync.Once
+}
+
+func (s *subscriber) close() {
+	s.once.Do(func() { close(s.messages) })
+}
+
+func NewBus() *Bus {
+	return &Bus{subscribers: map[string]map[*subscriber]bool{}}
+}
+
+// Subscribe returns the messages published on topic from now on, holding up
+// to buffer of them that are not read yet. cancel ends the subscription and
+// closes the channel.
+func (bus *Bus) Subscribe(topic string, buffer int) (messages <-chan interface{}, cancel func()) {
+	s := &subscriber{messages: make(chan interface{}, buffer)}
+	bus.mu.Lock()
+	if bus.subscribers[topic] == nil {
+		bus.subscribers[topic] = map[*subscriber]bool{}
+	}
+	bus.subscribers[topic][s] = true
+	bus.mu.Unlock()
+
+	return s.messages, func() {
+		bus.remove(topic, s)
+		s.close()
+	}
+}
+
+// Publish sends message to the subscribers of topic.
+func (bus *Bus) Publish(topic string, message interface{}) {
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+	for s := range bus.subscribers[topic] {
+		select {
+		case s.messages <- message:
+		default:
+			delete(bus.subscribers[topic], s)
+			s.close()
+		}
+	}
+	if len(bus.subscribers[topic]) == 0 {
+		delete(bus.subscribers, topic)
+	}
+}
+
+func (bus *Bus) remove(topic string, s *subscriber) {
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+	delete(bus.subscribers[topic], s)
+	if len(bus.subscribers[topic]) == 0 {
+		delete(bus.subscribers, topic)
+	}
+}
@@ -0,0 +1,106 @@
+package similarity
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// NormalizeName folds a personal name into a comparable form: accents and
+// punctuation are dropped, "Last, First" is reordered to "First Last" and
+// the remaining tokens are lower-cased and sorted.
+func NormalizeName(name string) string {
+	if last, first, ok := strings.Cut(name, ","); ok {
+		name = first + " " + last
+	}
+
+	var builder strings.Builder
+	for _, r := range norm.NFD.String(name) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			continue
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			builder.WriteRune(unicode.ToLower(r))
+		default:
+			builder.WriteRune(' ')
+		}
+	}
+
+	tokens := strings.Fields(builder.String())
+	sort.Strings(tokens)
+	return strings.Join(tokens, " ")
+}
+
+// NameSimilarity returns a score between 0 and 1 based on the edit distance
+// of the normalized names.
+func NameSimilarity(a, b string) float64 {
+	return NormalizedSimilarity(NormalizeName(a), NormalizeName(b))
+}
+
+// NormalizedSimilarity is NameSimilarity for names that went through
+// NormalizeName already.
+func NormalizedSimilarity(a, b string) float64 {
+	left := []rune(a)
+	right := []rune(b)
+	longest := len(left)
+	if len(right) > longest {
+		longest = len(right)
+	}
+	if longest == 0 {
+		return 0
+	}
+	return 1 - float64(levenshtein(left, right))/float64(longest)
+}
+
+// NameKeys returns the blocking keys of a normalized name: each token of at
+// least three letters, which catches a reordered or abbreviated name, and
+// the sorted initials, which catches a misspelt surname. Names that share no
+// key are not worth comparing.
+func NameKeys(normalized string) []string {
+	var keys []string
+	var initials []rune
+	for _, token := range strings.Fields(normalized) {
+		runes := []rune(token)
+		if len(runes) >= 3 {
+			keys = append(keys, token)
+		}
+		initials = append(initials, runes[0])
+	}
+	if len(initials) != 0 {
+		sort.Slice(initials, func(i, j int) bool { return initials[i] < initials[j] })
+		keys = append(keys, "#"+string(initials))
+	}
+	return keys
+}
+
+func levenshtein(a, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = minInt(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+func minInt(values ...int) int {
+	result := values[0]
+	for _, value := range values[1:] {
+		if value < result {
+			result = value
+		}
+	}
+	return result
+}
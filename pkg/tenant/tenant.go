@@ -0,0 +1,183 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+const Column = "tenant_id"
+
+var (
+	ErrMissingTenant = errors.New("tenant: no tenant in context")
+	ErrNotFound      = errors.New("tenant: record not found")
+)
+
+type contextKey struct{}
+
+// WithID returns a copy of ctx carrying the tenant id.
+func WithID(ctx context.Context, id uint) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the tenant id carried by ctx.
+func FromContext(ctx context.Context) (uint, bool) {
+	if ctx == nil {
+		return 0, false
+	}
+	id, ok := ctx.Value(contextKey{}).(uint)
+	return id, ok && id != 0
+}
+
+// Register installs callbacks on db that scope every query, update and
+// delete on the tables of models to the tenant of the statement context and
+// stamp that tenant on every record created in them. Statements on those
+// tables fail with ErrMissingTenant when the context has no tenant, so a
+// repository cannot reach another tenant's rows by forgetting a condition.
+func Register(db *gorm.DB, models ...interface{}) error {
+	tables := make(map[string]bool)
+	for _, model := range models {
+		s, err := schema.Parse(model, &sync.Map{}, db.NamingStrategy)
+		if err != nil {
+			return err
+		}
+		if s.LookUpField(Column) == nil {
+			return fmt.Errorf("tenant: %s has no %s column", s.Table, Column)
+		}
+		tables[s.Table] = true
+	}
+	scoped := func(stmt *gorm.Statement) bool {
+		return tables[stmt.Table]
+	}
+
+	callbacks := db.Callback()
+	if err := callbacks.Query().Before("gorm:query").Register("tenant:scope", scope(scoped, false)); err != nil {
+		return err
+	}
+	if err := callbacks.Row().Before("gorm:row").Register("tenant:scope", scope(scoped, false)); err != nil {
+		return err
+	}
+	if err := callbacks.Update().Before("gorm:update").Register("tenant:scope", scope(scoped, true)); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().Before("gorm:delete").Register("tenant:scope", scope(scoped, false)); err != nil {
+		return err
+	}
+	if err := callbacks.Create().Before("gorm:create").Register("tenant:stamp", stamp(scoped)); err != nil {
+		return err
+	}
+	return callbacks.Create().After("gorm:create").Register("tenant:check_upsert", checkUpsert(scoped))
+}
+
+// scope adds the tenant condition to the statement. With update set, the
+// tenant is also written into the updated records so that saving a whole
+// struct keeps its tenant.
+func scope(scoped func(*gorm.Statement) bool, update bool) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		stmt := db.Statement
+		if db.Error != nil || !scoped(stmt) {
+			return
+		}
+		id, ok := FromContext(stmt.Context)
+		if !ok {
+			db.AddError(ErrMissingTenant)
+			return
+		}
+
+		if update {
+			setTenant(stmt, id)
+		}
+
+		// the existing conditions are grouped so that an OR among them
+		// cannot escape the tenant condition
+		condition := clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: Column}, Value: id}
+		if c, ok := stmt.Clauses["WHERE"]; ok {
+			if where, ok := c.Expression.(clause.Where); ok && len(where.Exprs) > 0 {
+				c.Expression = clause.Where{Exprs: []clause.Expression{group(where), condition}}
+				stmt.Clauses["WHERE"] = c
+				return
+			}
+		}
+		stmt.AddClause(clause.Where{Exprs: []clause.Expression{condition}})
+	}
+}
+
+func stamp(scoped func(*gorm.Statement) bool) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		stmt := db.Statement
+		if db.Error != nil || !scoped(stmt) {
+			return
+		}
+		id, ok := FromContext(stmt.Context)
+		if !ok {
+			db.AddError(ErrMissingTenant)
+			return
+		}
+		setTenant(stmt, id)
+
+		// an upsert must not update a row of another tenant that happens to
+		// hold the same key
+		if c, ok := stmt.Clauses["ON CONFLICT"]; ok {
+			if onConflict, ok := c.Expression.(clause.OnConflict); ok && !onConflict.DoNothing {
+				onConflict.Where.Exprs = append(onConflict.Where.Exprs, clause.Eq{
+					Column: clause.Column{Table: stmt.Table, Name: Column},
+					Value:  id,
+				})
+				c.Expression = onConflict
+				stmt.Clauses["ON CONFLICT"] = c
+			}
+		}
+	}
+}
+
+// checkUpsert fails the update-or-insert gorm falls back to when Save
+// updates no row, if the row it collided with belongs to another tenant.
+func checkUpsert(scoped func(*gorm.Statement) bool) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		stmt := db.Statement
+		if db.Error != nil || !scoped(stmt) || db.RowsAffected > 0 {
+			return
+		}
+		if c, ok := stmt.Clauses["ON CONFLICT"]; ok {
+			if onConflict, ok := c.Expression.(clause.OnConflict); ok && !onConflict.DoNothing {
+				db.AddError(ErrNotFound)
+			}
+		}
+	}
+}
+
+// setTenant writes the tenant id into the records of the statement, so that
+// creates and full-row updates never store another tenant.
+func setTenant(stmt *gorm.Statement, id uint) {
+	if stmt.Schema == nil {
+		return
+	}
+	field := stmt.Schema.LookUpField(Column)
+	if field == nil {
+		return
+	}
+	switch stmt.ReflectValue.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < stmt.ReflectValue.Len(); i++ {
+			stmt.AddError(field.Set(stmt.Context, reflect.Indirect(stmt.ReflectValue.Index(i)), id))
+		}
+	case reflect.Struct:
+		if stmt.ReflectValue.CanAddr() {
+			stmt.AddError(field.Set(stmt.Context, stmt.ReflectValue, id))
+		}
+	}
+}
+
+type group clause.Where
+
+func (g group) Build(builder clause.Builder) {
+	builder.WriteByte('(')
+	clause.Where(g).Build(builder)
+	builder.WriteByte(')')
+}
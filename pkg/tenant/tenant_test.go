@@ -0,0 +1,164 @@
+package tenant
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type testNote struct {
+	ID       uint
+	TenantID uint   `gorm:"uniqueIndex:idx_test_notes_tenant_code"`
+	Code     string `gorm:"uniqueIndex:idx_test_notes_tenant_code;uniqueIndex:idx_test_notes_code"`
+	Title    string
+}
+
+type testSetting struct {
+	ID    uint
+	Value string
+}
+
+// newTestDB registers testNote for tenants and creates note "a" for tenant
+// 1 and note "b" for tenant 2.
+func newTestDB(t *testing.T) (*gorm.DB, context.Context, context.Context) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	assert.Nil(t, err)
+	assert.Nil(t, db.AutoMigrate(&testNote{}, &testSetting{}))
+	assert.Nil(t, Register(db, &testNote{}))
+
+	first, second := WithID(context.Background(), 1), WithID(context.Background(), 2)
+	assert.Nil(t, db.WithContext(first).Create(&testNote{Code: "a", Title: "first"}).Error)
+	assert.Nil(t, db.WithContext(second).Create(&testNote{Code: "b", Title: "second"}).Error)
+	return db, first, second
+}
+
+func TestFromContext(t *testing.T) {
+	id, ok := FromContext(WithID(context.Background(), 3))
+	assert.True(t, ok)
+	assert.Equal(t, uint(3), id)
+
+	_, ok = FromContext(context.Background())
+	assert.False(t, ok)
+	_, ok = FromContext(WithID(context.Background(), 0))
+	assert.False(t, ok)
+	_, ok = FromContext(nil)
+	assert.False(t, ok)
+}
+
+func TestRegister_ModelWithoutTenant(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	assert.Nil(t, err)
+
+	err = Register(db, &testSetting{})
+
+	assert.EqualError(t, err, "tenant: test_settings has no tenant_id column")
+}
+
+func TestCreate_StampsTenant(t *testing.T) {
+	db, first, _ := newTestDB(t)
+
+	// a tenant set by the caller is overwritten by the one of the context
+	note := &testNote{TenantID: 2, Code: "c"}
+	assert.Nil(t, db.WithContext(first).Create(note).Error)
+	notes := []*testNote{{TenantID: 2, Code: "d"}, {Code: "e"}}
+	assert.Nil(t, db.WithContext(first).Create(&notes).Error)
+
+	assert.Equal(t, uint(1), note.TenantID)
+	assert.Equal(t, uint(1), notes[0].TenantID)
+	assert.Equal(t, uint(1), notes[1].TenantID)
+	var count int64
+	assert.Nil(t, db.WithContext(first).Model(&testNote{}).Count(&count).Error)
+	assert.Equal(t, int64(4), count)
+}
+
+func TestScope(t *testing.T) {
+	db, first, _ := newTestDB(t)
+
+	tests := []struct {
+		name  string
+		query func(tx *gorm.DB) *gorm.DB
+		codes []string
+	}{
+		{"every row", func(tx *gorm.DB) *gorm.DB { return tx }, []string{"a"}},
+		{"row of another tenant", func(tx *gorm.DB) *gorm.DB { return tx.Where("code = ?", "b") }, nil},
+		{"OR does not escape the tenant", func(tx *gorm.DB) *gorm.DB { return tx.Where("code = ?", "b").Or("1 = 1") }, []string{"a"}},
+		{"raw OR does not escape the tenant", func(tx *gorm.DB) *gorm.DB { return tx.Where("code = 'b' OR 1 = 1") }, []string{"a"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var notes []*testNote
+			assert.Nil(t, test.query(db.WithContext(first)).Find(&notes).Error)
+
+			var codes []string
+			for _, note := range notes {
+				codes = append(codes, note.Code)
+			}
+			assert.Equal(t, test.codes, codes)
+		})
+	}
+}
+
+func TestScope_MissingTenant(t *testing.T) {
+	db, _, _ := newTestDB(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		run  func(tx *gorm.DB) error
+	}{
+		{"query", func(tx *gorm.DB) error { return tx.Find(&[]*testNote{}).Error }},
+		{"rows", func(tx *gorm.DB) error {
+			rows, err := tx.Model(&testNote{}).Rows()
+			if err == nil {
+				rows.Close()
+			}
+			return err
+		}},
+		{"create", func(tx *gorm.DB) error { return tx.Create(&testNote{Code: "c"}).Error }},
+		{"update", func(tx *gorm.DB) error {
+			return tx.Model(&testNote{}).Where("code = ?", "a").Update("title", "changed").Error
+		}},
+		{"delete", func(tx *gorm.DB) error { return tx.Where("code = ?", "a").Delete(&testNote{}).Error }},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.ErrorIs(t, test.run(db.WithContext(ctx)), ErrMissingTenant)
+		})
+	}
+
+	// tables that are not registered need no tenant
+	assert.Nil(t, db.WithContext(ctx).Create(&testSetting{Value: "on"}).Error)
+	assert.Nil(t, db.WithContext(ctx).Find(&[]*testSetting{}).Error)
+}
+
+func TestScope_WritesOfAnotherTenant(t *testing.T) {
+	db, first, second := newTestDB(t)
+	var other testNote
+	assert.Nil(t, db.WithContext(second).First(&other).Error)
+
+	result := db.WithContext(first).Model(&testNote{}).Where("id = ?", other.ID).Update("title", "changed")
+	assert.Nil(t, result.Error)
+	assert.Equal(t, int64(0), result.RowsAffected)
+
+	// saving the whole row falls back to an insert that collides with the
+	// row of the other tenant
+	err := db.WithContext(first).Save(&testNote{ID: other.ID, TenantID: 2, Code: "b", Title: "changed"}).Error
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// an upsert on a key the other tenant holds leaves its row alone
+	db.WithContext(first).Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "code"}}, UpdateAll: true}).
+		Create(&testNote{Code: "b", Title: "changed"})
+
+	result = db.WithContext(first).Where("id = ?", other.ID).Delete(&testNote{})
+	assert.Nil(t, result.Error)
+	assert.Equal(t, int64(0), result.RowsAffected)
+
+	var stored testNote
+	assert.Nil(t, db.WithContext(second).First(&stored, other.ID).Error)
+	assert.Equal(t, "second", stored.Title)
+	assert.Equal(t, uint(2), stored.TenantID)
+}
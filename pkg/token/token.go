@@ -10,8 +10,9 @@ import (
 )
 
 type Token struct {
-	AuthId  int
-	Expired time.Time
+	AuthId   int
+	TenantId int
+	Expired  time.Time
 }
 
 const (
@@ -19,10 +20,11 @@ const (
 	TOKEN_Expiry = 24 * time.Hour
 )
 
-func GenerateToken(authId int) (string, error) {
+func GenerateToken(authId, tenantId int) (string, error) {
 	payload := Token{
-		AuthId:  authId,
-		Expired: time.Now().Add(TOKEN_Expiry),
+		AuthId:   authId,
+		TenantId: tenantId,
+		Expired:  time.Now().Add(TOKEN_Expiry),
 	}
 	claims := jwt.MapClaims{
 		"payload": payload,
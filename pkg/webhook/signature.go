@@ -0,0 +1,65 @@
+// Package webhook signs webhook deliveries and checks their signatures.
+//
+// A delivery carries its time in the Webhook-Timestamp header, as Unix
+// seconds, and in Webhook-Signature the hex HMAC-SHA256 of the timestamp, a
+// dot and the body, keyed with the subscription secret and prefixed with
+// "sha256=". Signing the timestamp lets receivers turn away replays of old
+// deliveries.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"time"
+)
+
+const (
+	SignatureHeader = "Webhook-Signature"
+	TimestampHeader = "Webhook-Timestamp"
+	EventHeader     = "Webhook-Event"
+	DeliveryHeader  = "Webhook-Delivery"
+)
+
+var (
+	ErrInvalidSignature = errors.New("webhook: invalid signature")
+	ErrExpiredTimestamp = errors.New("webhook: timestamp out of tolerance")
+)
+
+// Sign returns the signature of body sent at timestamp.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature and timestamp headers of a delivery, turning
+// it away when its timestamp is further than tolerance from now.
+func Verify(secret, signature, timestamp string, body []byte, tolerance time.Duration, now time.Time) error {
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	sentAt := time.Unix(seconds, 0)
+	if sentAt.Before(now.Add(-tolerance)) || sentAt.After(now.Add(tolerance)) {
+		return ErrExpiredTimestamp
+	}
+	if !hmac.Equal([]byte(signature), []byte(Sign(secret, sentAt, body))) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// NewSecret returns a random secret for a subscription.
+func NewSecret() (string, error) {
+	buffer := make([]byte, 24)
+	if _, err := rand.Read(buffer); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(buffer), nil
+}
@@ -0,0 +1,73 @@
+package webhook
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSign(t *testing.T) {
+	sentAt := time.Unix(1700000000, 0)
+
+	signature := Sign("whsec_test", sentAt, []byte(`{"event":"book.created"}`))
+
+	// the hex HMAC-SHA256 of the timestamp, a dot and the body
+	assert.Equal(t, "sha256=a277cbc57685ce7c1bc59ceedd3ed971ad32f5096604eb1be15bea0df54ec629", signature)
+	assert.Equal(t, signature, Sign("whsec_test", sentAt.Add(999*time.Millisecond), []byte(`{"event":"book.created"}`)))
+	assert.NotEqual(t, signature, Sign("whsec_test", sentAt.Add(time.Second), []byte(`{"event":"book.created"}`)))
+	assert.NotEqual(t, signature, Sign("whsec_other", sentAt, []byte(`{"event":"book.created"}`)))
+}
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"event":"book.created","data":{"id":1}}`)
+	sentAt := time.Unix(1700000000, 0)
+	timestamp := strconv.FormatInt(sentAt.Unix(), 10)
+	signature := Sign("whsec_test", sentAt, body)
+	tolerance := 5 * time.Minute
+
+	tests := []struct {
+		name      string
+		secret    string
+		signature string
+		timestamp string
+		body      []byte
+		now       time.Time
+		err       error
+	}{
+		{"fresh delivery", "whsec_test", signature, timestamp, body, sentAt, nil},
+		{"received at the end of the tolerance", "whsec_test", signature, timestamp, body, sentAt.Add(tolerance), nil},
+		{"clock of the receiver behind", "whsec_test", signature, timestamp, body, sentAt.Add(-tolerance), nil},
+		{"replayed after the tolerance", "whsec_test", signature, timestamp, body, sentAt.Add(tolerance + time.Second), ErrExpiredTimestamp},
+		{"sent too far in the future", "whsec_test", signature, timestamp, body, sentAt.Add(-tolerance - time.Second), ErrExpiredTimestamp},
+		// a replay cannot pass for fresh by changing the timestamp, as the
+		// timestamp is signed
+		{"replayed with a fresh timestamp", "whsec_test", signature, strconv.FormatInt(sentAt.Add(time.Hour).Unix(), 10), body, sentAt.Add(time.Hour), ErrInvalidSignature},
+		{"changed body", "whsec_test", signature, timestamp, []byte(`{"event":"book.created","data":{"id":2}}`), sentAt, ErrInvalidSignature},
+		{"other secret", "whsec_other", signature, timestamp, body, sentAt, ErrInvalidSignature},
+		{"signature without prefix", "whsec_test", strings.TrimPrefix(signature, "sha256="), timestamp, body, sentAt, ErrInvalidSignature},
+		{"missing signature", "whsec_test", "", timestamp, body, sentAt, ErrInvalidSignature},
+		{"missing timestamp", "whsec_test", signature, "", body, sentAt, ErrInvalidSignature},
+		{"timestamp that is not a number", "whsec_test", signature, "yesterday", body, sentAt, ErrInvalidSignature},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := Verify(test.secret, test.signature, test.timestamp, test.body, tolerance, test.now)
+
+			assert.Equal(t, test.err, err)
+		})
+	}
+}
+
+func TestNewSecret(t *testing.T) {
+	secret, err := NewSecret()
+	assert.Nil(t, err)
+	other, err := NewSecret()
+	assert.Nil(t, err)
+
+	assert.True(t, strings.HasPrefix(secret, "whsec_"))
+	assert.Len(t, secret, len("whsec_")+48)
+	assert.NotEqual(t, secret, other)
+}
@@ -0,0 +1,6 @@
+// Package catalogv1 holds the protobuf messages and gRPC services of the
+// catalog. The Go files are generated from the .proto files next to them;
+// gateway.yaml maps the services to HTTP for grpc-gateway.
+package catalogv1
+
+//go:generate protoc -I ../.. --go_out=../.. --go_opt=paths=source_relative --go-grpc_out=../.. --go-grpc_opt=paths=source_relative catalog/v1/author.proto catalog/v1/book.proto catalog/v1/auth.proto
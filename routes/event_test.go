@@ -0,0 +1,97 @@
+package routes
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// openStream connects to the event stream and returns its lines as they
+// come.
+func openStream(t *testing.T, ctx context.Context, client *testClient, url, lastEventID string) <-chan string {
+	server := httptest.NewServer(client.router)
+	t.Cleanup(server.Close)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", server.URL+url, nil)
+	assert.Nil(t, err)
+	req.Header.Set("Authorization", "Bearer "+client.token)
+	req.Header.Set("Accept", "text/event-stream")
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		defer resp.Body.Close()
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+	return lines
+}
+
+// nextEvent reads the lines of the next event with an id.
+func nextEvent(t *testing.T, lines <-chan string) map[string]string {
+	event := map[string]string{}
+	for {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				t.Fatal("stream closed")
+			}
+			if line == "" {
+				if event["id"] != "" {
+					return event
+				}
+				event = map[string]string{}
+				continue
+			}
+			name, value, _ := strings.Cut(line, ": ")
+			event[name] = value
+		case <-time.After(2 * time.Second):
+			t.Fatal("no event")
+		}
+	}
+}
+
+func TestEventStream(t *testing.T) {
+	client := &testClient{router: newTestRouter(t, false)}
+	client.login(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	lines := openStream(t, ctx, client, "/v1/events/stream?entity=book", "")
+
+	assert.Equal(t, http.StatusCreated, client.json("POST", "/v1/authors/", `{"name":"Ursula K. Le Guin","birthdate":"1929-10-21"}`).Code)
+	assert.Equal(t, http.StatusCreated, client.json("POST", "/v1/books/", `{"title":"A Wizard of Earthsea","author_id":1}`).Code)
+
+	// the user and the author are filtered out
+	event := nextEvent(t, lines)
+	assert.Equal(t, "book.created", event["event"])
+	assert.Equal(t, "3", event["id"])
+	assert.Contains(t, event["data"], `"title":"A Wizard of Earthsea"`)
+	cancel()
+
+	// reconnecting replays what came after the last event seen
+	assert.Equal(t, http.StatusOK, client.json("DELETE", "/v1/books/1", "").Code)
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	lines = openStream(t, ctx, client, "/v1/events/stream", "2")
+
+	event = nextEvent(t, lines)
+	assert.Equal(t, "book.created", event["event"])
+	event = nextEvent(t, lines)
+	assert.Equal(t, "book.deleted", event["event"])
+	assert.Equal(t, `{"id":1}`, event["data"])
+}
@@ -0,0 +1,40 @@
+package routes
+
+import (
+	"fmt"
+	"golang-backend-test/database"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestGraphQLLoadsBookAuthorsTogether(t *testing.T) {
+	db, err := database.NewSQLiteMemoryConnection()
+	assert.Nil(t, err)
+	client := &testClient{router: newTestRouterWithDB(t, db, false)}
+	client.login(t)
+	for i := 1; i <= 3; i++ {
+		w := client.json("POST", "/authors/", fmt.Sprintf(`{"name":"Author %d","birthdate":"1950-01-01"}`, i))
+		assert.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		for j := 1; j <= 2; j++ {
+			w = client.json("POST", "/books/", fmt.Sprintf(`{"title":"Book %d.%d","isbn":"97800000000%d%d","author_id":%d}`, i, j, i, j, i))
+			assert.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		}
+	}
+
+	var authorQueries []string
+	assert.Nil(t, db.Callback().Query().After("gorm:query").Register("test:count_authors", func(tx *gorm.DB) {
+		if tx.Statement.Table == "authors" {
+			authorQueries = append(authorQueries, tx.Statement.SQL.String())
+		}
+	}))
+	w := client.json("POST", "/graphql", `{"query":"{ books { items { title author { name } } } }"}`)
+
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, 6, strings.Count(w.Body.String(), `"author":{"name":"Author `))
+	// the six books of three authors take one query for their authors
+	assert.Len(t, authorQueries, 1, authorQueries)
+}
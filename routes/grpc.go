@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"golang-backend-test/app/rpc"
+	"golang-backend-test/factory"
+	catalogv1 "golang-backend-test/proto/catalog/v1"
+
+	"google.golang.org/grpc"
+)
+
+// NewGRPCServer serves the catalog over gRPC. Every service but AuthService
+// needs a bearer token in the authorization metadata.
+func NewGRPCServer(provider *factory.Provider) *grpc.Server {
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(rpc.AuthUnaryInterceptor()),
+		grpc.StreamInterceptor(rpc.AuthStreamInterceptor()),
+	)
+	catalogv1.RegisterBookServiceServer(server, provider.BookRPCProvider)
+	catalogv1.RegisterAuthorServiceServer(server, provider.AuthorRPCProvider)
+	catalogv1.RegisterAuthServiceServer(server, provider.AuthRPCProvider)
+	return server
+}
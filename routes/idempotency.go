@@ -0,0 +1,83 @@
+package routes
+
+import (
+	"bytes"
+	"golang-backend-test/app/services"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// responseRecorder keeps a copy of what a handler writes.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Idempotency lets clients retry POST requests safely. A request sent with an
+// Idempotency-Key header runs once; retries with the same key and body get
+// the stored response back, marked with an Idempotent-Replayed header. It
+// has to run after CheckAuth, as keys belong to the authenticated user.
+func Idempotency(service services.IdempotencyService) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		key := ctx.GetHeader("Idempotency-Key")
+		if key == "" || ctx.Request.Method != http.MethodPost {
+			ctx.Next()
+			return
+		}
+
+		body, err := io.ReadAll(ctx.Request.Body)
+		if err != nil {
+			ctx.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+		ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		idempotencyKey, custErr := service.Reserve(ctx, ctx.GetInt("authId"), key, ctx.Request.Method, ctx.Request.URL.RequestURI(), body)
+		if custErr != nil {
+			ctx.AbortWithStatusJSON(custErr.StatusCode, custErr)
+			return
+		}
+		if idempotencyKey.StatusCode != 0 {
+			ctx.Header("Idempotent-Replayed", "true")
+			ctx.Data(idempotencyKey.StatusCode, idempotencyKey.ContentType, idempotencyKey.Body)
+			ctx.Abort()
+			return
+		}
+
+		// a key whose response is not stored is released, so that a retry
+		// runs the request again rather than waiting out the key; that
+		// covers server errors, a failure to store and a handler that
+		// panics on its way to gin.Recovery
+		stored := false
+		defer func() {
+			if stored {
+				return
+			}
+			if custErr := service.Release(ctx, idempotencyKey); custErr != nil {
+				log.Printf("releasing idempotency key %d: %s", idempotencyKey.ID, custErr.Message)
+			}
+		}()
+
+		recorder := &responseRecorder{ResponseWriter: ctx.Writer}
+		ctx.Writer = recorder
+		ctx.Next()
+
+		if recorder.Status() >= http.StatusInternalServerError {
+			return
+		}
+		stored = service.Complete(ctx, idempotencyKey, recorder.Status(), recorder.Header().Get("Content-Type"), recorder.body.Bytes()) == nil
+	}
+}
@@ -0,0 +1,83 @@
+package routes
+
+import (
+	"errors"
+	"golang-backend-test/app/models"
+	"golang-backend-test/app/repositories"
+	"golang-backend-test/app/services"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// newIdempotencyTestRouter serves handler behind the idempotency middleware
+// and gin.Recovery, with keys reserved in repo.
+func newIdempotencyTestRouter(repo *repositories.MockIdempotencyRepository, handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	repo.On("FindIdempotencyKey", mock.Anything, mock.Anything, 1, "key-1").Return(nil, errors.New("idempotency key not found"))
+	repo.On("DeleteExpiredIdempotencyKeys", mock.Anything, mock.Anything, mock.AnythingOfType("time.Time")).Return(nil)
+	repo.On("CreateIdempotencyKey", mock.Anything, mock.Anything, mock.AnythingOfType("*models.IdempotencyKey")).Run(func(args mock.Arguments) {
+		args.Get(2).(*models.IdempotencyKey).ID = 5
+	}).Return(nil)
+
+	router := gin.New()
+	router.Use(gin.Recovery(), func(ctx *gin.Context) {
+		ctx.Set("authId", 1)
+	})
+	router.POST("/", Idempotency(services.NewIdempotencyService(repo, time.Hour, new(gorm.DB))), handler)
+	return router
+}
+
+func postWithIdempotencyKey(router *gin.Engine) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", "/", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", "key-1")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotency_ReleasesKeyWhenHandlerPanics(t *testing.T) {
+	repo := new(repositories.MockIdempotencyRepository)
+	repo.On("DeleteIdempotencyKey", mock.Anything, mock.Anything, uint(5)).Return(nil)
+	router := newIdempotencyTestRouter(repo, func(ctx *gin.Context) {
+		panic("handler failed")
+	})
+
+	assert.Equal(t, http.StatusInternalServerError, postWithIdempotencyKey(router).Code)
+	repo.AssertExpectations(t)
+	repo.AssertNotCalled(t, "UpdateIdempotencyKey", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestIdempotency_ReleasesKeyWhenStoringFails(t *testing.T) {
+	repo := new(repositories.MockIdempotencyRepository)
+	repo.On("UpdateIdempotencyKey", mock.Anything, mock.Anything, mock.AnythingOfType("*models.IdempotencyKey")).Return(errors.New("db error"))
+	repo.On("DeleteIdempotencyKey", mock.Anything, mock.Anything, uint(5)).Return(nil)
+	router := newIdempotencyTestRouter(repo, func(ctx *gin.Context) {
+		ctx.JSON(http.StatusCreated, gin.H{"id": 1})
+	})
+
+	assert.Equal(t, http.StatusCreated, postWithIdempotencyKey(router).Code)
+	repo.AssertExpectations(t)
+}
+
+func TestIdempotency_KeepsStoredKey(t *testing.T) {
+	repo := new(repositories.MockIdempotencyRepository)
+	repo.On("UpdateIdempotencyKey", mock.Anything, mock.Anything, mock.MatchedBy(func(idempotencyKey *models.IdempotencyKey) bool {
+		return idempotencyKey.StatusCode == http.StatusCreated
+	})).Return(nil)
+	router := newIdempotencyTestRouter(repo, func(ctx *gin.Context) {
+		ctx.JSON(http.StatusCreated, gin.H{"id": 1})
+	})
+
+	assert.Equal(t, http.StatusCreated, postWithIdempotencyKey(router).Code)
+	repo.AssertExpectations(t)
+	repo.AssertNotCalled(t, "DeleteIdempotencyKey", mock.Anything, mock.Anything, mock.Anything)
+}
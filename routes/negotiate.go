@@ -0,0 +1,173 @@
+package routes
+
+import (
+	"bytes"
+	"encoding/json"
+	"golang-backend-test/app/commons/response"
+	"golang-backend-test/pkg/negotiate"
+	"golang-backend-test/pkg/openapi"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// responseConverter holds what a handler writes until it can be converted.
+type responseConverter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseConverter) Write(data []byte) (int, error) {
+	return w.body.Write(data)
+}
+
+func (w *responseConverter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
+
+// Negotiate lets every route answer in the representation the Accept header
+// prefers among the ones the OpenAPI document lists for it: the JSON that
+// handlers write is converted to XML, MessagePack or, for lists, CSV. Request
+// bodies in XML or MessagePack are turned into JSON before handlers read
+// them. Other types get a 406 or a 415.
+func Negotiate(spec *openapi.Document) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		op := spec.Find(ctx.Request.Method, ctx.FullPath())
+		if op == nil {
+			ctx.Next()
+			return
+		}
+		if !convertRequest(ctx, spec, op) {
+			return
+		}
+
+		offered := responseTypes(op)
+		if len(offered) == 0 {
+			ctx.Next()
+			return
+		}
+		if len(offered) > 1 {
+			ctx.Header("Vary", "Accept")
+		}
+		format, ok := negotiate.Accept(ctx.GetHeader("Accept"), offered)
+		if !ok {
+			errParam := response.NotAcceptableErrorWithAdditionalInfo(offered)
+			ctx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+			return
+		}
+		// files and streams are written as they are
+		if format != negotiate.XML && format != negotiate.MsgPack && format != negotiate.CSV {
+			ctx.Next()
+			return
+		}
+
+		converter := &responseConverter{ResponseWriter: ctx.Writer}
+		ctx.Writer = converter
+		ctx.Next()
+		ctx.Writer = converter.ResponseWriter
+
+		body := converter.body.Bytes()
+		if len(body) == 0 {
+			return
+		}
+		// CSV errors stay JSON
+		if negotiate.Canonical(ctx.Writer.Header().Get("Content-Type")) == negotiate.JSON && (format != negotiate.CSV || ctx.Writer.Status() < http.StatusBadRequest) {
+			if converted, err := negotiate.Encode(format, body); err == nil {
+				ctx.Writer.Header().Set("Content-Type", negotiate.ContentType(format))
+				body = converted
+			}
+		}
+		ctx.Writer.Write(body)
+	}
+}
+
+// convertRequest turns an XML or MessagePack body into the JSON the handler
+// reads, and rejects a body of a type op does not take with a 415. It reports
+// whether the request goes on.
+func convertRequest(ctx *gin.Context, spec *openapi.Document, op *openapi.Operation) bool {
+	if op.RequestBody == nil || ctx.Request.ContentLength == 0 {
+		return true
+	}
+	contentType := negotiate.Canonical(ctx.ContentType())
+	if contentType == "" {
+		contentType = negotiate.JSON
+	}
+	content, ok := op.RequestBody.Content[contentType]
+	if !ok {
+		errParam := response.UnsupportedMediaTypeErrorWithAdditionalInfo(requestTypes(op))
+		ctx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return false
+	}
+	if contentType != negotiate.XML && contentType != negotiate.MsgPack {
+		return true
+	}
+
+	body, err := io.ReadAll(ctx.Request.Body)
+	if err != nil {
+		ctx.AbortWithStatus(http.StatusBadRequest)
+		return false
+	}
+	value, err := negotiate.Decode(contentType, body)
+	if err != nil {
+		errParam := response.BadRequestErrorWithAdditionalInfo(err.Error())
+		ctx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return false
+	}
+	if contentType == negotiate.XML {
+		value = spec.Coerce(content.Schema, value)
+	}
+	body, err = json.Marshal(value)
+	if err != nil {
+		errParam := response.BadRequestErrorWithAdditionalInfo(err.Error())
+		ctx.AbortWithStatusJSON(errParam.StatusCode, errParam)
+		return false
+	}
+	ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+	ctx.Request.ContentLength = int64(len(body))
+	ctx.Request.Header.Set("Content-Length", strconv.Itoa(len(body)))
+	ctx.Request.Header.Set("Content-Type", negotiate.JSON)
+	return true
+}
+
+// responseTypes lists the content types of the successful responses of op,
+// JSON first so that it is picked when the client does not mind.
+func responseTypes(op *openapi.Operation) []string {
+	seen := map[string]bool{}
+	for status, resp := range op.Responses {
+		if !strings.HasPrefix(status, "2") {
+			continue
+		}
+		for contentType := range resp.Content {
+			seen[contentType] = true
+		}
+	}
+	return ordered(seen)
+}
+
+func requestTypes(op *openapi.Operation) []string {
+	seen := map[string]bool{}
+	for contentType := range op.RequestBody.Content {
+		seen[contentType] = true
+	}
+	return ordered(seen)
+}
+
+func ordered(seen map[string]bool) []string {
+	var types []string
+	for _, contentType := range []string{negotiate.JSON, negotiate.XML, negotiate.MsgPack} {
+		if seen[contentType] {
+			types = append(types, contentType)
+			delete(seen, contentType)
+		}
+	}
+	var rest []string
+	for contentType := range seen {
+		rest = append(rest, contentType)
+	}
+	sort.Strings(rest)
+	return append(types, rest...)
+}
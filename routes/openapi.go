@@ -0,0 +1,517 @@
+package routes
+
+import (
+	"bytes"
+	"encoding/json"
+	"golang-backend-test/app/commons/response"
+	"golang-backend-test/app/params"
+	"golang-backend-test/pkg/graphql"
+	"golang-backend-test/pkg/negotiate"
+	"golang-backend-test/pkg/openapi"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// endpoint describes a route for the OpenAPI document.
+type endpoint struct {
+	tag     string
+	summary string
+	// public routes run without CheckAuth
+	public bool
+	// idempotent routes take an Idempotency-Key header
+	idempotent bool
+	// query holds the structs the handler binds from the query string, and
+	// params any other query parameters it reads
+	query  []interface{}
+	params []*openapi.Parameter
+	// body is the JSON request, optional when optionalBody is set; rawBody
+	// lists the content types of bodies the handler reads as they are
+	body         interface{}
+	optionalBody bool
+	rawBody      []string
+	// status is the status of success, 200 when zero. data is the payload
+	// of the response envelope, left out when nil; plain responses are not
+	// wrapped in the envelope at all
+	status int
+	data   *openapi.Schema
+	plain  bool
+	// files lists the content types of file responses, csv adds a CSV form
+	// of the payload and redirect a 301 to another resource
+	files    []string
+	csv      bool
+	redirect bool
+	// deprecated marks the unversioned aliases of the v1 routes
+	deprecated bool
+	// admin routes are for the admins of the organization, and operator
+	// routes for whoever holds the operator key
+	admin    bool
+	operator bool
+	// v2 routes answer with data alone, nothing on 204, and with an
+	// ErrorV2Response on errors, a 404 for a missing resource
+	v2 bool
+}
+
+type specBuilder struct {
+	doc *openapi.Document
+}
+
+// NewOpenAPI describes every route of NewRoutes. TestOpenAPICoversRoutes
+// fails when a route is added without being described here.
+func NewOpenAPI() *openapi.Document {
+	spec := &specBuilder{doc: openapi.NewDocument("golang-backend-test", "1.0.0")}
+	spec.doc.Info.Description = "Library catalog API. Under /v1, successful responses are wrapped in the Response envelope and errors are a CustomError; " +
+		"the same routes without /v1 are deprecated. Under /v2, responses are the resource itself and errors an ErrorV2Response. " +
+		"Every JSON body can be sent and asked for as XML or MessagePack instead, and lists as CSV."
+	// the envelopes, named Response and CustomError, and the v2 error
+	spec.data(response.Response{})
+	spec.data(response.CustomError{})
+	spec.data(params.ErrorV2Response{})
+
+	spec.add("GET", "/openapi.json", endpoint{tag: "docs", summary: "This OpenAPI document", public: true, plain: true, data: &openapi.Schema{Type: "object"}})
+	spec.add("GET", "/docs", endpoint{tag: "docs", summary: "Swagger UI for this document", public: true, files: []string{"text/html"}})
+
+	spec.v1("POST", "/organizations/", endpoint{tag: "organizations", summary: "Create an organization with the invite of its first admin", public: true, operator: true, body: params.OrganizationRequest{}, status: http.StatusCreated, data: spec.data(params.OrganizationResponse{})})
+	spec.v1("GET", "/organizations/current", endpoint{tag: "organizations", summary: "The organization of the token", data: spec.data(params.OrganizationResponse{})})
+	spec.v1("POST", "/organizations/invites", endpoint{tag: "organizations", summary: "Invite a user to register into the organization", admin: true, status: http.StatusCreated, data: spec.data(params.InviteResponse{})})
+
+	spec.v1("POST", "/auth/register", endpoint{tag: "auth", summary: "Register a user", public: true, body: params.UserRequest{}, status: http.StatusCreated})
+	spec.v1("POST", "/auth/login", endpoint{tag: "auth", summary: "Log in for a bearer token", public: true, body: params.UserRequest{}, data: spec.data(params.UserResponse{})})
+
+	spec.v1("GET", "/authors/", endpoint{
+		tag:     "authors",
+		summary: "List authors: all of them, the ones matching q, or a page when limit, sort or cursor is given",
+		query:   []interface{}{params.PaginationRequest{}},
+		params:  []*openapi.Parameter{{Name: "q", In: "query", Description: "search by name", Schema: &openapi.Schema{Type: "string"}}},
+		data:    &openapi.Schema{AnyOf: []*openapi.Schema{spec.data([]*params.AuthorResponse{}), spec.page(params.AuthorResponse{})}},
+	})
+	spec.v1("POST", "/authors/", endpoint{tag: "authors", summary: "Create an author", idempotent: true, body: params.AuthorRequest{}, status: http.StatusCreated})
+	spec.v1("GET", "/authors/duplicates", endpoint{
+		tag:     "authors",
+		summary: "Find likely duplicate authors",
+		params:  []*openapi.Parameter{{Name: "min_score", In: "query", Description: "0.75 when left out", Schema: &openapi.Schema{Type: "number"}}},
+		data:    spec.data([]*params.AuthorDuplicateResponse{}),
+	})
+	spec.v1("GET", "/authors/:id", endpoint{tag: "authors", summary: "Get an author", redirect: true, data: spec.data(params.AuthorResponse{})})
+	spec.v1("PUT", "/authors/:id", endpoint{tag: "authors", summary: "Replace an author", body: params.AuthorRequest{}, data: spec.data(params.AuthorResponse{})})
+	spec.v1("PATCH", "/authors/:id", endpoint{tag: "authors", summary: "Patch an author with a JSON Patch or JSON Merge Patch", rawBody: patchTypes, data: spec.data(params.AuthorResponse{})})
+	spec.v1("DELETE", "/authors/:id", endpoint{tag: "authors", summary: "Delete an author"})
+	spec.v1("POST", "/authors/:id/merge", endpoint{tag: "authors", summary: "Merge a duplicate into the author", idempotent: true, body: params.AuthorMergeRequest{}, data: spec.data(params.AuthorResponse{})})
+	spec.v1("GET", "/authors/:id/books", endpoint{tag: "authors", summary: "List the books of an author", query: []interface{}{params.PaginationRequest{}}, data: spec.page(params.BookResponse{})})
+	spec.v1("GET", "/authors/:id/stats", endpoint{tag: "authors", summary: "Statistics of an author", data: spec.data(params.AuthorStatsResponse{})})
+
+	spec.v1("GET", "/events/stream", endpoint{
+		tag: "events", summary: "Server-Sent Events of the changes to books and authors",
+		query:  []interface{}{params.EventStreamRequest{}},
+		params: []*openapi.Parameter{{Name: "Last-Event-ID", In: "header", Description: "replays the events after this one", Schema: &openapi.Schema{Type: "integer"}}},
+		files:  []string{"text/event-stream"},
+	})
+	spec.v1("POST", "/batch", endpoint{tag: "batch", summary: "Run book and author operations in one transaction", idempotent: true, body: params.BatchRequest{}, data: spec.data(params.BatchResponse{})})
+	spec.v1("POST", "/graphql", endpoint{tag: "graphql", summary: "Run a GraphQL query or mutation", idempotent: true, body: graphql.Request{}, plain: true, data: spec.data(graphql.Result{})})
+
+	spec.v1("GET", "/books/", endpoint{
+		tag:     "books",
+		summary: "List books: all of them, the ones in a branch, or a page when limit, sort or cursor is given",
+		query:   []interface{}{params.FieldsetRequest{}, params.PaginationRequest{}},
+		params:  []*openapi.Parameter{{Name: "branch_id", In: "query", Schema: &openapi.Schema{Type: "integer"}}},
+		data:    &openapi.Schema{AnyOf: []*openapi.Schema{spec.data([]*params.BookResponse{}), spec.page(params.BookResponse{})}},
+	})
+	spec.v1("POST", "/books/", endpoint{tag: "books", summary: "Create a book", idempotent: true, body: params.BookRequest{}, status: http.StatusCreated})
+	spec.v1("POST", "/books/import", endpoint{tag: "books", summary: "Queue an import of books by ISBN", idempotent: true, body: params.BookImportRequest{}, status: http.StatusCreated, data: spec.data(params.JobResponse{})})
+	spec.v1("POST", "/books/labels", endpoint{tag: "labels", summary: "Print spine labels", idempotent: true, body: params.SpineLabelRequest{}, files: []string{"application/pdf"}})
+	spec.v1("GET", "/books/:id", endpoint{tag: "books", summary: "Get a book", query: []interface{}{params.FieldsetRequest{}}, data: spec.data(params.BookResponse{})})
+	spec.v1("PUT", "/books/:id", endpoint{tag: "books", summary: "Replace a book", body: params.BookRequest{}, data: spec.data(params.BookResponse{})})
+	spec.v1("PATCH", "/books/:id", endpoint{tag: "books", summary: "Patch a book with a JSON Patch or JSON Merge Patch", rawBody: patchTypes, data: spec.data(params.BookResponse{})})
+	spec.v1("DELETE", "/books/:id", endpoint{tag: "books", summary: "Delete a book"})
+	spec.v1("GET", "/books/:id/barcode", endpoint{tag: "labels", summary: "Barcode of a book", query: []interface{}{params.BarcodeRequest{}}, files: imageTypes})
+	spec.v1("GET", "/books/:id/qrcode", endpoint{tag: "labels", summary: "QR code linking to a book", query: []interface{}{params.BarcodeRequest{}}, files: imageTypes})
+	spec.v1("GET", "/books/:id/copies", endpoint{tag: "copies", summary: "List the copies of a book", data: spec.data([]*params.BookCopyResponse{})})
+	spec.v1("POST", "/books/:id/copies", endpoint{tag: "copies", summary: "Add a copy of a book", idempotent: true, body: params.BookCopyRequest{}, status: http.StatusCreated, data: spec.data(params.BookCopyResponse{})})
+	spec.v1("PUT", "/books/:id/copies/:copy_id", endpoint{tag: "copies", summary: "Replace a copy of a book", body: params.BookCopyRequest{}, data: spec.data(params.BookCopyResponse{})})
+	spec.v1("POST", "/books/:id/ratings", endpoint{tag: "books", summary: "Rate a book", idempotent: true, body: params.BookRatingRequest{}, data: spec.data(params.BookRatingResponse{})})
+
+	spec.v1("POST", "/loans/", endpoint{tag: "loans", summary: "Lend a copy", idempotent: true, body: params.LoanRequest{}, status: http.StatusCreated, data: spec.data(params.LoanResponse{})})
+	spec.v1("GET", "/loans/:id", endpoint{tag: "loans", summary: "Get a loan", data: spec.data(params.LoanResponse{})})
+	spec.v1("POST", "/loans/:id/return", endpoint{tag: "loans", summary: "Return a lent copy", idempotent: true, data: spec.data(params.LoanResponse{})})
+
+	spec.v1("GET", "/branches/", endpoint{tag: "branches", summary: "List branches", data: spec.data([]*params.BranchResponse{})})
+	spec.v1("POST", "/branches/", endpoint{tag: "branches", summary: "Create a branch", idempotent: true, body: params.BranchRequest{}, status: http.StatusCreated})
+	spec.v1("GET", "/branches/:id", endpoint{tag: "branches", summary: "Get a branch", data: spec.data(params.BranchResponse{})})
+	spec.v1("PUT", "/branches/:id", endpoint{tag: "branches", summary: "Replace a branch", body: params.BranchRequest{}, data: spec.data(params.BranchResponse{})})
+	spec.v1("DELETE", "/branches/:id", endpoint{tag: "branches", summary: "Delete a branch"})
+	spec.v1("POST", "/branches/:id/locations", endpoint{tag: "branches", summary: "Add a shelf location to a branch", idempotent: true, body: params.ShelfLocationRequest{}, status: http.StatusCreated, data: spec.data(params.ShelfLocationResponse{})})
+	spec.v1("GET", "/branches/:id/transfers", endpoint{tag: "transfers", summary: "Transfers in and out of a branch", data: spec.data(params.BranchTransfersResponse{})})
+
+	spec.v1("POST", "/transfers/", endpoint{tag: "transfers", summary: "Request a transfer of a copy", idempotent: true, body: params.TransferRequest{}, status: http.StatusCreated, data: spec.data(params.TransferResponse{})})
+	spec.v1("GET", "/transfers/:id", endpoint{tag: "transfers", summary: "Get a transfer", data: spec.data(params.TransferResponse{})})
+	spec.v1("POST", "/transfers/:id/ship", endpoint{tag: "transfers", summary: "Ship a transfer", idempotent: true, data: spec.data(params.TransferResponse{})})
+	spec.v1("POST", "/transfers/:id/receive", endpoint{tag: "transfers", summary: "Receive a transfer", idempotent: true, body: params.TransferReceiveRequest{}, data: spec.data(params.TransferResponse{})})
+	spec.v1("POST", "/transfers/:id/cancel", endpoint{tag: "transfers", summary: "Cancel a transfer", idempotent: true, data: spec.data(params.TransferResponse{})})
+
+	spec.v1("POST", "/stocktakes/", endpoint{tag: "stocktakes", summary: "Open a stocktake", idempotent: true, body: params.StocktakeRequest{}, status: http.StatusCreated, data: spec.data(params.StocktakeResponse{})})
+	spec.v1("GET", "/stocktakes/:id", endpoint{tag: "stocktakes", summary: "Get a stocktake", data: spec.data(params.StocktakeResponse{})})
+	spec.v1("POST", "/stocktakes/:id/scans", endpoint{tag: "stocktakes", summary: "Scan copies into a stocktake", idempotent: true, body: params.StocktakeScanRequest{}, status: http.StatusCreated, data: spec.data(params.StocktakeScanResponse{})})
+	spec.v1("POST", "/stocktakes/:id/close", endpoint{tag: "stocktakes", summary: "Close a stocktake", idempotent: true, data: spec.data(params.StocktakeReportResponse{})})
+	spec.v1("GET", "/stocktakes/:id/report", endpoint{tag: "stocktakes", summary: "Report of a stocktake", data: spec.data(params.StocktakeReportResponse{})})
+	spec.v1("POST", "/stocktakes/:id/mark-lost", endpoint{tag: "stocktakes", summary: "Mark missing copies lost, all of them without a body", idempotent: true, body: params.StocktakeMarkLostRequest{}, optionalBody: true, data: spec.data(params.StocktakeReportResponse{})})
+
+	spec.v1("GET", "/vendors/", endpoint{tag: "acquisitions", summary: "List vendors", data: spec.data([]*params.VendorResponse{})})
+	spec.v1("POST", "/vendors/", endpoint{tag: "acquisitions", summary: "Create a vendor", idempotent: true, body: params.VendorRequest{}, status: http.StatusCreated, data: spec.data(params.VendorResponse{})})
+	spec.v1("GET", "/funds/", endpoint{tag: "acquisitions", summary: "List funds", data: spec.data([]*params.FundResponse{})})
+	spec.v1("POST", "/funds/", endpoint{tag: "acquisitions", summary: "Create a fund", idempotent: true, body: params.FundRequest{}, status: http.StatusCreated, data: spec.data(params.FundResponse{})})
+	spec.v1("GET", "/funds/:id", endpoint{tag: "acquisitions", summary: "Get a fund", data: spec.data(params.FundResponse{})})
+	spec.v1("GET", "/purchase-orders/", endpoint{
+		tag:     "acquisitions",
+		summary: "List purchase orders",
+		params:  []*openapi.Parameter{{Name: "status", In: "query", Schema: &openapi.Schema{Type: "string"}}},
+		data:    spec.data([]*params.PurchaseOrderResponse{}),
+	})
+	spec.v1("POST", "/purchase-orders/", endpoint{tag: "acquisitions", summary: "Create a purchase order", idempotent: true, body: params.PurchaseOrderRequest{}, status: http.StatusCreated, data: spec.data(params.PurchaseOrderResponse{})})
+	spec.v1("GET", "/purchase-orders/:id", endpoint{tag: "acquisitions", summary: "Get a purchase order", data: spec.data(params.PurchaseOrderResponse{})})
+	spec.v1("POST", "/purchase-orders/:id/submit", endpoint{tag: "acquisitions", summary: "Submit a purchase order", idempotent: true, data: spec.data(params.PurchaseOrderResponse{})})
+	spec.v1("POST", "/purchase-orders/:id/cancel", endpoint{tag: "acquisitions", summary: "Cancel a purchase order", idempotent: true, data: spec.data(params.PurchaseOrderResponse{})})
+	spec.v1("POST", "/purchase-orders/:id/receive", endpoint{tag: "acquisitions", summary: "Receive the lines of a purchase order", idempotent: true, body: params.PurchaseOrderReceiveRequest{}, data: spec.data(params.PurchaseOrderReceiveResponse{})})
+
+	reports := []struct {
+		path, summary string
+		data          interface{}
+	}{
+		{"/reports/books-per-author", "Number of books per author", []*params.BooksPerAuthorReport{}},
+		{"/reports/books-without-isbn", "Books without an ISBN", []*params.BookWithoutISBNReport{}},
+		{"/reports/authors-without-books", "Authors without books", []*params.AuthorWithoutBooksReport{}},
+		{"/reports/most-borrowed", "Most borrowed books", []*params.BorrowedBookReport{}},
+		{"/reports/least-borrowed", "Least borrowed books", []*params.BorrowedBookReport{}},
+		{"/reports/acquisitions", "Acquisitions per month", []*params.AcquisitionReport{}},
+	}
+	for _, report := range reports {
+		spec.v1("GET", report.path, endpoint{tag: "reports", summary: report.summary, query: []interface{}{params.ReportRequest{}}, data: spec.data(report.data), csv: true})
+	}
+
+	spec.add("GET", "/v1/webhooks/", endpoint{tag: "webhooks", summary: "List webhook subscriptions", admin: true, data: spec.data([]*params.WebhookSubscriptionResponse{})})
+	spec.add("POST", "/v1/webhooks/", endpoint{tag: "webhooks", summary: "Subscribe to events, showing the signing secret once", admin: true, idempotent: true, body: params.WebhookSubscriptionRequest{}, status: http.StatusCreated, data: spec.data(params.WebhookSubscriptionResponse{})})
+	spec.add("GET", "/v1/webhooks/:id", endpoint{tag: "webhooks", summary: "Get a webhook subscription", admin: true, data: spec.data(params.WebhookSubscriptionResponse{})})
+	spec.add("PUT", "/v1/webhooks/:id", endpoint{tag: "webhooks", summary: "Replace a webhook subscription, keeping its secret unless given", admin: true, body: params.WebhookSubscriptionRequest{}, data: spec.data(params.WebhookSubscriptionResponse{})})
+	spec.add("DELETE", "/v1/webhooks/:id", endpoint{tag: "webhooks", summary: "Delete a webhook subscription and its deliveries", admin: true})
+	spec.add("GET", "/v1/webhooks/:id/deliveries", endpoint{tag: "webhooks", summary: "Delivery log of a webhook subscription, newest first", admin: true, query: []interface{}{params.WebhookDeliveryRequest{}}, data: spec.data([]*params.WebhookDeliveryResponse{})})
+	spec.add("POST", "/v1/webhooks/:id/deliveries/:delivery_id/redeliver", endpoint{tag: "webhooks", summary: "Send the event of a delivery again", admin: true, idempotent: true, status: http.StatusCreated, data: spec.data(params.WebhookDeliveryResponse{})})
+	spec.add("GET", "/v1/jobs/", endpoint{tag: "jobs", summary: "List background jobs, newest first", admin: true, query: []interface{}{params.JobListRequest{}}, data: spec.data([]*params.JobResponse{})})
+	spec.add("GET", "/v1/jobs/:id", endpoint{tag: "jobs", summary: "Get a background job", admin: true, data: spec.data(params.JobResponse{})})
+	spec.add("POST", "/v1/jobs/:id/retry", endpoint{tag: "jobs", summary: "Queue a failed or canceled job again with all of its attempts", admin: true, idempotent: true, data: spec.data(params.JobResponse{})})
+	spec.add("POST", "/v1/jobs/:id/cancel", endpoint{tag: "jobs", summary: "Cancel a pending or running job", admin: true, idempotent: true, data: spec.data(params.JobResponse{})})
+
+	spec.add("GET", "/v2/authors/", endpoint{
+		tag:     "authors",
+		summary: "List a page of authors, or every author matching q",
+		v2:      true,
+		query:   []interface{}{params.PaginationRequest{}},
+		params:  []*openapi.Parameter{{Name: "q", In: "query", Description: "search by name", Schema: &openapi.Schema{Type: "string"}}},
+		data:    spec.list(params.AuthorV2Response{}),
+	})
+	spec.add("POST", "/v2/authors/", endpoint{tag: "authors", summary: "Create an author", v2: true, idempotent: true, body: params.AuthorRequest{}, status: http.StatusCreated, data: spec.data(params.AuthorV2Response{})})
+	spec.add("GET", "/v2/authors/:id", endpoint{tag: "authors", summary: "Get an author", v2: true, redirect: true, data: spec.data(params.AuthorV2Response{})})
+	spec.add("PUT", "/v2/authors/:id", endpoint{tag: "authors", summary: "Replace an author", v2: true, body: params.AuthorRequest{}, data: spec.data(params.AuthorV2Response{})})
+	spec.add("PATCH", "/v2/authors/:id", endpoint{tag: "authors", summary: "Patch an author with a JSON Patch or JSON Merge Patch", v2: true, rawBody: patchTypes, data: spec.data(params.AuthorV2Response{})})
+	spec.add("DELETE", "/v2/authors/:id", endpoint{tag: "authors", summary: "Delete an author", v2: true, status: http.StatusNoContent})
+	spec.add("GET", "/v2/authors/:id/books", endpoint{tag: "authors", summary: "List the books of an author", v2: true, query: []interface{}{params.PaginationRequest{}}, data: spec.list(params.BookV2Response{})})
+
+	spec.add("GET", "/v2/books/", endpoint{tag: "books", summary: "List a page of books", v2: true, query: []interface{}{params.PaginationRequest{}}, data: spec.list(params.BookV2Response{})})
+	spec.add("POST", "/v2/books/", endpoint{tag: "books", summary: "Create a book", v2: true, idempotent: true, body: params.BookRequest{}, status: http.StatusCreated, data: spec.data(params.BookV2Response{})})
+	spec.add("GET", "/v2/books/:id", endpoint{tag: "books", summary: "Get a book", v2: true, data: spec.data(params.BookV2Response{})})
+	spec.add("PUT", "/v2/books/:id", endpoint{tag: "books", summary: "Replace a book", v2: true, body: params.BookRequest{}, data: spec.data(params.BookV2Response{})})
+	spec.add("PATCH", "/v2/books/:id", endpoint{tag: "books", summary: "Patch a book with a JSON Patch or JSON Merge Patch", v2: true, rawBody: patchTypes, data: spec.data(params.BookV2Response{})})
+	spec.add("DELETE", "/v2/books/:id", endpoint{tag: "books", summary: "Delete a book", v2: true, status: http.StatusNoContent})
+
+	return spec.doc
+}
+
+var (
+	patchTypes = []string{"application/json-patch+json", "application/merge-patch+json"}
+	imageTypes = []string{"image/png", "image/svg+xml"}
+)
+
+func (spec *specBuilder) data(value interface{}) *openapi.Schema {
+	return spec.doc.SchemaOf(value, openapi.ResponseMode)
+}
+
+// page is a PaginationResponse holding items of the type of item.
+func (spec *specBuilder) page(item interface{}) *openapi.Schema {
+	return &openapi.Schema{AllOf: []*openapi.Schema{
+		spec.data(params.PaginationResponse{}),
+		{Type: "object", Properties: map[string]*openapi.Schema{
+			"items": {Type: "array", Items: spec.data(item)},
+		}},
+	}}
+}
+
+// list is a ListV2Response holding items of the type of item.
+func (spec *specBuilder) list(item interface{}) *openapi.Schema {
+	return &openapi.Schema{AllOf: []*openapi.Schema{
+		spec.data(params.ListV2Response{}),
+		{Type: "object", Properties: map[string]*openapi.Schema{
+			"items": {Type: "array", Items: spec.data(item)},
+		}},
+	}}
+}
+
+// v1 adds a route under /v1 along with its deprecated unversioned alias.
+func (spec *specBuilder) v1(method, path string, e endpoint) {
+	spec.add(method, "/v1"+path, e)
+	e.deprecated = true
+	spec.add(method, path, e)
+}
+
+func (spec *specBuilder) add(method, path string, e endpoint) {
+	op := &openapi.Operation{
+		OperationID: operationID(method, path),
+		Summary:     e.summary,
+		Tags:        []string{e.tag},
+		Deprecated:  e.deprecated,
+		Responses:   map[string]*openapi.Response{},
+	}
+	for _, segment := range strings.Split(path, "/") {
+		if strings.HasPrefix(segment, ":") {
+			op.Parameters = append(op.Parameters, &openapi.Parameter{
+				Name:     segment[1:],
+				In:       "path",
+				Required: true,
+				Schema:   &openapi.Schema{Type: "integer"},
+			})
+		}
+	}
+	for _, query := range e.query {
+		op.Parameters = append(op.Parameters, spec.doc.Parameters(query)...)
+	}
+	op.Parameters = append(op.Parameters, e.params...)
+	if e.operator {
+		op.Parameters = append(op.Parameters, &openapi.Parameter{
+			Name:        OperatorKeyHeader,
+			In:          "header",
+			Required:    true,
+			Description: "the key of the operators of the API",
+			Schema:      &openapi.Schema{Type: "string"},
+		})
+	}
+	if e.idempotent {
+		op.Parameters = append(op.Parameters, &openapi.Parameter{
+			Name:        "Idempotency-Key",
+			In:          "header",
+			Description: "runs the request once, replaying its response to retries with the same key",
+			Schema:      &openapi.Schema{Type: "string"},
+		})
+	}
+
+	if e.body != nil {
+		op.RequestBody = &openapi.RequestBody{
+			Required: !e.optionalBody,
+			Content: map[string]*openapi.MediaType{
+				"application/json": {Schema: spec.doc.SchemaOf(e.body, openapi.RequestMode)},
+			},
+		}
+	}
+	if len(e.rawBody) > 0 {
+		op.RequestBody = &openapi.RequestBody{Required: true, Content: map[string]*openapi.MediaType{}}
+		for _, contentType := range e.rawBody {
+			op.RequestBody.Content[contentType] = &openapi.MediaType{}
+		}
+	}
+
+	status := e.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	success := &openapi.Response{Description: http.StatusText(status), Content: map[string]*openapi.MediaType{}}
+	switch {
+	case len(e.files) > 0:
+		for _, contentType := range e.files {
+			success.Content[contentType] = &openapi.MediaType{}
+		}
+	case status == http.StatusNoContent:
+		success.Content = nil
+	case e.plain || e.v2:
+		success.Content["application/json"] = &openapi.MediaType{Schema: e.data}
+	default:
+		success.Content["application/json"] = &openapi.MediaType{Schema: envelope(e.data)}
+	}
+	if e.csv {
+		success.Content["text/csv"] = &openapi.MediaType{Schema: &openapi.Schema{Type: "string"}}
+	}
+	op.Responses[strconv.Itoa(status)] = success
+
+	if e.redirect {
+		op.Responses["301"] = &openapi.Response{
+			Description: "The resource was merged into the one at Location",
+			Headers:     map[string]*openapi.Header{"Location": {Schema: &openapi.Schema{Type: "string"}}},
+		}
+	}
+	if e.deprecated {
+		success.Headers = map[string]*openapi.Header{
+			"Deprecation": {Description: "when the route was deprecated, as @<unix time>", Schema: &openapi.Schema{Type: "string"}},
+			"Sunset":      {Description: "when the route goes away", Schema: &openapi.Schema{Type: "string"}},
+			"Link":        {Description: "the same route under /v1", Schema: &openapi.Schema{Type: "string"}},
+		}
+	}
+	if e.v2 && status == http.StatusCreated {
+		success.Headers = map[string]*openapi.Header{"Location": {Schema: &openapi.Schema{Type: "string"}}}
+	}
+
+	customError := map[string]*openapi.MediaType{"application/json": {Schema: openapi.Ref("CustomError")}}
+	errors := customError
+	if e.v2 {
+		errors = map[string]*openapi.MediaType{"application/json": {Schema: openapi.Ref("ErrorV2Response")}}
+		if strings.Contains(path, "/:") {
+			op.Responses["404"] = &openapi.Response{Description: "Not Found", Content: errors}
+		}
+	}
+	if e.plain && e.body != nil {
+		// requests that fail before they run
+		op.Responses["400"] = &openapi.Response{Description: "Bad Request", Content: map[string]*openapi.MediaType{
+			"application/json": {Schema: e.data},
+		}}
+	}
+	if !e.public {
+		op.Security = []map[string][]string{{"bearerAuth": {}}}
+		op.Responses["401"] = &openapi.Response{Description: "Missing or invalid bearer token", Content: customError}
+	}
+	if e.admin {
+		op.Responses["403"] = &openapi.Response{Description: "The user is not an admin", Content: customError}
+	}
+	if e.operator {
+		op.Responses["403"] = &openapi.Response{Description: "Missing or wrong operator key", Content: customError}
+	}
+	op.Responses["default"] = &openapi.Response{Description: "Error", Content: errors}
+
+	// what Negotiate converts JSON to and from
+	for code, resp := range op.Responses {
+		content := resp.Content["application/json"]
+		if content == nil || resp.Content[negotiate.XML] != nil {
+			continue
+		}
+		resp.Content[negotiate.XML] = &openapi.MediaType{Schema: content.Schema}
+		resp.Content[negotiate.MsgPack] = &openapi.MediaType{Schema: content.Schema}
+		if code == strconv.Itoa(status) && isList(e.data) {
+			resp.Content[negotiate.CSV] = &openapi.MediaType{Schema: &openapi.Schema{Type: "string"}}
+		}
+	}
+	if op.RequestBody != nil {
+		if content := op.RequestBody.Content["application/json"]; content != nil {
+			op.RequestBody.Content[negotiate.XML] = &openapi.MediaType{Schema: content.Schema}
+			op.RequestBody.Content[negotiate.MsgPack] = &openapi.MediaType{Schema: content.Schema}
+		}
+	}
+
+	spec.doc.Add(method, path, op)
+}
+
+// isList tells whether data is a list or a page of one, which Negotiate can
+// write as CSV.
+func isList(data *openapi.Schema) bool {
+	if data == nil {
+		return false
+	}
+	if data.Type == "array" {
+		return true
+	}
+	for _, part := range append(append([]*openapi.Schema{}, data.AllOf...), data.AnyOf...) {
+		if part.Properties["items"] != nil || isList(part) {
+			return true
+		}
+	}
+	return false
+}
+
+// envelope is the Response envelope carrying data, or no data when nil.
+func envelope(data *openapi.Schema) *openapi.Schema {
+	if data == nil {
+		return openapi.Ref("Response")
+	}
+	return &openapi.Schema{AllOf: []*openapi.Schema{
+		openapi.Ref("Response"),
+		{Type: "object", Properties: map[string]*openapi.Schema{"data": data}, Required: []string{"data"}},
+	}}
+}
+
+// operationID names an operation after its method and path, such as
+// getBooksIdCopies.
+func operationID(method, path string) string {
+	id := strings.ToLower(method)
+	for _, segment := range strings.FieldsFunc(path, func(r rune) bool { return r == '/' || r == '-' || r == '.' || r == '_' }) {
+		segment = strings.TrimPrefix(segment, ":")
+		id += strings.ToUpper(segment[:1]) + segment[1:]
+	}
+	return id
+}
+
+// ServeOpenAPI writes the OpenAPI document.
+func ServeOpenAPI(spec *openapi.Document) gin.HandlerFunc {
+	body, err := json.Marshal(spec)
+	if err != nil {
+		panic(err)
+	}
+	return func(ctx *gin.Context) {
+		ctx.Data(http.StatusOK, "application/json; charset=utf-8", body)
+	}
+}
+
+// ServeSwaggerUI shows the document served at /openapi.json.
+func ServeSwaggerUI() gin.HandlerFunc {
+	page := openapi.SwaggerUI("golang-backend-test", "/openapi.json")
+	return func(ctx *gin.Context) {
+		ctx.Data(http.StatusOK, "text/html; charset=utf-8", page)
+	}
+}
+
+// ValidateRequests rejects requests whose parameters or JSON body do not
+// match the OpenAPI document with a 400. Routes the document does not
+// describe are let through.
+func ValidateRequests(spec *openapi.Document) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		op := spec.Find(ctx.Request.Method, ctx.FullPath())
+		if op == nil {
+			ctx.Next()
+			return
+		}
+
+		body, err := io.ReadAll(ctx.Request.Body)
+		if err != nil {
+			ctx.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+		ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		pathParams := map[string]string{}
+		for _, param := range ctx.Params {
+			pathParams[param.Key] = param.Value
+		}
+		problems := spec.ValidateRequest(op, pathParams, ctx.Request.URL.Query(), ctx.ContentType(), body)
+		if len(problems) > 0 {
+			resp := response.BadRequestErrorWithAdditionalInfo(problems)
+			ctx.AbortWithStatusJSON(resp.StatusCode, resp)
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// ValidateResponses checks every response against the OpenAPI document,
+// passing what does not match to report. It is meant for tests, as it holds
+// every response body in memory.
+func ValidateResponses(spec *openapi.Document, report func(method, path string, err error)) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		op := spec.Find(ctx.Request.Method, ctx.FullPath())
+		if op == nil {
+			ctx.Next()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: ctx.Writer}
+		ctx.Writer = recorder
+		ctx.Next()
+
+		if err := spec.ValidateResponse(op, recorder.Status(), recorder.Header().Get("Content-Type"), recorder.body.Bytes()); err != nil {
+			report(ctx.Request.Method, ctx.FullPath(), err)
+		}
+	}
+}
@@ -0,0 +1,449 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"golang-backend-test/database"
+	"golang-backend-test/factory"
+	"golang-backend-test/pkg/openapi"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+const testOperatorKey = "operator-key"
+
+// newTestRouter serves the routes on an empty database, checking every
+// response against the OpenAPI document.
+func newTestRouter(t *testing.T, validateRequests bool) *gin.Engine {
+	db, err := database.NewSQLiteMemoryConnection()
+	assert.Nil(t, err)
+	return newTestRouterWithDB(t, db, validateRequests)
+}
+
+// newTestRouterWithDB serves db, for tests that look at the queries a
+// request runs.
+func newTestRouterWithDB(t *testing.T, db *gorm.DB, validateRequests bool) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	provider, err := factory.InitFactory(db)
+	assert.Nil(t, err)
+	provider.ValidateRequests = validateRequests
+	provider.OperatorKey = testOperatorKey
+	// recorded events only reach the webhooks and the event stream through
+	// the relay
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go provider.OutboxServiceProvider.Run(ctx, 10*time.Millisecond)
+	go provider.JobServiceProvider.Run(ctx)
+
+	router := gin.New()
+	router.Use(ValidateResponses(NewOpenAPI(), func(method, path string, err error) {
+		t.Errorf("%s %s: %v", method, path, err)
+	}))
+	NewRoutes(router, provider)
+	return router
+}
+
+type testClient struct {
+	router *gin.Engine
+	token  string
+}
+
+func (client *testClient) do(method, url, contentType, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, url, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if client.token != "" {
+		req.Header.Set("Authorization", "Bearer "+client.token)
+	}
+	w := httptest.NewRecorder()
+	client.router.ServeHTTP(w, req)
+	return w
+}
+
+func (client *testClient) json(method, url, body string) *httptest.ResponseRecorder {
+	return client.do(method, url, "application/json", body)
+}
+
+// operator sends a request with the operator key.
+func (client *testClient) operator(method, url, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, url, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(OperatorKeyHeader, testOperatorKey)
+	w := httptest.NewRecorder()
+	client.router.ServeHTTP(w, req)
+	return w
+}
+
+// invite reads the token of the invite in an organization or invite
+// response.
+func invite(t *testing.T, w *httptest.ResponseRecorder) string {
+	var resp struct {
+		Data struct {
+			Token  string `json:"token"`
+			Invite struct {
+				Token string `json:"token"`
+			} `json:"invite"`
+		} `json:"data"`
+	}
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	if resp.Data.Invite.Token != "" {
+		return resp.Data.Invite.Token
+	}
+	return resp.Data.Token
+}
+
+func (client *testClient) login(t *testing.T) {
+	w := client.operator("POST", "/organizations/", `{"name":"City Library","slug":"city-library"}`)
+	assert.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	w = client.json("POST", "/auth/register", `{"organization":"city-library","username":"librarian","password":"secret-password","invite":"`+invite(t, w)+`"}`)
+	assert.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	w = client.json("POST", "/auth/login", `{"organization":"city-library","username":"librarian","password":"secret-password"}`)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp struct {
+		Data struct {
+			Token string `json:"token"`
+		} `json:"data"`
+	}
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	client.token = resp.Data.Token
+}
+
+func TestOpenAPICoversRoutes(t *testing.T) {
+	router := newTestRouter(t, false)
+	spec := NewOpenAPI()
+
+	described := map[string]bool{}
+	for _, route := range router.Routes() {
+		assert.NotNil(t, spec.Find(route.Method, route.Path), "%s %s is not in the OpenAPI document", route.Method, route.Path)
+		described[strings.ToLower(route.Method)+" "+openapi.Path(route.Path)] = true
+	}
+	for path, operations := range spec.Paths {
+		for method := range operations {
+			assert.True(t, described[method+" "+path], "%s %s is not a route", method, path)
+		}
+	}
+}
+
+func TestInvites(t *testing.T) {
+	client := &testClient{router: newTestRouter(t, true)}
+
+	assert.Equal(t, http.StatusForbidden, client.json("POST", "/organizations/", `{"name":"Acme","slug":"acme"}`).Code)
+	req := httptest.NewRequest("POST", "/organizations/", strings.NewReader(`{"name":"Acme","slug":"acme"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(OperatorKeyHeader, "guess")
+	w := httptest.NewRecorder()
+	client.router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	// joining an organization takes one of its invites
+	assert.Equal(t, http.StatusBadRequest, client.json("POST", "/auth/register", `{"organization":"default","username":"intruder","password":"secret-password"}`).Code)
+	client.login(t)
+
+	w = client.json("POST", "/organizations/invites", "")
+	assert.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	token := invite(t, w)
+	assert.Equal(t, http.StatusBadRequest, client.json("POST", "/auth/register", `{"organization":"default","username":"intruder","password":"secret-password","invite":"`+token+`"}`).Code)
+	assert.Equal(t, http.StatusCreated, client.json("POST", "/auth/register", `{"organization":"city-library","username":"cataloguer","password":"secret-password","invite":"`+token+`"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, client.json("POST", "/auth/register", `{"organization":"city-library","username":"intruder","password":"secret-password","invite":"`+token+`"}`).Code)
+
+	// only admins invite
+	w = client.json("POST", "/auth/login", `{"organization":"city-library","username":"cataloguer","password":"secret-password"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		Data struct {
+			Token string `json:"token"`
+		} `json:"data"`
+	}
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	cataloguer := &testClient{router: client.router, token: resp.Data.Token}
+	assert.Equal(t, http.StatusForbidden, cataloguer.json("POST", "/organizations/invites", "").Code)
+}
+
+func TestOpenAPIResponses(t *testing.T) {
+	client := &testClient{router: newTestRouter(t, false)}
+
+	assert.Equal(t, http.StatusOK, client.json("GET", "/openapi.json", "").Code)
+	assert.Equal(t, http.StatusOK, client.json("GET", "/docs", "").Code)
+	assert.Equal(t, http.StatusUnauthorized, client.json("GET", "/books/", "").Code)
+	client.login(t)
+
+	assert.Equal(t, http.StatusOK, client.json("GET", "/organizations/current", "").Code)
+	assert.Equal(t, http.StatusCreated, client.json("POST", "/authors/", `{"name":"Ursula K. Le Guin","birthdate":"1929-10-21","pseudonyms":["U. K. Le Guin"]}`).Code)
+	assert.Equal(t, http.StatusOK, client.json("GET", "/authors/", "").Code)
+	assert.Equal(t, http.StatusOK, client.json("GET", "/authors/?q=guin", "").Code)
+	assert.Equal(t, http.StatusOK, client.json("GET", "/authors/?limit=1", "").Code)
+	assert.Equal(t, http.StatusBadRequest, client.json("GET", "/authors/?page=2", "").Code)
+	assert.Equal(t, http.StatusOK, client.json("GET", "/authors/1", "").Code)
+	assert.Equal(t, http.StatusOK, client.json("PUT", "/authors/1", `{"name":"Ursula K. Le Guin","birthdate":"1929-10-21","death_date":"2018-01-22"}`).Code)
+	assert.Equal(t, http.StatusOK, client.do("PATCH", "/authors/1", "application/merge-patch+json", `{"nationality":"American"}`).Code)
+	assert.Equal(t, http.StatusOK, client.json("GET", "/authors/1/stats", "").Code)
+	assert.Equal(t, http.StatusOK, client.json("GET", "/authors/duplicates", "").Code)
+	assert.Equal(t, http.StatusBadRequest, client.json("GET", "/authors/99", "").Code)
+
+	assert.Equal(t, http.StatusCreated, client.json("POST", "/books/", `{"title":"A Wizard of Earthsea","isbn":"9780547773742","publication_date":"1968-11-01","author_id":1}`).Code)
+	assert.Equal(t, http.StatusBadRequest, client.json("POST", "/books/", `{"title":""}`).Code)
+	assert.Equal(t, http.StatusOK, client.json("GET", "/books/", "").Code)
+	assert.Equal(t, http.StatusOK, client.json("GET", "/books/?limit=10&sort=-title", "").Code)
+	assert.Equal(t, http.StatusOK, client.json("GET", "/books/?page=1", "").Code)
+	assert.Equal(t, http.StatusBadRequest, client.json("GET", "/books/?page=2", "").Code)
+	assert.Equal(t, http.StatusOK, client.json("GET", "/books/1", "").Code)
+	assert.Equal(t, http.StatusOK, client.json("GET", "/books/1?fields=title&expand=author,publisher", "").Code)
+	assert.Equal(t, http.StatusOK, client.json("GET", "/authors/1/books", "").Code)
+	assert.Equal(t, http.StatusOK, client.json("GET", "/books/1/barcode", "").Code)
+	// the QR code links to the configured base URL whatever the request host
+	qrcode := client.json("GET", "/books/1/qrcode", "")
+	assert.Equal(t, http.StatusOK, qrcode.Code)
+	req := httptest.NewRequest("GET", "/books/1/qrcode", nil)
+	req.Host = "evil.example.com"
+	req.Header.Set("Authorization", "Bearer "+client.token)
+	req.Header.Set("X-Forwarded-Proto", "javascript")
+	forged := httptest.NewRecorder()
+	client.router.ServeHTTP(forged, req)
+	assert.Equal(t, http.StatusOK, forged.Code)
+	assert.Equal(t, qrcode.Body.Bytes(), forged.Body.Bytes())
+
+	assert.Equal(t, http.StatusCreated, client.json("POST", "/branches/", `{"code":"MAIN","name":"Main Library"}`).Code)
+	assert.Equal(t, http.StatusOK, client.json("GET", "/branches/", "").Code)
+	assert.Equal(t, http.StatusCreated, client.json("POST", "/branches/1/locations", `{"name":"Fiction"}`).Code)
+	assert.Equal(t, http.StatusCreated, client.json("POST", "/books/1/copies", `{"barcode":"C0001","shelf_location_id":1}`).Code)
+	assert.Equal(t, http.StatusOK, client.json("GET", "/books/1/copies", "").Code)
+	branchBooks := client.json("GET", "/books/?branch_id=1&fields=title&expand=", "")
+	assert.Equal(t, http.StatusOK, branchBooks.Code)
+	assert.NotContains(t, branchBooks.Body.String(), "birthdate")
+	assert.Equal(t, http.StatusBadRequest, client.json("GET", "/books/?branch_id=1&fields=bogus", "").Code)
+	assert.Equal(t, http.StatusOK, client.json("GET", "/branches/1/transfers", "").Code)
+
+	assert.Equal(t, http.StatusCreated, client.json("POST", "/stocktakes/", `{"branch_id":1}`).Code)
+	assert.Equal(t, http.StatusCreated, client.json("POST", "/stocktakes/1/scans", `{"barcode":"C0001"}`).Code)
+	assert.Equal(t, http.StatusOK, client.json("POST", "/stocktakes/1/close", "").Code)
+
+	// loans and ratings are what the author stats count
+	assert.Equal(t, http.StatusCreated, client.json("POST", "/loans/", `{"book_copy_id":1}`).Code)
+	assert.Equal(t, http.StatusConflict, client.json("POST", "/loans/", `{"book_copy_id":1}`).Code)
+	assert.Equal(t, http.StatusOK, client.json("GET", "/loans/1", "").Code)
+	assert.Equal(t, http.StatusOK, client.json("POST", "/books/1/ratings", `{"score":2}`).Code)
+	assert.Equal(t, http.StatusOK, client.json("POST", "/books/1/ratings", `{"score":4}`).Code)
+	stats := client.json("GET", "/authors/1/stats", "")
+	assert.Contains(t, stats.Body.String(), `"total_loans":1`)
+	assert.Contains(t, stats.Body.String(), `"average_rating":4`)
+	assert.Contains(t, stats.Body.String(), `"rating_count":1`)
+	assert.Equal(t, http.StatusOK, client.json("POST", "/loans/1/return", "").Code)
+	assert.Equal(t, http.StatusBadRequest, client.json("POST", "/loans/1/return", "").Code)
+
+	assert.Equal(t, http.StatusCreated, client.json("POST", "/vendors/", `{"name":"Book Supply"}`).Code)
+	assert.Equal(t, http.StatusCreated, client.json("POST", "/funds/", `{"code":"GEN","name":"General","allocation":100000}`).Code)
+	assert.Equal(t, http.StatusCreated, client.json("POST", "/purchase-orders/", `{"vendor_id":1,"fund_id":1,"lines":[{"isbn":"9780547773742","title":"A Wizard of Earthsea","author_name":"Ursula K. Le Guin","quantity":2,"unit_price":1500}]}`).Code)
+	assert.Equal(t, http.StatusOK, client.json("GET", "/purchase-orders/", "").Code)
+
+	assert.Equal(t, http.StatusOK, client.json("POST", "/batch", `{"operations":[{"action":"create","resource":"author","body":{"name":"Octavia E. Butler","birthdate":"1947-06-22"}}]}`).Code)
+	assert.Equal(t, http.StatusOK, client.json("POST", "/graphql", `{"query":"{ me { username } books { items { title author { name } } } }"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, client.json("POST", "/graphql", `{"query":"{"}`).Code)
+
+	assert.Equal(t, http.StatusOK, client.json("GET", "/reports/books-per-author", "").Code)
+	assert.Equal(t, http.StatusCreated, client.json("POST", "/authors/", `{"name":"=HYPERLINK(\"http://example.com\")","birthdate":"1950-01-01"}`).Code)
+	assert.Equal(t, http.StatusCreated, client.json("POST", "/books/", `{"title":"Formula","author_id":3}`).Code)
+	w := client.json("GET", "/reports/books-per-author?format=csv", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	// text that a spreadsheet would run as a formula is quoted
+	assert.Contains(t, w.Body.String(), `"'=HYPERLINK(""http://example.com"")"`)
+
+	// the first user of the organization is its admin
+	assert.Equal(t, http.StatusOK, client.json("GET", "/v1/jobs/?status=failed", "").Code)
+	assert.Equal(t, http.StatusBadRequest, client.json("POST", "/v1/jobs/1/retry", "").Code)
+}
+
+func TestBookImport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metadata.json")
+	records := `[{"isbn":"978-0-06-093546-7","title":"To Kill a Mockingbird","authors":["Harper Lee"],"publisher":"Harper Perennial","page_count":336}]`
+	assert.Nil(t, os.WriteFile(path, []byte(records), 0o600))
+	t.Setenv("METADATA_PROVIDER", "file")
+	t.Setenv("METADATA_FILE", path)
+	client := &testClient{router: newTestRouter(t, true)}
+	client.login(t)
+
+	assert.Equal(t, http.StatusBadRequest, client.json("POST", "/books/import", `{"isbns":[]}`).Code)
+	w := client.json("POST", "/books/import", `{"isbns":["9780060935467","9780000000000"]}`)
+	assert.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), `"status":"pending"`)
+
+	// the workers add the book they find and fail the job for the other
+	var job *httptest.ResponseRecorder
+	assert.Eventually(t, func() bool {
+		job = client.json("GET", "/v1/jobs/1", "")
+		return strings.Contains(job.Body.String(), `"status":"failed"`)
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Contains(t, job.Body.String(), "no metadata found for isbn 9780000000000")
+	w = client.json("GET", "/books/", "")
+	assert.Contains(t, w.Body.String(), `"title":"To Kill a Mockingbird"`)
+	assert.Contains(t, w.Body.String(), `"name":"Harper Lee"`)
+}
+
+func TestValidateRequests(t *testing.T) {
+	client := &testClient{router: newTestRouter(t, true)}
+	client.login(t)
+
+	w := client.json("POST", "/authors/", `{"name":42,"birthdate":"1929-10-21","pseudonyms":[""]}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "name: must be string, not integer")
+	assert.Contains(t, w.Body.String(), "pseudonyms[0]: must be at least 1 characters long")
+
+	w = client.json("GET", "/books/?limit=500", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "query parameter limit: must be at most 100")
+
+	w = client.json("GET", "/books/first", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "path parameter id: must be integer, not string")
+
+	// Negotiate turns bodies of other types away before validation
+	w = client.do("PATCH", "/books/1", "text/plain", "title")
+	assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+	assert.Contains(t, w.Body.String(), "application/merge-patch+json")
+
+	w = client.do("POST", "/authors/", "application/xml", `<author><name>42</name><birthdate>1929-10-21</birthdate><pseudonyms><item></item></pseudonyms></author>`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "pseudonyms[0]: must be at least 1 characters long")
+
+	assert.Equal(t, http.StatusCreated, client.json("POST", "/authors/", `{"name":"Ursula K. Le Guin","birthdate":"1929-10-21"}`).Code)
+	assert.Equal(t, http.StatusOK, client.json("GET", "/authors/?limit=5", "").Code)
+}
+
+func TestVersionedRoutes(t *testing.T) {
+	client := &testClient{router: newTestRouter(t, false)}
+	client.login(t)
+
+	w := client.json("POST", "/v1/authors/", `{"name":"Ursula K. Le Guin","birthdate":"1929-10-21"}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Empty(t, w.Header().Get("Deprecation"))
+
+	w = client.json("GET", "/authors/1", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "@"+strconv.FormatInt(unversionedDeprecation.Unix(), 10), w.Header().Get("Deprecation"))
+	assert.Equal(t, "Fri, 30 Apr 2027 00:00:00 GMT", w.Header().Get("Sunset"))
+	assert.Equal(t, `</v1/authors/1>; rel="successor-version"`, w.Header().Get("Link"))
+
+	w = client.json("POST", "/v2/books/", `{"title":"A Wizard of Earthsea","isbn":"9780547773742","publication_date":"1968-11-01","author_id":1}`)
+	assert.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.Equal(t, "/v2/books/1", w.Header().Get("Location"))
+	assert.Contains(t, w.Body.String(), `"title":"A Wizard of Earthsea"`)
+	assert.Contains(t, w.Body.String(), `"author":{"id":1,"name":"Ursula K. Le Guin"}`)
+
+	assert.Equal(t, http.StatusOK, client.json("GET", "/v2/books/1", "").Code)
+	assert.Equal(t, http.StatusOK, client.json("GET", "/v2/books/?limit=1", "").Code)
+	assert.Equal(t, http.StatusOK, client.json("PUT", "/v2/books/1", `{"title":"A Wizard of Earthsea","isbn":"9780547773742","author_id":1}`).Code)
+	assert.Equal(t, http.StatusOK, client.do("PATCH", "/v2/books/1", "application/merge-patch+json", `{"page_count":183}`).Code)
+	assert.Equal(t, http.StatusOK, client.json("GET", "/v2/authors/", "").Code)
+	assert.Equal(t, http.StatusOK, client.json("GET", "/v2/authors/?q=guin", "").Code)
+	assert.Equal(t, http.StatusOK, client.json("GET", "/v2/authors/1/books", "").Code)
+
+	w = client.json("GET", "/v2/books/99", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"ERR0003"`)
+	assert.Equal(t, http.StatusNotFound, client.json("PUT", "/v2/books/99", `{"title":"Missing","author_id":1}`).Code)
+
+	assert.Equal(t, http.StatusNoContent, client.json("DELETE", "/v2/books/1", "").Code)
+	assert.Equal(t, http.StatusNotFound, client.json("DELETE", "/v2/books/1", "").Code)
+	assert.Equal(t, http.StatusNotFound, client.json("DELETE", "/v2/books/99", "").Code)
+	// v1 keeps answering a missing resource with a 400
+	assert.Equal(t, http.StatusBadRequest, client.json("GET", "/v1/books/1", "").Code)
+}
+
+func TestMergedAuthorChanges(t *testing.T) {
+	client := &testClient{router: newTestRouter(t, false)}
+	client.login(t)
+
+	assert.Equal(t, http.StatusCreated, client.json("POST", "/v1/authors/", `{"name":"J.R.R. Tolkien","birthdate":"1892-01-03"}`).Code)
+	assert.Equal(t, http.StatusCreated, client.json("POST", "/v1/authors/", `{"name":"Tolkien, J. R. R.","birthdate":"1892-01-03"}`).Code)
+	assert.Equal(t, http.StatusOK, client.json("POST", "/v1/authors/1/merge", `{"duplicate_id":2}`).Code)
+
+	// changes to the merged author go to the one it was merged into
+	w := client.json("PUT", "/v1/authors/2", `{"name":"J. R. R. Tolkien","birthdate":"1892-01-03"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"id":1`)
+	w = client.do("PATCH", "/v2/authors/2", "application/merge-patch+json", `{"nationality":"British"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"id":1`)
+	assert.Contains(t, w.Body.String(), `"name":"J. R. R. Tolkien"`)
+	w = client.json("GET", "/v1/authors/2/stats", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"author_id":1`)
+	assert.Equal(t, http.StatusOK, client.json("GET", "/v1/authors/2/books", "").Code)
+
+	// deleting it only drops the redirect
+	assert.Equal(t, http.StatusOK, client.json("DELETE", "/v1/authors/2", "").Code)
+	assert.Equal(t, http.StatusBadRequest, client.json("GET", "/v1/authors/2", "").Code)
+	assert.Equal(t, http.StatusOK, client.json("GET", "/v1/authors/1", "").Code)
+
+	// a deleted author cannot be brought back by a PUT
+	assert.Equal(t, http.StatusCreated, client.json("POST", "/v1/authors/", `{"name":"Ursula K. Le Guin","birthdate":"1929-10-21"}`).Code)
+	assert.Equal(t, http.StatusOK, client.json("DELETE", "/v1/authors/3", "").Code)
+	assert.Equal(t, http.StatusBadRequest, client.json("PUT", "/v1/authors/3", `{"name":"Ursula K. Le Guin","birthdate":"1929-10-21"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, client.json("GET", "/v1/authors/3", "").Code)
+	assert.Equal(t, http.StatusBadRequest, client.json("DELETE", "/v1/authors/3", "").Code)
+}
+
+func TestNegotiate(t *testing.T) {
+	client := &testClient{router: newTestRouter(t, false)}
+	client.login(t)
+
+	accept := func(method, url, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, url, nil)
+		req.Header.Set("Authorization", "Bearer "+client.token)
+		req.Header.Set("Accept", accept)
+		w := httptest.NewRecorder()
+		client.router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := client.do("POST", "/v1/authors/", "application/xml", `<author><name>Ursula K. Le Guin</name><birthdate>1929-10-21</birthdate><pseudonyms><item>U. K. Le Guin</item></pseudonyms></author>`)
+	assert.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	w = client.do("POST", "/v1/books/", "application/msgpack", "\x84\xa5title\xb4A Wizard of Earthsea\xa4isbn\xad9780547773742\xb0publication_date\xaa1968-11-01\xa9author_id\x01")
+	assert.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	w = client.do("POST", "/v1/books/", "text/plain", "A Wizard of Earthsea")
+	assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+
+	w = accept("GET", "/v1/authors/1", "application/xml")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/xml; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), "<name>Ursula K. Le Guin</name>")
+	assert.Contains(t, w.Body.String(), "<pseudonyms><item>U. K. Le Guin</item></pseudonyms>")
+
+	w = accept("GET", "/v2/books/1", "application/x-msgpack")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/msgpack", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), "\xa5title\xb4A Wizard of Earthsea")
+
+	w = accept("GET", "/v1/books/?limit=10", "text/csv, application/json;q=0.5")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.True(t, strings.HasPrefix(w.Body.String(), "id,title,isbn,"), w.Body.String())
+	assert.Contains(t, w.Body.String(), "\n1,A Wizard of Earthsea,9780547773742,")
+
+	assert.Equal(t, http.StatusCreated, client.json("POST", "/v1/authors/", `{"name":"@SUM(A1:A9)","birthdate":"1950-01-01"}`).Code)
+	w = accept("GET", "/v2/authors/", "text/csv")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "1,Ursula K. Le Guin,1929-10-21,")
+	assert.Contains(t, w.Body.String(), "2,'@SUM(A1:A9),1950-01-01,")
+
+	assert.Equal(t, http.StatusNotAcceptable, accept("GET", "/v1/books/1", "text/csv").Code)
+	assert.Equal(t, http.StatusNotAcceptable, accept("GET", "/v1/books/", "text/html").Code)
+	assert.Equal(t, http.StatusOK, accept("GET", "/v1/books/1/barcode", "image/png").Code)
+	assert.Equal(t, "application/json; charset=utf-8", accept("GET", "/v1/books/1", "*/*").Header().Get("Content-Type"))
+
+	// errors are converted too
+	w = accept("GET", "/v2/books/99", "application/xml")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "<code>ERR0003</code>")
+}
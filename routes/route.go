@@ -1,8 +1,11 @@
 package routes
 
 import (
+	"crypto/subtle"
 	"golang-backend-test/app/commons/response"
+	"golang-backend-test/app/services"
 	"golang-backend-test/factory"
+	"golang-backend-test/pkg/tenant"
 	"golang-backend-test/pkg/token"
 	"strings"
 
@@ -10,28 +13,162 @@ import (
 )
 
 func NewRoutes(router *gin.Engine, provider *factory.Provider) {
+	// handlers pass the gin context on as their context.Context, and the
+	// tenant set by CheckAuth lives in the request context behind it
+	router.ContextWithFallback = true
+	idempotent := Idempotency(provider.IdempotencyProvider)
+
+	spec := NewOpenAPI()
+	router.Use(Negotiate(spec))
+	if provider.ValidateRequests {
+		router.Use(ValidateRequests(spec))
+	}
+	router.GET("/openapi.json", ServeOpenAPI(spec))
+	router.GET("/docs", ServeSwaggerUI())
+
+	v1Routes(router.Group("/v1"), provider, idempotent)
+	// the routes from before versioning keep answering until their sunset
+	v1Routes(router.Group("", Deprecated("/v1", unversionedDeprecation, unversionedSunset)), provider, idempotent)
+	v2Routes(router.Group("/v2"), provider, idempotent)
+
+	// the admin routes only exist under /v1
+	webhooks := router.Group("/v1/webhooks", CheckAuth(), RequireAdmin(provider.UserServiceProvider), idempotent)
+	{
+		webhooks.GET("/", provider.WebhookProvider.GetListSubscriptions)
+		webhooks.POST("/", provider.WebhookProvider.CreateSubscription)
+		webhooks.GET("/:id", provider.WebhookProvider.FindSubscriptionById)
+		webhooks.PUT("/:id", provider.WebhookProvider.UpdateSubscription)
+		webhooks.DELETE("/:id", provider.WebhookProvider.DeleteSubscription)
+		webhooks.GET("/:id/deliveries", provider.WebhookProvider.GetListDeliveries)
+		webhooks.POST("/:id/deliveries/:delivery_id/redeliver", provider.WebhookProvider.Redeliver)
+	}
+	jobs := router.Group("/v1/jobs", CheckAuth(), RequireAdmin(provider.UserServiceProvider), idempotent)
+	{
+		jobs.GET("/", provider.JobProvider.GetListJobs)
+		jobs.GET("/:id", provider.JobProvider.FindJobById)
+		jobs.POST("/:id/retry", provider.JobProvider.RetryJob)
+		jobs.POST("/:id/cancel", provider.JobProvider.CancelJob)
+	}
+}
+
+func v1Routes(router *gin.RouterGroup, provider *factory.Provider, idempotent gin.HandlerFunc) {
+	organizations := router.Group("/organizations")
+	{
+		organizations.POST("/", RequireOperator(provider.OperatorKey), provider.OrganizationProvider.CreateOrganization)
+		organizations.GET("/current", CheckAuth(), provider.OrganizationProvider.GetCurrentOrganization)
+		organizations.POST("/invites", CheckAuth(), RequireAdmin(provider.UserServiceProvider), provider.OrganizationProvider.CreateInvite)
+	}
+
 	auth := router.Group("/auth")
 	{
 		auth.POST("/register", provider.UserProvider.Register)
 		auth.POST("/login", provider.UserProvider.Login)
 	}
 
-	authors := router.Group("/authors", CheckAuth())
+	authors := router.Group("/authors", CheckAuth(), idempotent)
 	{
 		authors.GET("/", provider.AuthorProvider.GetListAuthors)
 		authors.POST("/", provider.AuthorProvider.CreateAuthor)
+		authors.GET("/duplicates", provider.AuthorProvider.FindDuplicateAuthors)
 		authors.GET("/:id", provider.AuthorProvider.FindAuthorById)
 		authors.PUT("/:id", provider.AuthorProvider.UpdateAuthor)
+		authors.PATCH("/:id", provider.AuthorProvider.PatchAuthor)
 		authors.DELETE("/:id", provider.AuthorProvider.DeleteAuthor)
+		authors.POST("/:id/merge", provider.AuthorProvider.MergeAuthor)
+		authors.GET("/:id/books", provider.AuthorProvider.GetListAuthorBooks)
+		authors.GET("/:id/stats", provider.AuthorProvider.GetAuthorStats)
 	}
 
-	books := router.Group("/books", CheckAuth())
+	router.GET("/events/stream", CheckAuth(), provider.EventProvider.Stream)
+	router.POST("/batch", CheckAuth(), idempotent, provider.BatchProvider.RunBatch)
+	router.POST("/graphql", CheckAuth(), idempotent, provider.GraphQLProvider.Query)
+
+	books := router.Group("/books", CheckAuth(), idempotent)
 	{
 		books.GET("/", provider.BookProvider.GetListBooks)
 		books.POST("/", provider.BookProvider.CreateBook)
+		books.POST("/labels", provider.LabelProvider.SpineLabels)
+		books.POST("/import", provider.BookProvider.ImportBooks)
 		books.GET("/:id", provider.BookProvider.FindBookById)
 		books.PUT("/:id", provider.BookProvider.UpdateBook)
+		books.PATCH("/:id", provider.BookProvider.PatchBook)
 		books.DELETE("/:id", provider.BookProvider.DeleteBook)
+		books.GET("/:id/barcode", provider.LabelProvider.BookBarcode)
+		books.GET("/:id/qrcode", provider.LabelProvider.BookQRCode)
+		books.GET("/:id/copies", provider.BookCopyProvider.GetListBookCopies)
+		books.POST("/:id/copies", provider.BookCopyProvider.CreateBookCopy)
+		books.POST("/:id/ratings", provider.BookProvider.RateBook)
+		books.PUT("/:id/copies/:copy_id", provider.BookCopyProvider.UpdateBookCopy)
+	}
+
+	loans := router.Group("/loans", CheckAuth(), idempotent)
+	{
+		loans.POST("/", provider.LoanProvider.CreateLoan)
+		loans.GET("/:id", provider.LoanProvider.FindLoanById)
+		loans.POST("/:id/return", provider.LoanProvider.ReturnLoan)
+	}
+
+	branches := router.Group("/branches", CheckAuth(), idempotent)
+	{
+		branches.GET("/", provider.BranchProvider.GetListBranches)
+		branches.POST("/", provider.BranchProvider.CreateBranch)
+		branches.GET("/:id", provider.BranchProvider.FindBranchById)
+		branches.PUT("/:id", provider.BranchProvider.UpdateBranch)
+		branches.DELETE("/:id", provider.BranchProvider.DeleteBranch)
+		branches.POST("/:id/locations", provider.BranchProvider.CreateShelfLocation)
+		branches.GET("/:id/transfers", provider.TransferProvider.GetBranchTransfers)
+	}
+
+	transfers := router.Group("/transfers", CheckAuth(), idempotent)
+	{
+		transfers.POST("/", provider.TransferProvider.RequestTransfer)
+		transfers.GET("/:id", provider.TransferProvider.FindTransferById)
+		transfers.POST("/:id/ship", provider.TransferProvider.ShipTransfer)
+		transfers.POST("/:id/receive", provider.TransferProvider.ReceiveTransfer)
+		transfers.POST("/:id/cancel", provider.TransferProvider.CancelTransfer)
+	}
+
+	stocktakes := router.Group("/stocktakes", CheckAuth(), idempotent)
+	{
+		stocktakes.POST("/", provider.StocktakeProvider.OpenStocktake)
+		stocktakes.GET("/:id", provider.StocktakeProvider.FindStocktakeById)
+		stocktakes.POST("/:id/scans", provider.StocktakeProvider.ScanStocktake)
+		stocktakes.POST("/:id/close", provider.StocktakeProvider.CloseStocktake)
+		stocktakes.GET("/:id/report", provider.StocktakeProvider.GetStocktakeReport)
+		stocktakes.POST("/:id/mark-lost", provider.StocktakeProvider.MarkMissingLost)
+	}
+
+	vendors := router.Group("/vendors", CheckAuth(), idempotent)
+	{
+		vendors.GET("/", provider.AcquisitionProvider.GetListVendors)
+		vendors.POST("/", provider.AcquisitionProvider.CreateVendor)
+	}
+
+	funds := router.Group("/funds", CheckAuth(), idempotent)
+	{
+		funds.GET("/", provider.AcquisitionProvider.GetListFunds)
+		funds.POST("/", provider.AcquisitionProvider.CreateFund)
+		funds.GET("/:id", provider.AcquisitionProvider.FindFundById)
+	}
+
+	purchaseOrders := router.Group("/purchase-orders", CheckAuth(), idempotent)
+	{
+		purchaseOrders.GET("/", provider.AcquisitionProvider.GetListPurchaseOrders)
+		purchaseOrders.POST("/", provider.AcquisitionProvider.CreatePurchaseOrder)
+		purchaseOrders.GET("/:id", provider.AcquisitionProvider.FindPurchaseOrderById)
+		purchaseOrders.POST("/:id/submit", provider.AcquisitionProvider.SubmitPurchaseOrder)
+		purchaseOrders.POST("/:id/cancel", provider.AcquisitionProvider.CancelPurchaseOrder)
+		purchaseOrders.POST("/:id/receive", provider.AcquisitionProvider.ReceivePurchaseOrder)
+	}
+
+	reports := router.Group("/reports", CheckAuth())
+	{
+		reports.GET("/books-per-author", provider.ReportProvider.BooksPerAuthor)
+		reports.GET("/books-without-isbn", provider.ReportProvider.BooksWithoutISBN)
+		reports.GET("/authors-without-books", provider.ReportProvider.AuthorsWithoutBooks)
+		reports.GET("/most-borrowed", provider.ReportProvider.MostBorrowedBooks)
+		reports.GET("/least-borrowed", provider.ReportProvider.LeastBorrowedBooks)
+		reports.GET("/acquisitions", provider.ReportProvider.AcquisitionsPerMonth)
 	}
 }
 
@@ -53,7 +190,50 @@ func CheckAuth() gin.HandlerFunc {
 			ctx.AbortWithStatusJSON(resp.StatusCode, resp)
 			return
 		}
+		if payload.TenantId == 0 {
+			resp := response.UnauthorizedErrorWithAdditionalInfo("token has no organization")
+			ctx.AbortWithStatusJSON(resp.StatusCode, resp)
+			return
+		}
 		ctx.Set("authId", payload.AuthId)
+		ctx.Set("tenantId", payload.TenantId)
+		ctx.Request = ctx.Request.WithContext(tenant.WithID(ctx.Request.Context(), uint(payload.TenantId)))
+		ctx.Next()
+	}
+}
+
+// OperatorKeyHeader carries the key of the operators of the API.
+const OperatorKeyHeader = "X-Operator-Key"
+
+// RequireOperator lets through the requests carrying key in
+// OperatorKeyHeader only, and none when key is empty.
+func RequireOperator(key string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		given := ctx.GetHeader(OperatorKeyHeader)
+		if key == "" || subtle.ConstantTimeCompare([]byte(given), []byte(key)) != 1 {
+			resp := response.ForbiddenErrorWithAdditionalInfo("operators only")
+			ctx.AbortWithStatusJSON(resp.StatusCode, resp)
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// RequireAdmin lets through the admins of the organization only. It runs
+// after CheckAuth.
+func RequireAdmin(userService services.UserService) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		user, custErr := userService.FindDetailUser(ctx, ctx.GetInt("authId"))
+		if custErr != nil {
+			resp := response.UnauthorizedErrorWithAdditionalInfo("user not found")
+			ctx.AbortWithStatusJSON(resp.StatusCode, resp)
+			return
+		}
+		if !user.Admin {
+			resp := response.ForbiddenErrorWithAdditionalInfo("admins only")
+			ctx.AbortWithStatusJSON(resp.StatusCode, resp)
+			return
+		}
 		ctx.Next()
 	}
 }
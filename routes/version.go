@@ -0,0 +1,53 @@
+package routes
+
+import (
+	"golang-backend-test/factory"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// The routes from before /v1 existed are deprecated from unversionedDeprecation
+// and go away at unversionedSunset.
+var (
+	unversionedDeprecation = time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+	unversionedSunset      = time.Date(2027, time.April, 30, 0, 0, 0, 0, time.UTC)
+)
+
+// Deprecated marks responses with a Deprecation header (RFC 9745), a Sunset
+// header (RFC 8594) and a link to the same path under successor.
+func Deprecated(successor string, deprecation, sunset time.Time) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Header("Deprecation", "@"+strconv.FormatInt(deprecation.Unix(), 10))
+		ctx.Header("Sunset", sunset.UTC().Format(http.TimeFormat))
+		ctx.Header("Link", "<"+successor+ctx.Request.URL.Path+`>; rel="successor-version"`)
+		ctx.Next()
+	}
+}
+
+// v2Routes registers the v2 API, which answers with bare resources, real
+// 404s and the created resource on POST. Tokens come from /v1/auth/login.
+func v2Routes(router *gin.RouterGroup, provider *factory.Provider, idempotent gin.HandlerFunc) {
+	authors := router.Group("/authors", CheckAuth(), idempotent)
+	{
+		authors.GET("/", provider.AuthorV2Provider.GetListAuthors)
+		authors.POST("/", provider.AuthorV2Provider.CreateAuthor)
+		authors.GET("/:id", provider.AuthorV2Provider.FindAuthorById)
+		authors.PUT("/:id", provider.AuthorV2Provider.UpdateAuthor)
+		authors.PATCH("/:id", provider.AuthorV2Provider.PatchAuthor)
+		authors.DELETE("/:id", provider.AuthorV2Provider.DeleteAuthor)
+		authors.GET("/:id/books", provider.AuthorV2Provider.GetListAuthorBooks)
+	}
+
+	books := router.Group("/books", CheckAuth(), idempotent)
+	{
+		books.GET("/", provider.BookV2Provider.GetListBooks)
+		books.POST("/", provider.BookV2Provider.CreateBook)
+		books.GET("/:id", provider.BookV2Provider.FindBookById)
+		books.PUT("/:id", provider.BookV2Provider.UpdateBook)
+		books.PATCH("/:id", provider.BookV2Provider.PatchBook)
+		books.DELETE("/:id", provider.BookV2Provider.DeleteBook)
+	}
+}